@@ -0,0 +1,193 @@
+package api
+
+import (
+	gocontext "context"
+	"crypto/subtle"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/context"
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/baetyl/baetyl-go/v2/utils"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+// Activate activates the device with the fingerprint of batch, the node is created with the labels and sysApps of batch
+// and the certificate which the node syncs with is returned. The fingerprint can be activated only once, since the
+// certificate is returned to anyone who knows it, the repeated activations are rejected and logged
+func (api *InitAPI) Activate(c *common.Context) (interface{}, error) {
+	req := new(specV1.ActiveRequest)
+	if err := c.LoadBody(req); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if req.Namespace == "" || req.BatchName == "" || req.FingerprintValue == "" {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "namespace, batchName and fingerprintValue are required"))
+	}
+
+	batch, err := api.Batch.Get(req.Namespace, req.BatchName)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkActiveSecurity(batch, req); err != nil {
+		return nil, err
+	}
+
+	// the quota of batch is checked and acted under the lock of namespace, which the other mutators take too
+	ns := batch.Namespace
+	ctx := gocontext.Background()
+	lockName := "namespace_" + ns
+	version, err := api.Locker.Lock(ctx, lockName, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer api.Locker.Unlock(ctx, lockName, version)
+
+	record, err := api.Batch.GetActivationRecord(batch, req.FingerprintValue)
+	if err != nil {
+		return nil, err
+	}
+	// the record of the first activation is kept
+	if record.Active == common.Activated {
+		log.L().Warn("the activated device is activated again", log.Any("namespace", ns), log.Any("batch", batch.Name),
+			log.Any("node", record.NodeName), log.Any("activeIP", record.ActiveIP), log.Any("ip", c.ClientIP()))
+		return nil, common.Error(common.ErrResourceHasBeenUsed, common.Field("type", "fingerprint"), common.Field("name", req.FingerprintValue))
+	}
+
+	node, err := api.Node.Get(nil, ns, record.NodeName)
+	if err != nil {
+		if e, ok := err.(errors.Coder); !ok || e.Code() != common.ErrResourceNotFound {
+			return nil, err
+		}
+		node = nil
+	}
+	// the node of batch is left if the last activation failed, it's used by the other one otherwise
+	if node != nil && node.Labels[common.LabelBatch] != batch.Name {
+		return nil, common.Error(common.ErrResourceHasBeenUsed, common.Field("type", "node"), common.Field("name", record.NodeName))
+	}
+	if node == nil {
+		if node, err = api.createBatchNode(batch, record.NodeName, req.Mode); err != nil {
+			return nil, err
+		}
+	}
+	// the certificate is got before the record is activated, so the device can retry if it fails
+	cert, err := api.Init.GetNodeCertificate(ns, node.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	record.Active = common.Activated
+	record.ActiveIP = c.ClientIP()
+	record.ActiveTime = time.Now()
+	if _, err = api.Batch.UpdateRecord(record); err != nil {
+		return nil, err
+	}
+	log.L().Info("the device of batch is activated", log.Any("namespace", ns),
+		log.Any("batch", batch.Name), log.Any("node", node.Name), log.Any("ip", record.ActiveIP))
+	api.notifyActivated(batch, record)
+	return &specV1.ActiveResponse{
+		NodeName:  node.Name,
+		Namespace: ns,
+		Certificate: utils.Certificate{
+			CA:   string(cert.Data["ca.pem"]),
+			Cert: string(cert.Data["client.pem"]),
+			Key:  string(cert.Data["client.key"]),
+		},
+	}, nil
+}
+
+func (api *InitAPI) createBatchNode(batch *models.Batch, name, mode string) (*specV1.Node, error) {
+	if mode == "" {
+		mode = context.RunModeKube
+	}
+	if mode != context.RunModeKube && mode != context.RunModeNative && mode != context.RunModeAndroid {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "only kube or native or android is surpported with mode"))
+	}
+	cluster := batch.Cluster == 1
+	if cluster && mode == context.RunModeNative {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "cluster is not supported with native mode"))
+	}
+
+	labels := map[string]string{}
+	for k, v := range batch.Labels {
+		labels[k] = v
+	}
+	node := &specV1.Node{
+		Name:        name,
+		Namespace:   batch.Namespace,
+		Description: batch.Description,
+		Accelerator: batch.Accelerator,
+		Cluster:     cluster,
+		NodeMode:    mode,
+		SysApps:     common.UpdateSysAppByAccelerator(batch.Accelerator, append([]string{}, batch.SysApps...)),
+		Labels: common.AddSystemLabel(labels, map[string]string{
+			common.LabelNodeName:    name,
+			common.LabelBatch:       batch.Name,
+			common.LabelAccelerator: batch.Accelerator,
+			common.LabelCluster:     strconv.FormatBool(cluster),
+			common.LabelNodeMode:    mode,
+		}),
+	}
+	core, err := api.Module.GetLatestModule(BaetylModule)
+	if err != nil {
+		return nil, err
+	}
+	node.Attributes = map[string]interface{}{
+		"BaetylCoreVersion": core.Version,
+	}
+
+	if err = api.Quota.CheckQuota(batch.Namespace, api.Node.Count); err != nil {
+		return nil, err
+	}
+	if err = api.Quota.AcquireQuota(batch.Namespace, plugin.QuotaNode, NodeNumber); err != nil {
+		return nil, err
+	}
+	res, err := api.Wrapper.CreateNodeTx(api.Node.Create)(nil, batch.Namespace, node)
+	if err != nil {
+		if e := api.Quota.ReleaseQuota(batch.Namespace, plugin.QuotaNode, NodeNumber); e != nil {
+			log.L().Error("ReleaseQuota error", log.Error(e))
+		}
+		return nil, err
+	}
+	return res, nil
+}
+
+// notifyActivated emits the event of the device activated into the node for the first time
+func (api *InitAPI) notifyActivated(batch *models.Batch, record *models.Record) {
+	event := &models.Event{
+		ID:        fmt.Sprintf("%s/%s/%s", models.EventNodeActivated, batch.Name, record.Name),
+		Type:      models.EventNodeActivated,
+		Namespace: batch.Namespace,
+		Data: map[string]interface{}{
+			"node":             record.NodeName,
+			"batch":            batch.Name,
+			"fingerprintValue": record.FingerprintValue,
+			"ip":               record.ActiveIP,
+		},
+	}
+	if err := api.Webhook.Emit(event); err != nil {
+		log.L().Error("failed to emit node activated event", log.Any("namespace", batch.Namespace),
+			log.Any("node", record.NodeName), log.Error(err))
+	}
+}
+
+// checkActiveSecurity checks the security value of device with the key of batch
+func checkActiveSecurity(batch *models.Batch, req *specV1.ActiveRequest) error {
+	switch batch.SecurityType {
+	case "", common.None:
+		return nil
+	case common.Token:
+		if req.SecurityType == string(common.Token) &&
+			subtle.ConstantTimeCompare([]byte(req.SecurityValue), []byte(batch.SecurityKey)) == 1 {
+			return nil
+		}
+		return common.Error(common.ErrRequestAccessDenied, common.Field("error", "the security value of batch is invalid"))
+	default:
+		return common.Error(common.ErrRequestAccessDenied, common.Field("error", "the security type of batch is not supported"))
+	}
+}
@@ -0,0 +1,181 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+func initActivationAPI(t *testing.T) (*InitAPI, *gin.Engine, *gomock.Controller) {
+	api := &InitAPI{}
+	router := gin.Default()
+	mockCtl := gomock.NewController(t)
+	v1 := router.Group("v1")
+	v1.POST("/activate", common.Wrapper(api.Activate))
+	return api, router, mockCtl
+}
+
+func newActivateRequest(req *specV1.ActiveRequest) *http.Request {
+	body, _ := json.Marshal(req)
+	r, _ := http.NewRequest(http.MethodPost, "/v1/activate", bytes.NewReader(body))
+	r.RemoteAddr = "10.0.0.1:8080"
+	return r
+}
+
+func TestInitAPI_Activate(t *testing.T) {
+	api, router, mockCtl := initActivationAPI(t)
+	defer mockCtl.Finish()
+	sInit := ms.NewMockInitService(mockCtl)
+	sBatch := ms.NewMockBatchService(mockCtl)
+	sNode := ms.NewMockNodeService(mockCtl)
+	sModule := ms.NewMockModuleService(mockCtl)
+	sQuota := ms.NewMockQuotaService(mockCtl)
+	sWebhook := ms.NewMockWebhookService(mockCtl)
+	sLocker := ms.NewMockLockerService(mockCtl)
+	sLocker.EXPECT().Lock(gomock.Any(), "namespace_default", int64(0)).Return("v", nil).AnyTimes()
+	sLocker.EXPECT().Unlock(gomock.Any(), "namespace_default", "v").AnyTimes()
+	api.Locker = sLocker
+	api.Init = sInit
+	api.Batch = sBatch
+	api.Node = sNode
+	api.Module = sModule
+	api.Quota = sQuota
+	api.Webhook = sWebhook
+	cfg := &config.CloudConfig{}
+	cfg.Plugin.Tx = "defaulttx"
+	api.Wrapper, _ = service.NewWrapperService(cfg)
+
+	batch := &models.Batch{
+		Name:         "b1",
+		Namespace:    "default",
+		SecurityType: common.Token,
+		SecurityKey:  "key",
+		SysApps:      []string{"baetyl-function"},
+		Labels:       map[string]string{"a": "b"},
+	}
+	req := &specV1.ActiveRequest{
+		BatchName:        "b1",
+		Namespace:        "default",
+		FingerprintValue: "sn-1",
+		SecurityType:     string(common.Token),
+		SecurityValue:    "key",
+	}
+	notFound := common.Error(common.ErrResourceNotFound, common.Field("type", "node"))
+	cert := &specV1.Secret{Data: map[string][]byte{"ca.pem": []byte("ca"), "client.pem": []byte("cert"), "client.key": []byte("key")}}
+
+	// the device is activated into a new node
+	record := &models.Record{Name: "r1", Namespace: "default", BatchName: "b1", FingerprintValue: "sn-1", NodeName: "n1"}
+	sBatch.EXPECT().Get("default", "b1").Return(batch, nil).Times(1)
+	sBatch.EXPECT().GetActivationRecord(batch, "sn-1").Return(record, nil).Times(1)
+	sNode.EXPECT().Get(nil, "default", "n1").Return(nil, notFound).Times(1)
+	sModule.EXPECT().GetLatestModule(BaetylModule).Return(&models.Module{Version: "v2.4.0"}, nil).Times(1)
+	sQuota.EXPECT().CheckQuota("default", gomock.Any()).Return(nil).Times(1)
+	sQuota.EXPECT().AcquireQuota("default", plugin.QuotaNode, NodeNumber).Return(nil).Times(1)
+	sNode.EXPECT().Create(gomock.Any(), "default", gomock.Any()).DoAndReturn(func(_ interface{}, _ string, node *specV1.Node) (*specV1.Node, error) {
+		assert.Equal(t, "n1", node.Name)
+		assert.Equal(t, "kube", node.NodeMode)
+		assert.Equal(t, "b", node.Labels["a"])
+		assert.Equal(t, "b1", node.Labels[common.LabelBatch])
+		assert.Equal(t, []string{"baetyl-function"}, node.SysApps)
+		assert.Equal(t, "v2.4.0", node.Attributes["BaetylCoreVersion"])
+		return node, nil
+	}).Times(1)
+	sBatch.EXPECT().UpdateRecord(gomock.Any()).DoAndReturn(func(r *models.Record) (*models.Record, error) {
+		assert.Equal(t, common.Activated, r.Active)
+		assert.Equal(t, "10.0.0.1", r.ActiveIP)
+		return r, nil
+	}).Times(1)
+	sInit.EXPECT().GetNodeCertificate("default", "n1").Return(cert, nil).Times(1)
+	sWebhook.EXPECT().Emit(gomock.Any()).DoAndReturn(func(event *models.Event) error {
+		assert.Equal(t, models.EventNodeActivated, event.Type)
+		assert.Equal(t, "default", event.Namespace)
+		assert.Equal(t, "n1", event.Data["node"])
+		return nil
+	}).Times(1)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newActivateRequest(req))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	res := new(specV1.ActiveResponse)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, "n1", res.NodeName)
+	assert.Equal(t, "ca", res.Certificate.CA)
+	assert.Equal(t, "cert", res.Certificate.Cert)
+	assert.Equal(t, "key", res.Certificate.Key)
+
+	// the activated device is rejected and the first record is kept
+	record.Active = common.Activated
+	sBatch.EXPECT().Get("default", "b1").Return(batch, nil).Times(1)
+	sBatch.EXPECT().GetActivationRecord(batch, "sn-1").Return(record, nil).Times(1)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, newActivateRequest(req))
+	assert.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+	assert.Equal(t, "10.0.0.1", record.ActiveIP)
+
+	// the node of batch left by the failed activation is used
+	record.Active = common.Inactivated
+	sBatch.EXPECT().Get("default", "b1").Return(batch, nil).Times(1)
+	sBatch.EXPECT().GetActivationRecord(batch, "sn-1").Return(record, nil).Times(1)
+	sNode.EXPECT().Get(nil, "default", "n1").Return(&specV1.Node{Name: "n1", Namespace: "default",
+		Labels: map[string]string{common.LabelBatch: "b1"}}, nil).Times(1)
+	sInit.EXPECT().GetNodeCertificate("default", "n1").Return(nil, notFound).Times(1)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, newActivateRequest(req))
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+	assert.Equal(t, common.Inactivated, record.Active)
+
+	sBatch.EXPECT().Get("default", "b1").Return(batch, nil).Times(1)
+	sBatch.EXPECT().GetActivationRecord(batch, "sn-1").Return(record, nil).Times(1)
+	sNode.EXPECT().Get(nil, "default", "n1").Return(&specV1.Node{Name: "n1", Namespace: "default",
+		Labels: map[string]string{common.LabelBatch: "b1"}}, nil).Times(1)
+	sInit.EXPECT().GetNodeCertificate("default", "n1").Return(cert, nil).Times(1)
+	sBatch.EXPECT().UpdateRecord(gomock.Any()).Return(record, nil).Times(1)
+	sWebhook.EXPECT().Emit(gomock.Any()).Return(nil).Times(1)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, newActivateRequest(req))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, common.Activated, record.Active)
+
+	// the node name of inactivated record is used by the other node
+	record.Active = common.Inactivated
+	sBatch.EXPECT().Get("default", "b1").Return(batch, nil).Times(1)
+	sBatch.EXPECT().GetActivationRecord(batch, "sn-1").Return(record, nil).Times(1)
+	sNode.EXPECT().Get(nil, "default", "n1").Return(&specV1.Node{Name: "n1", Namespace: "default"}, nil).Times(1)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, newActivateRequest(req))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// the security value is wrong
+	req.SecurityValue = "wrong"
+	sBatch.EXPECT().Get("default", "b1").Return(batch, nil).Times(1)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, newActivateRequest(req))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// the fingerprint is required
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, newActivateRequest(&specV1.ActiveRequest{BatchName: "b1", Namespace: "default"}))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCheckActiveSecurity(t *testing.T) {
+	assert.NoError(t, checkActiveSecurity(&models.Batch{SecurityType: common.None}, &specV1.ActiveRequest{}))
+	assert.NoError(t, checkActiveSecurity(&models.Batch{SecurityType: common.Token, SecurityKey: "k"},
+		&specV1.ActiveRequest{SecurityType: string(common.Token), SecurityValue: "k"}))
+	assert.Error(t, checkActiveSecurity(&models.Batch{SecurityType: common.Token, SecurityKey: "k"},
+		&specV1.ActiveRequest{SecurityType: string(common.None), SecurityValue: "k"}))
+	assert.Error(t, checkActiveSecurity(&models.Batch{SecurityType: common.Cert}, &specV1.ActiveRequest{}))
+}
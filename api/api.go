@@ -11,26 +11,40 @@ import (
 
 // API baetyl api server
 type API struct {
-	Hooks    map[string]interface{}
-	NS       service.NamespaceService
-	Node     service.NodeService
-	Index    service.IndexService
-	Func     service.FunctionService
-	Obj      service.ObjectService
-	PKI      service.PKIService
-	Auth     service.AuthService
-	Prop     service.PropertyService
-	Module   service.ModuleService
-	Init     service.InitService
-	License  service.LicenseService
-	Quota    service.QuotaService
-	Template service.TemplateService
-	Task     service.TaskService
-	Locker   service.LockerService
-	SysApp   service.SystemAppService
-	Sign     service.SignService
-	Wrapper  service.WrapperService
-	Facade   facade.Facade
+	Hooks         map[string]interface{}
+	NS            service.NamespaceService
+	Node          service.NodeService
+	Batch         service.BatchService
+	Index         service.IndexService
+	Func          service.FunctionService
+	Obj           service.ObjectService
+	PKI           service.PKIService
+	Auth          service.AuthService
+	Prop          service.PropertyService
+	Module        service.ModuleService
+	Init          service.InitService
+	License       service.LicenseService
+	Quota         service.QuotaService
+	Template      service.TemplateService
+	Task          service.TaskService
+	Locker        service.LockerService
+	SysApp        service.SystemAppService
+	Sign          service.SignService
+	Wrapper       service.WrapperService
+	AppHistory    service.AppHistoryService
+	Cron          service.CronService
+	Rollout       service.RolloutService
+	AppStatus     service.AppStatusService
+	Preview       service.PreviewService
+	RecycleBin    service.RecycleBinService
+	CertExpiry    service.CertificateExpiryService
+	Webhook       service.WebhookService
+	RegistryCheck service.RegistryCheckService
+	ConfigUpload  service.ConfigUploadService
+	Providers     service.SecretProviderService
+	InstallToken  service.InstallTokenService
+	OfflineBundle service.OfflineBundleService
+	Facade        facade.Facade
 	*service.AppCombinedService
 	log *log.Logger
 }
@@ -45,6 +59,10 @@ func NewAPI(config *config.CloudConfig) (*API, error) {
 	if err != nil {
 		return nil, err
 	}
+	batchService, err := service.NewBatchService(config)
+	if err != nil {
+		return nil, err
+	}
 	namespaceService, err := service.NewNamespaceService(config)
 	if err != nil {
 		return nil, err
@@ -118,6 +136,58 @@ func NewAPI(config *config.CloudConfig) (*API, error) {
 	if err != nil {
 		return nil, err
 	}
+	appHistory, err := service.NewAppHistoryService(config)
+	if err != nil {
+		return nil, err
+	}
+	cronService, err := service.NewCronService(config)
+	if err != nil {
+		return nil, err
+	}
+	rolloutService, err := service.NewRolloutService(config)
+	if err != nil {
+		return nil, err
+	}
+	appStatus, err := service.NewAppStatusService(config)
+	if err != nil {
+		return nil, err
+	}
+	preview, err := service.NewPreviewService(config)
+	if err != nil {
+		return nil, err
+	}
+	recycleBin, err := service.NewRecycleBinService(config)
+	if err != nil {
+		return nil, err
+	}
+	certExpiry, err := service.NewCertificateExpiryService(config)
+	if err != nil {
+		return nil, err
+	}
+	webhook, err := service.NewWebhookService(config)
+	if err != nil {
+		return nil, err
+	}
+	registryCheck, err := service.NewRegistryCheckService(config)
+	if err != nil {
+		return nil, err
+	}
+	configUpload, err := service.NewConfigUploadService(config)
+	if err != nil {
+		return nil, err
+	}
+	secretProvider, err := service.NewSecretProviderService(config)
+	if err != nil {
+		return nil, err
+	}
+	installToken, err := service.NewInstallTokenService(config)
+	if err != nil {
+		return nil, err
+	}
+	offlineBundle, err := service.NewOfflineBundleService(config)
+	if err != nil {
+		return nil, err
+	}
 	appFacade, err := facade.NewFacade(config)
 	if err != nil {
 		return nil, err
@@ -125,6 +195,7 @@ func NewAPI(config *config.CloudConfig) (*API, error) {
 	return &API{
 		NS:                 namespaceService,
 		Node:               nodeService,
+		Batch:              batchService,
 		Index:              indexService,
 		Obj:                objectService,
 		Func:               functionService,
@@ -141,6 +212,19 @@ func NewAPI(config *config.CloudConfig) (*API, error) {
 		Locker:             lockerService,
 		SysApp:             sysApp,
 		Wrapper:            wrapper,
+		AppHistory:         appHistory,
+		Cron:               cronService,
+		Rollout:            rolloutService,
+		AppStatus:          appStatus,
+		Preview:            preview,
+		RecycleBin:         recycleBin,
+		CertExpiry:         certExpiry,
+		Webhook:            webhook,
+		RegistryCheck:      registryCheck,
+		ConfigUpload:       configUpload,
+		Providers:          secretProvider,
+		InstallToken:       installToken,
+		OfflineBundle:      offlineBundle,
 		AppCombinedService: acs,
 		Facade:             appFacade,
 		log:                log.L().With(log.Any("api", "admin")),
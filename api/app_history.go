@@ -0,0 +1,188 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/facade"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// ListAppRevisions list the revisions of application
+func (api *API) ListAppRevisions(c *common.Context) (interface{}, error) {
+	ns, name := c.GetNamespace(), c.GetNameFromParam()
+	if _, err := api.getVisibleApp(ns, name); err != nil {
+		return nil, err
+	}
+	params, err := api.ParseListOptions(c)
+	if err != nil {
+		return nil, err
+	}
+	return api.AppHistory.List(ns, name, params)
+}
+
+// DiffAppRevisions compare two revisions of application
+func (api *API) DiffAppRevisions(c *common.Context) (interface{}, error) {
+	ns, name := c.GetNamespace(), c.GetNameFromParam()
+	if _, err := api.getVisibleApp(ns, name); err != nil {
+		return nil, err
+	}
+	from, err := parseRevision(c.Query("from"))
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseRevision(c.Query("to"))
+	if err != nil {
+		return nil, err
+	}
+	return api.AppHistory.Diff(ns, name, from, to)
+}
+
+// RollbackApplication restore the application to the spec of the given revision, the referenced configs and secrets
+// must be unmodified since the revision unless force is set, then the current versions of them are used
+func (api *API) RollbackApplication(c *common.Context) (interface{}, error) {
+	ns, name := c.GetNamespace(), c.GetNameFromParam()
+	revision, err := parseRevision(c.Query("revision"))
+	if err != nil {
+		return nil, err
+	}
+	force, err := parseForce(c.Query("force"))
+	if err != nil {
+		return nil, err
+	}
+
+	oldApp, err := api.getVisibleApp(ns, name)
+	if err != nil {
+		return nil, err
+	}
+	if oldApp.CronStatus == specV1.CronWait {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "can't rollback a cron job which is waiting to be deployed"))
+	}
+
+	history, err := api.AppHistory.Get(ns, name, revision)
+	if err != nil {
+		return nil, err
+	}
+	if history.Application == nil {
+		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "appHistory"), common.Field("name", name))
+	}
+
+	app := history.Application
+	app.Namespace = oldApp.Namespace
+	app.Name = oldApp.Name
+	app.Version = oldApp.Version
+	app.CreationTimestamp = oldApp.CreationTimestamp
+	app.CronStatus = oldApp.CronStatus
+	app.CronTime = oldApp.CronTime
+	// ota can not modify
+	app.Ota = oldApp.Ota
+
+	// labels and Selector can't be modified of sys apps
+	if CheckIsSysResources(oldApp.Labels) && oldApp.Selector != app.Selector {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "selector can't be modified of sys apps"))
+	}
+
+	configs, err := api.getRollbackConfigs(ns, history, force)
+	if err != nil {
+		return nil, err
+	}
+
+	if f, exist := api.Hooks[HookUpdateApplicationOta]; exist {
+		if hk, ok := f.(UpdateApplicationOta); ok {
+			app, err = hk(c, app)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	app, err = api.Facade.UpdateApp(ns, oldApp, app, configs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return api.ToApplicationView(app)
+}
+
+func (api *API) getVisibleApp(ns, name string) (*specV1.Application, error) {
+	app, err := api.App.Get(ns, name, "")
+	if err != nil {
+		return nil, err
+	}
+	// sys app: core、init、function is not visible
+	if common.ValidIsInvisible(app.Labels) {
+		return nil, common.Error(common.ErrResourceInvisible, common.Field("type", common.APP), common.Field("name", app.Name))
+	}
+	return app, nil
+}
+
+// getRollbackConfigs checks the referenced resources are still of the versions recorded in the history if not forced,
+// pins the volumes to the current versions, and returns the generated configs of function app,
+// which must be kept when cleaning the generated configs of the current app
+func (api *API) getRollbackConfigs(ns string, history *models.AppHistory, force bool) ([]specV1.Configuration, error) {
+	var configs []specV1.Configuration
+	for _, v := range history.Application.Volumes {
+		if v.Config != nil {
+			cfg, err := api.Config.Get(nil, ns, v.Config.Name, "")
+			if err != nil {
+				return nil, err
+			}
+			if !force {
+				if err = checkRecordedVersion(common.Config, cfg.Name, cfg.Version, history.Configs, history.Revision); err != nil {
+					return nil, err
+				}
+			}
+			v.Config.Version = cfg.Version
+			if strings.HasPrefix(cfg.Name, facade.FunctionConfigPrefix) ||
+				strings.HasPrefix(cfg.Name, facade.FunctionProgramConfigPrefix) {
+				configs = append(configs, *cfg)
+			}
+		}
+		if v.Secret != nil {
+			secret, err := api.Secret.Get(ns, v.Secret.Name, "")
+			if err != nil {
+				return nil, err
+			}
+			if !force {
+				if err = checkRecordedVersion(common.Secret, secret.Name, secret.Version, history.Secrets, history.Revision); err != nil {
+					return nil, err
+				}
+			}
+			v.Secret.Version = secret.Version
+		}
+	}
+	return configs, nil
+}
+
+// checkRecordedVersion returns an error if the resource is modified since the revision was recorded,
+// the resources which aren't recorded are not checked
+func checkRecordedVersion(typ common.Resource, name, version string, recorded map[string]string, revision int64) error {
+	if v, ok := recorded[name]; ok && v != "" && v != version {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error",
+			fmt.Sprintf("the %s (%s) has been modified since revision %d, rollback with force to use the current version", typ, name, revision)))
+	}
+	return nil
+}
+
+func parseForce(s string) (bool, error) {
+	if s == "" {
+		return false, nil
+	}
+	force, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, common.Error(common.ErrRequestParamInvalid, common.Field("error", "force should be a boolean"))
+	}
+	return force, nil
+}
+
+func parseRevision(s string) (int64, error) {
+	revision, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || revision <= 0 {
+		return 0, common.Error(common.ErrRequestParamInvalid, common.Field("error", "revision should be a positive integer"))
+	}
+	return revision, nil
+}
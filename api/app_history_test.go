@@ -0,0 +1,236 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	mf "github.com/baetyl/baetyl-cloud/v2/mock/facade"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+func initAppHistoryAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
+	api := &API{log: log.L().With(log.Any("test", "api"))}
+	router := gin.Default()
+	mockCtl := gomock.NewController(t)
+	mockIM := func(c *gin.Context) { c.Set(common.KeyContextNamespace, "baetyl-cloud") }
+	v1 := router.Group("v1")
+	{
+		apps := v1.Group("/apps")
+		apps.GET("/:name/revisions", mockIM, common.Wrapper(api.ListAppRevisions))
+		apps.GET("/:name/revisions/diff", mockIM, common.Wrapper(api.DiffAppRevisions))
+		apps.POST("/:name/rollback", mockIM, common.Wrapper(api.RollbackApplication))
+	}
+	return api, router, mockCtl
+}
+
+func TestListAppRevisions(t *testing.T) {
+	api, router, mockCtl := initAppHistoryAPI(t)
+	defer mockCtl.Finish()
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sHistory := ms.NewMockAppHistoryService(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{App: sApp}
+	api.AppHistory = sHistory
+
+	ns, name := "baetyl-cloud", "abc"
+	sApp.EXPECT().Get(ns, name, "").Return(&specV1.Application{
+		Namespace: ns,
+		Name:      name,
+		Labels:    map[string]string{common.ResourceInvisible: "true"},
+	}, nil).Times(1)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/apps/abc/revisions", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	sApp.EXPECT().Get(ns, name, "").Return(&specV1.Application{Namespace: ns, Name: name}, nil).AnyTimes()
+	list := &models.AppHistoryList{
+		Total: 1,
+		Items: []models.AppHistory{{Namespace: ns, Name: name, Revision: 1, Version: "1"}},
+	}
+	sHistory.EXPECT().List(ns, name, gomock.Any()).Return(list, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/apps/abc/revisions?pageNo=1&pageSize=10", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.AppHistoryList{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, 1, res.Total)
+	assert.Equal(t, int64(1), res.Items[0].Revision)
+
+	req, _ = http.NewRequest(http.MethodGet, "/v1/apps/abc/revisions/diff?from=1", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	diff := &models.AppRevisionDiff{
+		Name: name,
+		From: 1,
+		To:   2,
+		Changes: []common.FieldDiff{
+			{Path: "application.selector", From: "a=b", To: "a=c"},
+		},
+	}
+	sHistory.EXPECT().Diff(ns, name, int64(1), int64(2)).Return(diff, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/apps/abc/revisions/diff?from=1&to=2", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	resDiff := &models.AppRevisionDiff{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), resDiff))
+	assert.Equal(t, diff.Changes[0].Path, resDiff.Changes[0].Path)
+}
+
+func TestRollbackApplication(t *testing.T) {
+	api, router, mockCtl := initAppHistoryAPI(t)
+	defer mockCtl.Finish()
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sConfig := ms.NewMockConfigService(mockCtl)
+	sSecret := ms.NewMockSecretService(mockCtl)
+	sHistory := ms.NewMockAppHistoryService(mockCtl)
+	fApp := mf.NewMockFacade(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{
+		App:    sApp,
+		Config: sConfig,
+		Secret: sSecret,
+	}
+	api.AppHistory = sHistory
+	api.Facade = fApp
+
+	ns, name := "baetyl-cloud", "abc"
+	req, _ := http.NewRequest(http.MethodPost, "/v1/apps/abc/rollback?revision=x", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	oldApp := getMockContainerApp()
+	oldApp.Version = "5"
+	oldApp.Selector = "a=c"
+	oldApp.Ota = specV1.OtaInfo{}
+
+	cronApp := getMockContainerApp()
+	cronApp.CronStatus = specV1.CronWait
+	sApp.EXPECT().Get(ns, name, "").Return(cronApp, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/apps/abc/rollback?revision=1", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	sApp.EXPECT().Get(ns, name, "").Return(oldApp, nil).AnyTimes()
+	sHistory.EXPECT().Get(ns, name, int64(3)).Return(nil, common.Error(common.ErrResourceNotFound, common.Field("type", "appHistory"), common.Field("name", name))).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/apps/abc/rollback?revision=3", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	genRevApp := func() *specV1.Application {
+		revApp := getMockContainerApp()
+		revApp.Version = "1"
+		revApp.Selector = "a=b"
+		revApp.Volumes = []specV1.Volume{
+			{
+				Name:         "name",
+				VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "cfg", Version: "1"}},
+			},
+			{
+				Name:         "sec",
+				VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "sec", Version: "1"}},
+			},
+		}
+		return revApp
+	}
+	sConfig.EXPECT().Get(nil, ns, "cfg", "").Return(&specV1.Configuration{Namespace: ns, Name: "cfg", Version: "2"}, nil).Times(4)
+	sSecret.EXPECT().Get(ns, "sec", "").Return(&specV1.Secret{Namespace: ns, Name: "sec", Version: "3"}, nil).AnyTimes()
+
+	// the config is modified since the revision
+	sHistory.EXPECT().Get(ns, name, int64(2)).Return(&models.AppHistory{
+		Namespace:   ns,
+		Name:        name,
+		Revision:    2,
+		Version:     "1",
+		Application: genRevApp(),
+		Configs:     map[string]string{"cfg": "1"},
+		Secrets:     map[string]string{"sec": "3"},
+	}, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/apps/abc/rollback?revision=2", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// the secret is modified since the revision
+	sHistory.EXPECT().Get(ns, name, int64(4)).Return(&models.AppHistory{
+		Namespace:   ns,
+		Name:        name,
+		Revision:    4,
+		Version:     "1",
+		Application: genRevApp(),
+		Configs:     map[string]string{"cfg": "2"},
+		Secrets:     map[string]string{"sec": "1"},
+	}, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/apps/abc/rollback?revision=4", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	req, _ = http.NewRequest(http.MethodPost, "/v1/apps/abc/rollback?revision=4&force=x", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// the current versions are used if forced
+	sHistory.EXPECT().Get(ns, name, int64(4)).Return(&models.AppHistory{
+		Namespace:   ns,
+		Name:        name,
+		Revision:    4,
+		Version:     "1",
+		Application: genRevApp(),
+		Configs:     map[string]string{"cfg": "2"},
+		Secrets:     map[string]string{"sec": "1"},
+	}, nil).Times(1)
+	fApp.EXPECT().UpdateApp(ns, oldApp, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ string, _, app *specV1.Application, configs []specV1.Configuration) (*specV1.Application, error) {
+			assert.Equal(t, "2", app.Volumes[0].Config.Version)
+			assert.Equal(t, "3", app.Volumes[1].Secret.Version)
+			return app, nil
+		}).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/apps/abc/rollback?revision=4&force=true", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	sHistory.EXPECT().Get(ns, name, int64(1)).Return(&models.AppHistory{
+		Namespace:   ns,
+		Name:        name,
+		Revision:    1,
+		Version:     "1",
+		Application: genRevApp(),
+		Configs:     map[string]string{"cfg": "2"},
+		Secrets:     map[string]string{"sec": "3"},
+	}, nil).Times(1)
+	fApp.EXPECT().UpdateApp(ns, oldApp, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ string, _, app *specV1.Application, configs []specV1.Configuration) (*specV1.Application, error) {
+			assert.Equal(t, "5", app.Version)
+			assert.Equal(t, "a=b", app.Selector)
+			assert.Equal(t, "2", app.Volumes[0].Config.Version)
+			assert.Equal(t, "3", app.Volumes[1].Secret.Version)
+			assert.Len(t, configs, 0)
+			return app, nil
+		}).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/apps/abc/rollback?revision=1", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	view := &models.ApplicationView{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), view))
+	assert.Equal(t, "a=b", view.Selector)
+}
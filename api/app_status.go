@@ -0,0 +1,75 @@
+package api
+
+import (
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// GetAppsNodeStatus list the status of apps on the matched nodes, the nodes are filtered and paginated for each app
+func (api *API) GetAppsNodeStatus(c *common.Context) (interface{}, error) {
+	ns := c.GetNamespace()
+	params := new(models.AppStatusParams)
+	if err := c.LoadBody(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	filter, err := api.parseAppNodeStatusFilter(c)
+	if err != nil {
+		return nil, err
+	}
+	return api.listAppsNodeStatus(ns, params.App, filter)
+}
+
+// GetAppNodeStatus list the status of app on the matched nodes
+func (api *API) GetAppNodeStatus(c *common.Context) (interface{}, error) {
+	ns, name := c.GetNamespace(), c.GetNameFromParam()
+	filter, err := api.parseAppNodeStatusFilter(c)
+	if err != nil {
+		return nil, err
+	}
+	res, err := api.listAppsNodeStatus(ns, []models.AppStatusParamsItem{{Name: name}}, filter)
+	if err != nil {
+		return nil, err
+	}
+	item := res.Items[name]
+	return &item, nil
+}
+
+func (api *API) parseAppNodeStatusFilter(c *common.Context) (*models.AppNodeStatusFilter, error) {
+	filter := new(models.AppNodeStatusFilter)
+	if err := c.BindQuery(filter); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	return filter, nil
+}
+
+func (api *API) listAppsNodeStatus(ns string, params []models.AppStatusParamsItem, filter *models.AppNodeStatusFilter) (*models.AppNodeStatusReturn, error) {
+	var names []string
+	for _, p := range params {
+		names = append(names, p.Name)
+	}
+	apps, err := api.App.ListByNames(ns, names)
+	if err != nil {
+		return nil, err
+	}
+	appMap := map[string]*models.AppItem{}
+	for i := range apps {
+		appMap[apps[i].Name] = &apps[i]
+	}
+
+	res := &models.AppNodeStatusReturn{Items: map[string]models.AppNodeStatusItem{}}
+	for _, p := range params {
+		app, ok := appMap[p.Name]
+		if !ok {
+			return nil, common.Error(common.ErrResourceNotFound, common.Field("type", common.APP), common.Field("name", p.Name), common.Field("namespace", ns))
+		}
+		if common.ValidIsInvisible(app.Labels) {
+			return nil, common.Error(common.ErrResourceInvisible, common.Field("type", common.APP), common.Field("name", p.Name))
+		}
+		item, err := api.AppStatus.ListNodeStatus(ns, app, p.Nodes, filter)
+		if err != nil {
+			return nil, err
+		}
+		res.Items[p.Name] = *item
+	}
+	return res, nil
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+func initAppStatusAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
+	api := &API{log: log.L().With(log.Any("test", "api"))}
+	router := gin.Default()
+	mockCtl := gomock.NewController(t)
+	mockIM := func(c *gin.Context) { c.Set(common.KeyContextNamespace, "baetyl-cloud") }
+	v1 := router.Group("v1")
+	{
+		apps := v1.Group("/apps")
+		apps.GET("/:name/status", mockIM, common.Wrapper(api.GetAppNodeStatus))
+		apps.POST("/status", mockIM, common.Wrapper(api.GetAppsNodeStatus))
+	}
+	return api, router, mockCtl
+}
+
+func TestGetAppsNodeStatus(t *testing.T) {
+	api, router, mockCtl := initAppStatusAPI(t)
+	defer mockCtl.Finish()
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sStatus := ms.NewMockAppStatusService(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{App: sApp}
+	api.AppStatus = sStatus
+
+	ns := "baetyl-cloud"
+	app1 := models.AppItem{Name: "app1", Namespace: ns, Version: "1"}
+	app2 := models.AppItem{Name: "app2", Namespace: ns, Version: "2"}
+	item := &models.AppNodeStatusItem{
+		Summary: models.AppStatusSummary{Converged: 1, Failed: 1},
+		Nodes: []models.AppNodeStatus{
+			{Node: "n1", Status: models.AppNodeFailed, DesireVersion: "1", ReportVersion: "1"},
+		},
+		Total: 1,
+	}
+
+	sApp.EXPECT().ListByNames(ns, []string{"app1"}).Return([]models.AppItem{app1}, nil).Times(1)
+	sStatus.EXPECT().ListNodeStatus(ns, &app1, nil, gomock.Any()).DoAndReturn(
+		func(_ string, _ *models.AppItem, _ []string, filter *models.AppNodeStatusFilter) (*models.AppNodeStatusItem, error) {
+			assert.Equal(t, models.AppNodeFailed, filter.Status)
+			assert.Equal(t, 1, filter.PageNo)
+			assert.Equal(t, 10, filter.PageSize)
+			return item, nil
+		}).Times(1)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/apps/app1/status?status=failed&pageNo=1&pageSize=10", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.AppNodeStatusItem{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, 1, res.Summary.Failed)
+	assert.Equal(t, "n1", res.Nodes[0].Node)
+
+	req, _ = http.NewRequest(http.MethodGet, "/v1/apps/app1/status?status=unknown", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	body, _ := json.Marshal(&models.AppStatusParams{App: []models.AppStatusParamsItem{
+		{Name: "app1", Nodes: []string{"n1"}},
+		{Name: "app2"},
+	}})
+	sApp.EXPECT().ListByNames(ns, []string{"app1", "app2"}).Return([]models.AppItem{app1, app2}, nil).Times(1)
+	sStatus.EXPECT().ListNodeStatus(ns, &app1, []string{"n1"}, gomock.Any()).Return(item, nil).Times(1)
+	sStatus.EXPECT().ListNodeStatus(ns, &app2, nil, gomock.Any()).Return(&models.AppNodeStatusItem{}, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/apps/status", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	ret := &models.AppNodeStatusReturn{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), ret))
+	assert.Len(t, ret.Items, 2)
+	assert.Equal(t, 1, ret.Items["app1"].Total)
+
+	// the app is not found
+	sApp.EXPECT().ListByNames(ns, []string{"app1", "app2"}).Return([]models.AppItem{app1}, nil).Times(1)
+	sStatus.EXPECT().ListNodeStatus(ns, &app1, []string{"n1"}, gomock.Any()).Return(item, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/apps/status", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	req, _ = http.NewRequest(http.MethodPost, "/v1/apps/status", bytes.NewReader([]byte(`{"appParams":[{"nodes":["n1"]}]}`)))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -70,6 +70,10 @@ func (api *API) ListApplication(c *common.Context) (interface{}, error) {
 
 // CreateApplication create one application
 func (api *API) CreateApplication(c *common.Context) (interface{}, error) {
+	mode, err := registryValidation(c)
+	if err != nil {
+		return nil, err
+	}
 	appView, err := api.ParseApplication(c)
 	if err != nil {
 		return nil, err
@@ -106,6 +110,17 @@ func (api *API) CreateApplication(c *common.Context) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
+	if appView.Warnings, err = api.checkAppImages(ns, appView, mode); err != nil {
+		return nil, err
+	}
+
+	if isDryRun(c) {
+		impact, err := api.Preview.PreviewApp(ns, nil, app)
+		if err != nil {
+			return nil, err
+		}
+		return &models.DryRunResult{DryRun: true, Resource: appView, Impact: impact}, nil
+	}
 
 	if f, exist := api.Hooks[HookCreateApplicationOta]; exist {
 		if hk, ok := f.(CreateApplicationOta); ok {
@@ -122,11 +137,15 @@ func (api *API) CreateApplication(c *common.Context) (interface{}, error) {
 		return nil, err
 	}
 
-	return api.ToApplicationView(app)
+	return api.toApplicationViewWithWarnings(app, appView.Warnings)
 }
 
 // UpdateApplication update the application
 func (api *API) UpdateApplication(c *common.Context) (interface{}, error) {
+	mode, err := registryValidation(c)
+	if err != nil {
+		return nil, err
+	}
 	appView, err := api.ParseApplication(c)
 	if err != nil {
 		return nil, err
@@ -172,6 +191,17 @@ func (api *API) UpdateApplication(c *common.Context) (interface{}, error) {
 
 	// ota can not modify
 	app.Ota = oldApp.Ota
+	if appView.Warnings, err = api.checkAppImages(ns, appView, mode); err != nil {
+		return nil, err
+	}
+
+	if isDryRun(c) {
+		impact, err := api.Preview.PreviewApp(ns, oldApp, app)
+		if err != nil {
+			return nil, err
+		}
+		return &models.DryRunResult{DryRun: true, Resource: appView, Impact: impact}, nil
+	}
 
 	if f, exist := api.Hooks[HookUpdateApplicationOta]; exist {
 		if hk, ok := f.(UpdateApplicationOta); ok {
@@ -187,7 +217,7 @@ func (api *API) UpdateApplication(c *common.Context) (interface{}, error) {
 		return nil, errors.Trace(err)
 	}
 
-	return api.ToApplicationView(app)
+	return api.toApplicationViewWithWarnings(app, appView.Warnings)
 }
 
 // DeleteApplication delete the application
@@ -676,35 +706,88 @@ func (api *API) translateSecretsToSecretLikedResources(appView *models.Applicati
 	return nil
 }
 
+// checkAppImages resolves the images of the container app against the registries of app if the validation is set
+func (api *API) checkAppImages(namespace string, appView *models.ApplicationView, mode string) ([]string, error) {
+	if mode == "" || appView.Type != specV1.AppTypeContainer || appView.Mode == context.RunModeNative {
+		return nil, nil
+	}
+	var registries []models.Registry
+	for _, r := range appView.Registries {
+		secret, err := api.Secret.Get(namespace, r.Name, "")
+		if err != nil {
+			return nil, err
+		}
+		if registry := models.FromSecretToRegistry(secret, true); registry != nil {
+			registries = append(registries, *registry)
+		}
+	}
+	var failures []string
+	services := append(append([]models.ServiceView{}, appView.InitServices...), appView.Services...)
+	for _, svc := range services {
+		if svc.Image == "" {
+			continue
+		}
+		if err := api.RegistryCheck.CheckImage(svc.Image, registries); err != nil {
+			failures = append(failures, fmt.Sprintf("service (%s): %s", svc.Name, err.Error()))
+		}
+	}
+	return registryWarnings(mode, failures)
+}
+
+func (api *API) toApplicationViewWithWarnings(app *specV1.Application, warnings []string) (*models.ApplicationView, error) {
+	view, err := api.ToApplicationView(app)
+	if err != nil {
+		return nil, err
+	}
+	view.Warnings = warnings
+	return view, nil
+}
+
 func (api *API) validApplication(namespace string, app *models.ApplicationView) error {
+	return api.validApplicationWith(namespace, app, nil)
+}
+
+// validApplicationWith valid the application, the configs and secrets provided are regarded as existing,
+// the keys of provided are the kind and name of resources joined by '/'
+func (api *API) validApplicationWith(namespace string, app *models.ApplicationView, provided map[string]bool) error {
+	getConfig := func(name string) error {
+		if provided[string(common.Config)+"/"+name] {
+			return nil
+		}
+		_, err := api.Config.Get(nil, namespace, name, "")
+		return err
+	}
+	getSecret := func(name string) error {
+		if provided[string(common.Secret)+"/"+name] {
+			return nil
+		}
+		_, err := api.Secret.Get(namespace, name, "")
+		return err
+	}
 	for _, v := range app.Volumes {
 		if v.Config != nil {
 			// native program config will be validated by service.ProgramConfig
 			if isProgramConfig(v.Name) {
 				continue
 			}
-			_, err := api.Config.Get(nil, namespace, v.Config.Name, "")
-			if err != nil {
+			if err := getConfig(v.Config.Name); err != nil {
 				return err
 			}
 		}
 		if v.Secret != nil {
-			_, err := api.Secret.Get(namespace, v.Secret.Name, "")
-			if err != nil {
+			if err := getSecret(v.Secret.Name); err != nil {
 				return err
 			}
 		}
 		if v.Certificate != nil {
-			_, err := api.Secret.Get(namespace, v.Certificate.Name, "")
-			if err != nil {
+			if err := getSecret(v.Certificate.Name); err != nil {
 				return err
 			}
 		}
 	}
 
 	for _, r := range app.Registries {
-		_, err := api.Secret.Get(namespace, r.Name, "")
-		if err != nil {
+		if err := getSecret(r.Name); err != nil {
 			return err
 		}
 	}
@@ -713,8 +796,7 @@ func (api *API) validApplication(namespace string, app *models.ApplicationView)
 	updPorts := make(map[int32]bool)
 	for _, service := range app.Services {
 		if service.ProgramConfig != "" {
-			_, err := api.Config.Get(nil, namespace, service.ProgramConfig, "")
-			if err != nil {
+			if err := getConfig(service.ProgramConfig); err != nil {
 				return err
 			}
 		}
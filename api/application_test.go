@@ -3477,3 +3477,47 @@ func TestIsValidPort(t *testing.T) {
 	_, err = isValidPort(svc, tcpPorts, udpPorts)
 	assert.NotNil(t, err)
 }
+
+func TestCheckAppImages(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sSecret := ms.NewMockSecretService(mockCtl)
+	sCheck := ms.NewMockRegistryCheckService(mockCtl)
+	api := &API{AppCombinedService: &service.AppCombinedService{Secret: sSecret}, RegistryCheck: sCheck}
+
+	ns := "default"
+	reg := (&models.Registry{Name: "reg", Address: "registry.example.com", Username: "u", Password: "p"}).ToSecret()
+	appView := &models.ApplicationView{
+		Name:         "app",
+		Type:         specV1.AppTypeContainer,
+		Mode:         context.RunModeKube,
+		InitServices: []models.ServiceView{{Service: specV1.Service{Name: "init", Image: "registry.example.com/init:1"}}},
+		Services: []models.ServiceView{
+			{Service: specV1.Service{Name: "svc", Image: "registry.example.com/svc:1"}},
+			{Service: specV1.Service{Name: "empty"}},
+		},
+		Registries: []models.RegistryView{{Name: "reg"}},
+	}
+
+	// not validated
+	warnings, err := api.checkAppImages(ns, appView, "")
+	assert.NoError(t, err)
+	assert.Nil(t, warnings)
+
+	sSecret.EXPECT().Get(ns, "reg", "").Return(reg, nil).Times(2)
+	registries := []models.Registry{*models.FromSecretToRegistry(reg, false)}
+	sCheck.EXPECT().CheckImage("registry.example.com/init:1", registries).Return(nil).Times(2)
+	sCheck.EXPECT().CheckImage("registry.example.com/svc:1", registries).Return(errors.New("the image (registry.example.com/svc:1) is not found")).Times(2)
+	warnings, err = api.checkAppImages(ns, appView, models.RegistryValidationWarn)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"service (svc): the image (registry.example.com/svc:1) is not found"}, warnings)
+
+	_, err = api.checkAppImages(ns, appView, models.RegistryValidationStrict)
+	assert.Error(t, err)
+
+	// native app has no image
+	appView.Mode = context.RunModeNative
+	warnings, err = api.checkAppImages(ns, appView, models.RegistryValidationStrict)
+	assert.NoError(t, err)
+	assert.Nil(t, warnings)
+}
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"path"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+const (
+	BatchNumber            = 1
+	batchSecurityKeyLength = 32
+)
+
+// GetBatch get a batch
+func (api *API) GetBatch(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	return api.Batch.Get(ns, n)
+}
+
+// ListBatch list the batches
+func (api *API) ListBatch(c *common.Context) (interface{}, error) {
+	ns := c.GetNamespace()
+	params := &models.ListOptions{}
+	if err := c.Bind(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	return api.Batch.List(ns, params)
+}
+
+// CreateBatch create a batch which the devices are activated into nodes with
+func (api *API) CreateBatch(c *common.Context) (interface{}, error) {
+	batch, err := api.parseAndCheckBatch(c)
+	if err != nil {
+		return nil, err
+	}
+	ns := c.GetNamespace()
+	batch.Namespace = ns
+
+	if _, err = api.Batch.Get(ns, batch.Name); err == nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "this name is already in use"))
+	} else if e, ok := err.(errors.Coder); !ok || e.Code() != common.ErrResourceNotFound {
+		return nil, err
+	}
+
+	if err = api.Quota.CheckQuota(ns, api.Batch.Count); err != nil {
+		return nil, err
+	}
+	if err = api.Quota.AcquireQuota(ns, plugin.QuotaBatch, BatchNumber); err != nil {
+		return nil, err
+	}
+	res, err := api.Batch.Create(batch)
+	if err != nil {
+		if e := api.ReleaseQuota(ns, plugin.QuotaBatch, BatchNumber); e != nil {
+			log.L().Error("ReleaseQuota error", log.Error(e))
+		}
+		return nil, err
+	}
+	return res, nil
+}
+
+// UpdateBatch update the batch, the security and whitelist of batch can't be changed
+func (api *API) UpdateBatch(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	oldBatch, err := api.Batch.Get(ns, n)
+	if err != nil {
+		return nil, err
+	}
+	batch, err := api.parseAndCheckBatch(c)
+	if err != nil {
+		return nil, err
+	}
+	batch.Name = oldBatch.Name
+	batch.Namespace = oldBatch.Namespace
+	batch.SecurityType = oldBatch.SecurityType
+	batch.SecurityKey = oldBatch.SecurityKey
+	batch.EnableWhitelist = oldBatch.EnableWhitelist
+	batch.Cluster = oldBatch.Cluster
+	return api.Batch.Update(batch)
+}
+
+// DeleteBatch delete the batch and its records, the activated nodes are kept
+func (api *API) DeleteBatch(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	if _, err := api.Batch.Get(ns, n); err != nil {
+		if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err := api.Batch.Delete(ns, n); err != nil {
+		return nil, err
+	}
+	if e := api.ReleaseQuota(ns, plugin.QuotaBatch, BatchNumber); e != nil {
+		log.L().Error("ReleaseQuota error", log.Error(e))
+	}
+	return nil, nil
+}
+
+// ListBatchRecords list the fingerprint records of batch
+func (api *API) ListBatchRecords(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	params := &models.Filter{}
+	if err := c.Bind(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if _, err := api.Batch.Get(ns, n); err != nil {
+		return nil, err
+	}
+	return api.Batch.ListRecord(ns, n, params)
+}
+
+// GetBatchRecord get a fingerprint record of batch
+func (api *API) GetBatchRecord(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	return api.Batch.GetRecord(ns, n, c.Param("record"))
+}
+
+// ImportBatchRecords import the fingerprints into the whitelist of batch
+func (api *API) ImportBatchRecords(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	records := new(models.RecordImport)
+	if err := c.LoadBody(records); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if _, err := api.Batch.Get(ns, n); err != nil {
+		return nil, err
+	}
+	res, err := api.Batch.CreateRecords(ns, n, records.Items)
+	if err != nil {
+		return nil, err
+	}
+	return &models.RecordList{
+		Total: len(res),
+		Items: res,
+	}, nil
+}
+
+// DeleteBatchRecord delete the fingerprint record of batch, the activated node is kept
+func (api *API) DeleteBatchRecord(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	return nil, api.Batch.DeleteRecord(ns, n, c.Param("record"))
+}
+
+func (api *API) parseAndCheckBatch(c *common.Context) (*models.Batch, error) {
+	batch := new(models.Batch)
+	batch.Name = c.GetNameFromParam()
+	if err := c.LoadBody(batch); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if name := c.GetNameFromParam(); name != "" {
+		batch.Name = name
+	}
+	if batch.Name == "" {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "name is required"))
+	}
+
+	switch batch.SecurityType {
+	case "", common.None:
+		batch.SecurityType = common.None
+		batch.SecurityKey = ""
+	case common.Token:
+		if batch.SecurityKey == "" {
+			batch.SecurityKey = common.RandString(batchSecurityKeyLength)
+		}
+	default:
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "only None or Token is supported with securityType"))
+	}
+
+	if batch.EnableWhitelist != common.DisableWhitelist && batch.EnableWhitelist != common.EnableWhitelist {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "enableWhitelist should be 0 or 1"))
+	}
+
+	fp := &batch.Fingerprint
+	if fp.Type == 0 {
+		fp.Type = common.FingerprintSN
+	}
+	if _, ok := common.FingerprintMap[fp.Type]; !ok {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the type of fingerprint is not supported"))
+	}
+	switch fp.Type {
+	case common.FingerprintSN:
+		if fp.SnPath == "" {
+			fp.SnPath = path.Join(common.DefaultSNPath, common.DefaultSNFile)
+		}
+	case common.FingerprintInput:
+		if fp.InputField == "" {
+			fp.InputField = common.DefaultInputField
+		}
+	}
+	return batch, nil
+}
@@ -0,0 +1,158 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+func initBatchAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
+	api := &API{}
+	router := gin.Default()
+	mockCtl := gomock.NewController(t)
+	mockIM := func(c *gin.Context) { common.NewContext(c).SetNamespace("default") }
+	v1 := router.Group("v1")
+	{
+		batches := v1.Group("/batches")
+		batches.GET("/:name", mockIM, common.Wrapper(api.GetBatch))
+		batches.PUT("/:name", mockIM, common.Wrapper(api.UpdateBatch))
+		batches.DELETE("/:name", mockIM, common.Wrapper(api.DeleteBatch))
+		batches.POST("", mockIM, common.Wrapper(api.CreateBatch))
+		batches.GET("", mockIM, common.Wrapper(api.ListBatch))
+		batches.GET("/:name/records", mockIM, common.Wrapper(api.ListBatchRecords))
+		batches.POST("/:name/records", mockIM, common.Wrapper(api.ImportBatchRecords))
+		batches.GET("/:name/records/:record", mockIM, common.Wrapper(api.GetBatchRecord))
+		batches.DELETE("/:name/records/:record", mockIM, common.Wrapper(api.DeleteBatchRecord))
+	}
+	return api, router, mockCtl
+}
+
+func TestCreateBatch(t *testing.T) {
+	api, router, mockCtl := initBatchAPI(t)
+	defer mockCtl.Finish()
+	sBatch := ms.NewMockBatchService(mockCtl)
+	sQuota := ms.NewMockQuotaService(mockCtl)
+	api.Batch = sBatch
+	api.Quota = sQuota
+
+	notFound := common.Error(common.ErrResourceNotFound, common.Field("type", "batch"))
+	sBatch.EXPECT().Get("default", "b1").Return(nil, notFound).Times(1)
+	sQuota.EXPECT().CheckQuota("default", gomock.Any()).Return(nil).Times(1)
+	sQuota.EXPECT().AcquireQuota("default", plugin.QuotaBatch, BatchNumber).Return(nil).Times(1)
+	sBatch.EXPECT().Create(gomock.Any()).DoAndReturn(func(b *models.Batch) (*models.Batch, error) {
+		assert.Equal(t, "default", b.Namespace)
+		assert.Equal(t, common.Token, b.SecurityType)
+		assert.Len(t, b.SecurityKey, batchSecurityKeyLength)
+		assert.Equal(t, common.FingerprintSN, b.Fingerprint.Type)
+		assert.Equal(t, "/var/lib/baetyl/sn/fingerprint.txt", b.Fingerprint.SnPath)
+		return b, nil
+	}).Times(1)
+	body, _ := json.Marshal(&models.Batch{Name: "b1", SecurityType: common.Token, Labels: map[string]string{"a": "b"}})
+	req, _ := http.NewRequest(http.MethodPost, "/v1/batches", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// the name is in use
+	sBatch.EXPECT().Get("default", "b1").Return(&models.Batch{Name: "b1"}, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/batches", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// the quota is exceeded
+	sBatch.EXPECT().Get("default", "b1").Return(nil, notFound).Times(1)
+	sQuota.EXPECT().CheckQuota("default", gomock.Any()).Return(common.Error(common.ErrLicenseQuota, common.Field("name", plugin.QuotaBatch))).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/batches", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// the security type isn't supported
+	body, _ = json.Marshal(&models.Batch{Name: "b1", SecurityType: common.Dongle})
+	req, _ = http.NewRequest(http.MethodPost, "/v1/batches", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestUpdateAndDeleteBatch(t *testing.T) {
+	api, router, mockCtl := initBatchAPI(t)
+	defer mockCtl.Finish()
+	sBatch := ms.NewMockBatchService(mockCtl)
+	sQuota := ms.NewMockQuotaService(mockCtl)
+	api.Batch = sBatch
+	api.Quota = sQuota
+
+	old := &models.Batch{Name: "b1", Namespace: "default", SecurityType: common.Token, SecurityKey: "key", EnableWhitelist: common.EnableWhitelist}
+	sBatch.EXPECT().Get("default", "b1").Return(old, nil).Times(1)
+	sBatch.EXPECT().Update(gomock.Any()).DoAndReturn(func(b *models.Batch) (*models.Batch, error) {
+		assert.Equal(t, "desc", b.Description)
+		assert.Equal(t, "key", b.SecurityKey)
+		assert.Equal(t, common.EnableWhitelist, b.EnableWhitelist)
+		return b, nil
+	}).Times(1)
+	body, _ := json.Marshal(&models.Batch{Description: "desc"})
+	req, _ := http.NewRequest(http.MethodPut, "/v1/batches/b1", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	sBatch.EXPECT().Get("default", "b1").Return(old, nil).Times(1)
+	sBatch.EXPECT().Delete("default", "b1").Return(nil).Times(1)
+	sQuota.EXPECT().ReleaseQuota("default", plugin.QuotaBatch, BatchNumber).Return(nil).Times(1)
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/batches/b1", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestBatchRecords(t *testing.T) {
+	api, router, mockCtl := initBatchAPI(t)
+	defer mockCtl.Finish()
+	sBatch := ms.NewMockBatchService(mockCtl)
+	api.Batch = sBatch
+
+	batch := &models.Batch{Name: "b1", Namespace: "default"}
+	sBatch.EXPECT().Get("default", "b1").Return(batch, nil).Times(2)
+	sBatch.EXPECT().CreateRecords("default", "b1", []models.Record{{FingerprintValue: "sn-1"}, {FingerprintValue: "sn-2", NodeName: "n2"}}).
+		Return([]models.Record{{Name: "r1", FingerprintValue: "sn-1"}, {Name: "r2", FingerprintValue: "sn-2"}}, nil).Times(1)
+	body, _ := json.Marshal(&models.RecordImport{Items: []models.Record{{FingerprintValue: "sn-1"}, {FingerprintValue: "sn-2", NodeName: "n2"}}})
+	req, _ := http.NewRequest(http.MethodPost, "/v1/batches/b1/records", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	res := new(models.RecordList)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, 2, res.Total)
+
+	sBatch.EXPECT().ListRecord("default", "b1", &models.Filter{PageNo: 1, PageSize: 10}).Return(&models.RecordList{Total: 2}, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/batches/b1/records?pageNo=1&pageSize=10", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// the invalid fingerprint
+	body, _ = json.Marshal(&models.RecordImport{Items: []models.Record{{FingerprintValue: "sn 1"}}})
+	req, _ = http.NewRequest(http.MethodPost, "/v1/batches/b1/records", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	sBatch.EXPECT().DeleteRecord("default", "b1", "r1").Return(nil).Times(1)
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/batches/b1/records/r1", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,438 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/jinzhu/copier"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/facade"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+const (
+	bundleRenameSuffixLength = 5
+	bundleRenameRetry        = 10
+)
+
+// ExportAppBundle export the application with the configs and secrets it references, the data of secrets is cleared if redact=true
+func (api *API) ExportAppBundle(c *common.Context) (interface{}, error) {
+	ns, name := c.GetNamespace(), c.GetNameFromParam()
+	app, err := api.Facade.GetApp(ns, name, "")
+	if err != nil {
+		return nil, err
+	}
+	// sys app: core、init、function is not visible
+	if common.ValidIsInvisible(app.Labels) {
+		return nil, common.Error(common.ErrResourceInvisible, common.Field("type", common.APP), common.Field("name", app.Name))
+	}
+
+	bundle := &models.AppBundle{
+		Namespace:   ns,
+		ExportTime:  time.Now().UTC(),
+		Application: app,
+		Redacted:    c.Query("redact") == "true",
+	}
+	visited := map[string]bool{}
+	for _, v := range app.Volumes {
+		if v.Config != nil && !visited[string(common.Config)+"/"+v.Config.Name] {
+			visited[string(common.Config)+"/"+v.Config.Name] = true
+			cfg, err := api.Config.Get(nil, ns, v.Config.Name, "")
+			if err != nil {
+				return nil, err
+			}
+			bundle.Configs = append(bundle.Configs, *cfg)
+		}
+		if v.Secret != nil && !visited[string(common.Secret)+"/"+v.Secret.Name] {
+			visited[string(common.Secret)+"/"+v.Secret.Name] = true
+			secret, err := api.Secret.Get(ns, v.Secret.Name, "")
+			if err != nil {
+				return nil, err
+			}
+			if bundle.Redacted {
+				data := map[string][]byte{}
+				for k := range secret.Data {
+					data[k] = []byte{}
+				}
+				secret.Data = data
+			}
+			bundle.Secrets = append(bundle.Secrets, *secret)
+		}
+	}
+	return bundle, nil
+}
+
+// ImportAppBundle recreate the application and resources of bundle in the current namespace,
+// the existing resources are skipped, renamed or overwritten according to the conflict query
+func (api *API) ImportAppBundle(c *common.Context) (interface{}, error) {
+	ns := c.GetNamespace()
+	conflict := c.Query("conflict")
+	if conflict == "" {
+		conflict = models.BundleConflictSkip
+	}
+	if conflict != models.BundleConflictSkip && conflict != models.BundleConflictRename && conflict != models.BundleConflictOverwrite {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "conflict should be one of skip, rename and overwrite"))
+	}
+	bundle := new(models.AppBundle)
+	if err := c.LoadBody(bundle); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if !common.ValidNonBaetyl(bundle.Application.Name) {
+		return nil, common.Error(common.ErrInvalidName, common.Field("nonBaetyl", "Name"))
+	}
+
+	im := &bundleImporter{api: api, ns: ns, conflict: conflict, bundle: bundle}
+	if err := im.check(); err != nil {
+		return nil, err
+	}
+	if err := im.plan(); err != nil {
+		return nil, err
+	}
+	if err := im.apply(); err != nil {
+		return nil, err
+	}
+	return &models.AppBundleImportResult{Items: im.items}, nil
+}
+
+// bundleImporter resolves the conflicts of all resources before writing anything
+type bundleImporter struct {
+	api      *API
+	ns       string
+	conflict string
+	bundle   *models.AppBundle
+
+	oldApp     *specV1.Application
+	appAction  string
+	configs    map[string]*specV1.Configuration
+	genConfigs map[string]bool
+	secrets    map[string]*specV1.Secret
+	names      map[string]string
+	items      []models.AppBundleImportItem
+}
+
+// check validates all resources of bundle as they are saved one by one, nothing is written if any of them is invalid
+func (im *bundleImporter) check() error {
+	provided := map[string]bool{}
+	for i := range im.bundle.Secrets {
+		secret := &im.bundle.Secrets[i]
+		if err := validateSecret(secret); err != nil {
+			return err
+		}
+		// the redacted secrets are never written
+		if !im.bundle.Redacted {
+			if err := im.api.checkSecretView(models.FromSecretToView(secret, false)); err != nil {
+				return err
+			}
+		}
+		provided[string(common.Secret)+"/"+secret.Name] = true
+	}
+	for i := range im.bundle.Configs {
+		cfg := &im.bundle.Configs[i]
+		if err := validateConfig(cfg); err != nil {
+			return err
+		}
+		view, err := im.api.ToConfigurationView(cfg)
+		if err != nil {
+			return common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("the config %s is invalid: %s", cfg.Name, err.Error())))
+		}
+		if err = checkConfigDataItems(view.Data); err != nil {
+			return err
+		}
+		provided[string(common.Config)+"/"+cfg.Name] = true
+	}
+
+	app := im.bundle.Application
+	if err := common.ValidateResourceName(app.Name); err != nil {
+		return err
+	}
+	view := new(models.ApplicationView)
+	if err := copier.Copy(view, app); err != nil {
+		return errors.Trace(err)
+	}
+	view.Namespace = im.ns
+	return im.api.validApplicationWith(im.ns, view, provided)
+}
+
+func (im *bundleImporter) plan() error {
+	app := im.bundle.Application
+	im.configs = map[string]*specV1.Configuration{}
+	im.genConfigs = map[string]bool{}
+	im.secrets = map[string]*specV1.Secret{}
+	im.names = map[string]string{}
+
+	var err error
+	im.oldApp, err = im.api.App.Get(im.ns, app.Name, "")
+	if err != nil && !isResourceNotFound(err) {
+		return err
+	}
+	appTarget := app.Name
+	im.appAction = models.BundleActionCreated
+	if im.oldApp != nil {
+		if CheckIsSysResources(im.oldApp.Labels) || common.ValidIsInvisible(im.oldApp.Labels) {
+			return common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("the system app %s can't be overwritten", app.Name)))
+		}
+		if appTarget, im.appAction, err = im.resolve(app.Name, AppNameMaxLength, func(n string) (bool, error) {
+			_, err := im.api.App.Get(im.ns, n, "")
+			return exists(err)
+		}); err != nil {
+			return err
+		}
+	}
+	im.addItem(common.APP, app.Name, appTarget, im.appAction)
+
+	for i := range im.bundle.Secrets {
+		secret := &im.bundle.Secrets[i]
+		old, err := im.api.Secret.Get(im.ns, secret.Name, "")
+		if err != nil && !isResourceNotFound(err) {
+			return err
+		}
+		target, action := secret.Name, models.BundleActionCreated
+		if old != nil {
+			if im.bundle.Redacted || CheckIsSysResources(old.Labels) {
+				target, action = secret.Name, models.BundleActionSkipped
+			} else if target, action, err = im.resolve(secret.Name, 0, func(n string) (bool, error) {
+				_, err := im.api.Secret.Get(im.ns, n, "")
+				return exists(err)
+			}); err != nil {
+				return err
+			}
+		} else if im.bundle.Redacted {
+			return common.Error(common.ErrRequestParamInvalid,
+				common.Field("error", fmt.Sprintf("the redacted secret %s doesn't exist in namespace %s", secret.Name, im.ns)))
+		}
+		im.secrets[secret.Name] = secret
+		im.names[string(common.Secret)+"/"+secret.Name] = target
+		im.addItem(common.Secret, secret.Name, target, action)
+	}
+
+	for i := range im.bundle.Configs {
+		cfg := &im.bundle.Configs[i]
+		old, err := im.api.Config.Get(nil, im.ns, cfg.Name, "")
+		if err != nil && !isResourceNotFound(err) {
+			return err
+		}
+		target, action := cfg.Name, models.BundleActionCreated
+		if isGenConfig(cfg.Name) {
+			// the generated configs belong to the app, and are written along with it
+			im.genConfigs[cfg.Name] = true
+			switch {
+			case im.appAction == models.BundleActionSkipped:
+				action = models.BundleActionSkipped
+			case old != nil && im.appAction == models.BundleActionOverwritten:
+				action = models.BundleActionOverwritten
+			case old != nil:
+				target, action = strings.ToLower(fmt.Sprintf("%s-%s", cfg.Name, common.RandString(bundleRenameSuffixLength))), models.BundleActionRenamed
+			}
+		} else if old != nil {
+			if CheckIsSysResources(old.Labels) {
+				target, action = cfg.Name, models.BundleActionSkipped
+			} else if target, action, err = im.resolve(cfg.Name, 0, func(n string) (bool, error) {
+				_, err := im.api.Config.Get(nil, im.ns, n, "")
+				return exists(err)
+			}); err != nil {
+				return err
+			}
+		}
+		im.configs[cfg.Name] = cfg
+		im.names[string(common.Config)+"/"+cfg.Name] = target
+		im.addItem(common.Config, cfg.Name, target, action)
+	}
+
+	for _, v := range app.Volumes {
+		if v.Config != nil {
+			if _, ok := im.configs[v.Config.Name]; !ok {
+				return common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("the config %s is missing in bundle", v.Config.Name)))
+			}
+		}
+		if v.Secret != nil {
+			if _, ok := im.secrets[v.Secret.Name]; !ok {
+				return common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("the secret %s is missing in bundle", v.Secret.Name)))
+			}
+		}
+	}
+	return nil
+}
+
+// resolve returns the target name and the action of the existing resource according to the conflict policy
+func (im *bundleImporter) resolve(name string, maxLength int, exist func(string) (bool, error)) (string, string, error) {
+	switch im.conflict {
+	case models.BundleConflictOverwrite:
+		return name, models.BundleActionOverwritten, nil
+	case models.BundleConflictRename:
+		base := name
+		if maxLength > 0 && len(base)+bundleRenameSuffixLength+1 > maxLength {
+			base = strings.TrimSuffix(base[:maxLength-bundleRenameSuffixLength-1], "-")
+		}
+		for i := 0; i < bundleRenameRetry; i++ {
+			target := strings.ToLower(fmt.Sprintf("%s-%s", base, common.RandString(bundleRenameSuffixLength)))
+			ok, err := exist(target)
+			if err != nil {
+				return "", "", err
+			}
+			if !ok {
+				return target, models.BundleActionRenamed, nil
+			}
+		}
+		return "", "", common.Error(common.ErrResourceConflict, common.Field("name", name))
+	default:
+		return name, models.BundleActionSkipped, nil
+	}
+}
+
+// apply writes the resources of bundle, the resources created are removed if any write fails
+func (im *bundleImporter) apply() (err error) {
+	var created []models.AppBundleImportItem
+	defer func() {
+		if err != nil {
+			im.rollback(created)
+		}
+	}()
+	for _, item := range im.items {
+		if item.Action == models.BundleActionSkipped {
+			continue
+		}
+		switch common.Resource(item.Kind) {
+		case common.Secret:
+			if err = im.applySecret(im.secrets[item.Name], item); err != nil {
+				return err
+			}
+		case common.Config:
+			if im.genConfigs[item.Name] {
+				continue
+			}
+			if err = im.applyConfig(im.configs[item.Name], item); err != nil {
+				return err
+			}
+		default:
+			continue
+		}
+		if item.Action != models.BundleActionOverwritten {
+			created = append(created, item)
+		}
+	}
+	if im.appAction == models.BundleActionSkipped {
+		return nil
+	}
+	return im.applyApp()
+}
+
+func (im *bundleImporter) rollback(created []models.AppBundleImportItem) {
+	for i := len(created) - 1; i >= 0; i-- {
+		var err error
+		switch common.Resource(created[i].Kind) {
+		case common.Secret:
+			err = im.api.Facade.DeleteSecret(im.ns, created[i].Target)
+		case common.Config:
+			err = im.api.Facade.DeleteConfig(im.ns, created[i].Target)
+		}
+		if err != nil {
+			im.api.log.Warn("failed to remove the resource created by bundle import",
+				log.Any("kind", created[i].Kind), log.Any("name", created[i].Target), log.Error(err))
+		}
+	}
+}
+
+func (im *bundleImporter) applySecret(secret *specV1.Secret, item models.AppBundleImportItem) error {
+	s := *secret
+	s.Name, s.Namespace, s.Version = item.Target, im.ns, ""
+	s.CreationTimestamp, s.UpdateTimestamp = time.Time{}, time.Now()
+	if item.Action != models.BundleActionOverwritten {
+		_, err := im.api.Facade.CreateSecret(im.ns, &s)
+		return err
+	}
+	old, err := im.api.Secret.Get(im.ns, s.Name, "")
+	if err != nil {
+		return err
+	}
+	s.Version, s.CreationTimestamp = old.Version, old.CreationTimestamp
+	_, err = im.api.Facade.UpdateSecret(im.ns, &s)
+	return err
+}
+
+func (im *bundleImporter) applyConfig(cfg *specV1.Configuration, item models.AppBundleImportItem) error {
+	config := *cfg
+	config.Name, config.Namespace, config.Version = item.Target, im.ns, ""
+	config.CreationTimestamp, config.UpdateTimestamp = time.Time{}, time.Now()
+	if item.Action != models.BundleActionOverwritten {
+		_, err := im.api.Facade.CreateConfig(im.ns, &config)
+		return err
+	}
+	old, err := im.api.Config.Get(nil, im.ns, config.Name, "")
+	if err != nil {
+		return err
+	}
+	if models.EqualConfig(old, &config) {
+		return nil
+	}
+	config.Version, config.CreationTimestamp = old.Version, old.CreationTimestamp
+	_, err = im.api.Facade.UpdateConfig(im.ns, &config)
+	return err
+}
+
+func (im *bundleImporter) applyApp() error {
+	app := *im.bundle.Application
+	app.Namespace = im.ns
+	app.Name = im.names[string(common.APP)+"/"+app.Name]
+	app.Version = ""
+	app.CreationTimestamp = time.Time{}
+	app.Volumes = make([]specV1.Volume, len(im.bundle.Application.Volumes))
+	for i, v := range im.bundle.Application.Volumes {
+		if v.Config != nil {
+			v.Config = &specV1.ObjectReference{Name: im.names[string(common.Config)+"/"+v.Config.Name]}
+		}
+		if v.Secret != nil {
+			v.Secret = &specV1.ObjectReference{Name: im.names[string(common.Secret)+"/"+v.Secret.Name]}
+		}
+		app.Volumes[i] = v
+	}
+
+	var configs []specV1.Configuration
+	for name := range im.genConfigs {
+		cfg := *im.configs[name]
+		cfg.Name, cfg.Namespace, cfg.Version = im.names[string(common.Config)+"/"+name], im.ns, ""
+		cfg.CreationTimestamp, cfg.UpdateTimestamp = time.Time{}, time.Now()
+		configs = append(configs, cfg)
+	}
+
+	if im.appAction != models.BundleActionOverwritten {
+		_, err := im.api.Facade.CreateApp(im.ns, nil, &app, configs)
+		return errors.Trace(err)
+	}
+	app.Version = im.oldApp.Version
+	app.CreationTimestamp = im.oldApp.CreationTimestamp
+	// ota can not modify
+	app.Ota = im.oldApp.Ota
+	_, err := im.api.Facade.UpdateApp(im.ns, im.oldApp, &app, configs)
+	return errors.Trace(err)
+}
+
+func (im *bundleImporter) addItem(kind common.Resource, name, target, action string) {
+	if kind == common.APP {
+		im.names[string(common.APP)+"/"+name] = target
+	}
+	im.items = append(im.items, models.AppBundleImportItem{Kind: string(kind), Name: name, Target: target, Action: action})
+}
+
+func isGenConfig(name string) bool {
+	return strings.HasPrefix(name, facade.FunctionConfigPrefix) || strings.HasPrefix(name, facade.FunctionProgramConfigPrefix)
+}
+
+func isResourceNotFound(err error) bool {
+	e, ok := err.(errors.Coder)
+	return ok && e.Code() == common.ErrResourceNotFound
+}
+
+func exists(err error) (bool, error) {
+	if err == nil {
+		return true, nil
+	}
+	if isResourceNotFound(err) {
+		return false, nil
+	}
+	return false, err
+}
@@ -0,0 +1,367 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	mf "github.com/baetyl/baetyl-cloud/v2/mock/facade"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+func initBundleAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
+	api := &API{log: log.L().With(log.Any("test", "api"))}
+	router := gin.Default()
+	mockCtl := gomock.NewController(t)
+	mockIM := func(c *gin.Context) { c.Set(common.KeyContextNamespace, "prod") }
+	v1 := router.Group("v1")
+	{
+		apps := v1.Group("/apps")
+		apps.GET("/:name/bundle", mockIM, common.Wrapper(api.ExportAppBundle))
+		apps.POST("/bundle", mockIM, common.Wrapper(api.ImportAppBundle))
+	}
+	return api, router, mockCtl
+}
+
+func genBundleApp(ns string) *specV1.Application {
+	return &specV1.Application{
+		Namespace: ns,
+		Name:      "app",
+		Version:   "10",
+		Selector:  "site=bj",
+		Volumes: []specV1.Volume{
+			{Name: "conf", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "cfg"}}},
+			{Name: "program", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "baetyl-function-program-config-app-svc-abc"}}},
+			{Name: "reg", VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "reg"}}},
+			{Name: "reg2", VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "reg"}}},
+		},
+	}
+}
+
+func TestExportAppBundle(t *testing.T) {
+	api, router, mockCtl := initBundleAPI(t)
+	defer mockCtl.Finish()
+	sConfig := ms.NewMockConfigService(mockCtl)
+	sSecret := ms.NewMockSecretService(mockCtl)
+	fApp := mf.NewMockFacade(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{Config: sConfig, Secret: sSecret}
+	api.Facade = fApp
+
+	ns := "prod"
+	app := genBundleApp(ns)
+	secret := &specV1.Secret{
+		Namespace: ns,
+		Name:      "reg",
+		Labels:    map[string]string{specV1.SecretLabel: specV1.SecretRegistry},
+		Data:      map[string][]byte{"username": []byte("u"), "password": []byte("p")},
+	}
+	fApp.EXPECT().GetApp(ns, "app", "").Return(app, nil).Times(2)
+	sConfig.EXPECT().Get(nil, ns, "cfg", "").Return(&specV1.Configuration{Namespace: ns, Name: "cfg", Data: map[string]string{"a": "b"}}, nil).Times(2)
+	sConfig.EXPECT().Get(nil, ns, "baetyl-function-program-config-app-svc-abc", "").Return(&specV1.Configuration{Namespace: ns, Name: "baetyl-function-program-config-app-svc-abc"}, nil).Times(2)
+	sSecret.EXPECT().Get(ns, "reg", "").DoAndReturn(func(_, _, _ string) (*specV1.Secret, error) {
+		s := *secret
+		return &s, nil
+	}).Times(2)
+
+	req, _ := http.NewRequest(http.MethodGet, "/v1/apps/app/bundle", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.AppBundle{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, ns, res.Namespace)
+	assert.Equal(t, "app", res.Application.Name)
+	assert.Len(t, res.Configs, 2)
+	assert.Len(t, res.Secrets, 1)
+	assert.False(t, res.Redacted)
+	assert.Equal(t, []byte("p"), res.Secrets[0].Data["password"])
+
+	req, _ = http.NewRequest(http.MethodGet, "/v1/apps/app/bundle?redact=true", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res = &models.AppBundle{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.True(t, res.Redacted)
+	assert.Len(t, res.Secrets[0].Data, 2)
+	assert.Empty(t, res.Secrets[0].Data["password"])
+
+	fApp.EXPECT().GetApp(ns, "sys", "").Return(&specV1.Application{Name: "sys", Labels: map[string]string{common.ResourceInvisible: "true"}}, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/apps/sys/bundle", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestImportAppBundle(t *testing.T) {
+	api, router, mockCtl := initBundleAPI(t)
+	defer mockCtl.Finish()
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sConfig := ms.NewMockConfigService(mockCtl)
+	sSecret := ms.NewMockSecretService(mockCtl)
+	fApp := mf.NewMockFacade(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{App: sApp, Config: sConfig, Secret: sSecret}
+	api.Facade = fApp
+
+	ns := "prod"
+	notFound := common.Error(common.ErrResourceNotFound, common.Field("type", "x"), common.Field("name", "x"))
+	bundle := &models.AppBundle{
+		Namespace:   "staging",
+		Application: genBundleApp("staging"),
+		Configs: []specV1.Configuration{
+			{Namespace: "staging", Name: "cfg", Version: "1", Data: map[string]string{"a": "b"}},
+			{Namespace: "staging", Name: "baetyl-function-program-config-app-svc-abc", Version: "2", Data: map[string]string{"_object_x": `{"metadata":{"type":"object","source":"baidubos"}}`}},
+		},
+		Secrets: []specV1.Secret{
+			{Namespace: "staging", Name: "reg", Version: "3", Data: map[string][]byte{"password": []byte("p")}},
+		},
+	}
+	body, _ := json.Marshal(bundle)
+
+	req, _ := http.NewRequest(http.MethodPost, "/v1/apps/bundle?conflict=merge", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// create all resources
+	sApp.EXPECT().Get(ns, "app", "").Return(nil, notFound).Times(1)
+	sSecret.EXPECT().Get(ns, "reg", "").Return(nil, notFound).Times(1)
+	sConfig.EXPECT().Get(nil, ns, "cfg", "").Return(nil, notFound).Times(1)
+	sConfig.EXPECT().Get(nil, ns, "baetyl-function-program-config-app-svc-abc", "").Return(nil, notFound).Times(1)
+	fApp.EXPECT().CreateSecret(ns, gomock.Any()).DoAndReturn(func(_ string, s *specV1.Secret) (*specV1.Secret, error) {
+		assert.Equal(t, ns, s.Namespace)
+		assert.Equal(t, "reg", s.Name)
+		assert.Empty(t, s.Version)
+		return s, nil
+	}).Times(1)
+	fApp.EXPECT().CreateConfig(ns, gomock.Any()).DoAndReturn(func(_ string, c *specV1.Configuration) (*specV1.Configuration, error) {
+		assert.Equal(t, "cfg", c.Name)
+		return c, nil
+	}).Times(1)
+	fApp.EXPECT().CreateApp(ns, nil, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ string, _, app *specV1.Application, configs []specV1.Configuration) (*specV1.Application, error) {
+			assert.Equal(t, ns, app.Namespace)
+			assert.Empty(t, app.Version)
+			assert.Len(t, configs, 1)
+			assert.Equal(t, "baetyl-function-program-config-app-svc-abc", configs[0].Name)
+			return app, nil
+		}).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/apps/bundle", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.AppBundleImportResult{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Len(t, res.Items, 4)
+	for _, item := range res.Items {
+		assert.Equal(t, models.BundleActionCreated, item.Action)
+		assert.Equal(t, item.Name, item.Target)
+	}
+
+	// skip the existing resources
+	sApp.EXPECT().Get(ns, "app", "").Return(&specV1.Application{Namespace: ns, Name: "app"}, nil).Times(1)
+	sSecret.EXPECT().Get(ns, "reg", "").Return(&specV1.Secret{Namespace: ns, Name: "reg"}, nil).Times(1)
+	sConfig.EXPECT().Get(nil, ns, "cfg", "").Return(nil, notFound).Times(1)
+	sConfig.EXPECT().Get(nil, ns, "baetyl-function-program-config-app-svc-abc", "").Return(&specV1.Configuration{}, nil).Times(1)
+	fApp.EXPECT().CreateConfig(ns, gomock.Any()).Return(&specV1.Configuration{}, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/apps/bundle?conflict=skip", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res = &models.AppBundleImportResult{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, []string{models.BundleActionSkipped, models.BundleActionSkipped, models.BundleActionCreated, models.BundleActionSkipped},
+		[]string{res.Items[0].Action, res.Items[1].Action, res.Items[2].Action, res.Items[3].Action})
+
+	// rename the existing resources and the references of app
+	sApp.EXPECT().Get(ns, "app", "").Return(&specV1.Application{Namespace: ns, Name: "app"}, nil).Times(1)
+	sApp.EXPECT().Get(ns, gomock.Any(), "").Return(nil, notFound).Times(1)
+	sSecret.EXPECT().Get(ns, "reg", "").Return(&specV1.Secret{Namespace: ns, Name: "reg"}, nil).Times(1)
+	sSecret.EXPECT().Get(ns, gomock.Any(), "").Return(nil, notFound).Times(1)
+	sConfig.EXPECT().Get(nil, ns, "cfg", "").Return(nil, notFound).Times(1)
+	sConfig.EXPECT().Get(nil, ns, "baetyl-function-program-config-app-svc-abc", "").Return(&specV1.Configuration{}, nil).Times(1)
+	var secretName string
+	fApp.EXPECT().CreateSecret(ns, gomock.Any()).DoAndReturn(func(_ string, s *specV1.Secret) (*specV1.Secret, error) {
+		assert.True(t, strings.HasPrefix(s.Name, "reg-"))
+		secretName = s.Name
+		return s, nil
+	}).Times(1)
+	fApp.EXPECT().CreateConfig(ns, gomock.Any()).Return(&specV1.Configuration{}, nil).Times(1)
+	fApp.EXPECT().CreateApp(ns, nil, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ string, _, app *specV1.Application, configs []specV1.Configuration) (*specV1.Application, error) {
+			assert.True(t, strings.HasPrefix(app.Name, "app-"))
+			assert.Equal(t, "cfg", app.Volumes[0].Config.Name)
+			assert.Equal(t, configs[0].Name, app.Volumes[1].Config.Name)
+			assert.NotEqual(t, "baetyl-function-program-config-app-svc-abc", configs[0].Name)
+			assert.Equal(t, secretName, app.Volumes[2].Secret.Name)
+			assert.Equal(t, secretName, app.Volumes[3].Secret.Name)
+			return app, nil
+		}).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/apps/bundle?conflict=rename", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// overwrite the existing resources
+	oldApp := &specV1.Application{Namespace: ns, Name: "app", Version: "20"}
+	sApp.EXPECT().Get(ns, "app", "").Return(oldApp, nil).Times(1)
+	sSecret.EXPECT().Get(ns, "reg", "").Return(&specV1.Secret{Namespace: ns, Name: "reg", Version: "30"}, nil).Times(2)
+	sConfig.EXPECT().Get(nil, ns, "cfg", "").Return(&specV1.Configuration{Namespace: ns, Name: "cfg", Version: "40"}, nil).Times(2)
+	sConfig.EXPECT().Get(nil, ns, "baetyl-function-program-config-app-svc-abc", "").Return(&specV1.Configuration{}, nil).Times(1)
+	fApp.EXPECT().UpdateSecret(ns, gomock.Any()).DoAndReturn(func(_ string, s *specV1.Secret) (*specV1.Secret, error) {
+		assert.Equal(t, "30", s.Version)
+		return s, nil
+	}).Times(1)
+	fApp.EXPECT().UpdateConfig(ns, gomock.Any()).DoAndReturn(func(_ string, c *specV1.Configuration) (*specV1.Configuration, error) {
+		assert.Equal(t, "40", c.Version)
+		return c, nil
+	}).Times(1)
+	fApp.EXPECT().UpdateApp(ns, oldApp, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ string, _, app *specV1.Application, configs []specV1.Configuration) (*specV1.Application, error) {
+			assert.Equal(t, "20", app.Version)
+			assert.Equal(t, "baetyl-function-program-config-app-svc-abc", configs[0].Name)
+			return app, nil
+		}).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/apps/bundle?conflict=overwrite", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// the redacted secret must exist in the target namespace
+	bundle.Redacted = true
+	body, _ = json.Marshal(bundle)
+	sApp.EXPECT().Get(ns, "app", "").Return(nil, notFound).Times(1)
+	sSecret.EXPECT().Get(ns, "reg", "").Return(nil, notFound).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/apps/bundle", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestImportAppBundle_Invalid(t *testing.T) {
+	api, router, mockCtl := initBundleAPI(t)
+	defer mockCtl.Finish()
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sConfig := ms.NewMockConfigService(mockCtl)
+	sSecret := ms.NewMockSecretService(mockCtl)
+	sProvider := ms.NewMockSecretProviderService(mockCtl)
+	fApp := mf.NewMockFacade(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{App: sApp, Config: sConfig, Secret: sSecret}
+	api.Providers = sProvider
+	api.Facade = fApp
+
+	genBundle := func() *models.AppBundle {
+		return &models.AppBundle{
+			Namespace:   "staging",
+			Application: genBundleApp("staging"),
+			Configs: []specV1.Configuration{
+				{Namespace: "staging", Name: "cfg", Data: map[string]string{"a": "b"}},
+				{Namespace: "staging", Name: "baetyl-function-program-config-app-svc-abc", Data: map[string]string{"_object_x": `{"metadata":{"type":"object","source":"baidubos"}}`}},
+			},
+			Secrets: []specV1.Secret{
+				{Namespace: "staging", Name: "reg", Data: map[string][]byte{"password": []byte("p")}},
+			},
+		}
+	}
+	// nothing is read or written if any resource is invalid
+	invalids := map[string]func(b *models.AppBundle){
+		"config name":        func(b *models.AppBundle) { b.Configs[0].Name = "Cfg_1" },
+		"config key":         func(b *models.AppBundle) { b.Configs[0].Data = map[string]string{"a/b": "c"} },
+		"config placeholder": func(b *models.AppBundle) { b.Configs[0].Data = map[string]string{"a": "${node.unknown}"} },
+		"config object":      func(b *models.AppBundle) { b.Configs[1].Data = map[string]string{"_object_x": "y"} },
+		"config object data": func(b *models.AppBundle) {
+			b.Configs[1].Data = map[string]string{"_object_x": `{"metadata":{"type":"object"}}`}
+		},
+		"secret name":      func(b *models.AppBundle) { b.Secrets[0].Name = "" },
+		"secret reference": func(b *models.AppBundle) { b.Secrets[0].Data["password"] = []byte{} },
+		"app port":         func(b *models.AppBundle) { b.Application.Replica = 2 },
+		"app cron time":    func(b *models.AppBundle) { b.Application.CronStatus = specV1.CronWait },
+		"app autoscale": func(b *models.AppBundle) {
+			b.Application.AutoScaleCfg = &specV1.AutoScaleCfg{Metrics: []specV1.MetricSpec{{Type: "Resource"}}}
+		},
+		"app missing config": func(b *models.AppBundle) { b.Configs = b.Configs[1:] },
+		"app missing secret": func(b *models.AppBundle) { b.Secrets = nil },
+	}
+	notFound := common.Error(common.ErrResourceNotFound, common.Field("type", "x"), common.Field("name", "x"))
+	sProvider.EXPECT().CheckProvider("vault").Return(nil).AnyTimes()
+	sConfig.EXPECT().Get(nil, "prod", "cfg", "").Return(nil, notFound).AnyTimes()
+	sSecret.EXPECT().Get("prod", "reg", "").Return(nil, notFound).AnyTimes()
+	for name, invalid := range invalids {
+		bundle := genBundle()
+		if name == "secret reference" {
+			bundle.Secrets[0].Annotations = map[string]string{common.AnnotationSecretProvider: "vault"}
+		}
+		if name == "app port" {
+			bundle.Application.Services = []specV1.Service{{Name: "svc", Image: "image", Ports: []specV1.ContainerPort{{HostPort: 80, ContainerPort: 80}}}}
+		}
+		invalid(bundle)
+		body, _ := json.Marshal(bundle)
+		req, _ := http.NewRequest(http.MethodPost, "/v1/apps/bundle", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if strings.HasPrefix(name, "app missing") {
+			assert.Equal(t, http.StatusNotFound, w.Code, name)
+		} else {
+			assert.Equal(t, http.StatusBadRequest, w.Code, name)
+		}
+	}
+}
+
+func TestImportAppBundle_Rollback(t *testing.T) {
+	api, router, mockCtl := initBundleAPI(t)
+	defer mockCtl.Finish()
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sConfig := ms.NewMockConfigService(mockCtl)
+	sSecret := ms.NewMockSecretService(mockCtl)
+	fApp := mf.NewMockFacade(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{App: sApp, Config: sConfig, Secret: sSecret}
+	api.Facade = fApp
+
+	ns := "prod"
+	notFound := common.Error(common.ErrResourceNotFound, common.Field("type", "x"), common.Field("name", "x"))
+	app := genBundleApp("staging")
+	app.Volumes = append(app.Volumes[:1], app.Volumes[2:]...)
+	app.Volumes = append(app.Volumes, specV1.Volume{Name: "old", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "old"}}})
+	bundle := &models.AppBundle{
+		Namespace:   "staging",
+		Application: app,
+		Configs: []specV1.Configuration{
+			{Namespace: "staging", Name: "cfg", Data: map[string]string{"a": "b"}},
+			{Namespace: "staging", Name: "old", Data: map[string]string{"a": "b"}},
+		},
+		Secrets: []specV1.Secret{
+			{Namespace: "staging", Name: "reg", Data: map[string][]byte{"password": []byte("p")}},
+		},
+	}
+	body, _ := json.Marshal(bundle)
+
+	// the created resources are removed and the overwritten ones are kept if the app fails to be created
+	sApp.EXPECT().Get(ns, "app", "").Return(nil, notFound).Times(1)
+	sSecret.EXPECT().Get(ns, "reg", "").Return(nil, notFound).Times(1)
+	sConfig.EXPECT().Get(nil, ns, "cfg", "").Return(nil, notFound).Times(1)
+	sConfig.EXPECT().Get(nil, ns, "old", "").Return(&specV1.Configuration{Namespace: ns, Name: "old", Version: "1"}, nil).Times(2)
+	fApp.EXPECT().CreateSecret(ns, gomock.Any()).Return(&specV1.Secret{}, nil).Times(1)
+	fApp.EXPECT().CreateConfig(ns, gomock.Any()).Return(&specV1.Configuration{}, nil).Times(1)
+	fApp.EXPECT().UpdateConfig(ns, gomock.Any()).Return(&specV1.Configuration{}, nil).Times(1)
+	fApp.EXPECT().CreateApp(ns, nil, gomock.Any(), gomock.Any()).Return(nil, common.Error(common.ErrK8S)).Times(1)
+	gomock.InOrder(
+		fApp.EXPECT().DeleteConfig(ns, "cfg").Return(nil).Times(1),
+		fApp.EXPECT().DeleteSecret(ns, "reg").Return(nil).Times(1),
+	)
+	req, _ := http.NewRequest(http.MethodPost, "/v1/apps/bundle?conflict=overwrite", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.NotEqual(t, http.StatusOK, w.Code)
+}
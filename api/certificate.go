@@ -11,6 +11,8 @@ import (
 	"github.com/baetyl/baetyl-cloud/v2/models"
 )
 
+const CertificateExpiry = "certificateExpiry"
+
 // GetCertificate get a Certificate
 func (api *API) GetCertificate(c *common.Context) (interface{}, error) {
 	ns, n := c.GetNamespace(), c.GetNameFromParam()
@@ -109,6 +111,20 @@ func (api *API) DeleteCertificate(c *common.Context) (interface{}, error) {
 	return api.DeleteSecretResource(ns, n, "certificate")
 }
 
+// ListExpiringCertificates list the certificates and node certificates expiring within the days, with the apps referencing them
+func (api *API) ListExpiringCertificates(c *common.Context) (interface{}, error) {
+	params := &models.ExpiringCertificateOptions{}
+	if err := c.Bind(params); err != nil {
+		return nil, err
+	}
+	return api.CertExpiry.ListExpiring(c.GetNamespace(), params.Days)
+}
+
+// NotifyCertificateExpiry sends the expiry events of certificates to the callbacks
+func (api *API) NotifyCertificateExpiry() error {
+	return api.CertExpiry.NotifyExpiring()
+}
+
 // GetAppByCertificate list app
 func (api *API) GetAppByCertificate(c *common.Context) (interface{}, error) {
 	ns, n := c.GetNamespace(), c.GetNameFromParam()
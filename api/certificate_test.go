@@ -35,6 +35,7 @@ func initCertificateAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
 		certificate.POST("", mockIM, common.Wrapper(api.CreateCertificate))
 		certificate.GET("", mockIM, common.Wrapper(api.ListCertificate))
 		certificate.GET("/:name/apps", mockIM, common.Wrapper(api.GetAppByCertificate))
+		certificate.GET("/expiring", mockIM, common.Wrapper(api.ListExpiringCertificates))
 	}
 	return api, router, mockCtl
 }
@@ -733,3 +734,33 @@ func TestGetAppByCertificate(t *testing.T) {
 	router.ServeHTTP(w4, req4)
 	assert.Equal(t, http.StatusOK, w4.Code)
 }
+
+func TestListExpiringCertificates(t *testing.T) {
+	api, router, mockCtl := initCertificateAPI(t)
+	defer mockCtl.Finish()
+	sExpiry := ms.NewMockCertificateExpiryService(mockCtl)
+	api.CertExpiry = sExpiry
+
+	list := &models.ExpiringCertificateList{Days: 30, Total: 1, Items: []models.ExpiringCertificate{
+		{Kind: models.CertificateKindNode, Name: "crt-n1", Namespace: "default", Node: "n1", Apps: []string{"baetyl-core-n1"}},
+	}}
+	sExpiry.EXPECT().ListExpiring("default", 30).Return(list, nil).Times(1)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/certificates/expiring", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.ExpiringCertificateList{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, list, res)
+
+	sExpiry.EXPECT().ListExpiring("default", 7).Return(&models.ExpiringCertificateList{Days: 7, Items: []models.ExpiringCertificate{}}, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/certificates/expiring?days=7", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req, _ = http.NewRequest(http.MethodGet, "/v1/certificates/expiring?days=-1", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
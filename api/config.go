@@ -81,6 +81,18 @@ func (api *API) CreateConfig(c *common.Context) (interface{}, error) {
 			common.Field("error", "this name is already in use"))
 	}
 
+	if isDryRun(c) {
+		impact, err := api.Preview.PreviewConfig(ns, nil, config)
+		if err != nil {
+			return nil, err
+		}
+		view, err := api.ToConfigurationView(config)
+		if err != nil {
+			return nil, err
+		}
+		return &models.DryRunResult{DryRun: true, Resource: view, Impact: impact}, nil
+	}
+
 	config, err = api.Facade.CreateConfig(ns, config)
 	if err != nil {
 		return nil, err
@@ -107,6 +119,18 @@ func (api *API) UpdateConfig(c *common.Context) (interface{}, error) {
 		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "labels can't be modified of sys apps"))
 	}
 
+	if isDryRun(c) {
+		impact, err := api.Preview.PreviewConfig(ns, res, config)
+		if err != nil {
+			return nil, err
+		}
+		view, err := api.ToConfigurationView(config)
+		if err != nil {
+			return nil, err
+		}
+		return &models.DryRunResult{DryRun: true, Resource: view, Impact: impact}, nil
+	}
+
 	if models.EqualConfig(res, config) {
 		return api.ToConfigurationView(res)
 	}
@@ -178,44 +202,56 @@ func (api *API) parseAndCheckConfigView(c *common.Context) (*specV1.Configuratio
 		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "name is required"))
 	}
 
-	for _, item := range configView.Data {
+	if err = checkConfigDataItems(configView.Data); err != nil {
+		return nil, err
+	}
+
+	config, err := api.ToConfiguration(c.GetUser().ID, configView)
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// checkConfigDataItems checks the data of config by the type of items
+func checkConfigDataItems(items []models.ConfigDataItem) error {
+	for _, item := range items {
 		if _type, ok := item.Value["type"]; ok {
 			switch _type {
 			case ConfigTypeObject:
 				ok = checkElementsExist(item.Value, "source")
 				if !ok {
-					return nil, common.Error(common.ErrRequestParamInvalid,
+					return common.Error(common.ErrRequestParamInvalid,
 						common.Field("error", "failed to validate object data of config"))
 				}
 				if item.Value["source"] == ConfigObjectTypeHttp {
 					ok = checkElementsExist(item.Value, "url")
 				}
 				if !ok {
-					return nil, common.Error(common.ErrRequestParamInvalid,
+					return common.Error(common.ErrRequestParamInvalid,
 						common.Field("error", "failed to validate object data of config"))
 				}
 			case ConfigTypeFunction:
 				ok = checkElementsExist(item.Value, "function", "version", "runtime",
 					"handler", "bucket", "object")
 				if !ok {
-					return nil, common.Error(common.ErrRequestParamInvalid,
+					return common.Error(common.ErrRequestParamInvalid,
 						common.Field("error", "failed to validate function data of config"))
 				}
 			case ConfigTypeKV:
 				if strings.HasPrefix(item.Key, common.ConfigObjectPrefix) {
-					return nil, common.Error(common.ErrRequestParamInvalid,
+					return common.Error(common.ErrRequestParamInvalid,
 						common.Field("error", "key of kv data can't start with "+common.ConfigObjectPrefix))
 				}
+				if err := common.ValidateNodePlaceholders(item.Value["value"]); err != nil {
+					return common.Error(common.ErrRequestParamInvalid,
+						common.Field("error", "failed to validate kv data of config: "+err.Error()))
+				}
 			}
 		}
 	}
-
-	config, err := api.ToConfiguration(c.GetUser().ID, configView)
-	if err != nil {
-		return nil, err
-	}
-
-	return config, nil
+	return nil
 }
 
 func (api *API) ToConfigurationView(config *specV1.Configuration) (*models.ConfigurationView, error) {
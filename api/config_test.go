@@ -178,6 +178,27 @@ func TestCreateConfig(t *testing.T) {
 	router.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
+	// unknown node placeholder
+	mConf = &models.ConfigurationView{
+		Name:      "abc",
+		Namespace: "default",
+		Data: []models.ConfigDataItem{
+			{
+				Key: "conf.yml",
+				Value: map[string]string{
+					"type":  ConfigTypeKV,
+					"value": "site: ${node.site}",
+				},
+			},
+		},
+	}
+
+	w = httptest.NewRecorder()
+	body, _ = json.Marshal(mConf)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/configs", bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
 	mConf = &models.ConfigurationView{
 		Name:      "abc",
 		Namespace: "default",
@@ -602,6 +623,26 @@ func TestUpdateConfig(t *testing.T) {
 	req, _ = http.NewRequest(http.MethodPut, "/v1/configs/"+name, bytes.NewReader(body))
 	router.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusOK, w.Code)
+
+	// dry run: nothing is updated
+	sPreview := ms.NewMockPreviewService(mockCtl)
+	api.Preview = sPreview
+	impact := &models.ChangeImpact{
+		Apps: []string{"app"},
+		Changed: []models.NodeDesireDiff{
+			{Node: "n1", Changes: []common.FieldDiff{{Path: "apps.app", From: "1", To: models.PendingVersion}}},
+		},
+	}
+	sConfig.EXPECT().Get(nil, ns, name, gomock.Any()).Return(res3, nil).Times(1)
+	sPreview.EXPECT().PreviewConfig(ns, res3, gomock.Any()).Return(impact, nil).Times(1)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPut, "/v1/configs/"+name+"?dryRun=true", bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	dryRun := &models.DryRunResult{Impact: &models.ChangeImpact{}}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), dryRun))
+	assert.True(t, dryRun.DryRun)
+	assert.Equal(t, "n1", dryRun.Impact.Changed[0].Node)
 }
 
 func TestUpdateSysConfig(t *testing.T) {
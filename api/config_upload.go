@@ -0,0 +1,165 @@
+package api
+
+import (
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// UploadConfigObject uploads the file of multipart form into the internal bucket and saves it as the object of config,
+// the config is created if not exists
+func (api *API) UploadConfigObject(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	opts := new(models.ConfigObjectUploadOptions)
+	if err := c.BindQuery(opts); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if err := api.checkConfigForUpload(ns, n); err != nil {
+		return nil, err
+	}
+	// the file is streamed into the service without buffering the whole form
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the file is required"))
+		}
+		if err != nil {
+			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+		}
+		if part.FormName() != "file" {
+			part.Close()
+			continue
+		}
+		obj, err := api.ConfigUpload.Upload(c.GetUser().ID, ns, n, part.FileName(), part)
+		part.Close()
+		if err != nil {
+			return nil, err
+		}
+		key := opts.Key
+		if key == "" {
+			key = part.FileName()
+		}
+		return api.saveConfigObject(c.GetUser().ID, ns, n, key, opts.Unpack, obj)
+	}
+}
+
+// InitConfigUpload starts the resumable upload of the config object
+func (api *API) InitConfigUpload(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	upload := new(models.ConfigUpload)
+	if err := c.LoadBody(upload); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if err := api.checkConfigForUpload(ns, n); err != nil {
+		return nil, err
+	}
+	upload.Config = n
+	return api.ConfigUpload.InitUpload(c.GetUser().ID, ns, upload)
+}
+
+// GetConfigUpload gets the upload with the chunks uploaded, which is used to resume
+func (api *API) GetConfigUpload(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	return api.ConfigUpload.GetUpload(c.GetUser().ID, ns, n, c.Param("id"))
+}
+
+// PutConfigUploadChunk uploads the chunk in the request body
+func (api *API) PutConfigUploadChunk(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the index of chunk is invalid"))
+	}
+	return nil, api.ConfigUpload.PutChunk(c.GetUser().ID, ns, n, c.Param("id"), index, c.Request.Body)
+}
+
+// CompleteConfigUpload assembles the chunks and saves the file as the object of config
+func (api *API) CompleteConfigUpload(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	if err := api.checkConfigForUpload(ns, n); err != nil {
+		return nil, err
+	}
+	upload, obj, err := api.ConfigUpload.CompleteUpload(c.GetUser().ID, ns, n, c.Param("id"))
+	if err != nil {
+		return nil, err
+	}
+	return api.saveConfigObject(c.GetUser().ID, ns, n, upload.Key, upload.Unpack, obj)
+}
+
+// AbortConfigUpload aborts the upload and removes the chunks uploaded
+func (api *API) AbortConfigUpload(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	return nil, api.ConfigUpload.AbortUpload(c.GetUser().ID, ns, n, c.Param("id"))
+}
+
+// checkConfigForUpload checks the name of config which may be created by the upload
+func (api *API) checkConfigForUpload(ns, name string) error {
+	if !common.ValidNonBaetyl(name) {
+		return common.Error(common.ErrInvalidName, common.Field("nonBaetyl", "Name"))
+	}
+	return common.ValidateResourceName(name)
+}
+
+// saveConfigObject creates or updates the object item of config with the uploaded object
+func (api *API) saveConfigObject(userID, ns, name, key, unpack string, obj *models.ConfigObjectUpload) (*models.ConfigurationView, error) {
+	if err := common.ValidateKeyValue(key); err != nil {
+		return nil, err
+	}
+	item := models.ConfigDataItem{
+		Key: key,
+		Value: map[string]string{
+			"type":   ConfigTypeObject,
+			"source": obj.Source,
+			"bucket": obj.Bucket,
+			"object": obj.Object,
+			"md5":    obj.MD5,
+		},
+	}
+	if unpack != "" {
+		item.Value["unpack"] = unpack
+	}
+	data, err := api.ToConfiguration(userID, &models.ConfigurationView{Data: []models.ConfigDataItem{item}})
+	if err != nil {
+		return nil, err
+	}
+
+	old, err := api.Config.Get(nil, ns, name, "")
+	if err != nil {
+		if e, ok := err.(errors.Coder); !ok || e.Code() != common.ErrResourceNotFound {
+			return nil, err
+		}
+		config := &specV1.Configuration{Name: name, Namespace: ns, Labels: map[string]string{}, Data: data.Data}
+		res, err := api.Facade.CreateConfig(ns, config)
+		if err != nil {
+			return nil, err
+		}
+		return api.ToConfigurationView(res)
+	}
+
+	config := *old
+	config.Data = map[string]string{}
+	for k, v := range old.Data {
+		config.Data[k] = v
+	}
+	// the kv item with the same key is replaced by the object
+	delete(config.Data, key)
+	for k, v := range data.Data {
+		config.Data[k] = v
+	}
+	config.UpdateTimestamp = time.Now()
+	res, err := api.Facade.UpdateConfig(ns, &config)
+	if err != nil {
+		return nil, err
+	}
+	return api.ToConfigurationView(res)
+}
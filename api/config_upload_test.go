@@ -0,0 +1,179 @@
+package api
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	mf "github.com/baetyl/baetyl-cloud/v2/mock/facade"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+func initConfigUploadAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
+	api := &API{}
+	router := gin.Default()
+	mockCtl := gomock.NewController(t)
+	mockIM := func(c *gin.Context) {
+		common.NewContext(c).SetNamespace("default")
+		common.NewContext(c).SetUser(common.User{ID: "default"})
+	}
+	v1 := router.Group("v1")
+	{
+		configs := v1.Group("/configs")
+		configs.POST("/:name/objects", mockIM, common.Wrapper(api.UploadConfigObject))
+		configs.POST("/:name/uploads", mockIM, common.Wrapper(api.InitConfigUpload))
+		configs.GET("/:name/uploads/:id", mockIM, common.Wrapper(api.GetConfigUpload))
+		configs.PUT("/:name/uploads/:id/chunks/:index", mockIM, common.Wrapper(api.PutConfigUploadChunk))
+		configs.POST("/:name/uploads/:id/complete", mockIM, common.Wrapper(api.CompleteConfigUpload))
+		configs.DELETE("/:name/uploads/:id", mockIM, common.Wrapper(api.AbortConfigUpload))
+	}
+	return api, router, mockCtl
+}
+
+func newUploadRequest(t *testing.T, url, filename, content string) *http.Request {
+	body := new(bytes.Buffer)
+	w := multipart.NewWriter(body)
+	assert.NoError(t, w.WriteField("comment", "ignored"))
+	fw, err := w.CreateFormFile("file", filename)
+	assert.NoError(t, err)
+	fw.Write([]byte(content))
+	w.Close()
+	req, _ := http.NewRequest(http.MethodPost, url, body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req
+}
+
+func TestUploadConfigObject(t *testing.T) {
+	api, router, mockCtl := initConfigUploadAPI(t)
+	defer mockCtl.Finish()
+	sConfig := ms.NewMockConfigService(mockCtl)
+	sUpload := ms.NewMockConfigUploadService(mockCtl)
+	fConfig := mf.NewMockFacade(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{Config: sConfig}
+	api.ConfigUpload = sUpload
+	api.Facade = fConfig
+
+	obj := &models.ConfigObjectUpload{Source: "minio", Bucket: "baetyl-cloud-default", Object: "configs/cfg/model.bin", MD5: "md5", Size: 5}
+	sUpload.EXPECT().Upload("default", "default", "cfg", "model.bin", gomock.Any()).Return(obj, nil).Times(2)
+
+	// the config is created with the key of filename
+	sConfig.EXPECT().Get(nil, "default", "cfg", "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	fConfig.EXPECT().CreateConfig("default", gomock.Any()).DoAndReturn(func(_ string, cfg *specV1.Configuration) (*specV1.Configuration, error) {
+		assert.Len(t, cfg.Data, 1)
+		item := specV1.ConfigurationObject{}
+		assert.NoError(t, json.Unmarshal([]byte(cfg.Data[common.ConfigObjectPrefix+"model.bin"]), &item))
+		assert.Equal(t, "md5", item.MD5)
+		assert.Equal(t, map[string]string{"type": ConfigTypeObject, "source": "minio", "bucket": "baetyl-cloud-default",
+			"object": "configs/cfg/model.bin", "md5": "md5", "userID": "default"}, item.Metadata)
+		return cfg, nil
+	}).Times(1)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newUploadRequest(t, "/v1/configs/cfg/objects", "model.bin", "hello"))
+	assert.Equal(t, http.StatusOK, w.Code)
+	view := new(models.ConfigurationView)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), view))
+	assert.Equal(t, "model.bin", view.Data[0].Key)
+	assert.Equal(t, "configs/cfg/model.bin", view.Data[0].Value["object"])
+
+	// the kv item of the existing config is replaced
+	old := &specV1.Configuration{Name: "cfg", Namespace: "default", Version: "1", Data: map[string]string{"model": "kv", "other": "v"}}
+	sConfig.EXPECT().Get(nil, "default", "cfg", "").Return(old, nil).Times(1)
+	fConfig.EXPECT().UpdateConfig("default", gomock.Any()).DoAndReturn(func(_ string, cfg *specV1.Configuration) (*specV1.Configuration, error) {
+		assert.Equal(t, "1", cfg.Version)
+		assert.Equal(t, "v", cfg.Data["other"])
+		assert.NotContains(t, cfg.Data, "model")
+		assert.Contains(t, cfg.Data[common.ConfigObjectPrefix+"model"], `"unpack":"zip"`)
+		return cfg, nil
+	}).Times(1)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, newUploadRequest(t, "/v1/configs/cfg/objects?key=model&unpack=zip", "model.bin", "hello"))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "kv", old.Data["model"])
+
+	// invalid requests
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, newUploadRequest(t, "/v1/configs/baetyl-cfg/objects", "model.bin", "hello"))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, newUploadRequest(t, "/v1/configs/cfg/objects?key=a/b", "model.bin", "hello"))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/v1/configs/cfg/objects", strings.NewReader("hello"))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestConfigUploadChunked(t *testing.T) {
+	api, router, mockCtl := initConfigUploadAPI(t)
+	defer mockCtl.Finish()
+	sConfig := ms.NewMockConfigService(mockCtl)
+	sUpload := ms.NewMockConfigUploadService(mockCtl)
+	fConfig := mf.NewMockFacade(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{Config: sConfig}
+	api.ConfigUpload = sUpload
+	api.Facade = fConfig
+
+	upload := &models.ConfigUpload{ID: "abc", Config: "cfg", Key: "model", Filename: "model.bin", Size: 10, ChunkSize: 8, Chunks: 2, Uploaded: []int{}}
+	sUpload.EXPECT().InitUpload("default", "default", gomock.Any()).DoAndReturn(func(_, _ string, u *models.ConfigUpload) (*models.ConfigUpload, error) {
+		assert.Equal(t, "cfg", u.Config)
+		return upload, nil
+	}).Times(1)
+	w := httptest.NewRecorder()
+	body, _ := json.Marshal(map[string]interface{}{"key": "model", "filename": "model.bin", "size": 10})
+	req, _ := http.NewRequest(http.MethodPost, "/v1/configs/cfg/uploads", bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// the size is required
+	w = httptest.NewRecorder()
+	body, _ = json.Marshal(map[string]interface{}{"key": "model", "filename": "model.bin"})
+	req, _ = http.NewRequest(http.MethodPost, "/v1/configs/cfg/uploads", bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	sUpload.EXPECT().PutChunk("default", "default", "cfg", "abc", 1, gomock.Any()).Return(nil).Times(1)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPut, "/v1/configs/cfg/uploads/abc/chunks/1", strings.NewReader("lo"))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPut, "/v1/configs/cfg/uploads/abc/chunks/x", strings.NewReader("lo"))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	sUpload.EXPECT().GetUpload("default", "default", "cfg", "abc").Return(upload, nil).Times(1)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/v1/configs/cfg/uploads/abc", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	obj := &models.ConfigObjectUpload{Source: "minio", Bucket: "baetyl-cloud-default", Object: "configs/cfg/model.bin", MD5: "md5", Size: 10}
+	sUpload.EXPECT().CompleteUpload("default", "default", "cfg", "abc").Return(upload, obj, nil).Times(1)
+	sConfig.EXPECT().Get(nil, "default", "cfg", "").Return(&specV1.Configuration{Name: "cfg", Namespace: "default"}, nil).Times(1)
+	fConfig.EXPECT().UpdateConfig("default", gomock.Any()).DoAndReturn(func(_ string, cfg *specV1.Configuration) (*specV1.Configuration, error) {
+		assert.Contains(t, cfg.Data, common.ConfigObjectPrefix+"model")
+		return cfg, nil
+	}).Times(1)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/v1/configs/cfg/uploads/abc/complete", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	sUpload.EXPECT().AbortUpload("default", "default", "cfg", "abc").Return(nil).Times(1)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/configs/cfg/uploads/abc", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
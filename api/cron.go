@@ -0,0 +1,41 @@
+package api
+
+import (
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+const CronApp = "cronApp"
+
+// CronApp deploys the cron apps whose cron time is up, the failed ones are kept and retried next time
+func (api *API) CronApp() error {
+	cronApps, err := api.Cron.ListExpiredApps()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(cronApps) == 0 {
+		return nil
+	}
+
+	var done []uint64
+	for i := range cronApps {
+		cronApp := &cronApps[i]
+		_, err = api.Facade.ActivateCronApp(cronApp)
+		if err != nil {
+			if e, ok := err.(errors.Coder); !ok || e.Code() != common.ErrResourceNotFound {
+				api.log.Error("failed to deploy cron app, retry later",
+					log.Any(common.KeyContextNamespace, cronApp.Namespace),
+					log.Any("name", cronApp.Name),
+					log.Error(err))
+				continue
+			}
+		}
+		done = append(done, cronApp.Id)
+	}
+	if len(done) == 0 {
+		return nil
+	}
+	return api.Cron.DeleteExpiredApps(done)
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	mf "github.com/baetyl/baetyl-cloud/v2/mock/facade"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestCronApp(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sCron := ms.NewMockCronService(mockCtl)
+	fApp := mf.NewMockFacade(mockCtl)
+	api := &API{
+		Cron:   sCron,
+		Facade: fApp,
+		log:    log.L().With(log.Any("test", "api")),
+	}
+
+	sCron.EXPECT().ListExpiredApps().Return(nil, common.Error(common.ErrDatabase)).Times(1)
+	assert.Error(t, api.CronApp())
+
+	sCron.EXPECT().ListExpiredApps().Return([]models.Cron{}, nil).Times(1)
+	assert.NoError(t, api.CronApp())
+
+	cronApps := []models.Cron{
+		{Id: 1, Namespace: "default", Name: "app1", Selector: "a=b", CronTime: time.Now()},
+		{Id: 2, Namespace: "default", Name: "app2", Selector: "a=b", CronTime: time.Now()},
+		{Id: 3, Namespace: "default", Name: "app3", Selector: "a=b", CronTime: time.Now()},
+	}
+	sCron.EXPECT().ListExpiredApps().Return(cronApps, nil).Times(1)
+	fApp.EXPECT().ActivateCronApp(&cronApps[0]).Return(&specV1.Application{}, nil).Times(1)
+	// failed app is kept to retry
+	fApp.EXPECT().ActivateCronApp(&cronApps[1]).Return(nil, common.Error(common.ErrDatabase)).Times(1)
+	// deleted app is cleaned
+	fApp.EXPECT().ActivateCronApp(&cronApps[2]).Return(nil, common.Error(common.ErrResourceNotFound, common.Field("type", "app"), common.Field("name", "app3"))).Times(1)
+	sCron.EXPECT().DeleteExpiredApps([]uint64{1, 3}).Return(nil).Times(1)
+	assert.NoError(t, api.CronApp())
+
+	sCron.EXPECT().ListExpiredApps().Return(cronApps[1:2], nil).Times(1)
+	fApp.EXPECT().ActivateCronApp(gomock.Any()).Return(nil, common.Error(common.ErrDatabase)).Times(1)
+	assert.NoError(t, api.CronApp())
+}
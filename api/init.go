@@ -15,8 +15,16 @@ import (
 //go:generate mockgen -destination=../mock/api/init.go -package=api github.com/baetyl/baetyl-cloud/v2/api InitAPI
 
 type InitAPI struct {
-	Init service.InitService
-	Sign service.SignService
+	Init    service.InitService
+	Sign    service.SignService
+	Batch   service.BatchService
+	Node    service.NodeService
+	Module  service.ModuleService
+	Quota   service.QuotaService
+	Wrapper service.WrapperService
+	Webhook service.WebhookService
+	Token   service.InstallTokenService
+	Locker  service.LockerService
 }
 
 func NewInitAPI(cfg *config.CloudConfig) (*InitAPI, error) {
@@ -28,9 +36,49 @@ func NewInitAPI(cfg *config.CloudConfig) (*InitAPI, error) {
 	if err != nil {
 		return nil, err
 	}
+	batchService, err := service.NewBatchService(cfg)
+	if err != nil {
+		return nil, err
+	}
+	nodeService, err := service.NewNodeService(cfg)
+	if err != nil {
+		return nil, err
+	}
+	moduleService, err := service.NewModuleService(cfg)
+	if err != nil {
+		return nil, err
+	}
+	quotaService, err := service.NewQuotaService(cfg)
+	if err != nil {
+		return nil, err
+	}
+	wrapper, err := service.NewWrapperService(cfg)
+	if err != nil {
+		return nil, err
+	}
+	webhook, err := service.NewWebhookService(cfg)
+	if err != nil {
+		return nil, err
+	}
+	installToken, err := service.NewInstallTokenService(cfg)
+	if err != nil {
+		return nil, err
+	}
+	locker, err := service.NewLockerService(cfg)
+	if err != nil {
+		return nil, err
+	}
 	return &InitAPI{
-		Init: initService,
-		Sign: signService,
+		Init:    initService,
+		Sign:    signService,
+		Batch:   batchService,
+		Node:    nodeService,
+		Module:  moduleService,
+		Quota:   quotaService,
+		Wrapper: wrapper,
+		Webhook: webhook,
+		Token:   installToken,
+		Locker:  locker,
 	}, nil
 }
 
@@ -49,14 +97,26 @@ func (api *InitAPI) GetResource(c *common.Context) (interface{}, error) {
 			common.ErrRequestParamInvalid,
 			common.Field("error", err))
 	}
-	data, err := CheckAndParseToken(query.Token, api.Sign.GenToken)
+	data, err := CheckAndParseToken(query.Token, api.Sign.SignToken)
 	if err != nil {
 		return nil, common.Error(
 			common.ErrRequestParamInvalid,
 			common.Field("error", err))
 	}
-	return api.Init.GetResource(data[service.InfoNamespace].(string), data[service.InfoName].(string), resourceName, map[string]interface{}{
+	ns, name := data[service.InfoNamespace].(string), data[service.InfoName].(string)
+	// only the tokens registered by the install commands are accepted
+	tokenID, _ := data[service.InfoTokenID].(string)
+	if tokenID == "" {
+		log.L().Info("token not registered", log.Any("namespace", ns), log.Any("node", name))
+		return nil, common.Error(common.ErrInvalidToken)
+	}
+	token, err := api.Token.Consume(ns, name, tokenID, c.ClientIP())
+	if err != nil {
+		return nil, err
+	}
+	return api.Init.GetResource(ns, name, resourceName, map[string]interface{}{
 		"Token":          query.Token,
+		"SingleUse":      token.SingleUse,
 		"KubeNodeName":   query.Node,
 		"InitApplyYaml":  query.InitApplyYaml,
 		"Mode":           query.Mode,
@@ -16,6 +16,7 @@ import (
 	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/config"
 	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
 	"github.com/baetyl/baetyl-cloud/v2/service"
 )
 
@@ -45,11 +46,14 @@ func TestInitAPIImpl_GetResource(t *testing.T) {
 	api.Init = mInit
 	mSign := ms.NewMockSignService(mockCtl)
 	api.Sign = mSign
+	mToken := ms.NewMockInstallTokenService(mockCtl)
+	api.Token = mToken
 	// 构造token
 	info := map[string]interface{}{
 		service.InfoName:      "n0",
 		service.InfoNamespace: "default",
 		service.InfoExpiry:    time.Now().Unix() + 60*60*24*3650,
+		service.InfoTokenID:   "t0",
 	}
 	data, err := json.Marshal(info)
 	assert.NoError(t, err)
@@ -64,7 +68,8 @@ func TestInitAPIImpl_GetResource(t *testing.T) {
 
 	// ResourceSetup
 	mInit.EXPECT().GetResource("default", "n0", "kube-init-setup.sh", gomock.Any()).Return([]byte("setup"), nil)
-	mSign.EXPECT().GenToken(gomock.Any()).Return(token, nil).Times(3)
+	mSign.EXPECT().SignToken(gomock.Any()).Return(token, nil).Times(3)
+	mToken.EXPECT().Consume("default", "n0", "t0", gomock.Any()).Return(&models.InstallToken{Name: "t0"}, nil).Times(3)
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodGet, sendUrl.String(), nil)
 
@@ -96,6 +101,27 @@ func TestInitAPIImpl_GetResource(t *testing.T) {
 
 	router.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusOK, w.Code)
+
+	// the token is rejected
+	mSign.EXPECT().SignToken(gomock.Any()).Return(token, nil).Times(1)
+	mToken.EXPECT().Consume("default", "n0", "t0", gomock.Any()).Return(nil, common.Error(common.ErrInvalidToken)).Times(1)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, sendUrl.String(), nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// the token isn't registered
+	delete(info, service.InfoTokenID)
+	data, err = json.Marshal(info)
+	assert.NoError(t, err)
+	token = sign + hex.EncodeToString(data)
+	val.Set("token", token)
+	sendUrl.RawQuery = val.Encode()
+	mSign.EXPECT().SignToken(gomock.Any()).Return(token, nil).Times(1)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, sendUrl.String(), nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
 func TestInitAPIImpl_CheckAndParseToken(t *testing.T) {
@@ -115,9 +141,9 @@ func TestInitAPIImpl_CheckAndParseToken(t *testing.T) {
 	sign := "0123456789"
 	token := sign + encode
 
-	mSign.EXPECT().GenToken(gomock.Any()).Return(token, nil).Times(1)
+	mSign.EXPECT().SignToken(gomock.Any()).Return(token, nil).Times(1)
 
-	res, err := CheckAndParseToken(token, as.Sign.GenToken)
+	res, err := CheckAndParseToken(token, as.Sign.SignToken)
 	assert.NoError(t, err)
 	assert.Equal(t, info[service.InfoName], res[service.InfoName].(string))
 	assert.Equal(t, info[service.InfoNamespace], res[service.InfoNamespace].(string))
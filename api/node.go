@@ -2,6 +2,8 @@ package api
 
 import (
 	"fmt"
+	"io"
+	"net/http"
 	"reflect"
 	"strconv"
 	"strings"
@@ -39,6 +41,8 @@ const (
 	PlatformAndroid         = "android"
 	DeprecatedGPUMetrics    = "baetyl-gpu-metrics"
 	DeprecatedDmp           = "baetyl-dmp"
+	// InstallTokenExpiry the cron job purging the expired install tokens
+	InstallTokenExpiry = "installTokenExpiry"
 
 	templateInitProgramYaml = "baetyl-init-program.yml"
 	templateCoreProgramYaml = "baetyl-core-program.yml"
@@ -528,8 +532,9 @@ func (api *API) GenInitCmdFromNode(c *common.Context) (interface{}, error) {
 		return api.GenAndroidInitCmdFromNode()
 	}
 	params := map[string]interface{}{
-		"mode":     mode,
-		"template": template,
+		"mode":      mode,
+		"template":  template,
+		"singleUse": c.Query("singleUse") == "true",
 	}
 	if mode == context.RunModeKube {
 		params["InitApplyYaml"] = "baetyl-init-deployment.yml"
@@ -546,6 +551,79 @@ func (api *API) GenInitCmdFromNode(c *common.Context) (interface{}, error) {
 	return models.InitCMD{CMD: string(cmd.([]byte))}, nil
 }
 
+// GetNodeOfflineBundle returns the offline install bundle of node as a tar.gz for the sites without network
+func (api *API) GetNodeOfflineBundle(c *common.Context) (interface{}, error) {
+	ns, name := c.GetNamespace(), c.Param("name")
+	options := &models.OfflineBundleOptions{}
+	if err := c.Bind(options); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	bundle, err := api.OfflineBundle.Build(ns, name, options)
+	if err != nil {
+		return nil, err
+	}
+	defer bundle.Close()
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s-offline.tar.gz", name))
+	c.Header("Content-Type", "application/octet-stream")
+	c.Status(http.StatusOK)
+	// the status is sent already, the bundle is truncated if it fails to be packed
+	if _, err = io.Copy(c.Writer, bundle); err != nil {
+		log.L().Error("failed to write the offline bundle", log.Any("node", name), log.Error(err))
+	}
+	return nil, nil
+}
+
+// ListNodeInstallTokens lists the outstanding install tokens of node
+func (api *API) ListNodeInstallTokens(c *common.Context) (interface{}, error) {
+	ns, name := c.GetNamespace(), c.Param("name")
+	_, err := api.Node.Get(nil, ns, name)
+	if err != nil {
+		return nil, err
+	}
+	return api.InstallToken.List(ns, name)
+}
+
+// RevokeNodeInstallToken revokes the install token of node, the init server rejects it at once
+func (api *API) RevokeNodeInstallToken(c *common.Context) (interface{}, error) {
+	ns, name, tokenName := c.GetNamespace(), c.Param("name"), c.Param("token")
+	token, err := api.InstallToken.Get(ns, tokenName)
+	if err != nil {
+		return nil, err
+	}
+	if token.NodeName != name {
+		return nil, common.Error(common.ErrResourceNotFound,
+			common.Field("type", "token"),
+			common.Field("name", tokenName),
+			common.Field("namespace", ns))
+	}
+	if err = api.InstallToken.Revoke(ns, tokenName); err != nil {
+		return nil, err
+	}
+	token.Revoked = true
+	return token, nil
+}
+
+// ListNodeInstallTokenConsumes lists the consumptions of the install token of node
+func (api *API) ListNodeInstallTokenConsumes(c *common.Context) (interface{}, error) {
+	ns, name, tokenName := c.GetNamespace(), c.Param("name"), c.Param("token")
+	token, err := api.InstallToken.Get(ns, tokenName)
+	if err != nil {
+		return nil, err
+	}
+	if token.NodeName != name {
+		return nil, common.Error(common.ErrResourceNotFound,
+			common.Field("type", "token"),
+			common.Field("name", tokenName),
+			common.Field("namespace", ns))
+	}
+	return api.InstallToken.ListConsumes(ns, tokenName)
+}
+
+// PurgeInstallToken deletes the expired install tokens
+func (api *API) PurgeInstallToken() error {
+	return api.InstallToken.PurgeExpired()
+}
+
 func (api *API) GenAndroidInitCmdFromNode() (interface{}, error) {
 	apk, err := api.Prop.GetPropertyValue(service.PropInitCommandAndroid)
 	if err != nil {
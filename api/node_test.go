@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -82,6 +83,10 @@ func initNodeAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
 		nodes.PUT("/:name", mockIM, common.Wrapper(api.UpdateNode))
 		nodes.DELETE("/:name", mockIM, common.Wrapper(api.DeleteNode))
 		nodes.GET("/:name/init", mockIM, common.Wrapper(api.GenInitCmdFromNode))
+		nodes.GET("/:name/tokens", mockIM, common.Wrapper(api.ListNodeInstallTokens))
+		nodes.GET("/:name/bundle", mockIM, common.WrapperRaw(api.GetNodeOfflineBundle, true))
+		nodes.POST("/:name/tokens/:token/revoke", mockIM, common.Wrapper(api.RevokeNodeInstallToken))
+		nodes.GET("/:name/tokens/:token/consumes", mockIM, common.Wrapper(api.ListNodeInstallTokenConsumes))
 		nodes.POST("", mockIM, common.Wrapper(api.CreateNode))
 		nodes.GET("", mockIM, common.Wrapper(api.ListNode))
 		nodes.GET("/:name/deploys", mockIM, common.Wrapper(api.GetNodeDeployHistory))
@@ -1576,6 +1581,7 @@ func TestGenInitCmdFromNode(t *testing.T) {
 		"InitApplyYaml": "baetyl-init-deployment.yml",
 		"mode":          "kube",
 		"template":      service.TemplateBaetylInitCommand,
+		"singleUse":     false,
 	}
 	var expect interface{} = []byte("setup")
 	sInit.EXPECT().GetResource("default", "abc", service.TemplateBaetylInitCommand, params).Return(expect, nil).Times(1)
@@ -1606,6 +1612,88 @@ func TestGenInitCmdFromNode_ErrNode(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, w.Code)
 }
 
+func TestGetNodeOfflineBundle(t *testing.T) {
+	api, router, mockCtl := initNodeAPI(t)
+	defer mockCtl.Finish()
+	sBundle := ms.NewMockOfflineBundleService(mockCtl)
+	api.OfflineBundle = sBundle
+
+	options := &models.OfflineBundleOptions{Mode: "native", Platform: "linux-amd64"}
+	sBundle.EXPECT().Build("default", "abc", options).Return(io.NopCloser(bytes.NewReader([]byte("bundle"))), nil).Times(1)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/nodes/abc/bundle?mode=native&platform=linux-amd64", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "bundle", w.Body.String())
+	assert.Equal(t, "attachment; filename=abc-offline.tar.gz", w.Header().Get("Content-Disposition"))
+
+	sBundle.EXPECT().Build("default", "abc", &models.OfflineBundleOptions{}).Return(nil, common.Error(common.ErrResourceNotFound, common.Field("type", "node"))).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/nodes/abc/bundle", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestNodeInstallTokens(t *testing.T) {
+	api, router, mockCtl := initNodeAPI(t)
+	defer mockCtl.Finish()
+	sNode := ms.NewMockNodeService(mockCtl)
+	api.Node = sNode
+	sToken := ms.NewMockInstallTokenService(mockCtl)
+	api.InstallToken = sToken
+
+	node := getMockNode()
+	token := &models.InstallToken{Name: "t1", Namespace: "default", NodeName: node.Name, SingleUse: true, ConsumeIP: "10.0.0.1"}
+	sNode.EXPECT().Get(nil, node.Namespace, node.Name).Return(node, nil).Times(1)
+	sToken.EXPECT().List("default", node.Name).Return(&models.InstallTokenList{Total: 1, Items: []models.InstallToken{*token}}, nil).Times(1)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/nodes/abc/tokens", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	list := &models.InstallTokenList{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), list))
+	assert.Equal(t, 1, list.Total)
+	assert.Equal(t, "10.0.0.1", list.Items[0].ConsumeIP)
+
+	sToken.EXPECT().Get("default", "t1").Return(token, nil).Times(1)
+	sToken.EXPECT().Revoke("default", "t1").Return(nil).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/nodes/abc/tokens/t1/revoke", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.InstallToken{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.True(t, res.Revoked)
+
+	// the token of other node
+	sToken.EXPECT().Get("default", "t1").Return(token, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/nodes/n2/tokens/t1/revoke", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	consumes := &models.InstallTokenConsumeList{Total: 2, Items: []models.InstallTokenConsume{
+		{Name: "t1", Namespace: "default", ConsumeIP: "10.0.0.1", Accepted: true},
+		{Name: "t1", Namespace: "default", ConsumeIP: "10.0.0.2"},
+	}}
+	sToken.EXPECT().Get("default", "t1").Return(token, nil).Times(1)
+	sToken.EXPECT().ListConsumes("default", "t1").Return(consumes, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/nodes/abc/tokens/t1/consumes", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	consumeList := &models.InstallTokenConsumeList{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), consumeList))
+	assert.Equal(t, 2, consumeList.Total)
+	assert.False(t, consumeList.Items[1].Accepted)
+
+	sToken.EXPECT().Get("default", "t1").Return(token, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/nodes/n2/tokens/t1/consumes", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
 func TestGetAppByNode(t *testing.T) {
 	api, router, mockCtl := initNodeAPI(t)
 	defer mockCtl.Finish()
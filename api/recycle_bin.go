@@ -0,0 +1,82 @@
+package api
+
+import (
+	"strconv"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+const RecycleBin = "recycleBin"
+
+// ListRecycleItems list the deleted resources kept in the recycle bin of namespace
+func (api *API) ListRecycleItems(c *common.Context) (interface{}, error) {
+	params := &models.RecycleListOptions{}
+	if err := c.Bind(params); err != nil {
+		return nil, err
+	}
+	res, err := api.RecycleBin.List(c.GetNamespace(), params)
+	if err != nil {
+		return nil, err
+	}
+	for i := range res.Items {
+		redactRecycleItem(&res.Items[i])
+	}
+	return res, nil
+}
+
+// RestoreRecycleItem restore the deleted resource, the name must not be used by another resource
+func (api *API) RestoreRecycleItem(c *common.Context) (interface{}, error) {
+	id, err := parseRecycleItemID(c.Param("id"))
+	if err != nil {
+		return nil, err
+	}
+	item, err := api.Facade.RestoreRecycleItem(c.GetNamespace(), id)
+	if err != nil {
+		return nil, err
+	}
+	redactRecycleItem(item)
+	return item, nil
+}
+
+// DeleteRecycleItem delete the resource from the recycle bin permanently
+func (api *API) DeleteRecycleItem(c *common.Context) (interface{}, error) {
+	ns := c.GetNamespace()
+	id, err := parseRecycleItemID(c.Param("id"))
+	if err != nil {
+		return nil, err
+	}
+	if _, err = api.RecycleBin.Get(ns, id); err != nil {
+		if isResourceNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return nil, api.RecycleBin.Delete(nil, ns, id)
+}
+
+// PurgeRecycleBin deletes the expired resources of recycle bin
+func (api *API) PurgeRecycleBin() error {
+	return api.RecycleBin.PurgeExpired()
+}
+
+// redactRecycleItem clears the values of secret data, only the keys are exposed
+func redactRecycleItem(item *models.RecycleItem) {
+	if item.Secret == nil {
+		return
+	}
+	secret := *item.Secret
+	secret.Data = map[string][]byte{}
+	for k := range item.Secret.Data {
+		secret.Data[k] = []byte{}
+	}
+	item.Secret = &secret
+}
+
+func parseRecycleItemID(s string) (int64, error) {
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, common.Error(common.ErrRequestParamInvalid, common.Field("error", "id should be a positive integer"))
+	}
+	return id, nil
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	mf "github.com/baetyl/baetyl-cloud/v2/mock/facade"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func initRecycleBinAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
+	api := &API{log: log.L().With(log.Any("test", "api"))}
+	router := gin.Default()
+	mockCtl := gomock.NewController(t)
+	mockIM := func(c *gin.Context) { c.Set(common.KeyContextNamespace, "baetyl-cloud") }
+	v1 := router.Group("v1")
+	{
+		recycleBin := v1.Group("/recyclebin")
+		recycleBin.GET("", mockIM, common.Wrapper(api.ListRecycleItems))
+		recycleBin.POST("/:id/restore", mockIM, common.Wrapper(api.RestoreRecycleItem))
+		recycleBin.DELETE("/:id", mockIM, common.Wrapper(api.DeleteRecycleItem))
+	}
+	return api, router, mockCtl
+}
+
+func TestRecycleBin(t *testing.T) {
+	api, router, mockCtl := initRecycleBinAPI(t)
+	defer mockCtl.Finish()
+	sRecycleBin := ms.NewMockRecycleBinService(mockCtl)
+	mFacade := mf.NewMockFacade(mockCtl)
+	api.RecycleBin = sRecycleBin
+	api.Facade = mFacade
+
+	ns := "baetyl-cloud"
+	secret := &specV1.Secret{Namespace: ns, Name: "reg", Data: map[string][]byte{"password": []byte("p")}}
+	list := &models.RecycleItemList{
+		Total: 1,
+		Items: []models.RecycleItem{{ID: 1, Namespace: ns, Kind: models.RecycleKindRegistry, Name: "reg", Secret: secret}},
+	}
+	sRecycleBin.EXPECT().List(ns, &models.RecycleListOptions{Kind: models.RecycleKindRegistry}).Return(list, nil).Times(1)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/recyclebin?kind=registry", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.RecycleItemList{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, 1, res.Total)
+	// the values of secret are not exposed
+	assert.Equal(t, []byte{}, res.Items[0].Secret.Data["password"])
+	assert.Equal(t, []byte("p"), secret.Data["password"])
+
+	req, _ = http.NewRequest(http.MethodGet, "/v1/recyclebin?kind=node", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// restore
+	req, _ = http.NewRequest(http.MethodPost, "/v1/recyclebin/abc/restore", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mFacade.EXPECT().RestoreRecycleItem(ns, int64(1)).Return(nil, common.Error(common.ErrResourceConflict)).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/recyclebin/1/restore", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	item := &models.RecycleItem{ID: 2, Namespace: ns, Kind: models.RecycleKindApp, Name: "app",
+		Application: &specV1.Application{Namespace: ns, Name: "app", Version: "2"}}
+	mFacade.EXPECT().RestoreRecycleItem(ns, int64(2)).Return(item, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/recyclebin/2/restore", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	restored := &models.RecycleItem{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), restored))
+	assert.Equal(t, "2", restored.Application.Version)
+
+	// purge
+	sRecycleBin.EXPECT().Get(ns, int64(3)).Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/recyclebin/3", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	sRecycleBin.EXPECT().Get(ns, int64(1)).Return(&list.Items[0], nil).Times(1)
+	sRecycleBin.EXPECT().Delete(nil, ns, int64(1)).Return(nil).Times(1)
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/recyclebin/1", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	sRecycleBin.EXPECT().PurgeExpired().Return(nil).Times(1)
+	assert.NoError(t, api.PurgeRecycleBin())
+}
@@ -43,6 +43,10 @@ func (api *API) ListRegistry(c *common.Context) (interface{}, error) {
 
 // CreateRegistry create one Registry
 func (api *API) CreateRegistry(c *common.Context) (interface{}, error) {
+	mode, err := registryValidation(c)
+	if err != nil {
+		return nil, err
+	}
 	cfg, err := api.parseAndCheckRegistryModel(c)
 	if err != nil {
 		return nil, err
@@ -63,11 +67,17 @@ func (api *API) CreateRegistry(c *common.Context) (interface{}, error) {
 	if err = api.ValidateRegistryModel(cfg); err != nil {
 		return nil, err
 	}
+	warnings, err := api.checkRegistryCredential(mode, cfg)
+	if err != nil {
+		return nil, err
+	}
 	secret, err := api.Facade.CreateSecret(ns, cfg.ToSecret())
 	if err != nil {
 		return nil, err
 	}
-	return hidePwd(api.ToFilteredRegistryView(secret)), nil
+	res := hidePwd(api.ToFilteredRegistryView(secret))
+	res.Warnings = warnings
+	return res, nil
 }
 
 // UpdateRegistry update the Registry
@@ -101,6 +111,10 @@ func (api *API) UpdateRegistry(c *common.Context) (interface{}, error) {
 }
 
 func (api *API) RefreshRegistryPassword(c *common.Context) (interface{}, error) {
+	mode, err := registryValidation(c)
+	if err != nil {
+		return nil, err
+	}
 	cfg, err := api.parseAndCheckRegistryModel(c)
 	if err != nil {
 		return nil, err
@@ -117,12 +131,30 @@ func (api *API) RefreshRegistryPassword(c *common.Context) (interface{}, error)
 	if err = api.ValidateRegistryModel(sd); err != nil {
 		return nil, err
 	}
+	warnings, err := api.checkRegistryCredential(mode, sd)
+	if err != nil {
+		return nil, err
+	}
 
 	secret, err = api.Facade.UpdateSecret(ns, sd.ToSecret())
 	if err != nil {
 		return nil, err
 	}
-	return hidePwd(api.ToRegistryView(secret)), nil
+	res := hidePwd(api.ToRegistryView(secret))
+	res.Warnings = warnings
+	return res, nil
+}
+
+// checkRegistryCredential checks the credential of registry if the validation is set
+func (api *API) checkRegistryCredential(mode string, registry *models.Registry) ([]string, error) {
+	if mode == "" {
+		return nil, nil
+	}
+	var failures []string
+	if err := api.RegistryCheck.CheckCredential(registry); err != nil {
+		failures = append(failures, err.Error())
+	}
+	return registryWarnings(mode, failures)
 }
 
 // DeleteRegistry delete the Registry
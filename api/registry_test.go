@@ -377,3 +377,53 @@ func TestGetAppByRegistry(t *testing.T) {
 	router.ServeHTTP(w4, req4)
 	assert.Equal(t, http.StatusOK, w4.Code)
 }
+
+func TestCreateRegistryWithValidation(t *testing.T) {
+	api, router, mockCtl := initRegistryAPI(t)
+	defer mockCtl.Finish()
+
+	sSecret := ms.NewMockSecretService(mockCtl)
+	fSecret := mf.NewMockFacade(mockCtl)
+	sCheck := ms.NewMockRegistryCheckService(mockCtl)
+	api.Facade = fSecret
+	api.RegistryCheck = sCheck
+	api.AppCombinedService = &service.AppCombinedService{
+		Secret: sSecret,
+	}
+
+	mConf := &models.Registry{
+		Namespace: "default",
+		Name:      "abc",
+		Username:  "username",
+		Password:  "password",
+		Address:   "address",
+	}
+	secret := mConf.ToSecret()
+	body, _ := json.Marshal(mConf)
+
+	// warn
+	sSecret.EXPECT().Get("default", "abc", "").Return(nil, nil).Times(2)
+	sCheck.EXPECT().CheckCredential(gomock.Any()).Return(errors.New("the credential of registry (address) is unauthorized")).Times(2)
+	fSecret.EXPECT().CreateSecret("default", gomock.Any()).Return(secret, nil).Times(1)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/v1/registries?validate=warn", bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.Registry{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, []string{"the credential of registry (address) is unauthorized"}, res.Warnings)
+	assert.Empty(t, res.Password)
+
+	// strict
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/v1/registries?validate=strict", bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "ErrRegistryValidation")
+
+	// invalid mode
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/v1/registries?validate=yes", bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
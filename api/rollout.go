@@ -0,0 +1,51 @@
+package api
+
+import (
+	"github.com/baetyl/baetyl-go/v2/utils"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+const AppRollout = "appRollout"
+
+// GetAppRollout get the rollout strategy and the progress of application
+func (api *API) GetAppRollout(c *common.Context) (interface{}, error) {
+	ns, name := c.GetNamespace(), c.GetNameFromParam()
+	if _, err := api.getVisibleApp(ns, name); err != nil {
+		return nil, err
+	}
+	return api.Rollout.Get(ns, name)
+}
+
+// SetAppRolloutStrategy set the rollout strategy used by the next release of application
+func (api *API) SetAppRolloutStrategy(c *common.Context) (interface{}, error) {
+	ns, name := c.GetNamespace(), c.GetNameFromParam()
+	strategy := new(models.RolloutStrategy)
+	if err := c.LoadBody(strategy); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if strategy.CanarySelector != "" {
+		if _, err := utils.IsLabelMatch(strategy.CanarySelector, map[string]string{}); err != nil {
+			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "invalid canarySelector: "+err.Error()))
+		}
+	}
+	if _, err := api.getVisibleApp(ns, name); err != nil {
+		return nil, err
+	}
+	return api.Rollout.SetStrategy(ns, name, strategy)
+}
+
+// DeleteAppRolloutStrategy remove the rollout strategy, the next release goes to all nodes at once
+func (api *API) DeleteAppRolloutStrategy(c *common.Context) (interface{}, error) {
+	ns, name := c.GetNamespace(), c.GetNameFromParam()
+	if _, err := api.getVisibleApp(ns, name); err != nil {
+		return nil, err
+	}
+	return api.Rollout.SetStrategy(ns, name, nil)
+}
+
+// RolloutApp moves the progressing rollouts forward
+func (api *API) RolloutApp() error {
+	return api.Rollout.Progress()
+}
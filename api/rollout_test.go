@@ -0,0 +1,90 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+func initRolloutAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
+	api := &API{log: log.L().With(log.Any("test", "api"))}
+	router := gin.Default()
+	mockCtl := gomock.NewController(t)
+	mockIM := func(c *gin.Context) { c.Set(common.KeyContextNamespace, "baetyl-cloud") }
+	v1 := router.Group("v1")
+	{
+		apps := v1.Group("/apps")
+		apps.GET("/:name/rollout", mockIM, common.Wrapper(api.GetAppRollout))
+		apps.PUT("/:name/rollout", mockIM, common.Wrapper(api.SetAppRolloutStrategy))
+		apps.DELETE("/:name/rollout", mockIM, common.Wrapper(api.DeleteAppRolloutStrategy))
+	}
+	return api, router, mockCtl
+}
+
+func TestAppRollout(t *testing.T) {
+	api, router, mockCtl := initRolloutAPI(t)
+	defer mockCtl.Finish()
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sRollout := ms.NewMockRolloutService(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{App: sApp}
+	api.Rollout = sRollout
+
+	ns, name := "baetyl-cloud", "abc"
+	sApp.EXPECT().Get(ns, name, "").Return(&specV1.Application{Namespace: ns, Name: name}, nil).AnyTimes()
+
+	rollout := &models.Rollout{
+		Namespace: ns,
+		Name:      name,
+		Strategy:  &models.RolloutStrategy{CanaryCount: 1, BatchPercent: 20},
+		Status:    models.RolloutProgressing,
+	}
+	sRollout.EXPECT().Get(ns, name).Return(rollout, nil).Times(1)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/apps/abc/rollout", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.Rollout{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, models.RolloutProgressing, res.Status)
+
+	body, _ := json.Marshal(&models.RolloutStrategy{BatchPercent: 101})
+	req, _ = http.NewRequest(http.MethodPut, "/v1/apps/abc/rollout", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	body, _ = json.Marshal(&models.RolloutStrategy{CanarySelector: "a in ("})
+	req, _ = http.NewRequest(http.MethodPut, "/v1/apps/abc/rollout", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	sRollout.EXPECT().SetStrategy(ns, name, rollout.Strategy).Return(rollout, nil).Times(1)
+	body, _ = json.Marshal(rollout.Strategy)
+	req, _ = http.NewRequest(http.MethodPut, "/v1/apps/abc/rollout", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	sRollout.EXPECT().SetStrategy(ns, name, nil).Return(&models.Rollout{Namespace: ns, Name: name}, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/apps/abc/rollout", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	sRollout.EXPECT().Progress().Return(nil).Times(1)
+	assert.NoError(t, api.RolloutApp())
+}
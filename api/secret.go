@@ -12,6 +12,9 @@ import (
 	"github.com/baetyl/baetyl-cloud/v2/models"
 )
 
+// DataKeyExpiry the cron job purging the retired data keys of secrets
+const DataKeyExpiry = "dataKeyExpiry"
+
 // TODO: optimize this layer, general abstraction
 
 // GetSecret get a secret
@@ -57,6 +60,13 @@ func (api *API) CreateSecret(c *common.Context) (interface{}, error) {
 	if sd != nil {
 		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "this name is already in use"))
 	}
+	if isDryRun(c) {
+		impact, err := api.Preview.PreviewSecret(ns, nil, cfg.ToSecret())
+		if err != nil {
+			return nil, err
+		}
+		return &models.DryRunResult{DryRun: true, Resource: api.ToFilteredSecretView(cfg.ToSecret()), Impact: impact}, nil
+	}
 	res, err := api.Facade.CreateSecret(ns, cfg.ToSecret())
 	if err != nil {
 		return nil, err
@@ -76,6 +86,14 @@ func (api *API) UpdateSecret(c *common.Context) (interface{}, error) {
 		return nil, err
 	}
 
+	if isDryRun(c) {
+		impact, err := api.Preview.PreviewSecret(ns, oldSecret, cfg.ToSecret())
+		if err != nil {
+			return nil, err
+		}
+		return &models.DryRunResult{DryRun: true, Resource: api.ToFilteredSecretView(cfg.ToSecret()), Impact: impact}, nil
+	}
+
 	sd := api.ToSecretView(oldSecret)
 	if sd.Equal(cfg) {
 		return sd, nil
@@ -118,11 +136,28 @@ func (api *API) parseAndCheckSecretModel(c *common.Context) (*models.SecretView,
 	if name := c.GetNameFromParam(); name != "" {
 		secret.Name = name
 	}
-	if secret.Name == "" {
-		err = common.Error(common.ErrRequestParamInvalid, common.Field("error", "name is required"))
+	if err = api.checkSecretView(secret); err != nil {
+		return nil, err
 	}
+	return secret, nil
+}
 
-	return secret, err
+// checkSecretView checks the name and the references of provider of secret
+func (api *API) checkSecretView(secret *models.SecretView) error {
+	if secret.Name == "" {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", "name is required"))
+	}
+	if secret.Provider != "" {
+		if err := api.Providers.CheckProvider(secret.Provider); err != nil {
+			return err
+		}
+		for k, v := range secret.Data {
+			if v == "" {
+				return common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("the reference of key (%s) is empty", k)))
+			}
+		}
+	}
+	return nil
 }
 
 func (api *API) ToFilteredSecretView(s *specV1.Secret) *models.SecretView {
@@ -225,3 +260,19 @@ func (api *API) listFunctionsByNames(namespace string, appNames []string) ([]str
 	}
 	return result, nil
 }
+
+// ReencryptSecrets for mis server api, re-encrypts the secrets of namespace (all namespaces if empty) with the current key of kms
+//   - param namespace string
+func (api *API) ReencryptSecrets(c *common.Context) (interface{}, error) {
+	ns := c.Query("namespace")
+	count, err := api.Secret.Reencrypt(ns)
+	if err != nil {
+		return nil, err
+	}
+	return &models.SecretReencryption{Namespace: ns, Count: count}, nil
+}
+
+// PurgeDataKeys deletes the retired data keys of secrets which are no longer used
+func (api *API) PurgeDataKeys() error {
+	return api.Secret.PurgeDataKeys()
+}
@@ -36,6 +36,7 @@ func initSecretAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
 		configs.DELETE("/:name", mockIM, common.Wrapper(api.DeleteSecret))
 		configs.POST("", mockIM, common.Wrapper(api.CreateSecret))
 		configs.GET("", mockIM, common.Wrapper(api.ListSecret))
+		configs.POST("/reencrypt", common.WrapperMis(api.ReencryptSecrets))
 	}
 
 	return api, router, mockCtl
@@ -150,6 +151,58 @@ func TestCreateSecret(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w2.Code)
 }
 
+func TestCreateSecretWithProvider(t *testing.T) {
+	api, router, mockCtl := initSecretAPI(t)
+	defer mockCtl.Finish()
+
+	sSecret := ms.NewMockSecretService(mockCtl)
+	sProvider := ms.NewMockSecretProviderService(mockCtl)
+	fSecret := mf.NewMockFacade(mockCtl)
+	api.Facade = fSecret
+	api.Providers = sProvider
+	api.AppCombinedService = &service.AppCombinedService{
+		Secret: sSecret,
+	}
+
+	mConf := &models.SecretView{
+		Name:     "abc",
+		Data:     map[string]string{"password": "db/password"},
+		Provider: "filesecret",
+	}
+	sProvider.EXPECT().CheckProvider("filesecret").Return(nil).Times(2)
+	sSecret.EXPECT().Get("default", "abc", "").Return(nil, nil).Times(1)
+	fSecret.EXPECT().CreateSecret("default", gomock.Any()).DoAndReturn(func(_ string, secret *specV1.Secret) (*specV1.Secret, error) {
+		// only the references are persisted
+		assert.Equal(t, "filesecret", secret.Annotations[common.AnnotationSecretProvider])
+		assert.Equal(t, []byte("db/password"), secret.Data["password"])
+		return secret, nil
+	}).Times(1)
+	w := httptest.NewRecorder()
+	body, _ := json.Marshal(mConf)
+	req, _ := http.NewRequest(http.MethodPost, "/v1/secrets", bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := new(models.SecretView)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, "filesecret", res.Provider)
+
+	// the reference is required
+	mConf.Data["user"] = ""
+	w = httptest.NewRecorder()
+	body, _ = json.Marshal(mConf)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/secrets", bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mConf.Provider = "vault"
+	sProvider.EXPECT().CheckProvider("vault").Return(common.Error(common.ErrRequestParamInvalid)).Times(1)
+	w = httptest.NewRecorder()
+	body, _ = json.Marshal(mConf)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/secrets", bytes.NewReader(body))
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 func TestUpdateSecret(t *testing.T) {
 	api, router, mockCtl := initSecretAPI(t)
 	defer mockCtl.Finish()
@@ -342,3 +395,46 @@ func TestGetAppBySecret(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, res.Total, 0)
 }
+
+func TestReencryptSecrets(t *testing.T) {
+	api, router, mockCtl := initSecretAPI(t)
+	defer mockCtl.Finish()
+
+	sSecret := ms.NewMockSecretService(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{
+		Secret: sSecret,
+	}
+
+	sSecret.EXPECT().Reencrypt("default").Return(3, nil).Times(1)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/v1/secrets/reencrypt?namespace=default", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := struct {
+		Status int                       `json:"status"`
+		Data   models.SecretReencryption `json:"data"`
+	}{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, 0, res.Status)
+	assert.Equal(t, 3, res.Data.Count)
+
+	sSecret.EXPECT().Reencrypt("").Return(0, errors.New("the encryption of secrets is disabled")).Times(1)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/v1/secrets/reencrypt", nil)
+	router.ServeHTTP(w, req)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, 1, res.Status)
+}
+
+func TestPurgeDataKeys(t *testing.T) {
+	api, _, mockCtl := initSecretAPI(t)
+	defer mockCtl.Finish()
+
+	sSecret := ms.NewMockSecretService(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{
+		Secret: sSecret,
+	}
+
+	sSecret.EXPECT().PurgeDataKeys().Return(nil).Times(1)
+	assert.NoError(t, api.PurgeDataKeys())
+}
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// ListSignKeys for mis server api, lists the keys of sign plugin, the active first
+func (api *API) ListSignKeys(_ *common.Context) (interface{}, error) {
+	keys, err := api.Sign.ListKeys()
+	if err != nil {
+		return nil, err
+	}
+	return &models.SignKeyList{Total: len(keys), Items: keys}, nil
+}
+
+// RotateSignKey for mis server api, creates a new active key of sign plugin, the signatures and tokens
+// of the retired keys still verify until the keys are removed
+func (api *API) RotateSignKey(_ *common.Context) (interface{}, error) {
+	return api.Sign.RotateKey()
+}
+
+// RemoveSignKey for mis server api, removes the retired key of sign plugin
+//   - param id string
+func (api *API) RemoveSignKey(c *common.Context) (interface{}, error) {
+	return nil, api.Sign.RemoveKey(c.Param("id"))
+}
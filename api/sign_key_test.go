@@ -0,0 +1,117 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func initSignKeyAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
+	api := &API{}
+	router := gin.Default()
+	mockCtl := gomock.NewController(t)
+	v1 := router.Group("v1")
+	{
+		signKey := v1.Group("/signkeys")
+		signKey.GET("", common.WrapperMis(api.ListSignKeys))
+		signKey.POST("/rotate", common.WrapperMis(api.RotateSignKey))
+		signKey.DELETE("/:id", common.WrapperMis(api.RemoveSignKey))
+	}
+	return api, router, mockCtl
+}
+
+func TestListSignKeys(t *testing.T) {
+	api, router, mockCtl := initSignKeyAPI(t)
+	defer mockCtl.Finish()
+	sSign := ms.NewMockSignService(mockCtl)
+	api.Sign = sSign
+
+	keys := []models.SignKey{
+		{KeyID: "k2", Algorithm: "rsa", Active: true, CreateTime: time.Unix(2000, 0).UTC()},
+		{KeyID: "k1", Algorithm: "rsa", CreateTime: time.Unix(1000, 0).UTC()},
+	}
+	sSign.EXPECT().ListKeys().Return(keys, nil).Times(1)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/v1/signkeys", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := struct {
+		Status int                `json:"status"`
+		Data   models.SignKeyList `json:"data"`
+	}{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, 0, res.Status)
+	assert.Equal(t, 2, res.Data.Total)
+	assert.Equal(t, keys, res.Data.Items)
+
+	sSign.EXPECT().ListKeys().Return(nil, errors.New("error")).Times(1)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/v1/signkeys", nil)
+	router.ServeHTTP(w, req)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, 1, res.Status)
+}
+
+func TestRotateSignKey(t *testing.T) {
+	api, router, mockCtl := initSignKeyAPI(t)
+	defer mockCtl.Finish()
+	sSign := ms.NewMockSignService(mockCtl)
+	api.Sign = sSign
+
+	key := &models.SignKey{KeyID: "k3", Algorithm: "ed25519", Active: true, CreateTime: time.Unix(3000, 0).UTC()}
+	sSign.EXPECT().RotateKey().Return(key, nil).Times(1)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/v1/signkeys/rotate", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := struct {
+		Status int            `json:"status"`
+		Data   models.SignKey `json:"data"`
+	}{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, 0, res.Status)
+	assert.Equal(t, *key, res.Data)
+
+	sSign.EXPECT().RotateKey().Return(nil, errors.New("error")).Times(1)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/v1/signkeys/rotate", nil)
+	router.ServeHTTP(w, req)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, 1, res.Status)
+}
+
+func TestRemoveSignKey(t *testing.T) {
+	api, router, mockCtl := initSignKeyAPI(t)
+	defer mockCtl.Finish()
+	sSign := ms.NewMockSignService(mockCtl)
+	api.Sign = sSign
+
+	sSign.EXPECT().RemoveKey("k1").Return(nil).Times(1)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodDelete, "/v1/signkeys/k1", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := struct {
+		Status int `json:"status"`
+	}{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, 0, res.Status)
+
+	sSign.EXPECT().RemoveKey("k2").Return(common.Error(common.ErrRequestParamInvalid)).Times(1)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodDelete, "/v1/signkeys/k2", nil)
+	router.ServeHTTP(w, req)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, 1, res.Status)
+}
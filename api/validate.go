@@ -3,10 +3,12 @@ package api
 import (
 	"bytes"
 	"io/ioutil"
+	"strings"
 
 	"github.com/baetyl/baetyl-go/v2/json"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
 )
 
 // ValidateResourceForCreating validate when resource create
@@ -40,3 +42,26 @@ func (api *API) ValidateResourceForDeleting(c *common.Context) (interface{}, err
 	}
 	return nil, nil
 }
+
+// isDryRun returns true if the change is only validated and previewed without persisting, which is set by dryRun=true
+func isDryRun(c *common.Context) bool {
+	return c.Query("dryRun") == "true"
+}
+
+// registryValidation returns the mode of validating registries and images, which is set by validate=warn or validate=strict
+func registryValidation(c *common.Context) (string, error) {
+	switch v := c.Query("validate"); v {
+	case "", models.RegistryValidationWarn, models.RegistryValidationStrict:
+		return v, nil
+	default:
+		return "", common.Error(common.ErrRequestParamInvalid, common.Field("error", "validate should be warn or strict"))
+	}
+}
+
+// registryWarnings returns the failures of validation as warnings, or as the error if the mode is strict
+func registryWarnings(mode string, failures []string) ([]string, error) {
+	if len(failures) > 0 && mode == models.RegistryValidationStrict {
+		return nil, common.Error(common.ErrRegistryValidation, common.Field("error", strings.Join(failures, "; ")))
+	}
+	return failures, nil
+}
@@ -0,0 +1,165 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+const (
+	WebhookDelivery = "webhookDelivery"
+	NodeOffline     = "nodeOffline"
+)
+
+var callbackMethods = map[string]bool{
+	http.MethodGet:    true,
+	http.MethodPost:   true,
+	http.MethodPut:    true,
+	http.MethodPatch:  true,
+	http.MethodDelete: true,
+}
+
+// GetCallback get a callback
+func (api *API) GetCallback(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	return api.Webhook.GetCallback(ns, n)
+}
+
+// ListCallback list the callbacks of namespace
+func (api *API) ListCallback(c *common.Context) (interface{}, error) {
+	return api.Webhook.ListCallback(c.GetNamespace())
+}
+
+// CreateCallback create a callback which the subscribed events are delivered to
+func (api *API) CreateCallback(c *common.Context) (interface{}, error) {
+	callback, err := api.parseAndCheckCallback(c)
+	if err != nil {
+		return nil, err
+	}
+	ns := c.GetNamespace()
+	callback.Namespace = ns
+	if _, err = api.Webhook.GetCallback(ns, callback.Name); err == nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "this name is already in use"))
+	} else if e, ok := err.(errors.Coder); !ok || e.Code() != common.ErrResourceNotFound {
+		return nil, err
+	}
+	return api.Webhook.CreateCallback(callback)
+}
+
+// UpdateCallback update the callback, the pending deliveries are sent with the updated one
+func (api *API) UpdateCallback(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	if _, err := api.Webhook.GetCallback(ns, n); err != nil {
+		return nil, err
+	}
+	callback, err := api.parseAndCheckCallback(c)
+	if err != nil {
+		return nil, err
+	}
+	callback.Namespace = ns
+	return api.Webhook.UpdateCallback(callback)
+}
+
+// DeleteCallback delete the callback and its deliveries
+func (api *API) DeleteCallback(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	return nil, api.Webhook.DeleteCallback(ns, n)
+}
+
+// ListCallbackDeliveries list the deliveries of callback, the latest first
+func (api *API) ListCallbackDeliveries(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	params := &models.ListOptions{}
+	if err := c.Bind(params); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if _, err := api.Webhook.GetCallback(ns, n); err != nil {
+		return nil, err
+	}
+	return api.Webhook.ListDelivery(ns, n, params)
+}
+
+// GetCallbackDelivery get a delivery of callback
+func (api *API) GetCallbackDelivery(c *common.Context) (interface{}, error) {
+	return api.getCallbackDelivery(c)
+}
+
+// RedeliverCallbackDelivery send the delivery of callback again at once
+func (api *API) RedeliverCallbackDelivery(c *common.Context) (interface{}, error) {
+	d, err := api.getCallbackDelivery(c)
+	if err != nil {
+		return nil, err
+	}
+	return api.Webhook.Redeliver(d.Namespace, d.Name)
+}
+
+// DeliverWebhook sends the due deliveries of events
+func (api *API) DeliverWebhook() error {
+	return api.Webhook.Deliver()
+}
+
+// NotifyNodeOffline emits the events of the nodes going offline
+func (api *API) NotifyNodeOffline() error {
+	return api.Webhook.NotifyOfflineNodes()
+}
+
+func (api *API) getCallbackDelivery(c *common.Context) (*models.Delivery, error) {
+	ns, n, name := c.GetNamespace(), c.GetNameFromParam(), c.Param("delivery")
+	d, err := api.Webhook.GetDelivery(ns, name)
+	if err != nil {
+		return nil, err
+	}
+	if d.Callback != n {
+		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "delivery"), common.Field("name", name))
+	}
+	return d, nil
+}
+
+func (api *API) parseAndCheckCallback(c *common.Context) (*models.Callback, error) {
+	callback := new(models.Callback)
+	callback.Name = c.GetNameFromParam()
+	if err := c.LoadBody(callback); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if name := c.GetNameFromParam(); name != "" {
+		callback.Name = name
+	}
+	if callback.Name == "" {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "name is required"))
+	}
+
+	callback.Method = strings.ToUpper(callback.Method)
+	if !callbackMethods[callback.Method] {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the method of callback is not supported"))
+	}
+	for _, e := range callback.Events {
+		if !models.WebhookEvents[e] {
+			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the event "+e+" is not supported"))
+		}
+	}
+
+	templates := []string{callback.URL}
+	for _, fields := range []map[string]string{callback.Params, callback.Header, callback.Body} {
+		for _, v := range fields {
+			templates = append(templates, v)
+		}
+	}
+	for _, t := range templates {
+		if _, err := template.New("callback").Parse(t); err != nil {
+			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "invalid template: "+err.Error()))
+		}
+	}
+	if !strings.Contains(callback.URL, "{{") {
+		u, err := url.Parse(callback.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "the url of callback should be http or https"))
+		}
+	}
+	return callback, nil
+}
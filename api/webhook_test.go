@@ -0,0 +1,120 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func initWebhookAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
+	api := &API{log: log.L().With(log.Any("test", "api"))}
+	router := gin.Default()
+	mockCtl := gomock.NewController(t)
+	mockIM := func(c *gin.Context) { c.Set(common.KeyContextNamespace, "default") }
+	v1 := router.Group("v1")
+	{
+		callbacks := v1.Group("/callbacks")
+		callbacks.GET("/:name", mockIM, common.Wrapper(api.GetCallback))
+		callbacks.PUT("/:name", mockIM, common.Wrapper(api.UpdateCallback))
+		callbacks.DELETE("/:name", mockIM, common.Wrapper(api.DeleteCallback))
+		callbacks.POST("", mockIM, common.Wrapper(api.CreateCallback))
+		callbacks.GET("", mockIM, common.Wrapper(api.ListCallback))
+		callbacks.GET("/:name/deliveries", mockIM, common.Wrapper(api.ListCallbackDeliveries))
+		callbacks.GET("/:name/deliveries/:delivery", mockIM, common.Wrapper(api.GetCallbackDelivery))
+		callbacks.POST("/:name/deliveries/:delivery/redeliver", mockIM, common.Wrapper(api.RedeliverCallbackDelivery))
+	}
+	return api, router, mockCtl
+}
+
+func TestCreateCallback(t *testing.T) {
+	api, router, mockCtl := initWebhookAPI(t)
+	defer mockCtl.Finish()
+	sWebhook := ms.NewMockWebhookService(mockCtl)
+	api.Webhook = sWebhook
+
+	notFound := common.Error(common.ErrResourceNotFound, common.Field("type", "callback"))
+	callback := &models.Callback{
+		Name:   "c1",
+		Method: "post",
+		URL:    "https://example.com/hooks",
+		Body:   map[string]string{"text": "{{.Data.node}} is offline"},
+		Events: []string{models.EventNodeOffline},
+	}
+	sWebhook.EXPECT().GetCallback("default", "c1").Return(nil, notFound).Times(1)
+	sWebhook.EXPECT().CreateCallback(gomock.Any()).DoAndReturn(func(cb *models.Callback) (*models.Callback, error) {
+		assert.Equal(t, "default", cb.Namespace)
+		assert.Equal(t, http.MethodPost, cb.Method)
+		return cb, nil
+	}).Times(1)
+	body, _ := json.Marshal(callback)
+	req, _ := http.NewRequest(http.MethodPost, "/v1/callbacks", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// the name is in use
+	sWebhook.EXPECT().GetCallback("default", "c1").Return(callback, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/callbacks", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// the invalid callbacks
+	for _, cb := range []*models.Callback{
+		{Name: "c2", Method: "post", URL: "https://example.com", Events: []string{"unknown"}},
+		{Name: "c2", Method: "connect", URL: "https://example.com"},
+		{Name: "c2", Method: "post", URL: "ftp://example.com"},
+		{Name: "c2", Method: "post", URL: "https://example.com", Body: map[string]string{"a": "{{.Data"}},
+	} {
+		body, _ = json.Marshal(cb)
+		req, _ = http.NewRequest(http.MethodPost, "/v1/callbacks", bytes.NewReader(body))
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, cb)
+	}
+}
+
+func TestCallbackDeliveries(t *testing.T) {
+	api, router, mockCtl := initWebhookAPI(t)
+	defer mockCtl.Finish()
+	sWebhook := ms.NewMockWebhookService(mockCtl)
+	api.Webhook = sWebhook
+
+	callback := &models.Callback{Name: "c1", Namespace: "default"}
+	list := &models.DeliveryList{Total: 1, Items: []models.Delivery{{Name: "d1", Namespace: "default", Callback: "c1"}}}
+	sWebhook.EXPECT().GetCallback("default", "c1").Return(callback, nil).Times(1)
+	sWebhook.EXPECT().ListDelivery("default", "c1", gomock.Any()).Return(list, nil).Times(1)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/callbacks/c1/deliveries", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	res := &models.DeliveryList{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, 1, res.Total)
+
+	// redeliver
+	sWebhook.EXPECT().GetDelivery("default", "d1").Return(&list.Items[0], nil).Times(1)
+	sWebhook.EXPECT().Redeliver("default", "d1").Return(&models.Delivery{Name: "d1", Status: models.DeliverySucceeded}, nil).Times(1)
+	req, _ = http.NewRequest(http.MethodPost, "/v1/callbacks/c1/deliveries/d1/redeliver", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// the delivery belongs to the other callback
+	sWebhook.EXPECT().GetDelivery("default", "d1").Return(&list.Items[0], nil).Times(1)
+	req, _ = http.NewRequest(http.MethodGet, "/v1/callbacks/c2/deliveries/d1", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
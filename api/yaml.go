@@ -3,6 +3,7 @@ package api
 import (
 	"bytes"
 	"io"
+	"path"
 	"reflect"
 	"regexp"
 	"strings"
@@ -27,12 +28,14 @@ import (
 )
 
 const (
-	TypeSecret    = "Secret"
-	TypeConfig    = "ConfigMap"
-	TypeDeploy    = "Deployment"
-	TypeDaemonset = "DaemonSet"
-	TypeJob       = "Job"
-	TypeService   = "Service"
+	TypeSecret      = "Secret"
+	TypeConfig      = "ConfigMap"
+	TypeDeploy      = "Deployment"
+	TypeDaemonset   = "DaemonSet"
+	TypeStatefulSet = "StatefulSet"
+	TypeJob         = "Job"
+	TypeCronJob     = "CronJob"
+	TypeService     = "Service"
 )
 
 // yaml resources api
@@ -60,13 +63,14 @@ func (api *API) CreateYamlResource(c *common.Context) (interface{}, error) {
 			}
 			res.Items = append(res.Items, cfg)
 			res.Total++
-		case TypeDeploy, TypeDaemonset, TypeJob:
+		case TypeDeploy, TypeDaemonset, TypeStatefulSet, TypeJob, TypeCronJob:
 			app, err := api.generateApplication(ns, r)
 			if err != nil {
 				return nil, err
 			}
 			res.Items = append(res.Items, app)
 			res.Total++
+			res.AddUnsupported(r.GetObjectKind().GroupVersionKind().Kind, app.Name, unsupportedAppFields(r))
 		case TypeService:
 			err = api.generateService(ns, r)
 			if err != nil {
@@ -101,13 +105,14 @@ func (api *API) UpdateYamlResource(c *common.Context) (interface{}, error) {
 			}
 			res.Items = append(res.Items, cfg)
 			res.Total++
-		case TypeDeploy, TypeDaemonset, TypeJob:
+		case TypeDeploy, TypeDaemonset, TypeStatefulSet, TypeJob, TypeCronJob:
 			app, err := api.updateApplication(ns, r)
 			if err != nil {
 				return nil, err
 			}
 			res.Items = append(res.Items, app)
 			res.Total++
+			res.AddUnsupported(r.GetObjectKind().GroupVersionKind().Kind, app.Name, unsupportedAppFields(r))
 		case TypeService:
 			err = api.updateService(ns, r)
 			if err != nil {
@@ -137,7 +142,7 @@ func (api *API) DeleteYamlResource(c *common.Context) (interface{}, error) {
 			if err != nil {
 				return nil, err
 			}
-		case TypeDeploy, TypeDaemonset, TypeJob:
+		case TypeDeploy, TypeDaemonset, TypeStatefulSet, TypeJob, TypeCronJob:
 			_, err := api.deleteApplication(ns, resources[i])
 			if err != nil {
 				return nil, err
@@ -174,7 +179,7 @@ func (api *API) parseYamlFileAndCheck(c *common.Context) ([]runtime.Object, erro
 }
 
 func (api *API) parseK8SYaml(fileR []byte) []runtime.Object {
-	acceptedK8sTypes := regexp.MustCompile(`(Secret|ConfigMap|Deployment|DaemonSet|Job|Service)`)
+	acceptedK8sTypes := regexp.MustCompile(`^(Secret|ConfigMap|Deployment|DaemonSet|StatefulSet|Job|CronJob|Service)$`)
 	fileAsString := string(fileR[:])
 	sepYamlfiles := strings.Split(fileAsString, "---")
 
@@ -199,7 +204,7 @@ func (api *API) parseK8SYaml(fileR []byte) []runtime.Object {
 			api.log.Error("K8s object types not supported!", log.Any("Skipping object with type: %s", kind))
 		} else if kind == TypeSecret || kind == TypeConfig {
 			res = append(res, obj)
-		} else if kind == TypeDeploy || kind == TypeDaemonset || kind == TypeJob {
+		} else if kind == TypeDeploy || kind == TypeDaemonset || kind == TypeStatefulSet || kind == TypeJob || kind == TypeCronJob {
 			deploys = append(deploys, obj)
 		} else {
 			services = append(services, obj)
@@ -634,17 +639,23 @@ func validateConfig(c *specV1.Configuration) error {
 	if err != nil {
 		return err
 	}
-	for k, _ := range c.Data {
+	for k, v := range c.Data {
 		err = common.ValidateKeyValue(k)
 		if err != nil {
 			return err
 		}
+		if strings.HasPrefix(k, common.ConfigObjectPrefix) {
+			continue
+		}
+		if err = common.ValidateNodePlaceholders(v); err != nil {
+			return common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+		}
 	}
 	return nil
 }
 
 // app resource
-func (api *API) generateApplication(ns string, r runtime.Object) (interface{}, error) {
+func (api *API) generateApplication(ns string, r runtime.Object) (*models.ApplicationView, error) {
 	app, err := api.generateAppData(ns, r)
 	if err != nil {
 		return nil, err
@@ -674,7 +685,7 @@ func (api *API) generateApplication(ns string, r runtime.Object) (interface{}, e
 	return api.ToApplicationView(app)
 }
 
-func (api *API) updateApplication(ns string, r runtime.Object) (interface{}, error) {
+func (api *API) updateApplication(ns string, r runtime.Object) (*models.ApplicationView, error) {
 	app, err := api.generateAppData(ns, r)
 	if err != nil {
 		return nil, err
@@ -691,28 +702,41 @@ func (api *API) updateApplication(ns string, r runtime.Object) (interface{}, err
 			return nil, err
 		}
 	}
+	if err = inheritYamlApp(oldApp, app); err != nil {
+		return nil, err
+	}
+
+	app, err = api.Facade.UpdateApp(ns, oldApp, app, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return api.ToApplicationView(app)
+}
+
+// inheritYamlApp checks the app generated from yaml against the old one and inherits the fields not in yaml
+func inheritYamlApp(oldApp, app *specV1.Application) error {
 	// sys app: core、init、function is not visible
 	if common.ValidIsInvisible(oldApp.Labels) {
-		return nil, common.Error(common.ErrResourceInvisible, common.Field("type", common.APP), common.Field("name", oldApp.Name))
+		return common.Error(common.ErrResourceInvisible, common.Field("type", common.APP), common.Field("name", oldApp.Name))
 	}
 
 	// labels and Selector can't be modified of sys apps
 	if CheckIsSysResources(oldApp.Labels) &&
 		(oldApp.Selector != app.Selector || !reflect.DeepEqual(oldApp.Labels, app.Labels) || !app.System) {
-		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "selector，labels or system field can't be modified of sys apps"))
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", "selector，labels or system field can't be modified of sys apps"))
 	}
 
 	app.Version = oldApp.Version
 	app.CreationTimestamp = oldApp.CreationTimestamp
 	app.Selector = oldApp.Selector
 	app.NodeSelector = oldApp.NodeSelector
-
-	app, err = api.Facade.UpdateApp(ns, oldApp, app, nil)
-	if err != nil {
-		return nil, errors.Trace(err)
+	// the cron of app isn't set by yaml, the cron status of the deployed app is kept to leave it unchanged
+	if oldApp.CronStatus != specV1.CronWait {
+		app.CronStatus = oldApp.CronStatus
+		app.CronTime = oldApp.CronTime
 	}
-
-	return api.ToApplicationView(app)
+	return nil
 }
 
 func (api *API) deleteApplication(ns string, r runtime.Object) (string, error) {
@@ -732,12 +756,20 @@ func (api *API) deleteApplication(ns string, r runtime.Object) (string, error) {
 			return "", common.Error(common.ErrRequestParamInvalid, common.Field("error", "k8s daemonset typecasting failed"))
 		}
 		name = ds.Name
+	case TypeStatefulSet:
+		sts, ok := r.(*appv1.StatefulSet)
+		if !ok {
+			return "", common.Error(common.ErrRequestParamInvalid, common.Field("error", "k8s statefulset typecasting failed"))
+		}
+		name = sts.Name
 	case TypeJob:
 		job, ok := r.(*batchv1.Job)
 		if !ok {
 			return "", common.Error(common.ErrRequestParamInvalid, common.Field("error", "k8s job typecasting failed"))
 		}
 		name = job.Name
+	case TypeCronJob:
+		return "", errCronJobUnsupported()
 	}
 
 	err = common.ValidateResourceName(name)
@@ -786,6 +818,15 @@ func (api *API) generateAppData(ns string, r runtime.Object) (*specV1.Applicatio
 		if err != nil {
 			return nil, err
 		}
+	case TypeStatefulSet:
+		sts, ok := r.(*appv1.StatefulSet)
+		if !ok {
+			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "k8s statefulset typecasting failed"))
+		}
+		app, err = api.generateStatefulSetApp(ns, sts)
+		if err != nil {
+			return nil, err
+		}
 	case TypeJob:
 		job, ok := r.(*batchv1.Job)
 		if !ok {
@@ -795,10 +836,17 @@ func (api *API) generateAppData(ns string, r runtime.Object) (*specV1.Applicatio
 		if err != nil {
 			return nil, err
 		}
+	case TypeCronJob:
+		return nil, errCronJobUnsupported()
 	}
 	return app, nil
 }
 
+// errCronJobUnsupported rejects the cronjob, whose schedule can't be parsed the same as kubernetes
+func errCronJobUnsupported() error {
+	return common.Error(common.ErrRequestParamInvalid, common.Field("error", "cronjob isn't supported, please use the cron of application instead"))
+}
+
 func (api *API) generateDeployApp(ns string, deploy *appv1.Deployment) (*specV1.Application, error) {
 	app, err := api.generateCommonAppInfo(ns, &deploy.Spec.Template.Spec)
 	if err != nil {
@@ -854,6 +902,49 @@ func (api *API) generateDaemonSetApp(ns string, ds *appv1.DaemonSet) (*specV1.Ap
 	return app, nil
 }
 
+func (api *API) generateStatefulSetApp(ns string, sts *appv1.StatefulSet) (*specV1.Application, error) {
+	app, err := api.generateCommonAppInfo(ns, &sts.Spec.Template.Spec)
+	if err != nil {
+		return nil, err
+	}
+	app.Name = sts.Name
+	app.Namespace = ns
+	if sts.Spec.Replicas != nil {
+		app.Replica = int(*sts.Spec.Replicas)
+	} else {
+		app.Replica = 1
+	}
+
+	labels := map[string]string{}
+	for k, v := range sts.Labels {
+		labels[k] = v
+	}
+	app.Labels = labels
+
+	// the volume claim templates are mapped to the host paths of node, which are shared by the replicas on the same node
+	for _, c := range sts.Spec.VolumeClaimTemplates {
+		app.Volumes = append(app.Volumes, specV1.Volume{
+			Name: c.Name,
+			VolumeSource: specV1.VolumeSource{
+				HostPath: &specV1.HostPathVolumeSource{
+					Path: claimHostPath(sts.Name, c.Name),
+					Type: string(corev1.HostPathDirectoryOrCreate),
+				},
+			},
+		})
+	}
+
+	app.Type = specV1.AppTypeContainer
+	app.Mode = context.RunModeKube
+	app.Workload = specV1.WorkloadStatefulSet
+
+	app.Labels = common.AddSystemLabel(app.Labels, map[string]string{
+		common.LabelAppMode: app.Mode,
+	})
+
+	return app, nil
+}
+
 func (api *API) generateJobApp(ns string, job *batchv1.Job) (*specV1.Application, error) {
 	app, err := api.generateCommonAppInfo(ns, &job.Spec.Template.Spec)
 	if err != nil {
@@ -1214,3 +1305,78 @@ func resetAppPort(app *specV1.Application, svc *corev1.Service) {
 		}
 	}
 }
+
+// claimHostPath returns the host path keeping the data of the volume claim of statefulset
+func claimHostPath(app, claim string) string {
+	return path.Join(context.DefaultHostPathLib, "app-data", app, claim)
+}
+
+// unsupportedAppFields returns the fields of the workload which are set but can't be translated into the app
+func unsupportedAppFields(r runtime.Object) []string {
+	var fields []string
+	switch o := r.(type) {
+	case *appv1.Deployment:
+		if o.Spec.Strategy.Type != "" || o.Spec.Strategy.RollingUpdate != nil {
+			fields = append(fields, "spec.strategy")
+		}
+		fields = append(fields, unsupportedPodFields("spec.template.spec", &o.Spec.Template.Spec)...)
+	case *appv1.DaemonSet:
+		if o.Spec.UpdateStrategy.Type != "" || o.Spec.UpdateStrategy.RollingUpdate != nil {
+			fields = append(fields, "spec.updateStrategy")
+		}
+		fields = append(fields, unsupportedPodFields("spec.template.spec", &o.Spec.Template.Spec)...)
+	case *appv1.StatefulSet:
+		for _, c := range o.Spec.VolumeClaimTemplates {
+			fields = append(fields, "spec.volumeClaimTemplates["+c.Name+"] (mapped to hostPath)")
+		}
+		if o.Spec.ServiceName != "" {
+			fields = append(fields, "spec.serviceName")
+		}
+		if o.Spec.PodManagementPolicy != "" {
+			fields = append(fields, "spec.podManagementPolicy")
+		}
+		if o.Spec.UpdateStrategy.Type != "" || o.Spec.UpdateStrategy.RollingUpdate != nil {
+			fields = append(fields, "spec.updateStrategy")
+		}
+		fields = append(fields, unsupportedPodFields("spec.template.spec", &o.Spec.Template.Spec)...)
+	case *batchv1.Job:
+		fields = append(fields, unsupportedJobFields("spec", &o.Spec)...)
+	}
+	return fields
+}
+
+func unsupportedJobFields(prefix string, spec *batchv1.JobSpec) []string {
+	var fields []string
+	if spec.ActiveDeadlineSeconds != nil {
+		fields = append(fields, prefix+".activeDeadlineSeconds")
+	}
+	if spec.TTLSecondsAfterFinished != nil {
+		fields = append(fields, prefix+".ttlSecondsAfterFinished")
+	}
+	return append(fields, unsupportedPodFields(prefix+".template.spec", &spec.Template.Spec)...)
+}
+
+func unsupportedPodFields(prefix string, spec *corev1.PodSpec) []string {
+	var fields []string
+	for _, v := range spec.Volumes {
+		if v.ConfigMap == nil && v.Secret == nil && v.HostPath == nil && v.EmptyDir == nil {
+			fields = append(fields, prefix+".volumes["+v.Name+"]")
+		}
+	}
+	set := map[string]bool{
+		"nodeSelector":       len(spec.NodeSelector) > 0,
+		"affinity":           spec.Affinity != nil,
+		"tolerations":        len(spec.Tolerations) > 0,
+		"serviceAccountName": spec.ServiceAccountName != "",
+		"securityContext":    spec.SecurityContext != nil && !reflect.DeepEqual(*spec.SecurityContext, corev1.PodSecurityContext{}),
+		"hostPID":            spec.HostPID,
+		"hostIPC":            spec.HostIPC,
+		"priorityClassName":  spec.PriorityClassName != "",
+	}
+	for _, k := range []string{"nodeSelector", "affinity", "tolerations", "serviceAccountName", "securityContext", "hostPID", "hostIPC", "priorityClassName"} {
+		if set[k] {
+			fields = append(fields, prefix+"."+k)
+		}
+	}
+	return fields
+}
@@ -689,6 +689,144 @@ func TestAPI_CreateJobApp(t *testing.T) {
 	assert.DeepEqual(t, &aaa, appView)
 }
 
+func TestAPI_CreateStatefulSetApp(t *testing.T) {
+	api, router, mockCtl := initYamlAPI(t)
+	defer mockCtl.Finish()
+
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sConfig := ms.NewMockConfigService(mockCtl)
+	sSecret := ms.NewMockSecretService(mockCtl)
+	sFacade := mf.NewMockFacade(mockCtl)
+
+	api.AppCombinedService = &service.AppCombinedService{
+		App:    sApp,
+		Config: sConfig,
+		Secret: sSecret,
+	}
+	api.Facade = sFacade
+
+	testAppSts := `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: web
+  labels:
+    app: web
+spec:
+  serviceName: nginx
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:latest
+        volumeMounts:
+        - name: www
+          mountPath: /usr/share/nginx/html
+        - name: cache-volume
+          mountPath: /cache
+      volumes:
+      - name: cache-volume
+        emptyDir: {}
+  volumeClaimTemplates:
+  - metadata:
+      name: www
+    spec:
+      accessModes: ["ReadWriteOnce"]`
+
+	sApp.EXPECT().Get("default", "web", "").Return(nil, nil).Times(1)
+	sFacade.EXPECT().CreateApp("default", nil, gomock.Any(), nil).DoAndReturn(
+		func(_ string, _ *specV1.Application, app *specV1.Application, _ []specV1.Configuration) (*specV1.Application, error) {
+			assert.Equal(t, specV1.WorkloadStatefulSet, app.Workload)
+			assert.Equal(t, 2, app.Replica)
+			assert.DeepEqual(t, []specV1.VolumeMount{
+				{Name: "www", MountPath: "/usr/share/nginx/html"},
+				{Name: "cache-volume", MountPath: "/cache"},
+			}, app.Services[0].VolumeMounts)
+			// the claim is mapped to the host path
+			assert.DeepEqual(t, &specV1.HostPathVolumeSource{Path: "/var/lib/baetyl/app-data/web/www", Type: "DirectoryOrCreate"},
+				app.Volumes[len(app.Volumes)-1].HostPath)
+			return app, nil
+		}).Times(1)
+
+	re := httptest.NewRecorder()
+	router.ServeHTTP(re, newYamlRequest(testAppSts))
+	assert.Equal(t, http.StatusOK, re.Code)
+
+	var res gmodels.YamlResourceList
+	err := json.Unmarshal(re.Body.Bytes(), &res)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(res.Items))
+	assert.DeepEqual(t, []gmodels.YamlUnsupported{{
+		Kind:   "StatefulSet",
+		Name:   "web",
+		Fields: []string{"spec.volumeClaimTemplates[www] (mapped to hostPath)", "spec.serviceName"},
+	}}, res.Unsupported)
+}
+
+func TestAPI_RejectCronJobApp(t *testing.T) {
+	api, router, mockCtl := initYamlAPI(t)
+	defer mockCtl.Finish()
+
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sConfig := ms.NewMockConfigService(mockCtl)
+	sSecret := ms.NewMockSecretService(mockCtl)
+	sFacade := mf.NewMockFacade(mockCtl)
+
+	api.AppCombinedService = &service.AppCombinedService{
+		App:    sApp,
+		Config: sConfig,
+		Secret: sSecret,
+	}
+	api.Facade = sFacade
+
+	testAppCronJob := `
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: hello
+spec:
+  schedule: "*/5 * * * *"
+  timeZone: Etc/UTC
+  concurrencyPolicy: Forbid
+  jobTemplate:
+    spec:
+      backoffLimit: 2
+      activeDeadlineSeconds: 60
+      template:
+        spec:
+          containers:
+          - name: hello
+            image: busybox
+            command: ["date"]
+          restartPolicy: OnFailure
+          nodeSelector:
+            disk: ssd`
+
+	// the cronjob is rejected
+	re := httptest.NewRecorder()
+	router.ServeHTTP(re, newYamlRequest(testAppCronJob))
+	assert.Equal(t, http.StatusBadRequest, re.Code)
+	assert.Check(t, strings.Contains(re.Body.String(), "cronjob isn't supported"))
+
+	req := newYamlRequest(testAppCronJob)
+	req.URL.Path = "/v1/yaml/delete"
+	re = httptest.NewRecorder()
+	router.ServeHTTP(re, req)
+	assert.Equal(t, http.StatusBadRequest, re.Code)
+}
+
+func newYamlRequest(content string) *http.Request {
+	buf := new(bytes.Buffer)
+	w := multipart.NewWriter(buf)
+	fw, _ := w.CreateFormFile("file", "app.yaml")
+	io.Copy(fw, strings.NewReader(content))
+	w.Close()
+	req, _ := http.NewRequest(http.MethodPost, "/v1/yaml", buf)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req
+}
+
 func TestAPI_UpdateDeployApp(t *testing.T) {
 	api, router, mockCtl := initYamlAPI(t)
 	defer mockCtl.Finish()
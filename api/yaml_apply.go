@@ -0,0 +1,343 @@
+package api
+
+import (
+	"bytes"
+	"reflect"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/json"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// ApplyYamlResource creates or updates each object of the yaml file, the failed object doesn't stop the others.
+// The resources with the label of apply set but not in the file are pruned if required.
+func (api *API) ApplyYamlResource(c *common.Context) (interface{}, error) {
+	ns, userID := c.GetNamespace(), c.GetUser().ID
+	opts := new(models.YamlApplyOptions)
+	if err := c.BindQuery(opts); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	if opts.Prune && opts.ApplySet == "" {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "applySet is required to prune"))
+	}
+	resources, err := api.parseYamlFileAndCheck(c)
+	if err != nil {
+		return nil, err
+	}
+	return api.applyYamlResources(ns, userID, resources, opts)
+}
+
+// applyYamlResources applies the resources parsed from yaml
+func (api *API) applyYamlResources(ns, userID string, resources []runtime.Object, opts *models.YamlApplyOptions) (*models.YamlApplyResultList, error) {
+	var svcs []*corev1.Service
+	for _, r := range resources {
+		if svc, ok := r.(*corev1.Service); ok {
+			svcs = append(svcs, svc)
+		}
+	}
+
+	res := &models.YamlApplyResultList{DryRun: opts.DryRun, Items: []models.YamlApplyResult{}}
+	// the resources in the file, which aren't pruned
+	applied := map[string]bool{}
+	// the secrets and configs are ahead of apps and services after parsing
+	for _, r := range resources {
+		kind := r.GetObjectKind().GroupVersionKind().Kind
+		obj, err := meta.Accessor(r)
+		if err != nil {
+			res.Add(kind, "", "", err)
+			continue
+		}
+		var action string
+		switch kind {
+		case TypeSecret:
+			applied[TypeSecret+"/"+obj.GetName()] = true
+			action, err = api.applySecret(ns, r.(*corev1.Secret), opts)
+			res.Add(kind, obj.GetName(), action, err)
+		case TypeConfig:
+			applied[TypeConfig+"/"+obj.GetName()] = true
+			action, err = api.applyConfig(ns, userID, r.(*corev1.ConfigMap), opts)
+			res.Add(kind, obj.GetName(), action, err)
+		case TypeDeploy, TypeDaemonset, TypeStatefulSet, TypeJob, TypeCronJob:
+			applied["app/"+obj.GetName()] = true
+			action, err = api.applyApplication(ns, r, svcs, opts)
+			res.Add(kind, obj.GetName(), action, err).Unsupported = unsupportedAppFields(r)
+		case TypeService:
+			action, err = api.applyService(ns, r.(*corev1.Service), opts)
+			res.Add(kind, obj.GetName(), action, err)
+		}
+	}
+
+	if opts.Prune {
+		if err := api.pruneYamlResources(ns, applied, opts, res); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (api *API) applySecret(ns string, sec *corev1.Secret, opts *models.YamlApplyOptions) (string, error) {
+	secret, err := api.generateSecretResource(ns, sec)
+	if err != nil {
+		return "", err
+	}
+	secret.Labels = addApplySetLabel(secret.Labels, opts.ApplySet)
+
+	oldSecret, err := api.Secret.Get(ns, secret.Name, "")
+	if err != nil {
+		if e, ok := err.(errors.Coder); !ok || e.Code() != common.ErrResourceNotFound {
+			return "", err
+		}
+		if opts.DryRun {
+			return models.YamlApplyCreated, nil
+		}
+		_, err = api.Facade.CreateSecret(ns, secret)
+		return models.YamlApplyCreated, err
+	}
+
+	if reflect.DeepEqual(oldSecret.Labels, secret.Labels) && api.ToSecretView(oldSecret).Equal(api.ToSecretView(secret)) {
+		return models.YamlApplyUnchanged, nil
+	}
+	if opts.DryRun {
+		return models.YamlApplyUpdated, nil
+	}
+	secret.Version = oldSecret.Version
+	secret.UpdateTimestamp = time.Now()
+	_, err = api.Facade.UpdateSecret(ns, secret)
+	return models.YamlApplyUpdated, err
+}
+
+func (api *API) applyConfig(ns, userID string, cfg *corev1.ConfigMap, opts *models.YamlApplyOptions) (string, error) {
+	config := &specV1.Configuration{
+		Namespace: ns,
+		Name:      cfg.Name,
+		Labels:    addApplySetLabel(cfg.Labels, opts.ApplySet),
+		Data:      map[string]string{},
+	}
+	if err := generateConfigData(userID, cfg.Data, config.Data); err != nil {
+		return "", err
+	}
+	if err := validateConfig(config); err != nil {
+		return "", err
+	}
+
+	oldConfig, err := api.Config.Get(nil, ns, cfg.Name, "")
+	if err != nil {
+		if e, ok := err.(errors.Coder); !ok || e.Code() != common.ErrResourceNotFound {
+			return "", err
+		}
+		if opts.DryRun {
+			return models.YamlApplyCreated, nil
+		}
+		_, err = api.Facade.CreateConfig(ns, config)
+		return models.YamlApplyCreated, err
+	}
+
+	// labels can't be modified of sys apps
+	if CheckIsSysResources(oldConfig.Labels) && !reflect.DeepEqual(oldConfig.Labels, config.Labels) {
+		return "", common.Error(common.ErrRequestParamInvalid, common.Field("error", "labels can't be modified of sys apps"))
+	}
+	if models.EqualConfig(oldConfig, config) {
+		return models.YamlApplyUnchanged, nil
+	}
+	if opts.DryRun {
+		return models.YamlApplyUpdated, nil
+	}
+	config.Version = oldConfig.Version
+	config.UpdateTimestamp = time.Now()
+	config.CreationTimestamp = oldConfig.CreationTimestamp
+	_, err = api.Facade.UpdateConfig(ns, config)
+	return models.YamlApplyUpdated, err
+}
+
+func (api *API) applyApplication(ns string, r runtime.Object, svcs []*corev1.Service, opts *models.YamlApplyOptions) (string, error) {
+	app, err := api.generateAppData(ns, r)
+	if err != nil {
+		return "", err
+	}
+	if err = validateApp(app); err != nil {
+		return "", err
+	}
+	app.Labels = common.AddSystemLabel(addApplySetLabel(app.Labels, opts.ApplySet), opts.AppLabels)
+	// the ports of services in the file are set ahead, otherwise the app is changed again by the services
+	for _, svc := range svcs {
+		if labels.SelectorFromSet(svc.Spec.Selector).Matches(labels.Set(app.Labels)) {
+			updateAppPort(app, svc)
+		}
+	}
+
+	oldApp, err := api.App.Get(ns, app.Name, "")
+	if err != nil {
+		if e, ok := err.(errors.Coder); !ok || e.Code() != common.ErrResourceNotFound {
+			return "", err
+		}
+		if opts.DryRun {
+			return models.YamlApplyCreated, nil
+		}
+		_, err = api.Facade.CreateApp(ns, nil, app, nil)
+		return models.YamlApplyCreated, err
+	}
+
+	if err = inheritYamlApp(oldApp, app); err != nil {
+		return "", err
+	}
+	if equalYamlApp(oldApp, app) {
+		return models.YamlApplyUnchanged, nil
+	}
+	if opts.DryRun {
+		return models.YamlApplyUpdated, nil
+	}
+	_, err = api.Facade.UpdateApp(ns, oldApp, app, nil)
+	return models.YamlApplyUpdated, errors.Trace(err)
+}
+
+func (api *API) applyService(ns string, svc *corev1.Service, opts *models.YamlApplyOptions) (string, error) {
+	apps, err := api.App.List(ns, &models.ListOptions{LabelSelector: labels.SelectorFromSet(svc.Spec.Selector).String()})
+	if err != nil {
+		return "", err
+	}
+	action := models.YamlApplyUnchanged
+	for _, item := range apps.Items {
+		app, err := api.App.Get(ns, item.Name, "")
+		if err != nil {
+			return "", err
+		}
+		ports := appPorts(app)
+		updateAppPort(app, svc)
+		if reflect.DeepEqual(ports, appPorts(app)) {
+			continue
+		}
+		action = models.YamlApplyUpdated
+		if opts.DryRun {
+			continue
+		}
+		if _, err = api.App.Update(nil, ns, app); err != nil {
+			return "", err
+		}
+	}
+	return action, nil
+}
+
+// pruneYamlResources deletes the apps, configs and secrets of apply set which aren't applied
+func (api *API) pruneYamlResources(ns string, applied map[string]bool, opts *models.YamlApplyOptions, res *models.YamlApplyResultList) error {
+	listOptions := &models.ListOptions{LabelSelector: common.LabelApplySet + "=" + opts.ApplySet}
+
+	apps, err := api.App.List(ns, listOptions)
+	if err != nil {
+		return err
+	}
+	for _, item := range apps.Items {
+		if applied["app/"+item.Name] {
+			continue
+		}
+		kind, err := api.pruneApplication(ns, item.Name, opts.DryRun)
+		res.Add(kind, item.Name, models.YamlApplyPruned, err)
+	}
+
+	configs, err := api.Config.List(ns, listOptions)
+	if err != nil {
+		return err
+	}
+	for _, item := range configs.Items {
+		if applied[TypeConfig+"/"+item.Name] {
+			continue
+		}
+		res.Add(TypeConfig, item.Name, models.YamlApplyPruned, api.pruneConfig(ns, item.Name, opts.DryRun))
+	}
+
+	secrets, err := api.Secret.List(ns, listOptions)
+	if err != nil {
+		return err
+	}
+	for _, item := range secrets.Items {
+		if applied[TypeSecret+"/"+item.Name] {
+			continue
+		}
+		err = nil
+		if !opts.DryRun {
+			secretType := "secret"
+			switch item.Labels[specV1.SecretLabel] {
+			case specV1.SecretRegistry:
+				secretType = "registry"
+			case specV1.SecretCertificate:
+				secretType = "certificate"
+			}
+			_, err = api.DeleteSecretResource(ns, item.Name, secretType)
+		}
+		res.Add(TypeSecret, item.Name, models.YamlApplyPruned, err)
+	}
+	return nil
+}
+
+// pruneApplication deletes the app and returns the kind of its workload
+func (api *API) pruneApplication(ns, name string, dryRun bool) (string, error) {
+	app, err := api.App.Get(ns, name, "")
+	if err != nil {
+		return TypeDeploy, err
+	}
+	kind := TypeDeploy
+	switch app.Workload {
+	case specV1.WorkloadDaemonSet:
+		kind = TypeDaemonset
+	case specV1.WorkloadStatefulSet:
+		kind = TypeStatefulSet
+	case specV1.WorkloadJob:
+		kind = TypeJob
+	}
+	if dryRun {
+		return kind, nil
+	}
+	if canDelete, err := api.IsAppCanDelete(ns, name); err != nil {
+		return kind, err
+	} else if !canDelete {
+		return kind, common.Error(common.ErrAppReferencedByNode, common.Field("name", name))
+	}
+	return kind, api.Facade.DeleteApp(ns, name, app)
+}
+
+func (api *API) pruneConfig(ns, name string, dryRun bool) error {
+	if dryRun {
+		return nil
+	}
+	appNames, err := api.Index.ListAppIndexByConfig(ns, name)
+	if err != nil {
+		return err
+	}
+	if len(appNames) > 0 {
+		return common.Error(common.ErrResourceHasBeenUsed, common.Field("type", "config"), common.Field("name", name))
+	}
+	return api.Facade.DeleteConfig(ns, name)
+}
+
+func addApplySetLabel(ls map[string]string, applySet string) map[string]string {
+	if applySet == "" {
+		return ls
+	}
+	return common.AddSystemLabel(ls, map[string]string{common.LabelApplySet: applySet})
+}
+
+// equalYamlApp compares the fields of apps which are generated from yaml, the nil and empty slices are equal
+// since the app is stored as json
+func equalYamlApp(oldApp, app *specV1.Application) bool {
+	fields := func(a *specV1.Application) []byte {
+		data, _ := json.Marshal([]interface{}{a.Labels, a.Replica, a.Workload, a.HostNetwork, a.JobConfig,
+			a.InitServices, a.Services, a.Volumes, a.CronStatus, a.CronTime.UTC()})
+		return data
+	}
+	return bytes.Equal(fields(oldApp), fields(app))
+}
+
+func appPorts(app *specV1.Application) [][]specV1.ContainerPort {
+	var ports [][]specV1.ContainerPort
+	for _, svc := range app.Services {
+		ports = append(ports, append([]specV1.ContainerPort{}, svc.Ports...))
+	}
+	return ports
+}
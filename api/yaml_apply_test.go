@@ -0,0 +1,207 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	mf "github.com/baetyl/baetyl-cloud/v2/mock/facade"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+const testApplyYaml = `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: sec
+data:
+  token: YWJj
+type: Opaque
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cfg
+data:
+  conf: a
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  labels:
+    app: nginx
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:latest
+        ports:
+        - containerPort: 80
+        volumeMounts:
+        - name: cfg
+          mountPath: /etc/config
+      volumes:
+      - name: cfg
+        configMap:
+          name: cfg
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: nginx
+spec:
+  type: NodePort
+  selector:
+    app: nginx
+  ports:
+  - port: 80
+    targetPort: 80
+    nodePort: 30080`
+
+func newApplyRequest(query string) *http.Request {
+	buf := new(bytes.Buffer)
+	w := multipart.NewWriter(buf)
+	fw, _ := w.CreateFormFile("file", "app.yaml")
+	io.Copy(fw, strings.NewReader(testApplyYaml))
+	w.Close()
+	req, _ := http.NewRequest(http.MethodPost, "/v1/yaml/apply"+query, buf)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req
+}
+
+func TestAPI_ApplyYamlResource(t *testing.T) {
+	api, router, mockCtl := initYamlAPI(t)
+	defer mockCtl.Finish()
+
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sConfig := ms.NewMockConfigService(mockCtl)
+	sSecret := ms.NewMockSecretService(mockCtl)
+	sIndex := ms.NewMockIndexService(mockCtl)
+	sFacade := mf.NewMockFacade(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{
+		App:    sApp,
+		Config: sConfig,
+		Secret: sSecret,
+	}
+	api.Index = sIndex
+	api.Facade = sFacade
+
+	notFound := common.Error(common.ErrResourceNotFound, common.Field("type", "app"), common.Field("name", "x"))
+	cfg := &specV1.Configuration{Name: "cfg", Namespace: "default", Data: map[string]string{"conf": "a"}}
+	apply := func(query string) *models.YamlApplyResultList {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newApplyRequest(query))
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		res := new(models.YamlApplyResultList)
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+		return res
+	}
+	actions := func(res *models.YamlApplyResultList) []string {
+		var as []string
+		for _, item := range res.Items {
+			as = append(as, fmt.Sprintf("%s/%s:%s", item.Kind, item.Name, item.Action))
+		}
+		return as
+	}
+
+	// dry run creates nothing
+	sSecret.EXPECT().Get("default", "sec", "").Return(nil, notFound).Times(1)
+	sConfig.EXPECT().Get(nil, "default", "cfg", "").Return(nil, notFound).Times(1)
+	sConfig.EXPECT().Get(nil, "default", "cfg", "").Return(cfg, nil).Times(1)
+	sApp.EXPECT().Get("default", "nginx", "").Return(nil, notFound).Times(1)
+	sApp.EXPECT().List("default", gomock.Any()).Return(&models.ApplicationList{}, nil).Times(1)
+	res := apply("?dryRun=true")
+	assert.True(t, res.DryRun)
+	assert.Equal(t, []string{"Secret/sec:created", "ConfigMap/cfg:created", "Deployment/nginx:created", "Service/nginx:unchanged"}, actions(res))
+
+	// the app failed doesn't stop the others, and the ports of service are set ahead
+	sSecret.EXPECT().Get("default", "sec", "").Return(&specV1.Secret{Name: "sec", Namespace: "default",
+		Labels: map[string]string{specV1.SecretLabel: specV1.SecretConfig}, Data: map[string][]byte{"token": []byte("abc")}}, nil).Times(1)
+	sConfig.EXPECT().Get(nil, "default", "cfg", "").Return(&specV1.Configuration{Name: "cfg", Data: map[string]string{"conf": "b"}}, nil).Times(1)
+	sFacade.EXPECT().UpdateConfig("default", gomock.Any()).Return(cfg, nil).Times(1)
+	sConfig.EXPECT().Get(nil, "default", "cfg", "").Return(cfg, nil).Times(1)
+	sApp.EXPECT().Get("default", "nginx", "").Return(nil, notFound).Times(1)
+	sFacade.EXPECT().CreateApp("default", nil, gomock.Any(), nil).DoAndReturn(
+		func(_ string, _ *specV1.Application, app *specV1.Application, _ []specV1.Configuration) (*specV1.Application, error) {
+			assert.Equal(t, "NodePort", app.Services[0].Ports[0].ServiceType)
+			assert.Equal(t, int32(30080), app.Services[0].Ports[0].NodePort)
+			return nil, fmt.Errorf("failed to create app")
+		}).Times(1)
+	sApp.EXPECT().List("default", gomock.Any()).Return(&models.ApplicationList{}, nil).Times(1)
+	res = apply("")
+	assert.Equal(t, []string{"Secret/sec:unchanged", "ConfigMap/cfg:updated", "Deployment/nginx:failed", "Service/nginx:unchanged"}, actions(res))
+	assert.Equal(t, "failed to create app", res.Items[2].Error)
+
+	// the resources of apply set not in the file are pruned
+	labels := map[string]string{"app": "nginx", common.LabelAppMode: "kube", common.LabelApplySet: "demo"}
+	sSecret.EXPECT().Get("default", "sec", "").Return(&specV1.Secret{Name: "sec", Namespace: "default",
+		Labels: map[string]string{specV1.SecretLabel: specV1.SecretConfig, common.LabelApplySet: "demo"},
+		Data:   map[string][]byte{"token": []byte("abc")}}, nil).Times(1)
+	sConfig.EXPECT().Get(nil, "default", "cfg", "").Return(&specV1.Configuration{Name: "cfg",
+		Labels: map[string]string{common.LabelApplySet: "demo"}, Data: map[string]string{"conf": "a"}}, nil).Times(1)
+	sConfig.EXPECT().Get(nil, "default", "cfg", "").Return(cfg, nil).Times(1)
+	oldApp := &specV1.Application{
+		Name:      "nginx",
+		Namespace: "default",
+		Version:   "1",
+		Labels:    labels,
+		Type:      specV1.AppTypeContainer,
+		Mode:      "kube",
+		Workload:  specV1.WorkloadDeployment,
+		Replica:   1,
+		Services: []specV1.Service{{
+			Name:         "nginx",
+			Image:        "nginx:latest",
+			Ports:        []specV1.ContainerPort{{ContainerPort: 80, ServiceType: "NodePort", NodePort: 30080}},
+			VolumeMounts: []specV1.VolumeMount{{Name: "cfg", MountPath: "/etc/config"}},
+			Resources:    &specV1.Resources{},
+		}},
+		Volumes: []specV1.Volume{{Name: "cfg", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "cfg"}}}},
+	}
+	sApp.EXPECT().Get("default", "nginx", "").DoAndReturn(func(_, _, _ string) (*specV1.Application, error) {
+		app := *oldApp
+		app.Services = []specV1.Service{oldApp.Services[0]}
+		app.Services[0].Ports = append([]specV1.ContainerPort{}, oldApp.Services[0].Ports...)
+		return &app, nil
+	}).Times(2)
+	sApp.EXPECT().List("default", &models.ListOptions{LabelSelector: "app=nginx"}).Return(&models.ApplicationList{
+		Items: []models.AppItem{{Name: "nginx"}},
+	}, nil).Times(1)
+	sApp.EXPECT().List("default", &models.ListOptions{LabelSelector: common.LabelApplySet + "=demo"}).Return(&models.ApplicationList{
+		Items: []models.AppItem{{Name: "nginx"}, {Name: "old"}},
+	}, nil).Times(1)
+	sApp.EXPECT().Get("default", "old", "").Return(&specV1.Application{Name: "old", Workload: specV1.WorkloadDaemonSet}, nil).Times(1)
+	sFacade.EXPECT().DeleteApp("default", "old", gomock.Any()).Return(nil).Times(1)
+	sConfig.EXPECT().List("default", gomock.Any()).Return(&models.ConfigurationList{
+		Items: []specV1.Configuration{{Name: "cfg"}, {Name: "old-cfg"}},
+	}, nil).Times(1)
+	sIndex.EXPECT().ListAppIndexByConfig("default", "old-cfg").Return(nil, nil).Times(1)
+	sFacade.EXPECT().DeleteConfig("default", "old-cfg").Return(nil).Times(1)
+	sSecret.EXPECT().List("default", gomock.Any()).Return(&models.SecretList{
+		Items: []specV1.Secret{{Name: "sec"}},
+	}, nil).Times(1)
+	res = apply("?prune=true&applySet=demo")
+	assert.Equal(t, []string{"Secret/sec:unchanged", "ConfigMap/cfg:unchanged", "Deployment/nginx:unchanged",
+		"Service/nginx:unchanged", "DaemonSet/old:pruned", "ConfigMap/old-cfg:pruned"}, actions(res))
+
+	// prune requires the apply set
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newApplyRequest("?prune=true"))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
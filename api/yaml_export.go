@@ -0,0 +1,356 @@
+package api
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"gopkg.in/yaml.v2"
+	appv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sjson "k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/kubectl/pkg/scheme"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// ExportYamlApplication exports the app with its configs, secrets and services as the k8s yaml manifests,
+// which can be imported by CreateYamlResource
+func (api *API) ExportYamlApplication(c *common.Context) (interface{}, error) {
+	ns, n := c.GetNamespace(), c.GetNameFromParam()
+	app, err := api.App.Get(ns, n, "")
+	if err != nil {
+		return nil, err
+	}
+	objs, err := api.generateYamlObjects(ns, app)
+	if err != nil {
+		return nil, err
+	}
+	serializer := k8sjson.NewSerializerWithOptions(k8sjson.DefaultMetaFactory, scheme.Scheme, scheme.Scheme,
+		k8sjson.SerializerOptions{Yaml: true})
+	buf := new(bytes.Buffer)
+	for i, obj := range objs {
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		if err = serializer.Encode(obj, buf); err != nil {
+			return nil, common.Error(common.ErrK8S, common.Field("error", err.Error()))
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// generateYamlObjects generates the secrets, configmaps, workload and services of app in the order of importing
+func (api *API) generateYamlObjects(ns string, app *specV1.Application) ([]runtime.Object, error) {
+	if app.Type != specV1.AppTypeContainer {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "only the container app can be exported"))
+	}
+	var secrets, configs []runtime.Object
+	var volumes []corev1.Volume
+	var pullSecrets []corev1.LocalObjectReference
+	for _, v := range app.Volumes {
+		vol := corev1.Volume{Name: v.Name}
+		switch {
+		case v.Config != nil:
+			cfg, err := api.Config.Get(nil, ns, v.Config.Name, "")
+			if err != nil {
+				return nil, err
+			}
+			cm, err := generateYamlConfigMap(cfg)
+			if err != nil {
+				return nil, err
+			}
+			configs = append(configs, cm)
+			vol.ConfigMap = &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: v.Config.Name}}
+		case v.Secret != nil:
+			sec, err := api.Secret.Get(ns, v.Secret.Name, "")
+			if err != nil {
+				return nil, err
+			}
+			s, err := generateYamlSecret(sec)
+			if err != nil {
+				return nil, err
+			}
+			secrets = append(secrets, s)
+			// the registry is imported from the image pull secret instead of volume
+			if isRegistrySecret(*sec) {
+				pullSecrets = append(pullSecrets, corev1.LocalObjectReference{Name: v.Secret.Name})
+				continue
+			}
+			vol.Secret = &corev1.SecretVolumeSource{SecretName: v.Secret.Name}
+		case v.HostPath != nil:
+			vol.HostPath = &corev1.HostPathVolumeSource{Path: v.HostPath.Path}
+			if v.HostPath.Type != "" {
+				t := corev1.HostPathType(v.HostPath.Type)
+				vol.HostPath.Type = &t
+			}
+		case v.EmptyDir != nil:
+			vol.EmptyDir = &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMedium(v.EmptyDir.Medium)}
+			if v.EmptyDir.SizeLimit != "" {
+				q, err := resource.ParseQuantity(v.EmptyDir.SizeLimit)
+				if err != nil {
+					return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+				}
+				vol.EmptyDir.SizeLimit = &q
+			}
+		default:
+			continue
+		}
+		volumes = append(volumes, vol)
+	}
+
+	podSpec := corev1.PodSpec{
+		Volumes:          volumes,
+		ImagePullSecrets: pullSecrets,
+		HostNetwork:      app.HostNetwork,
+	}
+	for _, svc := range app.InitServices {
+		container, err := transSvcToContainer(&svc)
+		if err != nil {
+			return nil, err
+		}
+		podSpec.InitContainers = append(podSpec.InitContainers, *container)
+	}
+	for _, svc := range app.Services {
+		container, err := transSvcToContainer(&svc)
+		if err != nil {
+			return nil, err
+		}
+		podSpec.Containers = append(podSpec.Containers, *container)
+	}
+
+	workload, err := generateYamlWorkload(app, podSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	objs := append(secrets, configs...)
+	objs = append(objs, workload)
+	return append(objs, generateYamlServices(app)...), nil
+}
+
+func generateYamlWorkload(app *specV1.Application, podSpec corev1.PodSpec) (runtime.Object, error) {
+	meta := metav1.ObjectMeta{Name: app.Name, Labels: app.Labels}
+	template := corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: app.Labels}, Spec: podSpec}
+	selector := &metav1.LabelSelector{MatchLabels: app.Labels}
+	replica := int32(app.Replica)
+	switch app.Workload {
+	case "", specV1.WorkloadDeployment:
+		return &appv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: TypeDeploy},
+			ObjectMeta: meta,
+			Spec:       appv1.DeploymentSpec{Replicas: &replica, Selector: selector, Template: template},
+		}, nil
+	case specV1.WorkloadDaemonSet:
+		return &appv1.DaemonSet{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: TypeDaemonset},
+			ObjectMeta: meta,
+			Spec:       appv1.DaemonSetSpec{Selector: selector, Template: template},
+		}, nil
+	case specV1.WorkloadStatefulSet:
+		return &appv1.StatefulSet{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: TypeStatefulSet},
+			ObjectMeta: meta,
+			Spec:       appv1.StatefulSetSpec{Replicas: &replica, Selector: selector, Template: template},
+		}, nil
+	case specV1.WorkloadJob:
+		job := &batchv1.Job{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "batch/v1", Kind: TypeJob},
+			ObjectMeta: meta,
+			Spec:       batchv1.JobSpec{Template: template},
+		}
+		if app.JobConfig != nil {
+			completions, parallelism, backoffLimit := int32(app.JobConfig.Completions),
+				int32(app.JobConfig.Parallelism), int32(app.JobConfig.BackoffLimit)
+			job.Spec.Completions = &completions
+			job.Spec.Parallelism = &parallelism
+			job.Spec.BackoffLimit = &backoffLimit
+			job.Spec.Template.Spec.RestartPolicy = corev1.RestartPolicy(app.JobConfig.RestartPolicy)
+		}
+		return job, nil
+	default:
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "workload ("+app.Workload+") can't be exported"))
+	}
+}
+
+// generateYamlServices generates the ClusterIP and NodePort services from the ports of app
+func generateYamlServices(app *specV1.Application) []runtime.Object {
+	ports := map[corev1.ServiceType][]corev1.ServicePort{}
+	for _, svc := range app.Services {
+		for _, p := range svc.Ports {
+			typ := corev1.ServiceTypeClusterIP
+			if p.ServiceType == string(corev1.ServiceTypeNodePort) {
+				typ = corev1.ServiceTypeNodePort
+			}
+			protocol := corev1.ProtocolTCP
+			if p.Protocol != "" {
+				protocol = corev1.Protocol(p.Protocol)
+			}
+			ports[typ] = append(ports[typ], corev1.ServicePort{
+				Name:       fmt.Sprintf("%s-%d", strings.ToLower(string(protocol)), p.ContainerPort),
+				Protocol:   protocol,
+				Port:       p.ContainerPort,
+				TargetPort: intstr.FromInt(int(p.ContainerPort)),
+				NodePort:   p.NodePort,
+			})
+		}
+	}
+	var res []runtime.Object
+	for _, typ := range []corev1.ServiceType{corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort} {
+		if len(ports[typ]) == 0 {
+			continue
+		}
+		name := app.Name
+		if typ == corev1.ServiceTypeNodePort {
+			name += "-nodeport"
+		}
+		res = append(res, &corev1.Service{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: TypeService},
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       corev1.ServiceSpec{Type: typ, Selector: app.Labels, Ports: ports[typ]},
+		})
+	}
+	return res
+}
+
+// generateYamlConfigMap generates the configmap, the object item is exported as the yaml of its metadata
+func generateYamlConfigMap(cfg *specV1.Configuration) (*corev1.ConfigMap, error) {
+	cm := &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: TypeConfig},
+		ObjectMeta: metav1.ObjectMeta{Name: cfg.Name, Labels: cfg.Labels},
+		Data:       map[string]string{},
+	}
+	for k, v := range cfg.Data {
+		if !strings.HasPrefix(k, common.ConfigObjectPrefix) {
+			cm.Data[k] = v
+			continue
+		}
+		var object specV1.ConfigurationObject
+		if err := json.Unmarshal([]byte(v), &object); err != nil {
+			return nil, err
+		}
+		meta := map[string]string{}
+		for mk, mv := range object.Metadata {
+			meta[mk] = mv
+		}
+		delete(meta, "userID")
+		data, err := yaml.Marshal(meta)
+		if err != nil {
+			return nil, err
+		}
+		cm.Data[strings.TrimPrefix(k, common.ConfigObjectPrefix)] = string(data)
+	}
+	return cm, nil
+}
+
+// generateYamlSecret generates the secret, the registry is exported as dockerconfigjson and the certificate as tls
+func generateYamlSecret(sec *specV1.Secret) (*corev1.Secret, error) {
+	s := &corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: TypeSecret},
+		ObjectMeta: metav1.ObjectMeta{Name: sec.Name, Labels: sec.Labels, Annotations: sec.Annotations},
+	}
+	switch sec.Labels[specV1.SecretLabel] {
+	case specV1.SecretRegistry:
+		registry := models.FromSecretToRegistry(sec, false)
+		auth := base64.StdEncoding.EncodeToString([]byte(registry.Username + ":" + registry.Password))
+		data, err := json.Marshal(map[string]interface{}{
+			"auths": map[string]interface{}{
+				registry.Address: map[string]string{
+					"username": registry.Username,
+					"password": registry.Password,
+					"auth":     auth,
+				},
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		s.Type = corev1.SecretTypeDockerConfigJson
+		s.Labels = nil
+		s.Data = map[string][]byte{corev1.DockerConfigJsonKey: data}
+	case specV1.SecretCertificate:
+		certificate := models.FromSecretToCertificate(sec, false)
+		s.Type = corev1.SecretTypeTLS
+		s.Labels = nil
+		s.Data = map[string][]byte{
+			corev1.TLSCertKey:       []byte(certificate.Data.Certificate),
+			corev1.TLSPrivateKeyKey: []byte(certificate.Data.Key),
+		}
+	default:
+		s.Type = corev1.SecretTypeOpaque
+		s.Data = sec.Data
+	}
+	return s, nil
+}
+
+// transSvcToContainer is the reverse of TransContainerToSvc
+func transSvcToContainer(svc *specV1.Service) (*corev1.Container, error) {
+	c := &corev1.Container{
+		Name:            svc.Name,
+		Image:           svc.Image,
+		Command:         svc.Command,
+		Args:            svc.Args,
+		WorkingDir:      svc.WorkingDir,
+		ImagePullPolicy: corev1.PullPolicy(svc.ImagePullPolicy),
+		LivenessProbe:   svc.LivenessProbe,
+		ReadinessProbe:  svc.ReadinessProbe,
+		StartupProbe:    svc.StartupProbe,
+	}
+	for _, e := range svc.Env {
+		c.Env = append(c.Env, corev1.EnvVar{Name: e.Name, Value: e.Value})
+	}
+	for _, p := range svc.Ports {
+		c.Ports = append(c.Ports, corev1.ContainerPort{
+			HostPort:      p.HostPort,
+			ContainerPort: p.ContainerPort,
+			Protocol:      corev1.Protocol(p.Protocol),
+			HostIP:        p.HostIP,
+		})
+	}
+	for _, m := range svc.VolumeMounts {
+		c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
+			Name:      m.Name,
+			MountPath: m.MountPath,
+			SubPath:   m.SubPath,
+			ReadOnly:  m.ReadOnly,
+		})
+	}
+	if svc.Resources != nil {
+		var err error
+		if c.Resources.Limits, err = toResourceList(svc.Resources.Limits); err != nil {
+			return nil, err
+		}
+		if c.Resources.Requests, err = toResourceList(svc.Resources.Requests); err != nil {
+			return nil, err
+		}
+	}
+	if svc.SecurityContext != nil && svc.SecurityContext.Privileged {
+		privileged := true
+		c.SecurityContext = &corev1.SecurityContext{Privileged: &privileged}
+	}
+	return c, nil
+}
+
+func toResourceList(res map[string]string) (corev1.ResourceList, error) {
+	if len(res) == 0 {
+		return nil, nil
+	}
+	list := corev1.ResourceList{}
+	for k, v := range res {
+		q, err := resource.ParseQuantity(v)
+		if err != nil {
+			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+		}
+		list[corev1.ResourceName(k)] = q
+	}
+	return list, nil
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/context"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/stretchr/testify/assert"
+	appv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+func TestAPI_ExportYamlApplication(t *testing.T) {
+	api, router, mockCtl := initYamlAPI(t)
+	defer mockCtl.Finish()
+
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sConfig := ms.NewMockConfigService(mockCtl)
+	sSecret := ms.NewMockSecretService(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{
+		App:    sApp,
+		Config: sConfig,
+		Secret: sSecret,
+	}
+
+	app := &specV1.Application{
+		Name:      "nginx",
+		Namespace: "default",
+		Labels: map[string]string{
+			"app":               "nginx",
+			common.LabelAppMode: context.RunModeKube,
+		},
+		Type:     specV1.AppTypeContainer,
+		Mode:     context.RunModeKube,
+		Workload: specV1.WorkloadDeployment,
+		Replica:  1,
+		Services: []specV1.Service{
+			{
+				Name:    "nginx",
+				Image:   "nginx:latest",
+				Command: []string{"nginx"},
+				Env:     []specV1.Environment{{Name: "a", Value: "b"}},
+				VolumeMounts: []specV1.VolumeMount{
+					{Name: "cfg", MountPath: "/etc/config"},
+					{Name: "sec", MountPath: "/etc/secret", ReadOnly: true},
+					{Name: "cache", MountPath: "/cache"},
+					{Name: "host", MountPath: "/host"},
+				},
+				Ports: []specV1.ContainerPort{
+					{ContainerPort: 80, Protocol: "TCP", ServiceType: string(corev1.ServiceTypeClusterIP)},
+					{ContainerPort: 443, Protocol: "TCP", ServiceType: string(corev1.ServiceTypeNodePort), NodePort: 30443},
+				},
+				Resources: &specV1.Resources{
+					Limits:   map[string]string{"cpu": "500m", "memory": "64Mi"},
+					Requests: map[string]string{"cpu": "100m"},
+				},
+				SecurityContext: &specV1.SecurityContext{Privileged: true},
+			},
+		},
+		Volumes: []specV1.Volume{
+			{Name: "cfg", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "cfg"}}},
+			{Name: "sec", VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "sec"}}},
+			{Name: "cache", VolumeSource: specV1.VolumeSource{EmptyDir: &specV1.EmptyDirVolumeSource{SizeLimit: "1Gi"}}},
+			{Name: "host", VolumeSource: specV1.VolumeSource{HostPath: &specV1.HostPathVolumeSource{Path: "/var/lib/baetyl", Type: "Directory"}}},
+			{Name: "reg", VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "reg"}}},
+		},
+	}
+	cfg := &specV1.Configuration{
+		Name:      "cfg",
+		Namespace: "default",
+		Data: map[string]string{
+			"conf.yml":                          "a: b",
+			common.ConfigObjectPrefix + "model": `{"metadata":{"type":"object","source":"http","url":"http://a/model.zip","userID":"u"}}`,
+		},
+	}
+	sec := &specV1.Secret{
+		Name:      "sec",
+		Namespace: "default",
+		Labels:    map[string]string{specV1.SecretLabel: specV1.SecretConfig},
+		Data:      map[string][]byte{"token": []byte("abc")},
+	}
+	reg := (&models.Registry{Name: "reg", Namespace: "default", Address: "hub.baidubce.com", Username: "u", Password: "p"}).ToSecret()
+
+	sApp.EXPECT().Get("default", "nginx", "").Return(app, nil).Times(1)
+	sConfig.EXPECT().Get(nil, "default", "cfg", "").Return(cfg, nil).AnyTimes()
+	sSecret.EXPECT().Get("default", "sec", "").Return(sec, nil).AnyTimes()
+	sSecret.EXPECT().Get("default", "reg", "").Return(reg, nil).AnyTimes()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/v1/yaml/apps/nginx", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// the manifests are imported as the same resources
+	resources := api.parseK8SYaml(w.Body.Bytes())
+	var kinds []string
+	for _, r := range resources {
+		kinds = append(kinds, r.GetObjectKind().GroupVersionKind().Kind)
+	}
+	assert.Equal(t, []string{TypeSecret, TypeSecret, TypeConfig, TypeDeploy, TypeService, TypeService}, kinds)
+
+	s, err := api.generateSecretResource("default", resources[0].(*corev1.Secret))
+	assert.NoError(t, err)
+	assert.Equal(t, sec.Data, s.Data)
+	s, err = api.generateSecretResource("default", resources[1].(*corev1.Secret))
+	assert.NoError(t, err)
+	assert.Equal(t, reg.Data, s.Data)
+
+	data := map[string]string{}
+	assert.NoError(t, generateConfigData("u", resources[2].(*corev1.ConfigMap).Data, data))
+	assert.Equal(t, "a: b", data["conf.yml"])
+	assert.Contains(t, data, common.ConfigObjectPrefix+"model")
+	view, err := api.ToConfigurationView(&specV1.Configuration{Data: data})
+	assert.NoError(t, err)
+	expect, err := api.ToConfigurationView(cfg)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, expect.Data, view.Data)
+
+	res, err := api.generateDeployApp("default", resources[3].(*appv1.Deployment))
+	assert.NoError(t, err)
+	assert.Equal(t, corev1.ServiceTypeClusterIP, resources[4].(*corev1.Service).Spec.Type)
+	updateAppPort(res, resources[5].(*corev1.Service))
+	assert.Equal(t, app, res)
+
+	// the job app
+	app.Workload = specV1.WorkloadJob
+	app.Replica = 0
+	app.Services[0].Ports = nil
+	app.JobConfig = &specV1.AppJobConfig{Completions: 1, Parallelism: 1, BackoffLimit: 3, RestartPolicy: "Never"}
+	sApp.EXPECT().Get("default", "nginx", "").Return(app, nil).Times(1)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, strings.Contains(w.Body.String(), "kind: Service"))
+	resources = api.parseK8SYaml(w.Body.Bytes())
+	res, err = api.generateJobApp("default", resources[3].(*batchv1.Job))
+	assert.NoError(t, err)
+	assert.Equal(t, app, res)
+
+	// the function app can't be exported
+	sApp.EXPECT().Get("default", "nginx", "").Return(&specV1.Application{Name: "nginx", Type: specV1.AppTypeFunction}, nil).Times(1)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
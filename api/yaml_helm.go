@@ -0,0 +1,102 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// ImportHelmChart renders the chart archive with the values file offline and applies the manifests,
+// the apps are labeled with the chart, so the chart uploaded again with new values updates the same resources
+func (api *API) ImportHelmChart(c *common.Context) (interface{}, error) {
+	ns, userID := c.GetNamespace(), c.GetUser().ID
+	opts := new(models.HelmImportOptions)
+	if err := c.BindQuery(opts); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+
+	file, _, err := c.Request.FormFile("chart")
+	if err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	defer file.Close()
+	ch, err := loader.LoadArchive(file)
+	if err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "failed to load chart: "+err.Error()))
+	}
+
+	var values []byte
+	if vf, _, err := c.Request.FormFile("values"); err == nil {
+		defer vf.Close()
+		if values, err = io.ReadAll(vf); err != nil {
+			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+		}
+	}
+
+	name := opts.Name
+	if name == "" {
+		name = ch.Name()
+	}
+	manifests, err := renderHelmChart(ch, values, name, ns)
+	if err != nil {
+		return nil, err
+	}
+
+	return api.applyYamlResources(ns, userID, api.parseK8SYaml(manifests), &models.YamlApplyOptions{
+		DryRun:   opts.DryRun,
+		Prune:    opts.Prune,
+		ApplySet: name,
+		AppLabels: map[string]string{
+			common.LabelHelmChart: ch.Name(),
+			// the semver build metadata isn't allowed in label
+			common.LabelHelmVersion: strings.ReplaceAll(ch.Metadata.Version, "+", "_"),
+		},
+	})
+}
+
+// renderHelmChart renders the templates of chart into the multi-document yaml without the cluster
+func renderHelmChart(ch *chart.Chart, values []byte, name, ns string) ([]byte, error) {
+	vals, err := chartutil.ReadValues(values)
+	if err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "failed to read values: "+err.Error()))
+	}
+	if err = chartutil.ProcessDependencies(ch, vals); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	options := chartutil.ReleaseOptions{Name: name, Namespace: ns, Revision: 1, IsInstall: true}
+	renderVals, err := chartutil.ToRenderValues(ch, vals, options, chartutil.DefaultCapabilities)
+	if err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+	}
+	files, err := engine.Render(ch, renderVals)
+	if err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "failed to render chart: "+err.Error()))
+	}
+
+	var names []string
+	for n, content := range files {
+		// the partials and notes aren't manifests
+		base := path.Base(n)
+		if strings.HasPrefix(base, "_") || strings.HasSuffix(base, ".txt") || strings.TrimSpace(content) == "" {
+			continue
+		}
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	buf := new(bytes.Buffer)
+	for _, n := range names {
+		buf.WriteString("\n---\n")
+		buf.WriteString(files[n])
+	}
+	return buf.Bytes(), nil
+}
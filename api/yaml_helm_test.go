@@ -0,0 +1,157 @@
+package api
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	appv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	mf "github.com/baetyl/baetyl-cloud/v2/mock/facade"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+var testChartFiles = map[string]string{
+	"demo/Chart.yaml": `apiVersion: v2
+name: demo
+version: 0.1.0+build`,
+	"demo/values.yaml": `image: nginx:latest
+replicas: 1
+config:
+  conf: a`,
+	"demo/templates/_helpers.tpl": `{{- define "demo.name" -}}{{ .Release.Name }}-app{{- end -}}`,
+	"demo/templates/NOTES.txt":    `installed {{ .Release.Name }}`,
+	"demo/templates/configmap.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ include "demo.name" . }}
+data:
+{{ toYaml .Values.config | indent 2 }}`,
+	"demo/templates/deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ include "demo.name" . }}
+  labels:
+    app: {{ include "demo.name" . }}
+spec:
+  replicas: {{ .Values.replicas }}
+  template:
+    spec:
+      containers:
+      - name: app
+        image: {{ .Values.image | quote }}`,
+}
+
+func newTestChart(t *testing.T) []byte {
+	buf := new(bytes.Buffer)
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+	for name, content := range testChartFiles {
+		assert.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))}))
+		_, err := tw.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, tw.Close())
+	assert.NoError(t, gw.Close())
+	return buf.Bytes()
+}
+
+func loadTestChart(t *testing.T) (*chart.Chart, error) {
+	return loader.LoadArchive(bytes.NewReader(newTestChart(t)))
+}
+
+func newHelmRequest(t *testing.T, query string, chart []byte, values string) *http.Request {
+	buf := new(bytes.Buffer)
+	w := multipart.NewWriter(buf)
+	fw, _ := w.CreateFormFile("chart", "demo-0.1.0.tgz")
+	fw.Write(chart)
+	if values != "" {
+		fw, _ = w.CreateFormFile("values", "values.yaml")
+		fw.Write([]byte(values))
+	}
+	w.Close()
+	req, _ := http.NewRequest(http.MethodPost, "/v1/yaml/helm"+query, buf)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req
+}
+
+func TestRenderHelmChart(t *testing.T) {
+	api := &API{}
+	ch, err := loadTestChart(t)
+	assert.NoError(t, err)
+	manifests, err := renderHelmChart(ch, []byte("replicas: 3\nimage: busybox"), "rel", "default")
+	assert.NoError(t, err)
+	assert.NotContains(t, string(manifests), "installed")
+
+	resources := api.parseK8SYaml(manifests)
+	assert.Len(t, resources, 2)
+	cm := resources[0].(*corev1.ConfigMap)
+	assert.Equal(t, "rel-app", cm.Name)
+	assert.Equal(t, map[string]string{"conf": "a"}, cm.Data)
+	deploy := resources[1].(*appv1.Deployment)
+	assert.Equal(t, int32(3), *deploy.Spec.Replicas)
+	assert.Equal(t, "busybox", deploy.Spec.Template.Spec.Containers[0].Image)
+
+	_, err = renderHelmChart(ch, []byte("replicas: [a"), "rel", "default")
+	assert.Error(t, err)
+}
+
+func TestAPI_ImportHelmChart(t *testing.T) {
+	api, router, mockCtl := initYamlAPI(t)
+	defer mockCtl.Finish()
+
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sConfig := ms.NewMockConfigService(mockCtl)
+	sSecret := ms.NewMockSecretService(mockCtl)
+	sFacade := mf.NewMockFacade(mockCtl)
+	api.AppCombinedService = &service.AppCombinedService{
+		App:    sApp,
+		Config: sConfig,
+		Secret: sSecret,
+	}
+	api.Facade = sFacade
+
+	notFound := common.Error(common.ErrResourceNotFound, common.Field("type", "app"), common.Field("name", "x"))
+	sConfig.EXPECT().Get(nil, "default", "rel-app", "").Return(nil, notFound).Times(1)
+	sFacade.EXPECT().CreateConfig("default", gomock.Any()).DoAndReturn(func(_ string, cfg *specV1.Configuration) (*specV1.Configuration, error) {
+		assert.Equal(t, "rel", cfg.Labels[common.LabelApplySet])
+		return cfg, nil
+	}).Times(1)
+	sApp.EXPECT().Get("default", "rel-app", "").Return(nil, notFound).Times(1)
+	sFacade.EXPECT().CreateApp("default", nil, gomock.Any(), nil).DoAndReturn(
+		func(_ string, _ *specV1.Application, app *specV1.Application, _ []specV1.Configuration) (*specV1.Application, error) {
+			assert.Equal(t, "demo", app.Labels[common.LabelHelmChart])
+			assert.Equal(t, "0.1.0_build", app.Labels[common.LabelHelmVersion])
+			assert.Equal(t, "rel", app.Labels[common.LabelApplySet])
+			assert.Equal(t, 2, app.Replica)
+			return app, nil
+		}).Times(1)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newHelmRequest(t, "?name=rel", newTestChart(t), "replicas: 2"))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	res := new(models.YamlApplyResultList)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, 2, res.Total)
+	assert.Equal(t, models.YamlApplyCreated, res.Items[0].Action)
+	assert.Equal(t, models.YamlApplyCreated, res.Items[1].Action)
+
+	// bad case: invalid chart
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, newHelmRequest(t, "", []byte("invalid"), ""))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -236,6 +236,9 @@ func initYamlAPI(t *testing.T) (*API, *gin.Engine, *gomock.Controller) {
 		yaml.POST("", mockIM, common.Wrapper(api.CreateYamlResource))
 		yaml.PUT("", mockIM, common.Wrapper(api.UpdateYamlResource))
 		yaml.POST("/delete", mockIM, common.Wrapper(api.DeleteYamlResource))
+		yaml.GET("/apps/:name", mockIM, common.WrapperRaw(api.ExportYamlApplication, true))
+		yaml.POST("/apply", mockIM, common.Wrapper(api.ApplyYamlResource))
+		yaml.POST("/helm", mockIM, common.Wrapper(api.ImportHelmChart))
 	}
 
 	return api, router, mockCtl
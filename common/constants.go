@@ -132,6 +132,9 @@ const (
 	LabelCluster     = "baetyl-cluster"
 	LabelNodeMode    = "baetyl-node-mode"
 	LabelAppMode     = "baetyl-app-mode"
+	LabelApplySet    = "baetyl-apply-set"
+	LabelHelmChart   = "baetyl-helm-chart"
+	LabelHelmVersion = "baetyl-helm-chart-version"
 )
 
 const (
@@ -144,6 +147,7 @@ const (
 	NodeSelector     = "nodeSelector"
 	WorkLoad         = "workLoad"
 	JobConfig        = "jobConfig"
+	SecretProvider   = "secretProvider"
 
 	AnnotationDescription     = BaetylCloudGroup + "/" + Description
 	AnnotationUpdateTimestamp = BaetylCloudGroup + "/" + UpdateTimestamp
@@ -152,6 +156,11 @@ const (
 	AnnotationNodeSelector    = BaetylCloudGroup + "/" + NodeSelector
 	AnnotationWorkLoad        = BaetylCloudGroup + "/" + WorkLoad
 	AnnotationJobConfig       = BaetylCloudGroup + "/" + JobConfig
+	// AnnotationSecretProvider the provider which the values of secret are resolved from, the data of secret keeps the references
+	AnnotationSecretProvider = BaetylCloudGroup + "/" + SecretProvider
+
+	// NamespaceCASuffix the suffix of the common name of the intermediate ca of namespace, which is prefixed by the namespace
+	NamespaceCASuffix = ".ns.baetyl.ca"
 )
 
 const (
@@ -0,0 +1,95 @@
+package common
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+)
+
+// FieldDiff a changed field between two objects
+type FieldDiff struct {
+	Path string      `json:"path"`
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to,omitempty"`
+}
+
+// Diff compares the json representation of two objects and returns the changed fields,
+// paths are joined by '.' for objects and '[i]' for arrays
+func Diff(from, to interface{}) ([]FieldDiff, error) {
+	f, err := toGeneric(from)
+	if err != nil {
+		return nil, err
+	}
+	t, err := toGeneric(to)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]FieldDiff, 0)
+	diffValue("", f, t, &res)
+	return res, nil
+}
+
+func toGeneric(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var res interface{}
+	if err = json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func diffValue(path string, from, to interface{}, res *[]FieldDiff) {
+	switch f := from.(type) {
+	case map[string]interface{}:
+		t, ok := to.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(f)+len(t))
+		for k := range f {
+			keys = append(keys, k)
+		}
+		for k := range t {
+			if _, ok := f[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			diffValue(p, f[k], t[k], res)
+		}
+		return
+	case []interface{}:
+		t, ok := to.([]interface{})
+		if !ok {
+			break
+		}
+		n := len(f)
+		if len(t) > n {
+			n = len(t)
+		}
+		for i := 0; i < n; i++ {
+			var fi, ti interface{}
+			if i < len(f) {
+				fi = f[i]
+			}
+			if i < len(t) {
+				ti = t[i]
+			}
+			diffValue(fmt.Sprintf("%s[%d]", path, i), fi, ti, res)
+		}
+		return
+	}
+	if !reflect.DeepEqual(from, to) {
+		*res = append(*res, FieldDiff{Path: path, From: from, To: to})
+	}
+}
@@ -0,0 +1,43 @@
+package common
+
+import (
+	"testing"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	from := &specV1.Application{
+		Name:     "app",
+		Selector: "a=b",
+		Labels:   map[string]string{"a": "b"},
+		Services: []specV1.Service{{Name: "s0", Image: "image:v1"}},
+	}
+	to := &specV1.Application{
+		Name:     "app",
+		Selector: "a=c",
+		Labels:   map[string]string{"a": "b", "c": "d"},
+		Services: []specV1.Service{{Name: "s0", Image: "image:v2"}, {Name: "s1", Image: "image:v1"}},
+	}
+
+	res, err := Diff(from, from)
+	assert.NoError(t, err)
+	assert.Len(t, res, 0)
+
+	res, err = Diff(from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, []FieldDiff{
+		{Path: "labels.c", To: "d"},
+		{Path: "selector", From: "a=b", To: "a=c"},
+		{Path: "services[0].image", From: "image:v1", To: "image:v2"},
+		{Path: "services[1]", To: map[string]interface{}{"name": "s1", "image": "image:v1"}},
+	}, res)
+
+	res, err = Diff(map[string]interface{}{"a": []string{"x"}}, map[string]interface{}{"a": "x"})
+	assert.NoError(t, err)
+	assert.Equal(t, []FieldDiff{{Path: "a", From: []interface{}{"x"}, To: "x"}}, res)
+
+	_, err = Diff(make(chan int), nil)
+	assert.Error(t, err)
+}
@@ -54,6 +54,10 @@ const (
 	ErrRegisterDeleteCallback  = "ErrRegisterDeleteCallback"
 	ErrRegisterPackage         = "ErrRegisterPackage"
 	ErrRegisterRecordActivated = "ErrRegisterRecordActivated"
+	// * registry
+	ErrRegistryValidation = "ErrRegistryValidation"
+	// * secret provider
+	ErrSecretProvider = "ErrSecretProvider"
 	// * db
 	ErrDatabase  = "ErrDatabase"
 	ErrUpdateCas = "ErrUpdateCas"
@@ -136,6 +140,10 @@ var templates = map[Code]string{
 	ErrRegisterDeleteCallback:  "Callback {{if .name}}({{.name}}){{end}} is used, cannot delete.",
 	ErrRegisterPackage:         "Problem with package.{{if .error}} ({{.error}}){{end}}",
 	ErrRegisterRecordActivated: "The record is activated.",
+	// * registry
+	ErrRegistryValidation: "The validation of registry failed.{{if .error}} ({{.error}}){{end}}",
+	// * secret provider
+	ErrSecretProvider: "The secret {{if .name}}({{.name}}){{end}} can't be resolved from the provider {{if .provider}}({{.provider}}){{end}}.{{if .error}} ({{.error}}){{end}}",
 	// * db
 	ErrDatabase: "Problem with database operation.{{if .error}} ({{.error}}){{end}}",
 	// * k8s
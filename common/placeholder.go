@@ -0,0 +1,82 @@
+package common
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+)
+
+const (
+	placeholderPrefix     = "${node."
+	placeholderName       = "name"
+	placeholderLabels     = "labels."
+	placeholderProperties = "properties."
+)
+
+var placeholderRegexp = regexp.MustCompile(`\$\{node\.([^}]*)\}`)
+
+// NodePlaceholderContext the values of node used to resolve the placeholders
+type NodePlaceholderContext struct {
+	Name       string
+	Labels     map[string]string
+	Properties map[string]interface{}
+}
+
+// HasNodePlaceholders returns true if the data contains any node placeholder
+func HasNodePlaceholders(data string) bool {
+	return strings.Contains(data, placeholderPrefix)
+}
+
+// ValidateNodePlaceholders checks the placeholders, such as ${node.name}, ${node.labels.x} and ${node.properties.x}
+func ValidateNodePlaceholders(data string) error {
+	if !HasNodePlaceholders(data) {
+		return nil
+	}
+	matches := placeholderRegexp.FindAllStringSubmatch(data, -1)
+	if strings.Count(data, placeholderPrefix) != len(matches) {
+		return fmt.Errorf("unclosed node placeholder")
+	}
+	for _, m := range matches {
+		key := m[1]
+		switch {
+		case key == placeholderName:
+		case strings.HasPrefix(key, placeholderLabels) && len(key) > len(placeholderLabels):
+		case strings.HasPrefix(key, placeholderProperties) && len(key) > len(placeholderProperties):
+		default:
+			return fmt.Errorf("unknown node placeholder %s", m[0])
+		}
+	}
+	return nil
+}
+
+// RenderNodePlaceholders resolves the node placeholders, the missing labels and properties are resolved to empty
+func RenderNodePlaceholders(data string, ctx *NodePlaceholderContext) string {
+	if !HasNodePlaceholders(data) {
+		return data
+	}
+	return placeholderRegexp.ReplaceAllStringFunc(data, func(s string) string {
+		key := placeholderRegexp.FindStringSubmatch(s)[1]
+		switch {
+		case key == placeholderName:
+			return ctx.Name
+		case strings.HasPrefix(key, placeholderLabels):
+			return ctx.Labels[strings.TrimPrefix(key, placeholderLabels)]
+		case strings.HasPrefix(key, placeholderProperties):
+			v, ok := ctx.Properties[strings.TrimPrefix(key, placeholderProperties)]
+			if !ok || v == nil {
+				return ""
+			}
+			if str, ok := v.(string); ok {
+				return str
+			}
+			bs, err := json.Marshal(v)
+			if err != nil {
+				return fmt.Sprint(v)
+			}
+			return string(bs)
+		}
+		return s
+	})
+}
@@ -0,0 +1,30 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateNodePlaceholders(t *testing.T) {
+	assert.NoError(t, ValidateNodePlaceholders("a: b"))
+	assert.NoError(t, ValidateNodePlaceholders("id: ${node.name}\nsite: ${node.labels.site}\nbroker: ${node.properties.broker}"))
+	assert.NoError(t, ValidateNodePlaceholders("${HOME}"))
+	assert.Error(t, ValidateNodePlaceholders("id: ${node.id}"))
+	assert.Error(t, ValidateNodePlaceholders("site: ${node.labels.}"))
+	assert.Error(t, ValidateNodePlaceholders("id: ${node.name"))
+}
+
+func TestRenderNodePlaceholders(t *testing.T) {
+	ctx := &NodePlaceholderContext{
+		Name:   "node01",
+		Labels: map[string]string{"site": "bj", "baetyl-node-mode": "kube"},
+		Properties: map[string]interface{}{
+			"broker": "tcp://10.0.0.1:1883",
+			"port":   1883,
+		},
+	}
+	assert.Equal(t, "a: b", RenderNodePlaceholders("a: b", ctx))
+	assert.Equal(t, "id: node01-bj\nbroker: tcp://10.0.0.1:1883\nport: 1883\nmode: kube\nmissing: ",
+		RenderNodePlaceholders("id: ${node.name}-${node.labels.site}\nbroker: ${node.properties.broker}\nport: ${node.properties.port}\nmode: ${node.labels.baetyl-node-mode}\nmissing: ${node.labels.x}", ctx))
+}
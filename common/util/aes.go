@@ -4,6 +4,9 @@ import (
 	"bytes"
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"io"
 )
 
 func EncryptMap(data map[string][]byte, key []byte) (result map[string][]byte, err error) {
@@ -74,6 +77,50 @@ func Decrypt(ciphertext, key []byte) ([]byte, error) {
 	return origData, nil
 }
 
+// SealGCM encrypts the plaintext by AES-GCM with a random nonce, which is prepended to the ciphertext
+func SealGCM(plaintext, key []byte) ([]byte, error) {
+	return SealGCMWithAAD(plaintext, key, nil)
+}
+
+// OpenGCM decrypts the ciphertext sealed by SealGCM
+func OpenGCM(ciphertext, key []byte) ([]byte, error) {
+	return OpenGCMWithAAD(ciphertext, key, nil)
+}
+
+// SealGCMWithAAD encrypts as SealGCM and authenticates the additional data, which is required to decrypt
+func SealGCMWithAAD(plaintext, key, additionalData []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aesgcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aesgcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aesgcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// OpenGCMWithAAD decrypts the ciphertext sealed by SealGCMWithAAD with the same additional data
+func OpenGCMWithAAD(ciphertext, key, additionalData []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aesgcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < aesgcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce := ciphertext[:aesgcm.NonceSize()]
+	return aesgcm.Open(nil, nonce, ciphertext[aesgcm.NonceSize():], additionalData)
+}
+
 func PKCS7Padding(ciphertext []byte, blockSize int) []byte {
 	padding := blockSize - len(ciphertext)%blockSize
 	padtext := bytes.Repeat([]byte{byte(padding)}, padding)
@@ -21,3 +21,32 @@ func TestAES(t *testing.T) {
 	otext, _ := Decrypt(itext, []byte("0123456789abcdef"))
 	assert.Equal(t, "hello world", string(otext))
 }
+
+func TestGCM(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	c1, err := SealGCM([]byte("hello world"), key)
+	assert.NoError(t, err)
+	c2, err := SealGCM([]byte("hello world"), key)
+	assert.NoError(t, err)
+	assert.NotEqual(t, c1, c2)
+
+	p, err := OpenGCM(c1, key)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello world", string(p))
+
+	_, err = OpenGCM(c1, []byte("0123456789abcdef0123456789abcdeX"))
+	assert.Error(t, err)
+	_, err = OpenGCM([]byte("short"), key)
+	assert.Error(t, err)
+
+	// the additional data is required to decrypt
+	c3, err := SealGCMWithAAD([]byte("hello world"), key, []byte("ns/a"))
+	assert.NoError(t, err)
+	p, err = OpenGCMWithAAD(c3, key, []byte("ns/a"))
+	assert.NoError(t, err)
+	assert.Equal(t, "hello world", string(p))
+	_, err = OpenGCMWithAAD(c3, key, []byte("ns/b"))
+	assert.Error(t, err)
+	_, err = OpenGCM(c3, key)
+	assert.Error(t, err)
+}
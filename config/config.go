@@ -22,6 +22,45 @@ type CloudConfig struct {
 	Template struct {
 		Path string `yaml:"path" json:"path" default:"/etc/baetyl/templates"`
 	} `yaml:"template" json:"template"`
+	RecycleBin struct {
+		// the deleted resources are kept for the retention, 0 means they are deleted permanently
+		Retention time.Duration `yaml:"retention" json:"retention" default:"168h"`
+	} `yaml:"recycleBin" json:"recycleBin"`
+	CertificateExpiry struct {
+		// the certificates expiring within the days are notified
+		Days int `yaml:"days" json:"days" default:"30"`
+		// the name of the callback which the expiry events of namespace are sent to
+		Callback string `yaml:"callback" json:"callback" default:"certificate-expiry"`
+	} `yaml:"certificateExpiry" json:"certificateExpiry"`
+	Webhook struct {
+		// the failed deliveries are retried with the exponential backoff until the max attempts
+		MaxAttempts int           `yaml:"maxAttempts" json:"maxAttempts" default:"5"`
+		Backoff     time.Duration `yaml:"backoff" json:"backoff" default:"30s"`
+		MaxBackoff  time.Duration `yaml:"maxBackoff" json:"maxBackoff" default:"1h"`
+		Timeout     time.Duration `yaml:"timeout" json:"timeout" default:"10s"`
+		// the hosts, ips or cidrs allowed to be sent to even if they are loopback, link-local or private addresses
+		AllowedHosts []string `yaml:"allowedHosts" json:"allowedHosts"`
+	} `yaml:"webhook" json:"webhook"`
+	RegistryCheck struct {
+		Timeout time.Duration `yaml:"timeout" json:"timeout" default:"10s"`
+		// the tls certificates of registries are not verified if insecure
+		Insecure bool `yaml:"insecure" json:"insecure"`
+		// the hosts, ips or cidrs allowed to be requested even if they are loopback, link-local or private addresses
+		AllowedHosts []string `yaml:"allowedHosts" json:"allowedHosts"`
+		// the hosts trusted as the token realms of any registry, the realm on the host of registry is always trusted
+		TokenRealms []string `yaml:"tokenRealms" json:"tokenRealms"`
+	} `yaml:"registryCheck" json:"registryCheck"`
+	ConfigUpload struct {
+		// the max size of the file uploaded in one request
+		MaxSize int64 `yaml:"maxSize" json:"maxSize" default:"104857600"`
+		// the max size of the file uploaded in chunks
+		MaxChunkedSize int64 `yaml:"maxChunkedSize" json:"maxChunkedSize" default:"10737418240"`
+		ChunkSize      int64 `yaml:"chunkSize" json:"chunkSize" default:"8388608"`
+	} `yaml:"configUpload" json:"configUpload"`
+	SecretProvider struct {
+		// the resolved values are cached in memory, never in the resource backend
+		CacheDuration time.Duration `yaml:"cacheDuration" json:"cacheDuration" default:"5m"`
+	} `yaml:"secretProvider" json:"secretProvider"`
 	Plugin struct {
 		Pubsub     string   `yaml:"pubsub" json:"pubsub" default:"defaultpubsub"`
 		PKI        string   `yaml:"pki" json:"pki" default:"defaultpki"`
@@ -35,20 +74,25 @@ type CloudConfig struct {
 		Record     string   `yaml:"record" json:"record" default:"database"`
 		Callback   string   `yaml:"callback" json:"callback" default:"database"`
 		AppHistory string   `yaml:"appHistory" json:"appHistory" default:"database"`
+		Rollout    string   `yaml:"rollout" json:"rollout" default:"database"`
+		RecycleBin string   `yaml:"recycleBin" json:"recycleBin" default:"database"`
 		Objects    []string `yaml:"objects" json:"objects" default:"[]"`
 		Functions  []string `yaml:"functions" json:"functions" default:"[]"`
-		Property   string   `yaml:"property" json:"property" default:"database"`
-		Module     string   `yaml:"module" json:"module" default:"database"`
-		SyncLinks  []string `yaml:"synclinks" json:"synclinks" default:"[\"httplink\"]"`
-		Locker     string   `yaml:"locker" json:"locker" default:"defaultlocker"`
-		Task       string   `yaml:"task" json:"task" default:"defaulttask"`
-		Sign       string   `yaml:"sign" json:"sign" default:"defaultsign"`
-		DM         string   `yaml:"dm" json:"dm" default:"database"`
-		Tx         string   `yaml:"tx" json:"tx" default:"defaulttx"`
-		Cron       string   `yaml:"cron" json:"cron" default:"database"`
-		Csrf       string   `yaml:"csrf" json:"csrf" default:"defaultcsrf"`
-		JWT        string   `yaml:"jwt" json:"jwt" default:"defaultjwt"`
-		Cache      string   `yaml:"cache" json:"cache" default:"freecache"`
+		// the external providers which the values of secrets are resolved from at sync time
+		SecretProviders []string `yaml:"secretProviders" json:"secretProviders" default:"[]"`
+		Property        string   `yaml:"property" json:"property" default:"database"`
+		Module          string   `yaml:"module" json:"module" default:"database"`
+		SyncLinks       []string `yaml:"synclinks" json:"synclinks" default:"[\"httplink\"]"`
+		Locker          string   `yaml:"locker" json:"locker" default:"defaultlocker"`
+		Task            string   `yaml:"task" json:"task" default:"defaulttask"`
+		Sign            string   `yaml:"sign" json:"sign" default:"defaultsign"`
+		DM              string   `yaml:"dm" json:"dm" default:"database"`
+		Tx              string   `yaml:"tx" json:"tx" default:"defaulttx"`
+		Cron            string   `yaml:"cron" json:"cron" default:"database"`
+		Csrf            string   `yaml:"csrf" json:"csrf" default:"defaultcsrf"`
+		JWT             string   `yaml:"jwt" json:"jwt" default:"defaultjwt"`
+		Cache           string   `yaml:"cache" json:"cache" default:"freecache"`
+		InstallToken    string   `yaml:"installToken" json:"installToken" default:"database"`
 	} `yaml:"plugin" json:"plugin"`
 }
 
@@ -46,8 +46,12 @@ func TestDefaultValue(t *testing.T) {
 	expect.Plugin.Record = "database"
 	expect.Plugin.Callback = "database"
 	expect.Plugin.AppHistory = "database"
+	expect.Plugin.Rollout = "database"
+	expect.Plugin.RecycleBin = "database"
+	expect.Plugin.InstallToken = "database"
 	expect.Plugin.Functions = []string{}
 	expect.Plugin.Objects = []string{}
+	expect.Plugin.SecretProviders = []string{}
 	expect.Plugin.Property = "database"
 	expect.Plugin.Module = "database"
 	expect.Plugin.SyncLinks = []string{"httplink"}
@@ -66,6 +70,19 @@ func TestDefaultValue(t *testing.T) {
 
 	expect.Template.Path = "/etc/baetyl/templates"
 
+	expect.RecycleBin.Retention = time.Hour * 24 * 7
+	expect.CertificateExpiry.Days = 30
+	expect.CertificateExpiry.Callback = "certificate-expiry"
+	expect.Webhook.MaxAttempts = 5
+	expect.Webhook.Backoff = time.Second * 30
+	expect.Webhook.MaxBackoff = time.Hour
+	expect.Webhook.Timeout = time.Second * 10
+	expect.RegistryCheck.Timeout = time.Second * 10
+	expect.ConfigUpload.MaxSize = 100 << 20
+	expect.ConfigUpload.MaxChunkedSize = 10 << 30
+	expect.ConfigUpload.ChunkSize = 8 << 20
+	expect.SecretProvider.CacheDuration = 5 * time.Minute
+
 	expect.Cache.ExpirationDuration = time.Minute * 10
 
 	expect.CronJobs = []CronJob{}
@@ -47,7 +47,12 @@ func (a *facade) CreateApp(ns string, baseApp, app *specV1.Application, configs
 			a.txFactory.Commit(tx)
 		}
 	}()
-	err = a.updateGenConfigsOfFunctionApp(tx, ns, configs)
+	app, err = a.createApp(tx, ns, baseApp, app, configs)
+	return app, err
+}
+
+func (a *facade) createApp(tx interface{}, ns string, baseApp, app *specV1.Application, configs []specV1.Configuration) (*specV1.Application, error) {
+	err := a.updateGenConfigsOfFunctionApp(tx, ns, configs)
 	if err != nil {
 		return nil, err
 	}
@@ -74,6 +79,10 @@ func (a *facade) CreateApp(ns string, baseApp, app *specV1.Application, configs
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+
+	if err = a.recordAppHistory(tx, app); err != nil {
+		return nil, errors.Trace(err)
+	}
 	return app, nil
 }
 
@@ -130,11 +139,24 @@ func (a *facade) UpdateApp(ns string, oldApp, app *specV1.Application, configs [
 		}
 	}
 
-	// update nodes
-	if err = a.UpdateNodeAndAppIndex(tx, ns, app); err != nil {
+	// update nodes, the nodes are updated wave by wave if the app has a rollout strategy
+	nodes, err := a.rollout.Start(tx, ns, oldApp, app)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if nodes != nil {
+		err = a.index.RefreshNodesIndexByApp(tx, ns, app.Name, nodes)
+	} else {
+		err = a.UpdateNodeAndAppIndex(tx, ns, app)
+	}
+	if err != nil {
 		return nil, err
 	}
 
+	if err = a.recordAppHistory(tx, app); err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	a.cleanGenConfigsOfFunctionApp(tx, configs, oldApp)
 	return app, nil
 }
@@ -156,6 +178,11 @@ func (a *facade) DeleteApp(ns, name string, app *specV1.Application) error {
 		}
 	}()
 
+	// keep the app with the selector of cron and the generated configs in recycle bin
+	if err = a.recycleApp(tx, ns, app); err != nil {
+		return errors.Trace(err)
+	}
+
 	if app.CronStatus == specV1.CronWait {
 		err = a.cron.DeleteCron(name, ns)
 		if err != nil {
@@ -172,10 +199,61 @@ func (a *facade) DeleteApp(ns, name string, app *specV1.Application) error {
 		return err
 	}
 
+	if err = a.history.Delete(tx, ns, name); err != nil {
+		return err
+	}
+
+	if err = a.rollout.Delete(tx, ns, name); err != nil {
+		return err
+	}
+
 	a.cleanGenConfigsOfFunctionApp(tx, nil, app)
 	return nil
 }
 
+// ActivateCronApp deploys the waiting cron app to the nodes matched by the selector saved in cron
+func (a *facade) ActivateCronApp(cronApp *models.Cron) (*specV1.Application, error) {
+	ns := cronApp.Namespace
+	app, err := a.app.Get(ns, cronApp.Name, "")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if app.CronStatus != specV1.CronWait {
+		return app, nil
+	}
+
+	tx, errTx := a.txFactory.BeginTx()
+	if errTx != nil {
+		return nil, errTx
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			a.txFactory.Rollback(tx)
+			panic(p)
+		} else if err != nil {
+			a.txFactory.Rollback(tx)
+		} else {
+			a.txFactory.Commit(tx)
+		}
+	}()
+
+	app.Selector = cronApp.Selector
+	app.CronStatus = specV1.CronFinished
+	app, err = a.app.Update(tx, ns, app)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	if err = a.UpdateNodeAndAppIndex(tx, ns, app); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	if err = a.recordAppHistory(tx, app); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return app, nil
+}
+
 func (a *facade) DeleteNodeAndAppIndex(tx interface{}, namespace string, app *specV1.Application) error {
 	_, err := a.node.DeleteNodeAppVersion(tx, namespace, app)
 	if err != nil {
@@ -185,6 +263,22 @@ func (a *facade) DeleteNodeAndAppIndex(tx interface{}, namespace string, app *sp
 	return a.index.RefreshNodesIndexByApp(tx, namespace, app.Name, make([]string, 0))
 }
 
+// recordAppHistory saves the app as a revision, the selector of waiting cron app is taken from the cron
+func (a *facade) recordAppHistory(tx interface{}, app *specV1.Application) error {
+	record := app
+	if app.CronStatus == specV1.CronWait {
+		cronApp, err := a.cron.GetCron(app.Name, app.Namespace)
+		if err != nil {
+			return err
+		}
+		cp := *app
+		cp.Selector = cronApp.Selector
+		record = &cp
+	}
+	_, err := a.history.Record(tx, record)
+	return err
+}
+
 func (a *facade) updateGenConfigsOfFunctionApp(tx interface{}, namespace string, configs []specV1.Configuration) error {
 	for _, cfg := range configs {
 		_, err := a.config.Upsert(tx, namespace, &cfg)
@@ -20,6 +20,8 @@ func TestCreateApplication(t *testing.T) {
 		config:    mAppFacade.sConfig,
 		index:     mAppFacade.sIndex,
 		cron:      mAppFacade.sCron,
+		history:   mAppFacade.sHistory,
+		rollout:   mAppFacade.sRollout,
 		txFactory: mAppFacade.txFactory,
 	}
 	mAppFacade.txFactory.EXPECT().BeginTx().Return(nil, nil).AnyTimes()
@@ -51,6 +53,11 @@ func TestCreateApplication(t *testing.T) {
 	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
 	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).Return(nil, nil)
 	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, gomock.Any(), gomock.Any()).Return(nil)
+	mAppFacade.sCron.EXPECT().GetCron(app.Name, app.Namespace).Return(&models.Cron{Selector: "a=b"}, nil)
+	mAppFacade.sHistory.EXPECT().Record(nil, gomock.Any()).DoAndReturn(func(_ interface{}, h *specV1.Application) (*models.AppHistory, error) {
+		assert.Equal(t, "a=b", h.Selector)
+		return &models.AppHistory{Revision: 1}, nil
+	})
 	_, err = appFacade.CreateApp(ns, app, app, configs)
 	assert.NoError(t, err)
 }
@@ -64,6 +71,9 @@ func TestDeleteApplication(t *testing.T) {
 		config:    mAppFacade.sConfig,
 		index:     mAppFacade.sIndex,
 		cron:      mAppFacade.sCron,
+		history:   mAppFacade.sHistory,
+		rollout:   mAppFacade.sRollout,
+		recycle:   mAppFacade.sRecycle,
 		txFactory: mAppFacade.txFactory,
 	}
 	ns := "baetyl-cloud"
@@ -129,22 +139,47 @@ func TestDeleteApplication(t *testing.T) {
 	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
 	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
 
-	mAppFacade.sApp.EXPECT().Delete(nil, ns, app.Name, "").Return(unknownErr).Times(1)
+	genConfig := &specV1.Configuration{Namespace: ns, Name: "baetyl-function-config-app-service-xxxxxxxxx"}
+	mAppFacade.sConfig.EXPECT().Get(nil, ns, genConfig.Name, "").Return(genConfig, nil).AnyTimes()
+	mAppFacade.sHistory.EXPECT().List(ns, app.Name, &models.ListOptions{}).Return(nil, unknownErr).Times(1)
 	err := appFacade.DeleteApp(ns, app.Name, app)
 	assert.Error(t, err, unknownErr)
 
+	histories := []models.AppHistory{{Namespace: ns, Name: app.Name, Revision: 1, Version: "1"}}
+	mAppFacade.sHistory.EXPECT().List(ns, app.Name, &models.ListOptions{}).Return(&models.AppHistoryList{Items: histories}, nil).AnyTimes()
+	mAppFacade.sRecycle.EXPECT().Recycle(nil, gomock.Any()).Return(unknownErr).Times(1)
+	err = appFacade.DeleteApp(ns, app.Name, app)
+	assert.Error(t, err, unknownErr)
+
+	var recycled *models.RecycleItem
+	mAppFacade.sRecycle.EXPECT().Recycle(nil, gomock.Any()).DoAndReturn(func(_ interface{}, item *models.RecycleItem) error {
+		recycled = item
+		return nil
+	}).AnyTimes()
+	mAppFacade.sApp.EXPECT().Delete(nil, ns, app.Name, "").Return(unknownErr).Times(1)
+	err = appFacade.DeleteApp(ns, app.Name, app)
+	assert.Error(t, err, unknownErr)
+	assert.Equal(t, models.RecycleKindApp, recycled.Kind)
+	assert.Equal(t, []specV1.Configuration{*genConfig}, recycled.Configs)
+	assert.Equal(t, histories, recycled.Histories)
+
 	mAppFacade.sApp.EXPECT().Delete(nil, ns, app.Name, "").Return(nil).AnyTimes()
 	mAppFacade.sNode.EXPECT().DeleteNodeAppVersion(nil, ns, app).Return(nil, unknownErr).Times(1)
 	err = appFacade.DeleteApp(ns, app.Name, app)
 	assert.Error(t, err, unknownErr)
 
 	app.CronStatus = specV1.CronWait
+	mAppFacade.sCron.EXPECT().GetCron(app.Name, ns).Return(&models.Cron{Selector: "a=b"}, nil)
 	mAppFacade.sCron.EXPECT().DeleteCron(app.Name, ns).Return(nil)
 	mAppFacade.sNode.EXPECT().DeleteNodeAppVersion(nil, ns, app).Return(nil, nil).Times(1)
 	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, app.Name, gomock.Any()).Return(nil).AnyTimes()
 	mAppFacade.sConfig.EXPECT().Delete(nil, ns, gomock.Any()).Return(unknownErr)
+	mAppFacade.sHistory.EXPECT().Delete(nil, ns, app.Name).Return(nil)
+	mAppFacade.sRollout.EXPECT().Delete(nil, ns, app.Name).Return(nil)
 	err = appFacade.DeleteApp(ns, app.Name, app)
 	assert.NoError(t, err)
+	// the selector of the waiting cron app is kept
+	assert.Equal(t, "a=b", recycled.Application.Selector)
 }
 
 func TestUpdateApplication(t *testing.T) {
@@ -156,6 +191,8 @@ func TestUpdateApplication(t *testing.T) {
 		config:    mAppFacade.sConfig,
 		index:     mAppFacade.sIndex,
 		cron:      mAppFacade.sCron,
+		history:   mAppFacade.sHistory,
+		rollout:   mAppFacade.sRollout,
 		txFactory: mAppFacade.txFactory,
 	}
 
@@ -232,6 +269,11 @@ func TestUpdateApplication(t *testing.T) {
 	_, err = appFacade.UpdateApp(ns, oldApp, app, configs)
 	assert.Error(t, err, unknownErr)
 
+	mAppFacade.sRollout.EXPECT().Start(nil, ns, app, app).Return(nil, unknownErr).Times(1)
+	_, err = appFacade.UpdateApp(ns, app, app, configs)
+	assert.Error(t, err, unknownErr)
+
+	mAppFacade.sRollout.EXPECT().Start(nil, ns, app, app).Return(nil, nil).Times(2)
 	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).Return(nil, unknownErr).Times(1)
 	_, err = appFacade.UpdateApp(ns, app, app, configs)
 	assert.Error(t, err, unknownErr)
@@ -251,6 +293,14 @@ func TestUpdateApplication(t *testing.T) {
 	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).Return(nil, nil).AnyTimes()
 	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, app.Name, gomock.Any()).Return(nil).AnyTimes()
 	mAppFacade.sConfig.EXPECT().Delete(nil, ns, gomock.Any()).Return(nil).AnyTimes()
+	mAppFacade.sCron.EXPECT().GetCron(app.Name, ns).Return(&models.Cron{}, nil).AnyTimes()
+	mAppFacade.sHistory.EXPECT().Record(nil, gomock.Any()).Return(nil, unknownErr).Times(1)
+	_, err = appFacade.UpdateApp(ns, app, app, configs)
+	assert.Error(t, err, unknownErr)
+
+	// the nodes of the first wave are updated by rollout
+	mAppFacade.sRollout.EXPECT().Start(nil, ns, app, app).Return([]string{"node01", "node02"}, nil).Times(1)
+	mAppFacade.sHistory.EXPECT().Record(nil, gomock.Any()).Return(&models.AppHistory{Revision: 2}, nil).Times(1)
 	_, err = appFacade.UpdateApp(ns, app, app, configs)
 	assert.NoError(t, err)
 }
@@ -284,3 +334,72 @@ func TestGetApplication(t *testing.T) {
 	_, err = appFacade.GetApp(ns, name, "")
 	assert.NoError(t, err)
 }
+
+func TestActivateCronApplication(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		history:   mAppFacade.sHistory,
+		rollout:   mAppFacade.sRollout,
+		txFactory: mAppFacade.txFactory,
+	}
+	mAppFacade.txFactory.EXPECT().BeginTx().Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+
+	ns, name := "baetyl-cloud", "abc"
+	cronApp := &models.Cron{
+		Id:        1,
+		Namespace: ns,
+		Name:      name,
+		Selector:  "a=b",
+		CronTime:  time.Now(),
+	}
+
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(nil, unknownErr).Times(1)
+	_, err := appFacade.ActivateCronApp(cronApp)
+	assert.Error(t, err, unknownErr)
+
+	// deployed already
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(&specV1.Application{
+		Namespace:  ns,
+		Name:       name,
+		Selector:   "a=b",
+		CronStatus: specV1.CronFinished,
+	}, nil).Times(1)
+	app, err := appFacade.ActivateCronApp(cronApp)
+	assert.NoError(t, err)
+	assert.Equal(t, specV1.CronFinished, app.CronStatus)
+
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(&specV1.Application{
+		Namespace:  ns,
+		Name:       name,
+		CronStatus: specV1.CronWait,
+	}, nil).Times(1)
+	mAppFacade.sApp.EXPECT().Update(nil, ns, gomock.Any()).Return(nil, unknownErr).Times(1)
+	_, err = appFacade.ActivateCronApp(cronApp)
+	assert.Error(t, err, unknownErr)
+
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(&specV1.Application{
+		Namespace:  ns,
+		Name:       name,
+		CronStatus: specV1.CronWait,
+	}, nil).Times(1)
+	mAppFacade.sApp.EXPECT().Update(nil, ns, gomock.Any()).DoAndReturn(func(_ interface{}, _ string, app *specV1.Application) (*specV1.Application, error) {
+		assert.Equal(t, "a=b", app.Selector)
+		assert.Equal(t, specV1.CronFinished, app.CronStatus)
+		app.Version = "2"
+		return app, nil
+	}).Times(1)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).Return([]string{"node1"}, nil).Times(1)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, name, []string{"node1"}).Return(nil).Times(1)
+	mAppFacade.sHistory.EXPECT().Record(nil, gomock.Any()).Return(&models.AppHistory{Revision: 2}, nil).Times(1)
+	app, err = appFacade.ActivateCronApp(cronApp)
+	assert.NoError(t, err)
+	assert.Equal(t, "2", app.Version)
+}
@@ -8,6 +8,7 @@ import (
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
 )
 
 func (a *facade) CreateConfig(ns string, config *specV1.Configuration) (*specV1.Configuration, error) {
@@ -54,7 +55,35 @@ func (a *facade) UpdateConfig(ns string, config *specV1.Configuration) (*specV1.
 }
 
 func (a *facade) DeleteConfig(ns, name string) error {
-	return a.config.Delete(nil, ns, name)
+	config, err := a.config.Get(nil, ns, name, "")
+	if err != nil {
+		return err
+	}
+	tx, errTx := a.txFactory.BeginTx()
+	if errTx != nil {
+		return errTx
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			a.txFactory.Rollback(tx)
+			panic(p)
+		} else if err != nil {
+			a.txFactory.Rollback(tx)
+		} else {
+			a.txFactory.Commit(tx)
+		}
+	}()
+	err = a.recycle.Recycle(tx, &models.RecycleItem{
+		Namespace: ns,
+		Kind:      models.RecycleKindConfig,
+		Name:      name,
+		Configs:   []specV1.Configuration{*config},
+	})
+	if err != nil {
+		return err
+	}
+	err = a.config.Delete(tx, ns, name)
+	return err
 }
 
 func (a *facade) updateNodeAndApp(namespace string, config *specV1.Configuration, appNames []string) error {
@@ -67,6 +96,7 @@ func (a *facade) updateNodeAndApp(namespace string, config *specV1.Configuration
 			return err
 		}
 
+		oldApp := *app
 		if !needUpdateApp(config, app) {
 			continue
 		}
@@ -75,14 +105,30 @@ func (a *facade) updateNodeAndApp(namespace string, config *specV1.Configuration
 		if err != nil {
 			return err
 		}
-		_, err = a.node.UpdateNodeAppVersion(nil, namespace, app)
-		if err != nil {
+		if err = a.releaseApp(namespace, &oldApp, app); err != nil {
+			return err
+		}
+		if err = a.recordAppHistory(nil, app); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// releaseApp updates the nodes of app whose config or secret is updated, the nodes are updated wave by wave
+// if the app has a rollout strategy, the same as the update of app
+func (a *facade) releaseApp(namespace string, oldApp, app *specV1.Application) error {
+	nodes, err := a.rollout.Start(nil, namespace, oldApp, app)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if nodes != nil {
+		return nil
+	}
+	_, err = a.node.UpdateNodeAppVersion(nil, namespace, app)
+	return err
+}
+
 func needUpdateApp(config *specV1.Configuration, app *specV1.Application) bool {
 	appNeedUpdate := false
 	for _, volume := range app.Volumes {
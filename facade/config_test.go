@@ -20,6 +20,7 @@ func TestCreateConfig(t *testing.T) {
 		app:       mFacade.sApp,
 		config:    mFacade.sConfig,
 		index:     mFacade.sIndex,
+		history:   mFacade.sHistory,
 		txFactory: mFacade.txFactory,
 	}
 	ns := "test"
@@ -43,6 +44,8 @@ func TestUpdateConfig(t *testing.T) {
 		app:       mFacade.sApp,
 		config:    mFacade.sConfig,
 		index:     mFacade.sIndex,
+		history:   mFacade.sHistory,
+		rollout:   mFacade.sRollout,
 		txFactory: mFacade.txFactory,
 	}
 	ns, name := "default", "abc"
@@ -161,9 +164,35 @@ func TestUpdateConfig(t *testing.T) {
 	apps[0].Volumes[0].Config.Version = "1"
 	mFacade.sApp.EXPECT().Get(ns, appNames[0], "").Return(apps[0], nil).Times(1)
 	mFacade.sApp.EXPECT().Update(nil, ns, gomock.Any()).Return(nil, nil).Times(1)
+	mFacade.sRollout.EXPECT().Start(nil, ns, gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
 	mFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).Return(nil, unknownErr).Times(1)
 	_, err = cfgFacade.UpdateConfig(ns, res3)
 	assert.Error(t, err, unknownErr)
+
+	apps[0].Volumes[0].Config.Version = "1"
+	mFacade.sApp.EXPECT().Get(ns, appNames[0], "").Return(apps[0], nil).Times(1)
+	mFacade.sApp.EXPECT().Update(nil, ns, gomock.Any()).Return(apps[0], nil).Times(1)
+	mFacade.sRollout.EXPECT().Start(nil, ns, gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+	mFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).Return(nil, nil).Times(1)
+	mFacade.sHistory.EXPECT().Record(nil, apps[0]).Return(nil, unknownErr).Times(1)
+	_, err = cfgFacade.UpdateConfig(ns, res3)
+	assert.Error(t, err, unknownErr)
+
+	// the nodes are updated by the rollout of the app
+	apps[0].Volumes[0].Config.Version = "1"
+	mFacade.sApp.EXPECT().Get(ns, appNames[0], "").Return(apps[0], nil).Times(1)
+	mFacade.sApp.EXPECT().Update(nil, ns, gomock.Any()).Return(apps[0], nil).Times(1)
+	mFacade.sRollout.EXPECT().Start(nil, ns, gomock.Any(), apps[0]).Return([]string{"n1"}, nil).Times(1)
+	mFacade.sHistory.EXPECT().Record(nil, apps[0]).Return(nil, unknownErr).Times(1)
+	_, err = cfgFacade.UpdateConfig(ns, res3)
+	assert.Error(t, err, unknownErr)
+
+	apps[0].Volumes[0].Config.Version = "1"
+	mFacade.sApp.EXPECT().Get(ns, appNames[0], "").Return(apps[0], nil).Times(1)
+	mFacade.sApp.EXPECT().Update(nil, ns, gomock.Any()).Return(apps[0], nil).Times(1)
+	mFacade.sRollout.EXPECT().Start(nil, ns, gomock.Any(), apps[0]).Return(nil, unknownErr).Times(1)
+	_, err = cfgFacade.UpdateConfig(ns, res3)
+	assert.Error(t, err, unknownErr)
 }
 
 func TestDeleteConfig(t *testing.T) {
@@ -174,10 +203,28 @@ func TestDeleteConfig(t *testing.T) {
 		app:       mFacade.sApp,
 		config:    mFacade.sConfig,
 		index:     mFacade.sIndex,
+		history:   mFacade.sHistory,
+		recycle:   mFacade.sRecycle,
 		txFactory: mFacade.txFactory,
 	}
 	ns, n := "test", "test"
-	mFacade.sConfig.EXPECT().Delete(nil, ns, n).Return(nil)
+	cfg := &specV1.Configuration{Namespace: ns, Name: n}
+	mFacade.txFactory.EXPECT().BeginTx().Return(nil, nil).AnyTimes()
+	mFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
+	mFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+
+	mFacade.sConfig.EXPECT().Get(nil, ns, n, "").Return(nil, unknownErr).Times(1)
 	err := cfgFacade.DeleteConfig(ns, n)
+	assert.Error(t, err, unknownErr)
+
+	mFacade.sConfig.EXPECT().Get(nil, ns, n, "").Return(cfg, nil).AnyTimes()
+	mFacade.sRecycle.EXPECT().Recycle(nil, &models.RecycleItem{
+		Namespace: ns,
+		Kind:      models.RecycleKindConfig,
+		Name:      n,
+		Configs:   []specV1.Configuration{*cfg},
+	}).Return(nil).Times(1)
+	mFacade.sConfig.EXPECT().Delete(nil, ns, n).Return(nil)
+	err = cfgFacade.DeleteConfig(ns, n)
 	assert.NoError(t, err)
 }
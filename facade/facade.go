@@ -6,6 +6,7 @@ import (
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 
 	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
 	"github.com/baetyl/baetyl-cloud/v2/plugin"
 	"github.com/baetyl/baetyl-cloud/v2/service"
 )
@@ -17,6 +18,7 @@ type Facade interface {
 	CreateApp(ns string, baseApp, app *specV1.Application, configs []specV1.Configuration) (*specV1.Application, error)
 	UpdateApp(ns string, oldApp, app *specV1.Application, configs []specV1.Configuration) (*specV1.Application, error)
 	DeleteApp(ns, name string, app *specV1.Application) error
+	ActivateCronApp(cronApp *models.Cron) (*specV1.Application, error)
 
 	CreateConfig(ns string, config *specV1.Configuration) (*specV1.Configuration, error)
 	UpdateConfig(ns string, config *specV1.Configuration) (*specV1.Configuration, error)
@@ -25,6 +27,9 @@ type Facade interface {
 	CreateSecret(ns string, secret *specV1.Secret) (*specV1.Secret, error)
 	UpdateSecret(ns string, secret *specV1.Secret) (*specV1.Secret, error)
 	DeleteSecret(ns, name string) error
+
+	// RestoreRecycleItem restores the deleted resource kept in the recycle bin
+	RestoreRecycleItem(ns string, id int64) (*models.RecycleItem, error)
 }
 
 type facade struct {
@@ -34,6 +39,9 @@ type facade struct {
 	secret    service.SecretService
 	index     service.IndexService
 	cron      service.CronService
+	history   service.AppHistoryService
+	rollout   service.RolloutService
+	recycle   service.RecycleBinService
 	txFactory plugin.TransactionFactory
 	log       *log.Logger
 }
@@ -63,6 +71,18 @@ func NewFacade(config *config.CloudConfig) (Facade, error) {
 	if err != nil {
 		return nil, err
 	}
+	history, err := service.NewAppHistoryService(config)
+	if err != nil {
+		return nil, err
+	}
+	rollout, err := service.NewRolloutService(config)
+	if err != nil {
+		return nil, err
+	}
+	recycle, err := service.NewRecycleBinService(config)
+	if err != nil {
+		return nil, err
+	}
 	tx, err := plugin.GetPlugin(config.Plugin.Tx)
 	if err != nil {
 		return nil, err
@@ -75,6 +95,9 @@ func NewFacade(config *config.CloudConfig) (Facade, error) {
 		secret:    secret,
 		index:     index,
 		cron:      cron,
+		history:   history,
+		rollout:   rollout,
+		recycle:   recycle,
 		txFactory: tx.(plugin.TransactionFactory),
 		log:       log.L().With(log.Any("level", "facade")),
 	}, nil
@@ -21,6 +21,9 @@ type MockAppFacade struct {
 	sSecret   *ms.MockSecretService
 	sIndex    *ms.MockIndexService
 	sCron     *ms.MockCronService
+	sHistory  *ms.MockAppHistoryService
+	sRollout  *ms.MockRolloutService
+	sRecycle  *ms.MockRecycleBinService
 	txFactory *mp.MockTransactionFactory
 }
 
@@ -33,6 +36,9 @@ func InitMockEnvironment(t *testing.T) (*MockAppFacade, *gomock.Controller) {
 		sSecret:   ms.NewMockSecretService(mockCtl),
 		sIndex:    ms.NewMockIndexService(mockCtl),
 		sCron:     ms.NewMockCronService(mockCtl),
+		sHistory:  ms.NewMockAppHistoryService(mockCtl),
+		sRollout:  ms.NewMockRolloutService(mockCtl),
+		sRecycle:  ms.NewMockRecycleBinService(mockCtl),
 		txFactory: mp.NewMockTransactionFactory(mockCtl),
 	}, mockCtl
 }
@@ -0,0 +1,133 @@
+package facade
+
+import (
+	"strings"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// RestoreRecycleItem recreates the deleted resource and removes it from the recycle bin,
+// the restored app is deployed to the matched nodes again with its revisions
+func (a *facade) RestoreRecycleItem(ns string, id int64) (*models.RecycleItem, error) {
+	item, err := a.recycle.Get(ns, id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err = a.checkRestoreConflict(ns, item); err != nil {
+		return nil, err
+	}
+
+	tx, errTx := a.txFactory.BeginTx()
+	if errTx != nil {
+		return nil, errTx
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			a.txFactory.Rollback(tx)
+			panic(p)
+		} else if err != nil {
+			a.txFactory.Rollback(tx)
+		} else {
+			a.txFactory.Commit(tx)
+		}
+	}()
+
+	switch item.Kind {
+	case models.RecycleKindApp:
+		if err = a.history.Restore(tx, item.Histories); err != nil {
+			return nil, errors.Trace(err)
+		}
+		item.Application, err = a.createApp(tx, ns, nil, item.Application, item.Configs)
+	case models.RecycleKindConfig:
+		var cfg *specV1.Configuration
+		if cfg, err = a.config.Create(tx, ns, &item.Configs[0]); err == nil {
+			item.Configs = []specV1.Configuration{*cfg}
+		}
+	default:
+		item.Secret, err = a.secret.Create(tx, ns, item.Secret)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	if err = a.recycle.Delete(tx, ns, id); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return item, nil
+}
+
+func (a *facade) checkRestoreConflict(ns string, item *models.RecycleItem) error {
+	var err error
+	switch item.Kind {
+	case models.RecycleKindApp:
+		_, err = a.app.Get(ns, item.Name, "")
+	case models.RecycleKindConfig:
+		_, err = a.config.Get(nil, ns, item.Name, "")
+	default:
+		_, err = a.secret.Get(ns, item.Name, "")
+	}
+	if err == nil {
+		return common.Error(common.ErrResourceConflict,
+			common.Field("type", item.Kind),
+			common.Field("name", item.Name))
+	}
+	if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+		return nil
+	}
+	return err
+}
+
+// recycleApp keeps the app in the recycle bin, with the selector saved in cron, the generated configs of function app
+// and the revisions, which are deleted with the app and kept until the item is purged
+func (a *facade) recycleApp(tx interface{}, ns string, app *specV1.Application) error {
+	record := *app
+	if app.CronStatus == specV1.CronWait {
+		cronApp, err := a.cron.GetCron(app.Name, ns)
+		if err != nil {
+			return err
+		}
+		record.Selector = cronApp.Selector
+	}
+	var configs []specV1.Configuration
+	for _, v := range app.Volumes {
+		if v.Config == nil || !(strings.HasPrefix(v.Config.Name, FunctionConfigPrefix) ||
+			strings.HasPrefix(v.Config.Name, FunctionProgramConfigPrefix)) {
+			continue
+		}
+		cfg, err := a.config.Get(tx, ns, v.Config.Name, "")
+		if err != nil {
+			if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+				continue
+			}
+			return err
+		}
+		configs = append(configs, *cfg)
+	}
+	histories, err := a.history.List(ns, app.Name, &models.ListOptions{})
+	if err != nil {
+		return err
+	}
+	return a.recycle.Recycle(tx, &models.RecycleItem{
+		Namespace:   ns,
+		Kind:        models.RecycleKindApp,
+		Name:        app.Name,
+		Application: &record,
+		Configs:     configs,
+		Histories:   histories.Items,
+	})
+}
+
+func recycleKindOfSecret(secret *specV1.Secret) string {
+	switch secret.Labels[specV1.SecretLabel] {
+	case specV1.SecretRegistry:
+		return models.RecycleKindRegistry
+	case specV1.SecretCertificate, specV1.SecretCustomCertificate:
+		return models.RecycleKindCertificate
+	default:
+		return models.RecycleKindSecret
+	}
+}
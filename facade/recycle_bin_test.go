@@ -0,0 +1,89 @@
+package facade
+
+import (
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestRestoreRecycleItem(t *testing.T) {
+	mFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	rFacade := &facade{
+		node:      mFacade.sNode,
+		app:       mFacade.sApp,
+		config:    mFacade.sConfig,
+		secret:    mFacade.sSecret,
+		index:     mFacade.sIndex,
+		cron:      mFacade.sCron,
+		history:   mFacade.sHistory,
+		rollout:   mFacade.sRollout,
+		recycle:   mFacade.sRecycle,
+		txFactory: mFacade.txFactory,
+	}
+	mFacade.txFactory.EXPECT().BeginTx().Return(nil, nil).AnyTimes()
+	mFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
+	mFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+	ns := "default"
+	notFound := common.Error(common.ErrResourceNotFound)
+
+	mFacade.sRecycle.EXPECT().Get(ns, int64(1)).Return(nil, notFound).Times(1)
+	_, err := rFacade.RestoreRecycleItem(ns, 1)
+	assert.Error(t, err)
+
+	// app
+	app := &specV1.Application{Namespace: ns, Name: "app", Selector: "a=b"}
+	genConfig := specV1.Configuration{Namespace: ns, Name: "baetyl-function-program-config-x"}
+	histories := []models.AppHistory{{Namespace: ns, Name: "app", Revision: 1, Version: "1", Application: app}}
+	appItem := &models.RecycleItem{ID: 1, Namespace: ns, Kind: models.RecycleKindApp, Name: "app",
+		Application: app, Configs: []specV1.Configuration{genConfig}, Histories: histories}
+	mFacade.sRecycle.EXPECT().Get(ns, int64(1)).Return(appItem, nil).AnyTimes()
+	mFacade.sApp.EXPECT().Get(ns, "app", "").Return(app, nil).Times(1)
+	_, err = rFacade.RestoreRecycleItem(ns, 1)
+	assert.Error(t, err)
+	assert.Equal(t, common.ErrResourceConflict, err.(errors.Coder).Code())
+
+	mFacade.sApp.EXPECT().Get(ns, "app", "").Return(nil, notFound).AnyTimes()
+	mFacade.sHistory.EXPECT().Restore(nil, histories).Return(unknownErr).Times(1)
+	_, err = rFacade.RestoreRecycleItem(ns, 1)
+	assert.Error(t, err, unknownErr)
+
+	mFacade.sHistory.EXPECT().Restore(nil, histories).Return(nil).Times(1)
+	mFacade.sConfig.EXPECT().Upsert(nil, ns, &genConfig).Return(&genConfig, nil).Times(1)
+	mFacade.sApp.EXPECT().CreateWithBase(nil, ns, app, nil).Return(app, nil).Times(1)
+	mFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, app).Return([]string{"n1"}, nil).Times(1)
+	mFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "app", []string{"n1"}).Return(nil).Times(1)
+	mFacade.sHistory.EXPECT().Record(nil, app).Return(&models.AppHistory{Revision: 1}, nil).Times(1)
+	mFacade.sRecycle.EXPECT().Delete(nil, ns, int64(1)).Return(nil).Times(1)
+	res, err := rFacade.RestoreRecycleItem(ns, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, app, res.Application)
+
+	// config
+	cfg := specV1.Configuration{Namespace: ns, Name: "cfg"}
+	cfgItem := &models.RecycleItem{ID: 2, Namespace: ns, Kind: models.RecycleKindConfig, Name: "cfg",
+		Configs: []specV1.Configuration{cfg}}
+	mFacade.sRecycle.EXPECT().Get(ns, int64(2)).Return(cfgItem, nil).Times(1)
+	mFacade.sConfig.EXPECT().Get(nil, ns, "cfg", "").Return(nil, notFound).Times(1)
+	mFacade.sConfig.EXPECT().Create(nil, ns, &cfg).Return(&cfg, nil).Times(1)
+	mFacade.sRecycle.EXPECT().Delete(nil, ns, int64(2)).Return(unknownErr).Times(1)
+	_, err = rFacade.RestoreRecycleItem(ns, 2)
+	assert.Error(t, err)
+
+	// certificate
+	secret := &specV1.Secret{Namespace: ns, Name: "cert", Labels: map[string]string{specV1.SecretLabel: specV1.SecretCustomCertificate}}
+	secretItem := &models.RecycleItem{ID: 3, Namespace: ns, Kind: recycleKindOfSecret(secret), Name: "cert", Secret: secret}
+	assert.Equal(t, models.RecycleKindCertificate, secretItem.Kind)
+	mFacade.sRecycle.EXPECT().Get(ns, int64(3)).Return(secretItem, nil).Times(1)
+	mFacade.sSecret.EXPECT().Get(ns, "cert", "").Return(nil, notFound).Times(1)
+	mFacade.sSecret.EXPECT().Create(nil, ns, secret).Return(secret, nil).Times(1)
+	mFacade.sRecycle.EXPECT().Delete(nil, ns, int64(3)).Return(nil).Times(1)
+	res, err = rFacade.RestoreRecycleItem(ns, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, secret, res.Secret)
+}
@@ -7,6 +7,7 @@ import (
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
 )
 
 func (a *facade) CreateSecret(ns string, secret *specV1.Secret) (*specV1.Secret, error) {
@@ -42,7 +43,35 @@ func (a *facade) UpdateSecret(ns string, secret *specV1.Secret) (*specV1.Secret,
 }
 
 func (a *facade) DeleteSecret(ns, name string) error {
-	return a.secret.Delete(nil, ns, name)
+	secret, err := a.secret.Get(ns, name, "")
+	if err != nil {
+		return err
+	}
+	tx, errTx := a.txFactory.BeginTx()
+	if errTx != nil {
+		return errTx
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			a.txFactory.Rollback(tx)
+			panic(p)
+		} else if err != nil {
+			a.txFactory.Rollback(tx)
+		} else {
+			a.txFactory.Commit(tx)
+		}
+	}()
+	err = a.recycle.Recycle(tx, &models.RecycleItem{
+		Namespace: ns,
+		Kind:      recycleKindOfSecret(secret),
+		Name:      name,
+		Secret:    secret,
+	})
+	if err != nil {
+		return err
+	}
+	err = a.secret.Delete(tx, ns, name)
+	return err
 }
 
 func (a *facade) updateAppSecret(namespace string, secret *specV1.Secret) error {
@@ -58,6 +87,7 @@ func (a *facade) updateAppSecret(namespace string, secret *specV1.Secret) error
 			}
 			return err
 		}
+		oldApp := *app
 		if !needUpdateAppSecret(secret, app) {
 			continue
 		}
@@ -65,8 +95,10 @@ func (a *facade) updateAppSecret(namespace string, secret *specV1.Secret) error
 		if err != nil {
 			return err
 		}
-		_, err = a.node.UpdateNodeAppVersion(nil, namespace, app)
-		if err != nil {
+		if err = a.releaseApp(namespace, &oldApp, app); err != nil {
+			return err
+		}
+		if err = a.recordAppHistory(nil, app); err != nil {
 			return err
 		}
 	}
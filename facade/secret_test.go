@@ -6,6 +6,8 @@ import (
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
 )
 
 func TestCreateSecret(t *testing.T) {
@@ -17,6 +19,7 @@ func TestCreateSecret(t *testing.T) {
 		config:    mFacade.sConfig,
 		secret:    mFacade.sSecret,
 		index:     mFacade.sIndex,
+		history:   mFacade.sHistory,
 		txFactory: mFacade.txFactory,
 	}
 	ns := "test"
@@ -41,6 +44,8 @@ func TestUpdateSecret(t *testing.T) {
 		config:    mFacade.sConfig,
 		secret:    mFacade.sSecret,
 		index:     mFacade.sIndex,
+		history:   mFacade.sHistory,
+		rollout:   mFacade.sRollout,
 		txFactory: mFacade.txFactory,
 	}
 	ns, name := "default", "abc"
@@ -104,7 +109,9 @@ func TestUpdateSecret(t *testing.T) {
 	mFacade.sApp.EXPECT().Get(ns, appNames[0], "").Return(apps[0], nil).Times(1)
 	mFacade.sApp.EXPECT().Get(ns, appNames[1], "").Return(apps[1], nil).Times(1)
 	mFacade.sApp.EXPECT().Update(nil, ns, gomock.Any()).Return(apps[0], nil).Times(1)
+	mFacade.sRollout.EXPECT().Start(nil, ns, gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
 	mFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).Return(nil, nil).Times(1)
+	mFacade.sHistory.EXPECT().Record(nil, apps[0]).Return(nil, nil).Times(1)
 	_, err = sFacade.UpdateSecret(ns, mConf)
 	assert.NoError(t, err)
 
@@ -130,6 +137,7 @@ func TestUpdateSecret(t *testing.T) {
 	apps[0].Volumes[0].Secret.Version = "1"
 	mFacade.sApp.EXPECT().Get(ns, appNames[0], "").Return(apps[0], nil).Times(1)
 	mFacade.sApp.EXPECT().Update(nil, ns, gomock.Any()).Return(nil, nil).Times(1)
+	mFacade.sRollout.EXPECT().Start(nil, ns, gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
 	mFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).Return(nil, unknownErr).Times(1)
 	_, err = sFacade.UpdateSecret(ns, mConf)
 	assert.Error(t, err, unknownErr)
@@ -144,11 +152,29 @@ func TestDeleteSecret(t *testing.T) {
 		config:    mFacade.sConfig,
 		secret:    mFacade.sSecret,
 		index:     mFacade.sIndex,
+		history:   mFacade.sHistory,
+		recycle:   mFacade.sRecycle,
 		txFactory: mFacade.txFactory,
 	}
 	ns, n := "test", "test"
 
-	mFacade.sSecret.EXPECT().Delete(nil, ns, n).Return(nil).Times(1)
+	secret := &specV1.Secret{Namespace: ns, Name: n, Labels: map[string]string{specV1.SecretLabel: specV1.SecretRegistry}}
+	mFacade.txFactory.EXPECT().BeginTx().Return(nil, nil).AnyTimes()
+	mFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
+	mFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+
+	mFacade.sSecret.EXPECT().Get(ns, n, "").Return(secret, nil).AnyTimes()
+	mFacade.sRecycle.EXPECT().Recycle(nil, &models.RecycleItem{
+		Namespace: ns,
+		Kind:      models.RecycleKindRegistry,
+		Name:      n,
+		Secret:    secret,
+	}).Return(unknownErr).Times(1)
 	err := sFacade.DeleteSecret(ns, n)
+	assert.Error(t, err, unknownErr)
+
+	mFacade.sRecycle.EXPECT().Recycle(nil, gomock.Any()).Return(nil).Times(1)
+	mFacade.sSecret.EXPECT().Delete(nil, ns, n).Return(nil).Times(1)
+	err = sFacade.DeleteSecret(ns, n)
 	assert.NoError(t, err)
 }
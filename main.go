@@ -24,8 +24,11 @@ import (
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/default/sign"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/default/task"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/default/transaction"
+	_ "github.com/baetyl/baetyl-cloud/v2/plugin/filesecret"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/kube"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/link/httplink"
+	_ "github.com/baetyl/baetyl-cloud/v2/plugin/localkms"
+	_ "github.com/baetyl/baetyl-cloud/v2/plugin/oidc"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/sign"
 	"github.com/baetyl/baetyl-cloud/v2/server"
 )
@@ -86,6 +89,16 @@ func main() {
 		defer as.Close()
 		ctx.Log().Info("init  server starting")
 
+		cs, err := server.NewCronServer(&cfg)
+		if err != nil {
+			return err
+		}
+		cs.SetAPI(a)
+		cs.InitJobs()
+		cs.Run()
+		defer cs.Close()
+		ctx.Log().Info("cron server starting")
+
 		ctx.Wait()
 		return nil
 	})
@@ -5,35 +5,52 @@
 package facade
 
 import (
+	reflect "reflect"
+
+	models "github.com/baetyl/baetyl-cloud/v2/models"
 	v1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 	gomock "github.com/golang/mock/gomock"
-	reflect "reflect"
 )
 
-// MockFacade is a mock of Facade interface
+// MockFacade is a mock of Facade interface.
 type MockFacade struct {
 	ctrl     *gomock.Controller
 	recorder *MockFacadeMockRecorder
 }
 
-// MockFacadeMockRecorder is the mock recorder for MockFacade
+// MockFacadeMockRecorder is the mock recorder for MockFacade.
 type MockFacadeMockRecorder struct {
 	mock *MockFacade
 }
 
-// NewMockFacade creates a new mock instance
+// NewMockFacade creates a new mock instance.
 func NewMockFacade(ctrl *gomock.Controller) *MockFacade {
 	mock := &MockFacade{ctrl: ctrl}
 	mock.recorder = &MockFacadeMockRecorder{mock}
 	return mock
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use
+// EXPECT returns an object that allows the caller to indicate expected use.
 func (m *MockFacade) EXPECT() *MockFacadeMockRecorder {
 	return m.recorder
 }
 
-// CreateApp mocks base method
+// ActivateCronApp mocks base method.
+func (m *MockFacade) ActivateCronApp(arg0 *models.Cron) (*v1.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActivateCronApp", arg0)
+	ret0, _ := ret[0].(*v1.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ActivateCronApp indicates an expected call of ActivateCronApp.
+func (mr *MockFacadeMockRecorder) ActivateCronApp(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActivateCronApp", reflect.TypeOf((*MockFacade)(nil).ActivateCronApp), arg0)
+}
+
+// CreateApp mocks base method.
 func (m *MockFacade) CreateApp(arg0 string, arg1, arg2 *v1.Application, arg3 []v1.Configuration) (*v1.Application, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateApp", arg0, arg1, arg2, arg3)
@@ -42,13 +59,13 @@ func (m *MockFacade) CreateApp(arg0 string, arg1, arg2 *v1.Application, arg3 []v
 	return ret0, ret1
 }
 
-// CreateApp indicates an expected call of CreateApp
+// CreateApp indicates an expected call of CreateApp.
 func (mr *MockFacadeMockRecorder) CreateApp(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApp", reflect.TypeOf((*MockFacade)(nil).CreateApp), arg0, arg1, arg2, arg3)
 }
 
-// CreateConfig mocks base method
+// CreateConfig mocks base method.
 func (m *MockFacade) CreateConfig(arg0 string, arg1 *v1.Configuration) (*v1.Configuration, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateConfig", arg0, arg1)
@@ -57,13 +74,13 @@ func (m *MockFacade) CreateConfig(arg0 string, arg1 *v1.Configuration) (*v1.Conf
 	return ret0, ret1
 }
 
-// CreateConfig indicates an expected call of CreateConfig
+// CreateConfig indicates an expected call of CreateConfig.
 func (mr *MockFacadeMockRecorder) CreateConfig(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateConfig", reflect.TypeOf((*MockFacade)(nil).CreateConfig), arg0, arg1)
 }
 
-// CreateSecret mocks base method
+// CreateSecret mocks base method.
 func (m *MockFacade) CreateSecret(arg0 string, arg1 *v1.Secret) (*v1.Secret, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateSecret", arg0, arg1)
@@ -72,13 +89,13 @@ func (m *MockFacade) CreateSecret(arg0 string, arg1 *v1.Secret) (*v1.Secret, err
 	return ret0, ret1
 }
 
-// CreateSecret indicates an expected call of CreateSecret
+// CreateSecret indicates an expected call of CreateSecret.
 func (mr *MockFacadeMockRecorder) CreateSecret(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSecret", reflect.TypeOf((*MockFacade)(nil).CreateSecret), arg0, arg1)
 }
 
-// DeleteApp mocks base method
+// DeleteApp mocks base method.
 func (m *MockFacade) DeleteApp(arg0, arg1 string, arg2 *v1.Application) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "DeleteApp", arg0, arg1, arg2)
@@ -86,13 +103,13 @@ func (m *MockFacade) DeleteApp(arg0, arg1 string, arg2 *v1.Application) error {
 	return ret0
 }
 
-// DeleteApp indicates an expected call of DeleteApp
+// DeleteApp indicates an expected call of DeleteApp.
 func (mr *MockFacadeMockRecorder) DeleteApp(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteApp", reflect.TypeOf((*MockFacade)(nil).DeleteApp), arg0, arg1, arg2)
 }
 
-// DeleteConfig mocks base method
+// DeleteConfig mocks base method.
 func (m *MockFacade) DeleteConfig(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "DeleteConfig", arg0, arg1)
@@ -100,13 +117,13 @@ func (m *MockFacade) DeleteConfig(arg0, arg1 string) error {
 	return ret0
 }
 
-// DeleteConfig indicates an expected call of DeleteConfig
+// DeleteConfig indicates an expected call of DeleteConfig.
 func (mr *MockFacadeMockRecorder) DeleteConfig(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConfig", reflect.TypeOf((*MockFacade)(nil).DeleteConfig), arg0, arg1)
 }
 
-// DeleteSecret mocks base method
+// DeleteSecret mocks base method.
 func (m *MockFacade) DeleteSecret(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "DeleteSecret", arg0, arg1)
@@ -114,13 +131,13 @@ func (m *MockFacade) DeleteSecret(arg0, arg1 string) error {
 	return ret0
 }
 
-// DeleteSecret indicates an expected call of DeleteSecret
+// DeleteSecret indicates an expected call of DeleteSecret.
 func (mr *MockFacadeMockRecorder) DeleteSecret(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSecret", reflect.TypeOf((*MockFacade)(nil).DeleteSecret), arg0, arg1)
 }
 
-// GetApp mocks base method
+// GetApp mocks base method.
 func (m *MockFacade) GetApp(arg0, arg1, arg2 string) (*v1.Application, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetApp", arg0, arg1, arg2)
@@ -129,13 +146,28 @@ func (m *MockFacade) GetApp(arg0, arg1, arg2 string) (*v1.Application, error) {
 	return ret0, ret1
 }
 
-// GetApp indicates an expected call of GetApp
+// GetApp indicates an expected call of GetApp.
 func (mr *MockFacadeMockRecorder) GetApp(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApp", reflect.TypeOf((*MockFacade)(nil).GetApp), arg0, arg1, arg2)
 }
 
-// UpdateApp mocks base method
+// RestoreRecycleItem mocks base method.
+func (m *MockFacade) RestoreRecycleItem(arg0 string, arg1 int64) (*models.RecycleItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreRecycleItem", arg0, arg1)
+	ret0, _ := ret[0].(*models.RecycleItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreRecycleItem indicates an expected call of RestoreRecycleItem.
+func (mr *MockFacadeMockRecorder) RestoreRecycleItem(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreRecycleItem", reflect.TypeOf((*MockFacade)(nil).RestoreRecycleItem), arg0, arg1)
+}
+
+// UpdateApp mocks base method.
 func (m *MockFacade) UpdateApp(arg0 string, arg1, arg2 *v1.Application, arg3 []v1.Configuration) (*v1.Application, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UpdateApp", arg0, arg1, arg2, arg3)
@@ -144,13 +176,13 @@ func (m *MockFacade) UpdateApp(arg0 string, arg1, arg2 *v1.Application, arg3 []v
 	return ret0, ret1
 }
 
-// UpdateApp indicates an expected call of UpdateApp
+// UpdateApp indicates an expected call of UpdateApp.
 func (mr *MockFacadeMockRecorder) UpdateApp(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateApp", reflect.TypeOf((*MockFacade)(nil).UpdateApp), arg0, arg1, arg2, arg3)
 }
 
-// UpdateConfig mocks base method
+// UpdateConfig mocks base method.
 func (m *MockFacade) UpdateConfig(arg0 string, arg1 *v1.Configuration) (*v1.Configuration, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UpdateConfig", arg0, arg1)
@@ -159,13 +191,13 @@ func (m *MockFacade) UpdateConfig(arg0 string, arg1 *v1.Configuration) (*v1.Conf
 	return ret0, ret1
 }
 
-// UpdateConfig indicates an expected call of UpdateConfig
+// UpdateConfig indicates an expected call of UpdateConfig.
 func (mr *MockFacadeMockRecorder) UpdateConfig(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConfig", reflect.TypeOf((*MockFacade)(nil).UpdateConfig), arg0, arg1)
 }
 
-// UpdateSecret mocks base method
+// UpdateSecret mocks base method.
 func (m *MockFacade) UpdateSecret(arg0 string, arg1 *v1.Secret) (*v1.Secret, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UpdateSecret", arg0, arg1)
@@ -174,7 +206,7 @@ func (m *MockFacade) UpdateSecret(arg0 string, arg1 *v1.Secret) (*v1.Secret, err
 	return ret0, ret1
 }
 
-// UpdateSecret indicates an expected call of UpdateSecret
+// UpdateSecret indicates an expected call of UpdateSecret.
 func (mr *MockFacadeMockRecorder) UpdateSecret(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSecret", reflect.TypeOf((*MockFacade)(nil).UpdateSecret), arg0, arg1)
@@ -180,6 +180,50 @@ func (mr *MockPKIMockRecorder) DeleteClientCert(certId interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteClientCert", reflect.TypeOf((*MockPKI)(nil).DeleteClientCert), certId)
 }
 
+// GetNamespaceCertID mocks base method
+func (m *MockPKI) GetNamespaceCertID(namespace string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNamespaceCertID", namespace)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNamespaceCertID indicates an expected call of GetNamespaceCertID
+func (mr *MockPKIMockRecorder) GetNamespaceCertID(namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNamespaceCertID", reflect.TypeOf((*MockPKI)(nil).GetNamespaceCertID), namespace)
+}
+
+// GetNamespaceCert mocks base method
+func (m *MockPKI) GetNamespaceCert(namespace string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNamespaceCert", namespace)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNamespaceCert indicates an expected call of GetNamespaceCert
+func (mr *MockPKIMockRecorder) GetNamespaceCert(namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNamespaceCert", reflect.TypeOf((*MockPKI)(nil).GetNamespaceCert), namespace)
+}
+
+// RevokeNamespaceCert mocks base method
+func (m *MockPKI) RevokeNamespaceCert(namespace string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeNamespaceCert", namespace)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeNamespaceCert indicates an expected call of RevokeNamespaceCert
+func (mr *MockPKIMockRecorder) RevokeNamespaceCert(namespace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeNamespaceCert", reflect.TypeOf((*MockPKI)(nil).RevokeNamespaceCert), namespace)
+}
+
 // Close mocks base method
 func (m *MockPKI) Close() error {
 	m.ctrl.T.Helper()
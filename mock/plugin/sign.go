@@ -1,10 +1,12 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/baetyl/baetyl-cloud/v2/plugin (interfaces: Sign)
+// Source: sign.go
 
 // Package plugin is a generated GoMock package.
 package plugin
 
 import (
+	models "github.com/baetyl/baetyl-cloud/v2/models"
+	plugin "github.com/baetyl/baetyl-cloud/v2/plugin"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
 )
@@ -32,6 +34,109 @@ func (m *MockSign) EXPECT() *MockSignMockRecorder {
 	return m.recorder
 }
 
+// Signature mocks base method
+func (m *MockSign) Signature(meta []byte) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Signature", meta)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Signature indicates an expected call of Signature
+func (mr *MockSignMockRecorder) Signature(meta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Signature", reflect.TypeOf((*MockSign)(nil).Signature), meta)
+}
+
+// SignatureWithKey mocks base method
+func (m *MockSign) SignatureWithKey(keyID string, meta []byte) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignatureWithKey", keyID, meta)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignatureWithKey indicates an expected call of SignatureWithKey
+func (mr *MockSignMockRecorder) SignatureWithKey(keyID, meta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignatureWithKey", reflect.TypeOf((*MockSign)(nil).SignatureWithKey), keyID, meta)
+}
+
+// Verify mocks base method
+func (m *MockSign) Verify(meta []byte, sign []byte) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", meta, sign)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Verify indicates an expected call of Verify
+func (mr *MockSignMockRecorder) Verify(meta, sign interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockSign)(nil).Verify), meta, sign)
+}
+
+// ActiveKeyID mocks base method
+func (m *MockSign) ActiveKeyID() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActiveKeyID")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ActiveKeyID indicates an expected call of ActiveKeyID
+func (mr *MockSignMockRecorder) ActiveKeyID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActiveKeyID", reflect.TypeOf((*MockSign)(nil).ActiveKeyID))
+}
+
+// RotateKey mocks base method
+func (m *MockSign) RotateKey() (*models.SignKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateKey")
+	ret0, _ := ret[0].(*models.SignKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateKey indicates an expected call of RotateKey
+func (mr *MockSignMockRecorder) RotateKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateKey", reflect.TypeOf((*MockSign)(nil).RotateKey))
+}
+
+// ListKeys mocks base method
+func (m *MockSign) ListKeys() ([]models.SignKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListKeys")
+	ret0, _ := ret[0].([]models.SignKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListKeys indicates an expected call of ListKeys
+func (mr *MockSignMockRecorder) ListKeys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListKeys", reflect.TypeOf((*MockSign)(nil).ListKeys))
+}
+
+// RemoveKey mocks base method
+func (m *MockSign) RemoveKey(keyID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveKey", keyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveKey indicates an expected call of RemoveKey
+func (mr *MockSignMockRecorder) RemoveKey(keyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveKey", reflect.TypeOf((*MockSign)(nil).RemoveKey), keyID)
+}
+
 // Close mocks base method
 func (m *MockSign) Close() error {
 	m.ctrl.T.Helper()
@@ -46,31 +151,82 @@ func (mr *MockSignMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockSign)(nil).Close))
 }
 
-// Signature mocks base method
-func (m *MockSign) Signature(arg0 []byte) ([]byte, error) {
+// MockSignKeyStorage is a mock of SignKeyStorage interface
+type MockSignKeyStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockSignKeyStorageMockRecorder
+}
+
+// MockSignKeyStorageMockRecorder is the mock recorder for MockSignKeyStorage
+type MockSignKeyStorageMockRecorder struct {
+	mock *MockSignKeyStorage
+}
+
+// NewMockSignKeyStorage creates a new mock instance
+func NewMockSignKeyStorage(ctrl *gomock.Controller) *MockSignKeyStorage {
+	mock := &MockSignKeyStorage{ctrl: ctrl}
+	mock.recorder = &MockSignKeyStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockSignKeyStorage) EXPECT() *MockSignKeyStorageMockRecorder {
+	return m.recorder
+}
+
+// CreateSignKey mocks base method
+func (m *MockSignKeyStorage) CreateSignKey(key plugin.SignKey) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Signature", arg0)
-	ret0, _ := ret[0].([]byte)
+	ret := m.ctrl.Call(m, "CreateSignKey", key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSignKey indicates an expected call of CreateSignKey
+func (mr *MockSignKeyStorageMockRecorder) CreateSignKey(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSignKey", reflect.TypeOf((*MockSignKeyStorage)(nil).CreateSignKey), key)
+}
+
+// ListSignKey mocks base method
+func (m *MockSignKeyStorage) ListSignKey(algorithm string) ([]plugin.SignKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSignKey", algorithm)
+	ret0, _ := ret[0].([]plugin.SignKey)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Signature indicates an expected call of Signature
-func (mr *MockSignMockRecorder) Signature(arg0 interface{}) *gomock.Call {
+// ListSignKey indicates an expected call of ListSignKey
+func (mr *MockSignKeyStorageMockRecorder) ListSignKey(algorithm interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Signature", reflect.TypeOf((*MockSign)(nil).Signature), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSignKey", reflect.TypeOf((*MockSignKeyStorage)(nil).ListSignKey), algorithm)
 }
 
-// Verify mocks base method
-func (m *MockSign) Verify(arg0, arg1 []byte) bool {
+// DeleteSignKey mocks base method
+func (m *MockSignKeyStorage) DeleteSignKey(algorithm string, keyID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Verify", arg0, arg1)
-	ret0, _ := ret[0].(bool)
+	ret := m.ctrl.Call(m, "DeleteSignKey", algorithm, keyID)
+	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Verify indicates an expected call of Verify
-func (mr *MockSignMockRecorder) Verify(arg0, arg1 interface{}) *gomock.Call {
+// DeleteSignKey indicates an expected call of DeleteSignKey
+func (mr *MockSignKeyStorageMockRecorder) DeleteSignKey(algorithm, keyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSignKey", reflect.TypeOf((*MockSignKeyStorage)(nil).DeleteSignKey), algorithm, keyID)
+}
+
+// Close mocks base method
+func (m *MockSignKeyStorage) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close
+func (mr *MockSignKeyStorageMockRecorder) Close() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockSign)(nil).Verify), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockSignKeyStorage)(nil).Close))
 }
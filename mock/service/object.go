@@ -5,35 +5,36 @@
 package service
 
 import (
+	reflect "reflect"
+
 	models "github.com/baetyl/baetyl-cloud/v2/models"
 	gomock "github.com/golang/mock/gomock"
-	reflect "reflect"
 )
 
-// MockObjectService is a mock of ObjectService interface
+// MockObjectService is a mock of ObjectService interface.
 type MockObjectService struct {
 	ctrl     *gomock.Controller
 	recorder *MockObjectServiceMockRecorder
 }
 
-// MockObjectServiceMockRecorder is the mock recorder for MockObjectService
+// MockObjectServiceMockRecorder is the mock recorder for MockObjectService.
 type MockObjectServiceMockRecorder struct {
 	mock *MockObjectService
 }
 
-// NewMockObjectService creates a new mock instance
+// NewMockObjectService creates a new mock instance.
 func NewMockObjectService(ctrl *gomock.Controller) *MockObjectService {
 	mock := &MockObjectService{ctrl: ctrl}
 	mock.recorder = &MockObjectServiceMockRecorder{mock}
 	return mock
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use
+// EXPECT returns an object that allows the caller to indicate expected use.
 func (m *MockObjectService) EXPECT() *MockObjectServiceMockRecorder {
 	return m.recorder
 }
 
-// CreateExternalBucket mocks base method
+// CreateExternalBucket mocks base method.
 func (m *MockObjectService) CreateExternalBucket(arg0 models.ExternalObjectInfo, arg1, arg2, arg3 string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateExternalBucket", arg0, arg1, arg2, arg3)
@@ -41,13 +42,13 @@ func (m *MockObjectService) CreateExternalBucket(arg0 models.ExternalObjectInfo,
 	return ret0
 }
 
-// CreateExternalBucket indicates an expected call of CreateExternalBucket
+// CreateExternalBucket indicates an expected call of CreateExternalBucket.
 func (mr *MockObjectServiceMockRecorder) CreateExternalBucket(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExternalBucket", reflect.TypeOf((*MockObjectService)(nil).CreateExternalBucket), arg0, arg1, arg2, arg3)
 }
 
-// CreateInternalBucketIfNotExist mocks base method
+// CreateInternalBucketIfNotExist mocks base method.
 func (m *MockObjectService) CreateInternalBucketIfNotExist(arg0, arg1, arg2, arg3 string) (*models.Bucket, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateInternalBucketIfNotExist", arg0, arg1, arg2, arg3)
@@ -56,13 +57,13 @@ func (m *MockObjectService) CreateInternalBucketIfNotExist(arg0, arg1, arg2, arg
 	return ret0, ret1
 }
 
-// CreateInternalBucketIfNotExist indicates an expected call of CreateInternalBucketIfNotExist
+// CreateInternalBucketIfNotExist indicates an expected call of CreateInternalBucketIfNotExist.
 func (mr *MockObjectServiceMockRecorder) CreateInternalBucketIfNotExist(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInternalBucketIfNotExist", reflect.TypeOf((*MockObjectService)(nil).CreateInternalBucketIfNotExist), arg0, arg1, arg2, arg3)
 }
 
-// DeleteExternalObject mocks base method
+// DeleteExternalObject mocks base method.
 func (m *MockObjectService) DeleteExternalObject(arg0 models.ExternalObjectInfo, arg1, arg2, arg3 string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "DeleteExternalObject", arg0, arg1, arg2, arg3)
@@ -70,13 +71,27 @@ func (m *MockObjectService) DeleteExternalObject(arg0 models.ExternalObjectInfo,
 	return ret0
 }
 
-// DeleteExternalObject indicates an expected call of DeleteExternalObject
+// DeleteExternalObject indicates an expected call of DeleteExternalObject.
 func (mr *MockObjectServiceMockRecorder) DeleteExternalObject(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExternalObject", reflect.TypeOf((*MockObjectService)(nil).DeleteExternalObject), arg0, arg1, arg2, arg3)
 }
 
-// GenExternalObjectURL mocks base method
+// DeleteInternalObject mocks base method.
+func (m *MockObjectService) DeleteInternalObject(arg0, arg1, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteInternalObject", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteInternalObject indicates an expected call of DeleteInternalObject.
+func (mr *MockObjectServiceMockRecorder) DeleteInternalObject(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteInternalObject", reflect.TypeOf((*MockObjectService)(nil).DeleteInternalObject), arg0, arg1, arg2, arg3)
+}
+
+// GenExternalObjectURL mocks base method.
 func (m *MockObjectService) GenExternalObjectURL(arg0 models.ExternalObjectInfo, arg1, arg2, arg3 string) (*models.ObjectURL, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GenExternalObjectURL", arg0, arg1, arg2, arg3)
@@ -85,13 +100,13 @@ func (m *MockObjectService) GenExternalObjectURL(arg0 models.ExternalObjectInfo,
 	return ret0, ret1
 }
 
-// GenExternalObjectURL indicates an expected call of GenExternalObjectURL
+// GenExternalObjectURL indicates an expected call of GenExternalObjectURL.
 func (mr *MockObjectServiceMockRecorder) GenExternalObjectURL(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenExternalObjectURL", reflect.TypeOf((*MockObjectService)(nil).GenExternalObjectURL), arg0, arg1, arg2, arg3)
 }
 
-// GenInternalObjectPutURL mocks base method
+// GenInternalObjectPutURL mocks base method.
 func (m *MockObjectService) GenInternalObjectPutURL(arg0, arg1, arg2, arg3 string) (*models.ObjectURL, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GenInternalObjectPutURL", arg0, arg1, arg2, arg3)
@@ -100,13 +115,13 @@ func (m *MockObjectService) GenInternalObjectPutURL(arg0, arg1, arg2, arg3 strin
 	return ret0, ret1
 }
 
-// GenInternalObjectPutURL indicates an expected call of GenInternalObjectPutURL
+// GenInternalObjectPutURL indicates an expected call of GenInternalObjectPutURL.
 func (mr *MockObjectServiceMockRecorder) GenInternalObjectPutURL(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenInternalObjectPutURL", reflect.TypeOf((*MockObjectService)(nil).GenInternalObjectPutURL), arg0, arg1, arg2, arg3)
 }
 
-// GenInternalObjectURL mocks base method
+// GenInternalObjectURL mocks base method.
 func (m *MockObjectService) GenInternalObjectURL(arg0, arg1, arg2, arg3 string) (*models.ObjectURL, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GenInternalObjectURL", arg0, arg1, arg2, arg3)
@@ -115,13 +130,13 @@ func (m *MockObjectService) GenInternalObjectURL(arg0, arg1, arg2, arg3 string)
 	return ret0, ret1
 }
 
-// GenInternalObjectURL indicates an expected call of GenInternalObjectURL
+// GenInternalObjectURL indicates an expected call of GenInternalObjectURL.
 func (mr *MockObjectServiceMockRecorder) GenInternalObjectURL(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenInternalObjectURL", reflect.TypeOf((*MockObjectService)(nil).GenInternalObjectURL), arg0, arg1, arg2, arg3)
 }
 
-// GetExternalObject mocks base method
+// GetExternalObject mocks base method.
 func (m *MockObjectService) GetExternalObject(arg0 models.ExternalObjectInfo, arg1, arg2, arg3 string) (*models.Object, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetExternalObject", arg0, arg1, arg2, arg3)
@@ -130,13 +145,28 @@ func (m *MockObjectService) GetExternalObject(arg0 models.ExternalObjectInfo, ar
 	return ret0, ret1
 }
 
-// GetExternalObject indicates an expected call of GetExternalObject
+// GetExternalObject indicates an expected call of GetExternalObject.
 func (mr *MockObjectServiceMockRecorder) GetExternalObject(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExternalObject", reflect.TypeOf((*MockObjectService)(nil).GetExternalObject), arg0, arg1, arg2, arg3)
 }
 
-// HeadExternalObject mocks base method
+// GetInternalObject mocks base method.
+func (m *MockObjectService) GetInternalObject(arg0, arg1, arg2, arg3 string) (*models.Object, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInternalObject", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.Object)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInternalObject indicates an expected call of GetInternalObject.
+func (mr *MockObjectServiceMockRecorder) GetInternalObject(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInternalObject", reflect.TypeOf((*MockObjectService)(nil).GetInternalObject), arg0, arg1, arg2, arg3)
+}
+
+// HeadExternalObject mocks base method.
 func (m *MockObjectService) HeadExternalObject(arg0 models.ExternalObjectInfo, arg1, arg2, arg3 string) (*models.ObjectMeta, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "HeadExternalObject", arg0, arg1, arg2, arg3)
@@ -145,13 +175,13 @@ func (m *MockObjectService) HeadExternalObject(arg0 models.ExternalObjectInfo, a
 	return ret0, ret1
 }
 
-// HeadExternalObject indicates an expected call of HeadExternalObject
+// HeadExternalObject indicates an expected call of HeadExternalObject.
 func (mr *MockObjectServiceMockRecorder) HeadExternalObject(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HeadExternalObject", reflect.TypeOf((*MockObjectService)(nil).HeadExternalObject), arg0, arg1, arg2, arg3)
 }
 
-// HeadInternalObject mocks base method
+// HeadInternalObject mocks base method.
 func (m *MockObjectService) HeadInternalObject(arg0, arg1, arg2, arg3 string) (*models.ObjectMeta, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "HeadInternalObject", arg0, arg1, arg2, arg3)
@@ -160,13 +190,13 @@ func (m *MockObjectService) HeadInternalObject(arg0, arg1, arg2, arg3 string) (*
 	return ret0, ret1
 }
 
-// HeadInternalObject indicates an expected call of HeadInternalObject
+// HeadInternalObject indicates an expected call of HeadInternalObject.
 func (mr *MockObjectServiceMockRecorder) HeadInternalObject(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HeadInternalObject", reflect.TypeOf((*MockObjectService)(nil).HeadInternalObject), arg0, arg1, arg2, arg3)
 }
 
-// ListExternalBucketObjects mocks base method
+// ListExternalBucketObjects mocks base method.
 func (m *MockObjectService) ListExternalBucketObjects(arg0 models.ExternalObjectInfo, arg1, arg2 string) (*models.ListObjectsResult, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListExternalBucketObjects", arg0, arg1, arg2)
@@ -175,13 +205,13 @@ func (m *MockObjectService) ListExternalBucketObjects(arg0 models.ExternalObject
 	return ret0, ret1
 }
 
-// ListExternalBucketObjects indicates an expected call of ListExternalBucketObjects
+// ListExternalBucketObjects indicates an expected call of ListExternalBucketObjects.
 func (mr *MockObjectServiceMockRecorder) ListExternalBucketObjects(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExternalBucketObjects", reflect.TypeOf((*MockObjectService)(nil).ListExternalBucketObjects), arg0, arg1, arg2)
 }
 
-// ListExternalBuckets mocks base method
+// ListExternalBuckets mocks base method.
 func (m *MockObjectService) ListExternalBuckets(arg0 models.ExternalObjectInfo, arg1 string) ([]models.Bucket, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListExternalBuckets", arg0, arg1)
@@ -190,13 +220,13 @@ func (m *MockObjectService) ListExternalBuckets(arg0 models.ExternalObjectInfo,
 	return ret0, ret1
 }
 
-// ListExternalBuckets indicates an expected call of ListExternalBuckets
+// ListExternalBuckets indicates an expected call of ListExternalBuckets.
 func (mr *MockObjectServiceMockRecorder) ListExternalBuckets(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExternalBuckets", reflect.TypeOf((*MockObjectService)(nil).ListExternalBuckets), arg0, arg1)
 }
 
-// ListInternalBucketObjects mocks base method
+// ListInternalBucketObjects mocks base method.
 func (m *MockObjectService) ListInternalBucketObjects(arg0, arg1, arg2 string) (*models.ListObjectsResult, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListInternalBucketObjects", arg0, arg1, arg2)
@@ -205,13 +235,13 @@ func (m *MockObjectService) ListInternalBucketObjects(arg0, arg1, arg2 string) (
 	return ret0, ret1
 }
 
-// ListInternalBucketObjects indicates an expected call of ListInternalBucketObjects
+// ListInternalBucketObjects indicates an expected call of ListInternalBucketObjects.
 func (mr *MockObjectServiceMockRecorder) ListInternalBucketObjects(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInternalBucketObjects", reflect.TypeOf((*MockObjectService)(nil).ListInternalBucketObjects), arg0, arg1, arg2)
 }
 
-// ListInternalBuckets mocks base method
+// ListInternalBuckets mocks base method.
 func (m *MockObjectService) ListInternalBuckets(arg0, arg1 string) ([]models.Bucket, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListInternalBuckets", arg0, arg1)
@@ -220,13 +250,13 @@ func (m *MockObjectService) ListInternalBuckets(arg0, arg1 string) ([]models.Buc
 	return ret0, ret1
 }
 
-// ListInternalBuckets indicates an expected call of ListInternalBuckets
+// ListInternalBuckets indicates an expected call of ListInternalBuckets.
 func (mr *MockObjectServiceMockRecorder) ListInternalBuckets(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInternalBuckets", reflect.TypeOf((*MockObjectService)(nil).ListInternalBuckets), arg0, arg1)
 }
 
-// ListSources mocks base method
+// ListSources mocks base method.
 func (m *MockObjectService) ListSources() map[string]models.ObjectStorageSourceV2 {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListSources")
@@ -234,13 +264,13 @@ func (m *MockObjectService) ListSources() map[string]models.ObjectStorageSourceV
 	return ret0
 }
 
-// ListSources indicates an expected call of ListSources
+// ListSources indicates an expected call of ListSources.
 func (mr *MockObjectServiceMockRecorder) ListSources() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSources", reflect.TypeOf((*MockObjectService)(nil).ListSources))
 }
 
-// PutExternalObject mocks base method
+// PutExternalObject mocks base method.
 func (m *MockObjectService) PutExternalObject(arg0 models.ExternalObjectInfo, arg1, arg2, arg3 string, arg4 []byte) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "PutExternalObject", arg0, arg1, arg2, arg3, arg4)
@@ -248,13 +278,13 @@ func (m *MockObjectService) PutExternalObject(arg0 models.ExternalObjectInfo, ar
 	return ret0
 }
 
-// PutExternalObject indicates an expected call of PutExternalObject
+// PutExternalObject indicates an expected call of PutExternalObject.
 func (mr *MockObjectServiceMockRecorder) PutExternalObject(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutExternalObject", reflect.TypeOf((*MockObjectService)(nil).PutExternalObject), arg0, arg1, arg2, arg3, arg4)
 }
 
-// PutExternalObjectFromURL mocks base method
+// PutExternalObjectFromURL mocks base method.
 func (m *MockObjectService) PutExternalObjectFromURL(arg0 models.ExternalObjectInfo, arg1, arg2, arg3, arg4 string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "PutExternalObjectFromURL", arg0, arg1, arg2, arg3, arg4)
@@ -262,13 +292,13 @@ func (m *MockObjectService) PutExternalObjectFromURL(arg0 models.ExternalObjectI
 	return ret0
 }
 
-// PutExternalObjectFromURL indicates an expected call of PutExternalObjectFromURL
+// PutExternalObjectFromURL indicates an expected call of PutExternalObjectFromURL.
 func (mr *MockObjectServiceMockRecorder) PutExternalObjectFromURL(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutExternalObjectFromURL", reflect.TypeOf((*MockObjectService)(nil).PutExternalObjectFromURL), arg0, arg1, arg2, arg3, arg4)
 }
 
-// PutInternalObject mocks base method
+// PutInternalObject mocks base method.
 func (m *MockObjectService) PutInternalObject(arg0, arg1, arg2, arg3 string, arg4 []byte) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "PutInternalObject", arg0, arg1, arg2, arg3, arg4)
@@ -276,13 +306,27 @@ func (m *MockObjectService) PutInternalObject(arg0, arg1, arg2, arg3 string, arg
 	return ret0
 }
 
-// PutInternalObject indicates an expected call of PutInternalObject
+// PutInternalObject indicates an expected call of PutInternalObject.
 func (mr *MockObjectServiceMockRecorder) PutInternalObject(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutInternalObject", reflect.TypeOf((*MockObjectService)(nil).PutInternalObject), arg0, arg1, arg2, arg3, arg4)
 }
 
-// PutInternalObjectFromURLIfNotExist mocks base method
+// PutInternalObjectFromFile mocks base method.
+func (m *MockObjectService) PutInternalObjectFromFile(arg0, arg1, arg2, arg3, arg4 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutInternalObjectFromFile", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutInternalObjectFromFile indicates an expected call of PutInternalObjectFromFile.
+func (mr *MockObjectServiceMockRecorder) PutInternalObjectFromFile(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutInternalObjectFromFile", reflect.TypeOf((*MockObjectService)(nil).PutInternalObjectFromFile), arg0, arg1, arg2, arg3, arg4)
+}
+
+// PutInternalObjectFromURLIfNotExist mocks base method.
 func (m *MockObjectService) PutInternalObjectFromURLIfNotExist(arg0, arg1, arg2, arg3, arg4 string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "PutInternalObjectFromURLIfNotExist", arg0, arg1, arg2, arg3, arg4)
@@ -290,7 +334,7 @@ func (m *MockObjectService) PutInternalObjectFromURLIfNotExist(arg0, arg1, arg2,
 	return ret0
 }
 
-// PutInternalObjectFromURLIfNotExist indicates an expected call of PutInternalObjectFromURLIfNotExist
+// PutInternalObjectFromURLIfNotExist indicates an expected call of PutInternalObjectFromURLIfNotExist.
 func (mr *MockObjectServiceMockRecorder) PutInternalObjectFromURLIfNotExist(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutInternalObjectFromURLIfNotExist", reflect.TypeOf((*MockObjectService)(nil).PutInternalObjectFromURLIfNotExist), arg0, arg1, arg2, arg3, arg4)
@@ -91,6 +91,35 @@ func (mr *MockPKIServiceMockRecorder) SignClientCertificate(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignClientCertificate", reflect.TypeOf((*MockPKIService)(nil).SignClientCertificate), arg0, arg1)
 }
 
+// RevokeNamespaceCA mocks base method
+func (m *MockPKIService) RevokeNamespaceCA(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeNamespaceCA", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeNamespaceCA indicates an expected call of RevokeNamespaceCA
+func (mr *MockPKIServiceMockRecorder) RevokeNamespaceCA(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeNamespaceCA", reflect.TypeOf((*MockPKIService)(nil).RevokeNamespaceCA), arg0)
+}
+
+// SignNodeCertificate mocks base method
+func (m *MockPKIService) SignNodeCertificate(arg0, arg1 string, arg2 models.AltNames) (*models.PEMCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignNodeCertificate", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.PEMCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignNodeCertificate indicates an expected call of SignNodeCertificate
+func (mr *MockPKIServiceMockRecorder) SignNodeCertificate(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignNodeCertificate", reflect.TypeOf((*MockPKIService)(nil).SignNodeCertificate), arg0, arg1, arg2)
+}
+
 // SignServerCertificate mocks base method
 func (m *MockPKIService) SignServerCertificate(arg0 string, arg1 models.AltNames) (*models.PEMCredential, error) {
 	m.ctrl.T.Helper()
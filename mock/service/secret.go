@@ -109,6 +109,35 @@ func (mr *MockSecretServiceMockRecorder) List(arg0, arg1 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockSecretService)(nil).List), arg0, arg1)
 }
 
+// PurgeDataKeys mocks base method.
+func (m *MockSecretService) PurgeDataKeys() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDataKeys")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeDataKeys indicates an expected call of PurgeDataKeys.
+func (mr *MockSecretServiceMockRecorder) PurgeDataKeys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDataKeys", reflect.TypeOf((*MockSecretService)(nil).PurgeDataKeys))
+}
+
+// Reencrypt mocks base method.
+func (m *MockSecretService) Reencrypt(arg0 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reencrypt", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reencrypt indicates an expected call of Reencrypt.
+func (mr *MockSecretServiceMockRecorder) Reencrypt(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reencrypt", reflect.TypeOf((*MockSecretService)(nil).Reencrypt), arg0)
+}
+
 // Update mocks base method.
 func (m *MockSecretService) Update(arg0 string, arg1 *v1.Secret) (*v1.Secret, error) {
 	m.ctrl.T.Helper()
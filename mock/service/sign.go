@@ -5,6 +5,7 @@
 package service
 
 import (
+	models "github.com/baetyl/baetyl-cloud/v2/models"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
 )
@@ -32,6 +33,21 @@ func (m *MockSignService) EXPECT() *MockSignServiceMockRecorder {
 	return m.recorder
 }
 
+// ActiveKeyID mocks base method
+func (m *MockSignService) ActiveKeyID() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActiveKeyID")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ActiveKeyID indicates an expected call of ActiveKeyID
+func (mr *MockSignServiceMockRecorder) ActiveKeyID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActiveKeyID", reflect.TypeOf((*MockSignService)(nil).ActiveKeyID))
+}
+
 // Close mocks base method
 func (m *MockSignService) Close() error {
 	m.ctrl.T.Helper()
@@ -61,6 +77,65 @@ func (mr *MockSignServiceMockRecorder) GenToken(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenToken", reflect.TypeOf((*MockSignService)(nil).GenToken), arg0)
 }
 
+// ListKeys mocks base method
+func (m *MockSignService) ListKeys() ([]models.SignKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListKeys")
+	ret0, _ := ret[0].([]models.SignKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListKeys indicates an expected call of ListKeys
+func (mr *MockSignServiceMockRecorder) ListKeys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListKeys", reflect.TypeOf((*MockSignService)(nil).ListKeys))
+}
+
+// RemoveKey mocks base method
+func (m *MockSignService) RemoveKey(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveKey", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveKey indicates an expected call of RemoveKey
+func (mr *MockSignServiceMockRecorder) RemoveKey(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveKey", reflect.TypeOf((*MockSignService)(nil).RemoveKey), arg0)
+}
+
+// RotateKey mocks base method
+func (m *MockSignService) RotateKey() (*models.SignKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateKey")
+	ret0, _ := ret[0].(*models.SignKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateKey indicates an expected call of RotateKey
+func (mr *MockSignServiceMockRecorder) RotateKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateKey", reflect.TypeOf((*MockSignService)(nil).RotateKey))
+}
+
+// SignToken mocks base method
+func (m *MockSignService) SignToken(arg0 map[string]interface{}) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignToken", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignToken indicates an expected call of SignToken
+func (mr *MockSignServiceMockRecorder) SignToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignToken", reflect.TypeOf((*MockSignService)(nil).SignToken), arg0)
+}
+
 // Signature mocks base method
 func (m *MockSignService) Signature(arg0 []byte) ([]byte, error) {
 	m.ctrl.T.Helper()
@@ -76,8 +151,23 @@ func (mr *MockSignServiceMockRecorder) Signature(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Signature", reflect.TypeOf((*MockSignService)(nil).Signature), arg0)
 }
 
+// SignatureWithKey mocks base method
+func (m *MockSignService) SignatureWithKey(arg0 string, arg1 []byte) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignatureWithKey", arg0, arg1)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignatureWithKey indicates an expected call of SignatureWithKey
+func (mr *MockSignServiceMockRecorder) SignatureWithKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignatureWithKey", reflect.TypeOf((*MockSignService)(nil).SignatureWithKey), arg0, arg1)
+}
+
 // Verify mocks base method
-func (m *MockSignService) Verify(arg0, arg1 []byte) bool {
+func (m *MockSignService) Verify(arg0 []byte, arg1 []byte) bool {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Verify", arg0, arg1)
 	ret0, _ := ret[0].(bool)
@@ -0,0 +1,37 @@
+// Package models 模型定义
+package models
+
+import (
+	"time"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+// AppHistory a revision of application, Configs and Secrets record the versions referenced by the revision
+type AppHistory struct {
+	Namespace   string              `json:"namespace,omitempty"`
+	Name        string              `json:"name,omitempty"`
+	Revision    int64               `json:"revision"`
+	Version     string              `json:"version,omitempty"`
+	Application *specV1.Application `json:"application,omitempty"`
+	Configs     map[string]string   `json:"configs,omitempty"`
+	Secrets     map[string]string   `json:"secrets,omitempty"`
+	CreateTime  time.Time           `json:"createTime,omitempty"`
+}
+
+// AppHistoryList app revision list
+type AppHistoryList struct {
+	Total        int `json:"total"`
+	*ListOptions `json:",inline"`
+	Items        []AppHistory `json:"items"`
+}
+
+// AppRevisionDiff changes between two revisions of application
+type AppRevisionDiff struct {
+	Name    string             `json:"name"`
+	From    int64              `json:"from"`
+	To      int64              `json:"to"`
+	Changes []common.FieldDiff `json:"changes"`
+}
@@ -35,6 +35,8 @@ type ApplicationView struct {
 	Ota               specV1.OtaInfo        `json:"ota,omitempty"`
 	AutoScaleCfg      *specV1.AutoScaleCfg  `json:"autoScaleCfg,omitempty"`
 	PreserveUpdates   bool                  `json:"preserveUpdates,omitempty"`
+	// the warnings of image validation if validate=warn is set
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 func (a *ApplicationView) ImageTrim() {
@@ -1,16 +1,33 @@
 package models
 
 import (
+	"time"
+
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 )
 
+// the aggregated status of application on node
+const (
+	AppNodeConverged = "converged"
+	AppNodePending   = "pending"
+	AppNodeFailed    = "failed"
+	AppNodeOffline   = "offline"
+)
+
 type AppStatusParams struct {
-	App []AppStatusParamsItem `json:"appParams" binding:"required"`
+	App []AppStatusParamsItem `json:"appParams" binding:"required,dive"`
 }
 
 type AppStatusParamsItem struct {
-	Name  string   `json:"name" binding:"required"`
-	Nodes []string `json:"nodes" binding:"required"`
+	Name string `json:"name" binding:"required"`
+	// only the given nodes are listed if set, otherwise all matched nodes of the app
+	Nodes []string `json:"nodes"`
+}
+
+// AppNodeStatusFilter filters and paginates the nodes of each app
+type AppNodeStatusFilter struct {
+	Status string `form:"status,omitempty" json:"status,omitempty" binding:"omitempty,oneof=converged pending failed offline"`
+	Filter `json:",inline"`
 }
 
 type AppNodeStatusReturn struct {
@@ -20,4 +37,29 @@ type AppNodeStatusReturn struct {
 type AppNodeStatusItem struct {
 	AppInfo    *AppItem                    `json:"app_info"`
 	NodeReport map[string]*specV1.AppStats `json:"node_report"`
+	Summary    AppStatusSummary            `json:"summary"`
+	Nodes      []AppNodeStatus             `json:"nodes"`
+	Total      int                         `json:"total"`
+	PageNo     int                         `json:"pageNo,omitempty"`
+	PageSize   int                         `json:"pageSize,omitempty"`
+}
+
+// AppStatusSummary the number of nodes in each status, counted before filtering and pagination
+type AppStatusSummary struct {
+	Converged int `json:"converged"`
+	Pending   int `json:"pending"`
+	Failed    int `json:"failed"`
+	Offline   int `json:"offline"`
+}
+
+// AppNodeStatus the status of application on one node
+type AppNodeStatus struct {
+	Node           string            `json:"node"`
+	Status         string            `json:"status"`
+	DesireVersion  string            `json:"desireVersion,omitempty"`
+	ReportVersion  string            `json:"reportVersion,omitempty"`
+	AppStatus      specV1.Status     `json:"appStatus,omitempty"`
+	Cause          string            `json:"cause,omitempty"`
+	InstanceErrors map[string]string `json:"instanceErrors,omitempty"`
+	ReportTime     *time.Time        `json:"reportTime,omitempty"`
 }
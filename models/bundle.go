@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+)
+
+const (
+	BundleConflictSkip      = "skip"
+	BundleConflictRename    = "rename"
+	BundleConflictOverwrite = "overwrite"
+
+	BundleActionCreated     = "created"
+	BundleActionSkipped     = "skipped"
+	BundleActionRenamed     = "renamed"
+	BundleActionOverwritten = "overwritten"
+)
+
+// AppBundle the portable bundle of application with the configs and secrets (including registries and certificates) it references
+type AppBundle struct {
+	// the source namespace
+	Namespace   string              `json:"namespace,omitempty"`
+	ExportTime  time.Time           `json:"exportTime,omitempty"`
+	Application *specV1.Application `json:"application" binding:"required"`
+	// the configs referenced by the volumes of application, including the generated function program configs
+	Configs []specV1.Configuration `json:"configs,omitempty" binding:"dive"`
+	Secrets []specV1.Secret        `json:"secrets,omitempty" binding:"dive"`
+	// the data of secrets is cleared if redacted, the secrets must exist in the target namespace when importing
+	Redacted bool `json:"redacted,omitempty"`
+}
+
+// AppBundleImportResult the result of importing bundle, one item for each resource
+type AppBundleImportResult struct {
+	Items []AppBundleImportItem `json:"items"`
+}
+
+type AppBundleImportItem struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// the name in the target namespace, which differs from the name if renamed
+	Target string `json:"target"`
+	Action string `json:"action"`
+}
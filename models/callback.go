@@ -4,14 +4,19 @@ package models
 import "time"
 
 type Callback struct {
-	Name        string            `json:"name,omitempty" binding:"omitempty,res_name"`
-	Namespace   string            `json:"namespace,omitempty"`
-	Method      string            `json:"method,omitempty" binding:"required"`
-	URL         string            `json:"url,omitempty" binding:"required"`
-	Params      map[string]string `json:"params,omitempty"`
-	Header      map[string]string `json:"header,omitempty"`
-	Body        map[string]string `json:"body,omitempty"`
-	Description string            `json:"description,omitempty"`
-	CreateTime  time.Time         `json:"createTime,omitempty"`
-	UpdateTime  time.Time         `json:"updateTime,omitempty"`
+	Name      string            `json:"name,omitempty" binding:"omitempty,res_name"`
+	Namespace string            `json:"namespace,omitempty"`
+	Method    string            `json:"method,omitempty" binding:"required"`
+	URL       string            `json:"url,omitempty" binding:"required"`
+	Params    map[string]string `json:"params,omitempty"`
+	Header    map[string]string `json:"header,omitempty"`
+	// the values of body are the templates rendered with the event
+	Body map[string]string `json:"body,omitempty"`
+	// the events which the callback subscribes
+	Events []string `json:"events,omitempty"`
+	// the key of the HMAC-SHA256 signatures of the webhook requests, which is generated on creation and returned only once
+	Secret      string    `json:"secret,omitempty"`
+	Description string    `json:"description,omitempty"`
+	CreateTime  time.Time `json:"createTime,omitempty"`
+	UpdateTime  time.Time `json:"updateTime,omitempty"`
 }
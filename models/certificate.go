@@ -53,6 +53,9 @@ type Certificate struct {
 	SerialNumber       string              `json:"serialNumber,omitempty"`
 	Issuer             string              `json:"issuer,omitempty"`
 	FingerPrint        string              `json:"fingerPrint,omitempty"`
+	NotBefore          *time.Time          `json:"notBefore,omitempty"`
+	NotAfter           *time.Time          `json:"notAfter,omitempty"`
+	SANs               []string            `json:"sans,omitempty"`
 	Data               CertificateDataItem `json:"data,omitempty"`
 	CreationTimestamp  time.Time           `json:"createTime,omitempty"`
 	UpdateTimestamp    time.Time           `json:"updateTime,omitempty"`
@@ -106,28 +109,61 @@ func (r *Certificate) ParseCertInfo() error {
 		return err
 	}
 
-	var block *pem.Block
-	rest := []byte(r.Data.Certificate)
-	block, rest = pem.Decode(rest)
-	if block == nil {
-		return errors.New("failed to find cert that matched private key")
-	}
-
-	cert, err := x509.ParseCertificate(block.Bytes)
+	cert, err := ParseCertificatePEM([]byte(r.Data.Certificate))
 	if err != nil {
-		return errors.Errorf("failed to parse certificate, err: %s", err)
+		return err
 	}
 
-	r.FingerPrint = fingerprint(block.Bytes)
+	r.FingerPrint = fingerprint(cert.Raw)
 	r.Issuer = cert.Issuer.CommonName
 	r.ExpiredTime = cert.NotAfter.String()
 	r.EffectiveTime = cert.NotBefore.String()
 	r.SerialNumber = cert.SerialNumber.String()
 	r.SignatureAlgorithm = cert.SignatureAlgorithm.String()
+	r.setValidity(cert)
 
 	return nil
 }
 
+// setValidity sets the validity and the subject alternative names parsed from the certificate
+func (r *Certificate) setValidity(cert *x509.Certificate) {
+	notBefore, notAfter := cert.NotBefore, cert.NotAfter
+	r.NotBefore = &notBefore
+	r.NotAfter = &notAfter
+	r.SANs = CertificateSANs(cert)
+}
+
+// ParseCertificatePEM parses the first certificate of the pem data
+func ParseCertificatePEM(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("failed to find cert that matched private key")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, errors.Errorf("failed to parse certificate, err: %s", err)
+	}
+	return cert, nil
+}
+
+// CertificateSANs returns the subject alternative names of the certificate, such as "DNS:localhost" and "IP:127.0.0.1"
+func CertificateSANs(cert *x509.Certificate) []string {
+	var sans []string
+	for _, v := range cert.DNSNames {
+		sans = append(sans, "DNS:"+v)
+	}
+	for _, v := range cert.IPAddresses {
+		sans = append(sans, "IP:"+v.String())
+	}
+	for _, v := range cert.EmailAddresses {
+		sans = append(sans, "email:"+v)
+	}
+	for _, v := range cert.URIs {
+		sans = append(sans, "URI:"+v.String())
+	}
+	return sans
+}
+
 func fingerprint(data []byte) string {
 	digest := sha256.Sum256(data)
 	buf := &bytes.Buffer{}
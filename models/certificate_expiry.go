@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+const (
+	// CertificateKindUser the certificate uploaded by user
+	CertificateKindUser = "certificate"
+	// CertificateKindNode the certificate issued by the pki for the node to connect to cloud
+	CertificateKindNode = "nodeCertificate"
+
+	CertificateExpiryEventType = "certificate.expiring"
+)
+
+// ExpiringCertificate the certificate expiring soon (or expired) with the apps referencing it
+type ExpiringCertificate struct {
+	Kind string `json:"kind"`
+	// the name of the secret storing the certificate
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	// the node which the node certificate is issued for
+	Node      string    `json:"node,omitempty"`
+	Issuer    string    `json:"issuer,omitempty"`
+	NotBefore time.Time `json:"notBefore"`
+	NotAfter  time.Time `json:"notAfter"`
+	SANs      []string  `json:"sans,omitempty"`
+	Expired   bool      `json:"expired"`
+	Apps      []string  `json:"apps"`
+}
+
+// ExpiringCertificateList the certificates expiring within the days, sorted by the expiry time
+type ExpiringCertificateList struct {
+	Days  int                   `json:"days"`
+	Total int                   `json:"total"`
+	Items []ExpiringCertificate `json:"items"`
+}
+
+// ExpiringCertificateOptions the options of listing expiring certificates
+type ExpiringCertificateOptions struct {
+	Days int `form:"days,default=30" binding:"min=0"`
+}
+
+// CertificateExpiryEvent the event sent to the callbacks of namespace
+type CertificateExpiryEvent struct {
+	Type      string                `json:"type"`
+	Namespace string                `json:"namespace"`
+	Time      time.Time             `json:"time"`
+	Days      int                   `json:"days"`
+	Items     []ExpiringCertificate `json:"items"`
+}
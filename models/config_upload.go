@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// ConfigObjectUpload the object uploaded into the internal bucket of namespace for the config
+type ConfigObjectUpload struct {
+	Source string `json:"source"`
+	Bucket string `json:"bucket"`
+	Object string `json:"object"`
+	MD5    string `json:"md5"`
+	Size   int64  `json:"size"`
+}
+
+// ConfigUpload the resumable upload of the config object, the file is uploaded in chunks and assembled when completed
+type ConfigUpload struct {
+	ID     string `json:"id"`
+	Config string `json:"config"`
+	// the key of the config object entry
+	Key       string `json:"key" binding:"required,config_key"`
+	Filename  string `json:"filename" binding:"required"`
+	Size      int64  `json:"size" binding:"required,min=1"`
+	ChunkSize int64  `json:"chunkSize"`
+	Chunks    int    `json:"chunks"`
+	// the md5 of the whole file, which is verified when completed if set
+	MD5    string `json:"md5,omitempty"`
+	Unpack string `json:"unpack,omitempty"`
+	// the indexes of the chunks uploaded, the missing ones should be uploaded to resume
+	Uploaded   []int     `json:"uploaded"`
+	CreateTime time.Time `json:"createTime"`
+}
+
+// ConfigObjectUploadOptions the options of the file uploaded directly, the key defaults to the filename
+type ConfigObjectUploadOptions struct {
+	Key    string `form:"key" binding:"omitempty,config_key"`
+	Unpack string `form:"unpack"`
+}
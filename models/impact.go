@@ -0,0 +1,30 @@
+package models
+
+import "github.com/baetyl/baetyl-cloud/v2/common"
+
+// PendingVersion the version of application which is generated when the change is applied
+const PendingVersion = "<pending>"
+
+// ChangeImpact the impact of the change of resource on the desire of nodes
+type ChangeImpact struct {
+	// the changed fields of resource
+	Changes []common.FieldDiff `json:"changes,omitempty"`
+	// the apps whose version will be updated
+	Apps    []string         `json:"apps,omitempty"`
+	Gained  []NodeDesireDiff `json:"gained"`
+	Lost    []NodeDesireDiff `json:"lost"`
+	Changed []NodeDesireDiff `json:"changed"`
+}
+
+// NodeDesireDiff the changes of desire of one node, the path is such as apps.<name> or sysapps.<name>
+type NodeDesireDiff struct {
+	Node    string             `json:"node"`
+	Changes []common.FieldDiff `json:"changes"`
+}
+
+// DryRunResult the result of the change in dry-run mode, nothing is persisted
+type DryRunResult struct {
+	DryRun   bool          `json:"dryRun"`
+	Resource interface{}   `json:"resource,omitempty"`
+	Impact   *ChangeImpact `json:"impact"`
+}
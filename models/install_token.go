@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// InstallToken the token registered for the install command of node, which is carried in the signed token string
+type InstallToken struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	NodeName  string `json:"nodeName"`
+	// the single-use token is consumed only once, the install shell fetched by it carries a new single-use token
+	SingleUse bool `json:"singleUse"`
+	Revoked   bool `json:"revoked"`
+	// the ip and time of the first consumption
+	ConsumeIP   string    `json:"consumeIP,omitempty"`
+	ConsumeTime time.Time `json:"consumeTime,omitempty"`
+	UseCount    int       `json:"useCount"`
+	ExpireTime  time.Time `json:"expireTime"`
+	CreateTime  time.Time `json:"createTime,omitempty"`
+	UpdateTime  time.Time `json:"updateTime,omitempty"`
+}
+
+// InstallTokenList install token list
+type InstallTokenList struct {
+	Total int            `json:"total"`
+	Items []InstallToken `json:"items"`
+}
+
+// InstallTokenConsume the audit record of the consumption of install token, the rejected ones are recorded too
+type InstallTokenConsume struct {
+	Name       string    `json:"name"`
+	Namespace  string    `json:"namespace"`
+	ConsumeIP  string    `json:"consumeIP"`
+	Accepted   bool      `json:"accepted"`
+	CreateTime time.Time `json:"createTime"`
+}
+
+// InstallTokenConsumeList install token consume list
+type InstallTokenConsumeList struct {
+	Total int                   `json:"total"`
+	Items []InstallTokenConsume `json:"items"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// OfflineBundleOptions the options of building the offline install bundle of node
+type OfflineBundleOptions struct {
+	// the install mode, kube or native, the mode of node is used if empty
+	Mode string `form:"mode,omitempty" json:"mode,omitempty"`
+	// the program packages of platform (e.g. linux-amd64) are downloaded into the bundle if set
+	Platform string `form:"platform,omitempty" json:"platform,omitempty"`
+}
+
+// OfflineBundleManifest describes the offline install bundle of node, which is packed as manifest.json
+type OfflineBundleManifest struct {
+	Namespace  string                 `json:"namespace"`
+	NodeName   string                 `json:"nodeName"`
+	Mode       string                 `json:"mode"`
+	Platform   string                 `json:"platform,omitempty"`
+	CreateTime time.Time              `json:"createTime"`
+	Images     []OfflineBundleImage   `json:"images"`
+	Programs   []OfflineBundleProgram `json:"programs"`
+	// the sha256 checksums of the files in bundle, except manifest.json and SHA256SUMS
+	Files []OfflineBundleFile `json:"files"`
+}
+
+// OfflineBundleImage the image of system apps to be loaded onto the node in advance
+type OfflineBundleImage struct {
+	Image string `json:"image"`
+	// the module of image, empty if no module record matches the image
+	Module  string `json:"module,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+// OfflineBundleProgram the program package of module, File and SHA256 are set if the package is downloaded into bundle
+type OfflineBundleProgram struct {
+	Module   string `json:"module"`
+	Version  string `json:"version"`
+	Platform string `json:"platform"`
+	URL      string `json:"url"`
+	File     string `json:"file,omitempty"`
+	SHA256   string `json:"sha256,omitempty"`
+}
+
+// OfflineBundleFile the file in bundle with its checksum
+type OfflineBundleFile struct {
+	Path   string `json:"path"`
+	Size   int    `json:"size"`
+	SHA256 string `json:"sha256"`
+}
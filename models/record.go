@@ -23,3 +23,8 @@ type RecordList struct {
 	*Filter `json:",inline"`
 	Items   []Record `json:"items"`
 }
+
+// RecordImport the fingerprint records imported into the batch, the names of records and nodes are generated if not set
+type RecordImport struct {
+	Items []Record `json:"items" binding:"required,min=1,max=1000,dive"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+)
+
+const (
+	RecycleKindApp         = "app"
+	RecycleKindConfig      = "config"
+	RecycleKindSecret      = "secret"
+	RecycleKindRegistry    = "registry"
+	RecycleKindCertificate = "certificate"
+)
+
+// RecycleItem the deleted resource kept in the recycle bin of namespace until it's restored, purged or expired
+type RecycleItem struct {
+	ID        int64  `json:"id"`
+	Namespace string `json:"namespace"`
+	// app, config, secret, registry or certificate
+	Kind        string              `json:"kind"`
+	Name        string              `json:"name"`
+	Application *specV1.Application `json:"application,omitempty"`
+	// the deleted config, or the generated configs of the deleted function app
+	Configs    []specV1.Configuration `json:"configs,omitempty"`
+	Secret     *specV1.Secret         `json:"secret,omitempty"`
+	DeleteTime time.Time              `json:"deleteTime,omitempty"`
+	ExpireTime time.Time              `json:"expireTime,omitempty"`
+	// the revisions of the deleted app, which are restored with the app
+	Histories []AppHistory `json:"-"`
+}
+
+type RecycleListOptions struct {
+	Kind   string `form:"kind" json:"kind,omitempty" binding:"omitempty,oneof=app config secret registry certificate"`
+	Filter `json:",inline"`
+}
+
+type RecycleItemList struct {
+	Total               int `json:"total"`
+	*RecycleListOptions `json:",inline"`
+	Items               []RecycleItem `json:"items"`
+}
@@ -20,8 +20,17 @@ type Registry struct {
 	UpdateTimestamp   time.Time `json:"updateTime,omitempty"`
 	Description       string    `json:"description"`
 	Version           string    `json:"version,omitempty"`
+	// the warnings of validation if validate=warn is set
+	Warnings []string `json:"warnings,omitempty"`
 }
 
+const (
+	// RegistryValidationWarn the failures of validation are returned as warnings
+	RegistryValidationWarn = "warn"
+	// RegistryValidationStrict the failures of validation are returned as errors
+	RegistryValidationStrict = "strict"
+)
+
 type RegistryView struct {
 	Name     string `json:"name,omitempty"`
 	Address  string `json:"address,omitempty"`
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+type RolloutStatus string
+
+const (
+	RolloutProgressing RolloutStatus = "Progressing"
+	RolloutSucceeded   RolloutStatus = "Succeeded"
+	RolloutFailed      RolloutStatus = "Failed"
+	RolloutPending     RolloutStatus = "Pending"
+)
+
+// RolloutStrategy releases the new version of application to the matched nodes wave by wave
+type RolloutStrategy struct {
+	// the number of nodes of each wave, BatchPercent is used if not set, all nodes in one wave if both are not set
+	BatchSize    int `json:"batchSize,omitempty" binding:"omitempty,min=1"`
+	BatchPercent int `json:"batchPercent,omitempty" binding:"omitempty,min=1,max=100"`
+	// the pause between the healthy wave and the next wave
+	PauseSeconds int64 `json:"pauseSeconds,omitempty" binding:"omitempty,min=0"`
+	// the wave is failed if the nodes don't report healthy status in time
+	HealthTimeoutSeconds int64 `json:"healthTimeoutSeconds,omitempty" binding:"omitempty,min=1"`
+	// the canary nodes get the new version first, selected by CanarySelector or the first CanaryCount nodes
+	CanarySelector string `json:"canarySelector,omitempty"`
+	CanaryCount    int    `json:"canaryCount,omitempty" binding:"omitempty,min=1"`
+}
+
+// Rollout the rollout state of application
+type Rollout struct {
+	Namespace       string           `json:"namespace,omitempty"`
+	Name            string           `json:"name,omitempty"`
+	Strategy        *RolloutStrategy `json:"strategy,omitempty"`
+	Version         string           `json:"version,omitempty"`
+	PreviousVersion string           `json:"previousVersion,omitempty"`
+	Status          RolloutStatus    `json:"status,omitempty"`
+	Reason          string           `json:"reason,omitempty"`
+	CurrentWave     int              `json:"currentWave"`
+	Waves           []RolloutWave    `json:"waves,omitempty"`
+	CreateTime      time.Time        `json:"createTime,omitempty"`
+	UpdateTime      time.Time        `json:"updateTime,omitempty"`
+}
+
+type RolloutWave struct {
+	Canary      bool          `json:"canary,omitempty"`
+	Nodes       []string      `json:"nodes"`
+	Status      RolloutStatus `json:"status,omitempty"`
+	FailedNodes []string      `json:"failedNodes,omitempty"`
+	StartTime   time.Time     `json:"startTime,omitempty"`
+	HealthyTime time.Time     `json:"healthyTime,omitempty"`
+}
@@ -7,6 +7,8 @@ import (
 
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 	"github.com/jinzhu/copier"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
 )
 
 type SecretList struct {
@@ -15,6 +17,13 @@ type SecretList struct {
 	Items        []specV1.Secret `json:"items"`
 }
 
+// SecretReencryption the result of re-encrypting the secrets with the current key
+type SecretReencryption struct {
+	// all namespaces if empty
+	Namespace string `json:"namespace,omitempty"`
+	Count     int    `json:"count"`
+}
+
 func FromSecretToRegistry(s *specV1.Secret, needToFilter bool) *Registry {
 	if v, ok := s.Labels[specV1.SecretLabel]; needToFilter && (!ok || v != specV1.SecretRegistry) {
 		return nil
@@ -88,6 +97,12 @@ func FromSecretToCertificate(s *specV1.Secret, needToFilter bool) *Certificate {
 	if v, ok := s.Data["fingerPrint"]; ok {
 		res.FingerPrint = string(v)
 	}
+	// the validity is parsed when viewing, since it is not stored by the earlier versions
+	if res.Data.Certificate != "" {
+		if cert, err := ParseCertificatePEM([]byte(res.Data.Certificate)); err == nil {
+			res.setValidity(cert)
+		}
+	}
 	return res
 }
 
@@ -116,11 +131,14 @@ type SecretView struct {
 	UpdateTimestamp   time.Time         `json:"updateTime,omitempty"`
 	Description       string            `json:"description"`
 	Version           string            `json:"version,omitempty"`
+	// the values of data are the references resolved from the provider at sync time if the provider is set
+	Provider string `json:"provider,omitempty"`
 }
 
 func (s *SecretView) Equal(target *SecretView) bool {
 	return reflect.DeepEqual(s.Data, target.Data) &&
-		reflect.DeepEqual(s.Description, target.Description)
+		reflect.DeepEqual(s.Description, target.Description) &&
+		s.Provider == target.Provider
 }
 
 type SecretViewList struct {
@@ -143,6 +161,9 @@ func (s *SecretView) ToSecret() *specV1.Secret {
 	for k, v := range s.Data {
 		res.Data[k] = []byte(v)
 	}
+	if s.Provider != "" {
+		res.Annotations = map[string]string{common.AnnotationSecretProvider: s.Provider}
+	}
 	return res
 }
 
@@ -159,6 +180,7 @@ func FromSecretToView(s *specV1.Secret, needToFilter bool) *SecretView {
 	for k, v := range s.Data {
 		res.Data[k] = string(v)
 	}
+	res.Provider = s.Annotations[common.AnnotationSecretProvider]
 	return res
 }
 
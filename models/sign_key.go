@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// SignKey the key of sign plugin, the private key is never exposed
+type SignKey struct {
+	KeyID     string `json:"keyId"`
+	Algorithm string `json:"algorithm"`
+	// the active key signs, the others are retired which verify only
+	Active     bool      `json:"active"`
+	CreateTime time.Time `json:"createTime"`
+}
+
+// SignKeyList sign key list
+type SignKeyList struct {
+	Total int       `json:"total"`
+	Items []SignKey `json:"items"`
+}
@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// the events which the callbacks subscribe
+const (
+	EventNodeActivated       = "node.activated"
+	EventNodeOffline         = "node.offline"
+	EventRolloutFinished     = "rollout.finished"
+	EventCertificateExpiring = CertificateExpiryEventType
+	EventQuotaExceeded       = "quota.exceeded"
+)
+
+// WebhookEvents the events which can be subscribed
+var WebhookEvents = map[string]bool{
+	EventNodeActivated:       true,
+	EventNodeOffline:         true,
+	EventRolloutFinished:     true,
+	EventCertificateExpiring: true,
+	EventQuotaExceeded:       true,
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "Pending"
+	DeliverySucceeded DeliveryStatus = "Succeeded"
+	DeliveryFailed    DeliveryStatus = "Failed"
+)
+
+// Event the event sent to the callbacks subscribing its type, the same event is delivered once to each callback
+type Event struct {
+	ID        string                 `json:"id"`
+	Type      string                 `json:"type"`
+	Namespace string                 `json:"namespace"`
+	Time      time.Time              `json:"time"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+}
+
+// Delivery the log of sending an event to a callback
+type Delivery struct {
+	Name      string         `json:"name"`
+	Namespace string         `json:"namespace"`
+	Callback  string         `json:"callback"`
+	Event     *Event         `json:"event"`
+	Status    DeliveryStatus `json:"status"`
+	Attempts  int            `json:"attempts"`
+	// the status code and error of the last attempt
+	ResponseCode int       `json:"responseCode,omitempty"`
+	Reason       string    `json:"reason,omitempty"`
+	NextTime     time.Time `json:"nextTime,omitempty"`
+	CreateTime   time.Time `json:"createTime,omitempty"`
+	UpdateTime   time.Time `json:"updateTime,omitempty"`
+}
+
+// DeliveryList delivery list
+type DeliveryList struct {
+	Total        int `json:"total"`
+	*ListOptions `json:",inline"`
+	Items        []Delivery `json:"items"`
+}
+
+// CallbackList callback list
+type CallbackList struct {
+	Total int        `json:"total"`
+	Items []Callback `json:"items"`
+}
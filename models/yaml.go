@@ -3,4 +3,74 @@ package models
 type YamlResourceList struct {
 	Total int           `json:"total"`
 	Items []interface{} `json:"items"`
+	// the fields of objects which can't be translated and are ignored
+	Unsupported []YamlUnsupported `json:"unsupported,omitempty"`
+}
+
+// YamlUnsupported the fields of the object which are ignored
+type YamlUnsupported struct {
+	Kind   string   `json:"kind"`
+	Name   string   `json:"name"`
+	Fields []string `json:"fields"`
+}
+
+// AddUnsupported adds the unsupported fields of the object if any
+func (l *YamlResourceList) AddUnsupported(kind, name string, fields []string) {
+	if len(fields) == 0 {
+		return
+	}
+	l.Unsupported = append(l.Unsupported, YamlUnsupported{Kind: kind, Name: name, Fields: fields})
+}
+
+const (
+	YamlApplyCreated   = "created"
+	YamlApplyUpdated   = "updated"
+	YamlApplyUnchanged = "unchanged"
+	YamlApplyFailed    = "failed"
+	YamlApplyPruned    = "pruned"
+)
+
+// YamlApplyOptions the options of applying yaml resources
+type YamlApplyOptions struct {
+	DryRun bool `form:"dryRun"`
+	// prune the resources with the label of apply set but not in the yaml
+	Prune    bool   `form:"prune"`
+	ApplySet string `form:"applySet" binding:"omitempty,res_name"`
+	// the labels added to the apps
+	AppLabels map[string]string `form:"-"`
+}
+
+// HelmImportOptions the options of importing helm chart
+type HelmImportOptions struct {
+	// the release name to render the chart, default to the chart name
+	Name   string `form:"name" binding:"omitempty,res_name"`
+	DryRun bool   `form:"dryRun"`
+	Prune  bool   `form:"prune"`
+}
+
+// YamlApplyResult the result of applying the object
+type YamlApplyResult struct {
+	Kind        string   `json:"kind"`
+	Name        string   `json:"name"`
+	Action      string   `json:"action"`
+	Error       string   `json:"error,omitempty"`
+	Unsupported []string `json:"unsupported,omitempty"`
+}
+
+type YamlApplyResultList struct {
+	Total  int               `json:"total"`
+	DryRun bool              `json:"dryRun"`
+	Items  []YamlApplyResult `json:"items"`
+}
+
+// Add adds the result of object, the action is failed if err isn't nil
+func (l *YamlApplyResultList) Add(kind, name, action string, err error) *YamlApplyResult {
+	res := YamlApplyResult{Kind: kind, Name: name, Action: action}
+	if err != nil {
+		res.Action = YamlApplyFailed
+		res.Error = err.Error()
+	}
+	l.Items = append(l.Items, res)
+	l.Total++
+	return &l.Items[len(l.Items)-1]
 }
@@ -0,0 +1,21 @@
+package plugin
+
+import (
+	"io"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+//go:generate mockgen -destination=../mock/plugin/app_history.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin AppHistory
+
+// AppHistory stores the revisions of applications
+type AppHistory interface {
+	// CreateAppHistory saves the history as the next revision of the application
+	CreateAppHistory(tx interface{}, history *models.AppHistory) (*models.AppHistory, error)
+	GetAppHistory(tx interface{}, namespace, name string, revision int64) (*models.AppHistory, error)
+	GetAppHistoryByVersion(tx interface{}, namespace, name, version string) (*models.AppHistory, error)
+	GetLatestAppHistory(tx interface{}, namespace, name string) (*models.AppHistory, error)
+	ListAppHistory(tx interface{}, namespace, name string, listOptions *models.ListOptions) (*models.AppHistoryList, error)
+	DeleteAppHistory(tx interface{}, namespace, name string) error
+	io.Closer
+}
@@ -0,0 +1,22 @@
+package plugin
+
+import (
+	"database/sql"
+	"io"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+//go:generate mockgen -destination=../mock/plugin/batch.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin Batch
+
+// Batch stores the batches which the nodes are provisioned from
+type Batch interface {
+	GetBatch(name, ns string) (*models.Batch, error)
+	ListBatch(ns string, filter *models.ListOptions) ([]models.Batch, error)
+	CreateBatch(batch *models.Batch) (sql.Result, error)
+	UpdateBatch(batch *models.Batch) (sql.Result, error)
+	DeleteBatch(name, ns string) (sql.Result, error)
+	CountBatch(ns, name string) (int, error)
+	CountBatchByCallback(callbackName, ns string) (int, error)
+	io.Closer
+}
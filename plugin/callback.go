@@ -0,0 +1,37 @@
+package plugin
+
+import (
+	"io"
+	"time"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+//go:generate mockgen -destination=../mock/plugin/callback.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin Callback
+
+// Callback stores the callbacks of namespaces and the deliveries of events to them
+type Callback interface {
+	GetCallback(tx interface{}, namespace, name string) (*models.Callback, error)
+	// ListCallbackByName lists the callbacks with the name of all namespaces
+	ListCallbackByName(tx interface{}, name string) ([]models.Callback, error)
+	ListCallback(tx interface{}, namespace string) ([]models.Callback, error)
+	// ListCallbackByEvent lists the callbacks subscribing the event of all namespaces
+	ListCallbackByEvent(tx interface{}, event string) ([]models.Callback, error)
+	CreateCallback(tx interface{}, callback *models.Callback) (*models.Callback, error)
+	UpdateCallback(tx interface{}, callback *models.Callback) (*models.Callback, error)
+	DeleteCallback(tx interface{}, namespace, name string) error
+
+	CreateDelivery(tx interface{}, delivery *models.Delivery) error
+	UpdateDelivery(tx interface{}, delivery *models.Delivery) error
+	GetDelivery(tx interface{}, namespace, name string) (*models.Delivery, error)
+	// CountDeliveryByEvent counts the deliveries of the event to the callback
+	CountDeliveryByEvent(tx interface{}, namespace, callback, eventID string) (int, error)
+	ListDelivery(tx interface{}, namespace, callback string, listOptions *models.ListOptions) (*models.DeliveryList, error)
+	// ListDueDelivery lists the pending deliveries of all namespaces which should be sent before the time
+	ListDueDelivery(tx interface{}, before time.Time, limit int) ([]models.Delivery, error)
+	// ClaimDelivery claims the due delivery by moving its next time to until if it's still in the same status with the same attempts,
+	// returns false if it has been claimed or sent by others
+	ClaimDelivery(tx interface{}, delivery *models.Delivery, now, until time.Time) (bool, error)
+	DeleteDeliveryByCallback(tx interface{}, namespace, callback string) error
+	io.Closer
+}
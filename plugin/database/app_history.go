@@ -0,0 +1,221 @@
+// Package database 数据库存储实现
+package database
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/utils"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/database/entities"
+)
+
+const appHistoryInsertRetries = 3
+
+func (d *BaetylCloudDB) CreateAppHistory(tx interface{}, history *models.AppHistory) (*models.AppHistory, error) {
+	defer utils.Trace(d.Log.Debug, "CreateAppHistory")()
+	var res *models.AppHistory
+	var err error
+	if tx == nil {
+		err = d.Transact(func(tx *sqlx.Tx) error {
+			res, err = d.CreateAppHistoryTx(tx, history)
+			return err
+		})
+	} else {
+		transaction, errTx := d.InterfaceToTx(tx)
+		if errTx != nil {
+			return nil, errTx
+		}
+		res, err = d.CreateAppHistoryTx(transaction, history)
+	}
+	return res, err
+}
+
+func (d *BaetylCloudDB) GetAppHistory(tx interface{}, namespace, name string, revision int64) (*models.AppHistory, error) {
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return d.GetAppHistoryTx(transaction, namespace, name, revision)
+}
+
+func (d *BaetylCloudDB) GetAppHistoryByVersion(tx interface{}, namespace, name, version string) (*models.AppHistory, error) {
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return d.GetAppHistoryByVersionTx(transaction, namespace, name, version)
+}
+
+func (d *BaetylCloudDB) GetLatestAppHistory(tx interface{}, namespace, name string) (*models.AppHistory, error) {
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return d.GetLatestAppHistoryTx(transaction, namespace, name)
+}
+
+func (d *BaetylCloudDB) ListAppHistory(tx interface{}, namespace, name string, listOptions *models.ListOptions) (*models.AppHistoryList, error) {
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	items, total, err := d.ListAppHistoryTx(transaction, namespace, name, listOptions)
+	if err != nil {
+		return nil, err
+	}
+	return &models.AppHistoryList{
+		Total:       total,
+		ListOptions: listOptions,
+		Items:       items,
+	}, nil
+}
+
+func (d *BaetylCloudDB) DeleteAppHistory(tx interface{}, namespace, name string) error {
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return err
+	}
+	deleteSQL := `DELETE FROM baetyl_app_history WHERE namespace=? AND name=?`
+	_, err = d.Exec(transaction, deleteSQL, namespace, name)
+	return err
+}
+
+func (d *BaetylCloudDB) CreateAppHistoryTx(tx *sqlx.Tx, history *models.AppHistory) (*models.AppHistory, error) {
+	// the revision is allocated by the insert itself, which reads the latest revision instead of the snapshot of tx,
+	// the insert is retried if the revision is taken by a concurrent one in between
+	insertSQL := `
+INSERT INTO baetyl_app_history (namespace, name, revision, version, content, configs, secrets)
+SELECT ?, ?, IFNULL(MAX(revision), 0) + 1, ?, ?, ?, ? FROM baetyl_app_history WHERE namespace=? AND name=?
+`
+	h, err := entities.FromAppHistoryModel(history)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var res sql.Result
+	for i := 0; ; i++ {
+		res, err = d.Exec(tx, insertSQL, h.Namespace, h.Name, h.Version, h.Content, h.Configs, h.Secrets, h.Namespace, h.Name)
+		if err == nil {
+			break
+		}
+		if i >= appHistoryInsertRetries || !isDuplicateEntry(err) {
+			return nil, err
+		}
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	selectSQL := `SELECT revision FROM baetyl_app_history WHERE id=?`
+	var revisions []struct {
+		Revision int64 `db:"revision"`
+	}
+	if err = d.Query(tx, selectSQL, &revisions, id); err != nil {
+		return nil, err
+	}
+	if len(revisions) == 0 {
+		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "appHistory"),
+			common.Field("name", history.Name), common.Field("namespace", history.Namespace))
+	}
+	history.Revision = revisions[0].Revision
+	return d.GetAppHistoryTx(tx, history.Namespace, history.Name, history.Revision)
+}
+
+// isDuplicateEntry returns true if the unique key is violated, both of mysql and sqlite are supported
+func isDuplicateEntry(err error) bool {
+	msg := errors.Cause(err).Error()
+	return strings.Contains(msg, "Duplicate entry") || strings.Contains(msg, "UNIQUE constraint failed")
+}
+
+func (d *BaetylCloudDB) GetAppHistoryTx(tx *sqlx.Tx, namespace, name string, revision int64) (*models.AppHistory, error) {
+	selectSQL := `
+SELECT id, namespace, name, revision, version, content, configs, secrets, create_time
+FROM baetyl_app_history WHERE namespace=? AND name=? AND revision=?
+`
+	var histories []entities.AppHistory
+	if err := d.Query(tx, selectSQL, &histories, namespace, name, revision); err != nil {
+		return nil, err
+	}
+	if len(histories) > 0 {
+		return entities.ToAppHistoryModel(&histories[0])
+	}
+	return nil, common.Error(
+		common.ErrResourceNotFound,
+		common.Field("type", "appHistory"),
+		common.Field("name", name),
+		common.Field("namespace", namespace))
+}
+
+func (d *BaetylCloudDB) GetAppHistoryByVersionTx(tx *sqlx.Tx, namespace, name, version string) (*models.AppHistory, error) {
+	selectSQL := `
+SELECT id, namespace, name, revision, version, content, configs, secrets, create_time
+FROM baetyl_app_history WHERE namespace=? AND name=? AND version=? ORDER BY revision DESC LIMIT 1
+`
+	var histories []entities.AppHistory
+	if err := d.Query(tx, selectSQL, &histories, namespace, name, version); err != nil {
+		return nil, err
+	}
+	if len(histories) > 0 {
+		return entities.ToAppHistoryModel(&histories[0])
+	}
+	return nil, common.Error(
+		common.ErrResourceNotFound,
+		common.Field("type", "appHistory"),
+		common.Field("name", name),
+		common.Field("namespace", namespace))
+}
+
+func (d *BaetylCloudDB) GetLatestAppHistoryTx(tx *sqlx.Tx, namespace, name string) (*models.AppHistory, error) {
+	selectSQL := `
+SELECT id, namespace, name, revision, version, content, configs, secrets, create_time
+FROM baetyl_app_history WHERE namespace=? AND name=? ORDER BY revision DESC LIMIT 1
+`
+	var histories []entities.AppHistory
+	if err := d.Query(tx, selectSQL, &histories, namespace, name); err != nil {
+		return nil, err
+	}
+	if len(histories) > 0 {
+		return entities.ToAppHistoryModel(&histories[0])
+	}
+	return nil, common.Error(
+		common.ErrResourceNotFound,
+		common.Field("type", "appHistory"),
+		common.Field("name", name),
+		common.Field("namespace", namespace))
+}
+
+func (d *BaetylCloudDB) ListAppHistoryTx(tx *sqlx.Tx, namespace, name string, listOptions *models.ListOptions) ([]models.AppHistory, int, error) {
+	countSQL := `SELECT count(id) AS count FROM baetyl_app_history WHERE namespace=? AND name=?`
+	var counts []struct {
+		Count int `db:"count"`
+	}
+	if err := d.Query(tx, countSQL, &counts, namespace, name); err != nil {
+		return nil, 0, err
+	}
+	selectSQL := `
+SELECT id, namespace, name, revision, version, content, configs, secrets, create_time
+FROM baetyl_app_history WHERE namespace=? AND name=? ORDER BY revision DESC
+`
+	args := []interface{}{namespace, name}
+	if listOptions.GetLimitNumber() > 0 {
+		selectSQL = selectSQL + "LIMIT ?,?"
+		args = append(args, listOptions.GetLimitOffset(), listOptions.GetLimitNumber())
+	}
+	var histories []entities.AppHistory
+	if err := d.Query(tx, selectSQL, &histories, args...); err != nil {
+		return nil, 0, err
+	}
+	res := make([]models.AppHistory, 0)
+	for i := range histories {
+		h, err := entities.ToAppHistoryModel(&histories[i])
+		if err != nil {
+			return nil, 0, err
+		}
+		res = append(res, *h)
+	}
+	return res, counts[0].Count, nil
+}
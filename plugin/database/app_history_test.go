@@ -0,0 +1,158 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+var (
+	appHistoryTables = []string{
+		`
+CREATE TABLE baetyl_app_history
+(
+	id          integer       PRIMARY KEY AUTOINCREMENT,
+	namespace   varchar(64)   NOT NULL DEFAULT '',
+	name        varchar(128)  NOT NULL DEFAULT '',
+	revision    integer       NOT NULL DEFAULT 0,
+	version     varchar(36)   NOT NULL DEFAULT '',
+	content     text          NULL,
+	configs     text          NULL,
+	secrets     text          NULL,
+	create_time timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (namespace, name, revision)
+);
+`,
+	}
+)
+
+func (d *BaetylCloudDB) MockCreateAppHistoryTable() {
+	for _, sql := range appHistoryTables {
+		_, err := d.Exec(nil, sql)
+		if err != nil {
+			panic(fmt.Sprintf("create app history exception: %s", err.Error()))
+		}
+	}
+}
+
+func TestAppHistory(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateAppHistoryTable()
+
+	ns, name := "default", "app"
+	_, err = db.GetLatestAppHistory(nil, ns, name)
+	assert.Error(t, err)
+
+	history := &models.AppHistory{
+		Namespace: ns,
+		Name:      name,
+		Version:   "1",
+		Application: &specV1.Application{
+			Name:      name,
+			Namespace: ns,
+			Version:   "1",
+			Selector:  "a=b",
+		},
+		Configs: map[string]string{"cfg": "10"},
+		Secrets: map[string]string{"sec": "11"},
+	}
+	res, err := db.CreateAppHistory(nil, history)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), res.Revision)
+	assert.Equal(t, "a=b", res.Application.Selector)
+	assert.Equal(t, map[string]string{"cfg": "10"}, res.Configs)
+	assert.Equal(t, map[string]string{"sec": "11"}, res.Secrets)
+
+	tx, err := db.BeginTx()
+	assert.NoError(t, err)
+	history.Version = "2"
+	history.Application.Version = "2"
+	history.Application.Selector = "a=c"
+	res, err = db.CreateAppHistory(tx, history)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), res.Revision)
+	assert.NoError(t, tx.Commit())
+
+	res, err = db.GetLatestAppHistory(nil, ns, name)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), res.Revision)
+	assert.Equal(t, "2", res.Version)
+
+	res, err = db.GetAppHistory(nil, ns, name, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", res.Version)
+
+	_, err = db.GetAppHistory(nil, ns, name, 3)
+	assert.Error(t, err)
+
+	res, err = db.GetAppHistoryByVersion(nil, ns, name, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), res.Revision)
+	assert.Equal(t, "a=b", res.Application.Selector)
+
+	_, err = db.GetAppHistoryByVersion(nil, ns, name, "3")
+	assert.Error(t, err)
+
+	list, err := db.ListAppHistory(nil, ns, name, &models.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, list.Total)
+	assert.Len(t, list.Items, 2)
+	assert.Equal(t, int64(2), list.Items[0].Revision)
+
+	list, err = db.ListAppHistory(nil, ns, name, &models.ListOptions{Filter: models.Filter{PageNo: 2, PageSize: 1}})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, list.Total)
+	assert.Len(t, list.Items, 1)
+	assert.Equal(t, int64(1), list.Items[0].Revision)
+
+	err = db.DeleteAppHistory(nil, ns, name)
+	assert.NoError(t, err)
+
+	list, err = db.ListAppHistory(nil, ns, name, &models.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, list.Total)
+	assert.Len(t, list.Items, 0)
+}
+
+func TestAppHistory_DuplicateRevision(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateAppHistoryTable()
+
+	history := &models.AppHistory{
+		Namespace:   "default",
+		Name:        "app",
+		Version:     "1",
+		Application: &specV1.Application{Name: "app", Namespace: "default", Version: "1"},
+	}
+	res, err := db.CreateAppHistory(nil, history)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), res.Revision)
+
+	// the revision taken by another one is rejected by the unique key
+	_, err = db.Exec(nil, `INSERT INTO baetyl_app_history (namespace, name, revision, version) VALUES (?, ?, ?, ?)`,
+		"default", "app", 1, "2")
+	assert.Error(t, err)
+	assert.True(t, isDuplicateEntry(err))
+	assert.False(t, isDuplicateEntry(fmt.Errorf("Error 1213: Deadlock found")))
+
+	// the next revision is allocated from the latest one
+	history.Version = "2"
+	res, err = db.CreateAppHistory(nil, history)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), res.Revision)
+	assert.Equal(t, "2", res.Version)
+}
@@ -0,0 +1,345 @@
+package database
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/database/entities"
+)
+
+func (d *BaetylCloudDB) GetCallback(tx interface{}, namespace, name string) (*models.Callback, error) {
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return d.GetCallbackTx(transaction, namespace, name)
+}
+
+func (d *BaetylCloudDB) ListCallbackByName(tx interface{}, name string) ([]models.Callback, error) {
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return d.ListCallbackByNameTx(transaction, name)
+}
+
+func (d *BaetylCloudDB) ListCallback(tx interface{}, namespace string) ([]models.Callback, error) {
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return d.ListCallbackTx(transaction, namespace)
+}
+
+func (d *BaetylCloudDB) ListCallbackByEvent(tx interface{}, event string) ([]models.Callback, error) {
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return d.ListCallbackByEventTx(transaction, event)
+}
+
+func (d *BaetylCloudDB) CreateCallback(tx interface{}, callback *models.Callback) (*models.Callback, error) {
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return d.CreateCallbackTx(transaction, callback)
+}
+
+func (d *BaetylCloudDB) UpdateCallback(tx interface{}, callback *models.Callback) (*models.Callback, error) {
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return d.UpdateCallbackTx(transaction, callback)
+}
+
+func (d *BaetylCloudDB) DeleteCallback(tx interface{}, namespace, name string) error {
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return err
+	}
+	deleteSQL := `DELETE FROM baetyl_callback WHERE namespace=? AND name=?`
+	_, err = d.Exec(transaction, deleteSQL, namespace, name)
+	return err
+}
+
+func (d *BaetylCloudDB) CreateDelivery(tx interface{}, delivery *models.Delivery) error {
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return err
+	}
+	return d.CreateDeliveryTx(transaction, delivery)
+}
+
+func (d *BaetylCloudDB) UpdateDelivery(tx interface{}, delivery *models.Delivery) error {
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return err
+	}
+	return d.UpdateDeliveryTx(transaction, delivery)
+}
+
+func (d *BaetylCloudDB) GetDelivery(tx interface{}, namespace, name string) (*models.Delivery, error) {
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return d.GetDeliveryTx(transaction, namespace, name)
+}
+
+func (d *BaetylCloudDB) CountDeliveryByEvent(tx interface{}, namespace, callback, eventID string) (int, error) {
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return 0, err
+	}
+	countSQL := `SELECT count(id) AS count FROM baetyl_callback_delivery WHERE namespace=? AND callback=? AND event_id=?`
+	var counts []struct {
+		Count int `db:"count"`
+	}
+	if err = d.Query(transaction, countSQL, &counts, namespace, callback, eventID); err != nil {
+		return 0, err
+	}
+	return counts[0].Count, nil
+}
+
+func (d *BaetylCloudDB) ListDelivery(tx interface{}, namespace, callback string, listOptions *models.ListOptions) (*models.DeliveryList, error) {
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	items, total, err := d.ListDeliveryTx(transaction, namespace, callback, listOptions)
+	if err != nil {
+		return nil, err
+	}
+	return &models.DeliveryList{
+		Total:       total,
+		ListOptions: listOptions,
+		Items:       items,
+	}, nil
+}
+
+func (d *BaetylCloudDB) ListDueDelivery(tx interface{}, before time.Time, limit int) ([]models.Delivery, error) {
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	selectSQL := `
+SELECT id, name, namespace, callback, event_id, event, status, attempts, response_code, reason, next_time, create_time, update_time
+FROM baetyl_callback_delivery WHERE status=? AND next_time<=? ORDER BY next_time LIMIT ?
+`
+	var deliveries []entities.Delivery
+	if err = d.Query(transaction, selectSQL, &deliveries, string(models.DeliveryPending), before, limit); err != nil {
+		return nil, err
+	}
+	return toDeliveryModels(deliveries)
+}
+
+func (d *BaetylCloudDB) ClaimDelivery(tx interface{}, delivery *models.Delivery, now, until time.Time) (bool, error) {
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return false, err
+	}
+	updateSQL := `
+UPDATE baetyl_callback_delivery SET next_time=?, update_time=CURRENT_TIMESTAMP
+WHERE namespace=? AND name=? AND status=? AND attempts=? AND next_time<=?
+`
+	res, err := d.Exec(transaction, updateSQL, until, delivery.Namespace, delivery.Name, string(delivery.Status), delivery.Attempts, now)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return affected == 1, nil
+}
+
+func (d *BaetylCloudDB) DeleteDeliveryByCallback(tx interface{}, namespace, callback string) error {
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return err
+	}
+	deleteSQL := `DELETE FROM baetyl_callback_delivery WHERE namespace=? AND callback=?`
+	_, err = d.Exec(transaction, deleteSQL, namespace, callback)
+	return err
+}
+
+func (d *BaetylCloudDB) GetCallbackTx(tx *sqlx.Tx, namespace, name string) (*models.Callback, error) {
+	selectSQL := `
+SELECT id, name, namespace, method, params, header, body, url, events, secret, description, create_time, update_time
+FROM baetyl_callback WHERE namespace=? AND name=? LIMIT 0,1
+`
+	var callbacks []entities.Callback
+	if err := d.Query(tx, selectSQL, &callbacks, namespace, name); err != nil {
+		return nil, err
+	}
+	if len(callbacks) == 0 {
+		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "callback"), common.Field("name", name))
+	}
+	return entities.ToCallbackModel(&callbacks[0])
+}
+
+func (d *BaetylCloudDB) ListCallbackByNameTx(tx *sqlx.Tx, name string) ([]models.Callback, error) {
+	selectSQL := `
+SELECT id, name, namespace, method, params, header, body, url, events, secret, description, create_time, update_time
+FROM baetyl_callback WHERE name=? ORDER BY namespace
+`
+	var callbacks []entities.Callback
+	if err := d.Query(tx, selectSQL, &callbacks, name); err != nil {
+		return nil, err
+	}
+	return toCallbackModels(callbacks)
+}
+
+func (d *BaetylCloudDB) ListCallbackTx(tx *sqlx.Tx, namespace string) ([]models.Callback, error) {
+	selectSQL := `
+SELECT id, name, namespace, method, params, header, body, url, events, secret, description, create_time, update_time
+FROM baetyl_callback WHERE namespace=? ORDER BY name
+`
+	var callbacks []entities.Callback
+	if err := d.Query(tx, selectSQL, &callbacks, namespace); err != nil {
+		return nil, err
+	}
+	return toCallbackModels(callbacks)
+}
+
+func (d *BaetylCloudDB) ListCallbackByEventTx(tx *sqlx.Tx, event string) ([]models.Callback, error) {
+	selectSQL := `
+SELECT id, name, namespace, method, params, header, body, url, events, secret, description, create_time, update_time
+FROM baetyl_callback WHERE events LIKE ? ORDER BY namespace, name
+`
+	var callbacks []entities.Callback
+	if err := d.Query(tx, selectSQL, &callbacks, `%"`+event+`"%`); err != nil {
+		return nil, err
+	}
+	return toCallbackModels(callbacks)
+}
+
+func (d *BaetylCloudDB) CreateCallbackTx(tx *sqlx.Tx, callback *models.Callback) (*models.Callback, error) {
+	cb, err := entities.FromCallbackModel(callback)
+	if err != nil {
+		return nil, err
+	}
+	insertSQL := `
+INSERT INTO baetyl_callback (name, namespace, method, params, header, body, url, events, secret, description)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+	if _, err = d.Exec(tx, insertSQL, cb.Name, cb.Namespace, cb.Method, cb.Params, cb.Header, cb.Body, cb.URL, cb.Events, cb.Secret, cb.Description); err != nil {
+		return nil, err
+	}
+	return d.GetCallbackTx(tx, callback.Namespace, callback.Name)
+}
+
+func (d *BaetylCloudDB) UpdateCallbackTx(tx *sqlx.Tx, callback *models.Callback) (*models.Callback, error) {
+	cb, err := entities.FromCallbackModel(callback)
+	if err != nil {
+		return nil, err
+	}
+	updateSQL := `
+UPDATE baetyl_callback SET method=?, params=?, header=?, body=?, url=?, events=?, description=?, update_time=CURRENT_TIMESTAMP
+WHERE namespace=? AND name=?
+`
+	if _, err = d.Exec(tx, updateSQL, cb.Method, cb.Params, cb.Header, cb.Body, cb.URL, cb.Events, cb.Description, cb.Namespace, cb.Name); err != nil {
+		return nil, err
+	}
+	return d.GetCallbackTx(tx, callback.Namespace, callback.Name)
+}
+
+func (d *BaetylCloudDB) CreateDeliveryTx(tx *sqlx.Tx, delivery *models.Delivery) error {
+	dl, err := entities.FromDeliveryModel(delivery)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	insertSQL := `
+INSERT INTO baetyl_callback_delivery (name, namespace, callback, event_id, event, status, attempts, response_code, reason, next_time)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+	_, err = d.Exec(tx, insertSQL, dl.Name, dl.Namespace, dl.Callback, dl.EventID, dl.Event, dl.Status, dl.Attempts, dl.ResponseCode, dl.Reason, dl.NextTime)
+	return err
+}
+
+func (d *BaetylCloudDB) UpdateDeliveryTx(tx *sqlx.Tx, delivery *models.Delivery) error {
+	dl, err := entities.FromDeliveryModel(delivery)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	updateSQL := `
+UPDATE baetyl_callback_delivery SET status=?, attempts=?, response_code=?, reason=?, next_time=?, update_time=CURRENT_TIMESTAMP
+WHERE namespace=? AND name=?
+`
+	_, err = d.Exec(tx, updateSQL, dl.Status, dl.Attempts, dl.ResponseCode, dl.Reason, dl.NextTime, dl.Namespace, dl.Name)
+	return err
+}
+
+func (d *BaetylCloudDB) GetDeliveryTx(tx *sqlx.Tx, namespace, name string) (*models.Delivery, error) {
+	selectSQL := `
+SELECT id, name, namespace, callback, event_id, event, status, attempts, response_code, reason, next_time, create_time, update_time
+FROM baetyl_callback_delivery WHERE namespace=? AND name=? LIMIT 0,1
+`
+	var deliveries []entities.Delivery
+	if err := d.Query(tx, selectSQL, &deliveries, namespace, name); err != nil {
+		return nil, err
+	}
+	if len(deliveries) == 0 {
+		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "delivery"), common.Field("name", name))
+	}
+	return entities.ToDeliveryModel(&deliveries[0])
+}
+
+func (d *BaetylCloudDB) ListDeliveryTx(tx *sqlx.Tx, namespace, callback string, listOptions *models.ListOptions) ([]models.Delivery, int, error) {
+	countSQL := `SELECT count(id) AS count FROM baetyl_callback_delivery WHERE namespace=? AND callback=?`
+	var counts []struct {
+		Count int `db:"count"`
+	}
+	if err := d.Query(tx, countSQL, &counts, namespace, callback); err != nil {
+		return nil, 0, err
+	}
+	selectSQL := `
+SELECT id, name, namespace, callback, event_id, event, status, attempts, response_code, reason, next_time, create_time, update_time
+FROM baetyl_callback_delivery WHERE namespace=? AND callback=? ORDER BY id DESC
+`
+	args := []interface{}{namespace, callback}
+	if listOptions.GetLimitNumber() > 0 {
+		selectSQL = selectSQL + "LIMIT ?,?"
+		args = append(args, listOptions.GetLimitOffset(), listOptions.GetLimitNumber())
+	}
+	var deliveries []entities.Delivery
+	if err := d.Query(tx, selectSQL, &deliveries, args...); err != nil {
+		return nil, 0, err
+	}
+	res, err := toDeliveryModels(deliveries)
+	if err != nil {
+		return nil, 0, err
+	}
+	return res, counts[0].Count, nil
+}
+
+func toCallbackModels(callbacks []entities.Callback) ([]models.Callback, error) {
+	var res []models.Callback
+	for i := range callbacks {
+		callback, err := entities.ToCallbackModel(&callbacks[i])
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *callback)
+	}
+	return res, nil
+}
+
+func toDeliveryModels(deliveries []entities.Delivery) ([]models.Delivery, error) {
+	res := make([]models.Delivery, 0)
+	for i := range deliveries {
+		delivery, err := entities.ToDeliveryModel(&deliveries[i])
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *delivery)
+	}
+	return res, nil
+}
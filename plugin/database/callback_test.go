@@ -0,0 +1,247 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+var (
+	callbackTables = []string{
+		`
+CREATE TABLE baetyl_callback
+(
+	id          integer        PRIMARY KEY AUTOINCREMENT,
+	name        varchar(128)   NOT NULL DEFAULT '',
+	namespace   varchar(64)    NOT NULL DEFAULT '',
+	method      varchar(36)    NOT NULL DEFAULT 'GET',
+	params      varchar(2048)  NOT NULL DEFAULT '{}',
+	body        varchar(2048)  NOT NULL DEFAULT '{}',
+	header      varchar(1024)  NOT NULL DEFAULT '{}',
+	url         varchar(1024)  NOT NULL DEFAULT '',
+	events      varchar(1024)  NOT NULL DEFAULT '[]',
+	secret      varchar(128)   NOT NULL DEFAULT '',
+	description varchar(1024)  NOT NULL DEFAULT '',
+	create_time timestamp      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	update_time timestamp      NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`,
+		`
+CREATE TABLE baetyl_callback_delivery
+(
+	id            integer        PRIMARY KEY AUTOINCREMENT,
+	name          varchar(128)   NOT NULL DEFAULT '',
+	namespace     varchar(64)    NOT NULL DEFAULT '',
+	callback      varchar(128)   NOT NULL DEFAULT '',
+	event_id      varchar(255)   NOT NULL DEFAULT '',
+	event         text           NULL,
+	status        varchar(32)    NOT NULL DEFAULT '',
+	attempts      integer        NOT NULL DEFAULT 0,
+	response_code integer        NOT NULL DEFAULT 0,
+	reason        varchar(1024)  NOT NULL DEFAULT '',
+	next_time     timestamp      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	create_time   timestamp      NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	update_time   timestamp      NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`,
+	}
+)
+
+func (d *BaetylCloudDB) MockCreateCallbackTable() {
+	for _, sql := range callbackTables {
+		_, err := d.Exec(nil, sql)
+		if err != nil {
+			panic(fmt.Sprintf("create callback exception: %s", err.Error()))
+		}
+	}
+}
+
+func TestCallback(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateCallbackTable()
+
+	_, err = db.GetCallback(nil, "default", "cb")
+	assert.Error(t, err)
+
+	insertSQL := `INSERT INTO baetyl_callback (name, namespace, method, params, header, body, url) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	_, err = db.Exec(nil, insertSQL, "cb", "ns2", "POST", `{"a":"1"}`, `{"Authorization":"x"}`, `{}`, "http://127.0.0.1/a")
+	assert.NoError(t, err)
+	_, err = db.Exec(nil, insertSQL, "cb", "default", "PUT", `{}`, `{}`, `{"b":"2"}`, "http://127.0.0.1/b")
+	assert.NoError(t, err)
+	_, err = db.Exec(nil, insertSQL, "other", "default", "GET", `{}`, `{}`, `{}`, "http://127.0.0.1/c")
+	assert.NoError(t, err)
+
+	callback, err := db.GetCallback(nil, "ns2", "cb")
+	assert.NoError(t, err)
+	assert.Equal(t, "POST", callback.Method)
+	assert.Equal(t, map[string]string{"a": "1"}, callback.Params)
+	assert.Equal(t, map[string]string{"Authorization": "x"}, callback.Header)
+	assert.Equal(t, map[string]string{}, callback.Body)
+
+	callbacks, err := db.ListCallbackByName(nil, "cb")
+	assert.NoError(t, err)
+	assert.Len(t, callbacks, 2)
+	assert.Equal(t, "default", callbacks[0].Namespace)
+	assert.Equal(t, map[string]string{"b": "2"}, callbacks[0].Body)
+	assert.Equal(t, "ns2", callbacks[1].Namespace)
+
+	callbacks, err = db.ListCallbackByName(nil, "none")
+	assert.NoError(t, err)
+	assert.Len(t, callbacks, 0)
+}
+
+func TestCallbackSubscription(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateCallbackTable()
+
+	callback := &models.Callback{
+		Name:      "cb",
+		Namespace: "default",
+		Method:    "POST",
+		URL:       "http://127.0.0.1/a",
+		Body:      map[string]string{"node": "{{.Data.node}}"},
+		Events:    []string{models.EventNodeActivated, models.EventNodeOffline},
+		Secret:    "s3cr3t",
+	}
+	res, err := db.CreateCallback(nil, callback)
+	assert.NoError(t, err)
+	assert.Equal(t, callback.Events, res.Events)
+	assert.Equal(t, map[string]string{}, res.Params)
+	assert.Equal(t, callback.Body, res.Body)
+	assert.Equal(t, "s3cr3t", res.Secret)
+
+	_, err = db.CreateCallback(nil, &models.Callback{Name: "other", Namespace: "ns2", Method: "GET", URL: "http://127.0.0.1/b",
+		Events: []string{models.EventNodeOffline}})
+	assert.NoError(t, err)
+
+	callbacks, err := db.ListCallbackByEvent(nil, models.EventNodeOffline)
+	assert.NoError(t, err)
+	assert.Len(t, callbacks, 2)
+	callbacks, err = db.ListCallbackByEvent(nil, models.EventNodeActivated)
+	assert.NoError(t, err)
+	assert.Len(t, callbacks, 1)
+	assert.Equal(t, "cb", callbacks[0].Name)
+
+	callback.Events = []string{models.EventQuotaExceeded}
+	callback.Description = "desc"
+	callback.Secret = "changed"
+	res, err = db.UpdateCallback(nil, callback)
+	assert.NoError(t, err)
+	assert.Equal(t, "desc", res.Description)
+	// the secret isn't updated
+	assert.Equal(t, "s3cr3t", res.Secret)
+	callbacks, err = db.ListCallbackByEvent(nil, models.EventNodeActivated)
+	assert.NoError(t, err)
+	assert.Len(t, callbacks, 0)
+
+	callbacks, err = db.ListCallback(nil, "default")
+	assert.NoError(t, err)
+	assert.Len(t, callbacks, 1)
+
+	assert.NoError(t, db.DeleteCallback(nil, "default", "cb"))
+	_, err = db.GetCallback(nil, "default", "cb")
+	assert.Error(t, err)
+}
+
+func TestCallbackDelivery(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateCallbackTable()
+
+	now := time.Now().UTC()
+	event := &models.Event{ID: "e1", Type: models.EventNodeActivated, Namespace: "default", Data: map[string]interface{}{"node": "n1"}}
+	d1 := &models.Delivery{Name: "d1", Namespace: "default", Callback: "cb", Event: event, Status: models.DeliveryPending, NextTime: now.Add(-time.Minute)}
+	d2 := &models.Delivery{Name: "d2", Namespace: "default", Callback: "cb", Event: event, Status: models.DeliveryPending, NextTime: now.Add(time.Hour)}
+	assert.NoError(t, db.CreateDelivery(nil, d1))
+	assert.NoError(t, db.CreateDelivery(nil, d2))
+
+	res, err := db.GetDelivery(nil, "default", "d1")
+	assert.NoError(t, err)
+	assert.Equal(t, event, res.Event)
+	assert.Equal(t, models.DeliveryPending, res.Status)
+	_, err = db.GetDelivery(nil, "default", "none")
+	assert.Error(t, err)
+
+	count, err := db.CountDeliveryByEvent(nil, "default", "cb", "e1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	due, err := db.ListDueDelivery(nil, now, 10)
+	assert.NoError(t, err)
+	assert.Len(t, due, 1)
+	assert.Equal(t, "d1", due[0].Name)
+
+	// only one of the instances claims the due delivery
+	claimed, err := db.ClaimDelivery(nil, &due[0], now, now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+	claimed, err = db.ClaimDelivery(nil, &due[0], now, now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+	due, err = db.ListDueDelivery(nil, now, 10)
+	assert.NoError(t, err)
+	assert.Len(t, due, 0)
+	// the claim expires
+	due, err = db.ListDueDelivery(nil, now.Add(2*time.Minute), 10)
+	assert.NoError(t, err)
+	assert.Len(t, due, 1)
+	claimed, err = db.ClaimDelivery(nil, &due[0], now.Add(2*time.Minute), now.Add(3*time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+	// the delivery attempted by others isn't claimed
+	claimed, err = db.ClaimDelivery(nil, &models.Delivery{Namespace: "default", Name: "d1", Status: models.DeliveryPending, Attempts: 1},
+		now.Add(4*time.Minute), now.Add(5*time.Minute))
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+
+	d1.Status = models.DeliveryFailed
+	d1.Attempts = 3
+	d1.ResponseCode = 500
+	d1.Reason = "error"
+	assert.NoError(t, db.UpdateDelivery(nil, d1))
+	res, err = db.GetDelivery(nil, "default", "d1")
+	assert.NoError(t, err)
+	assert.Equal(t, models.DeliveryFailed, res.Status)
+	assert.Equal(t, 3, res.Attempts)
+	assert.Equal(t, 500, res.ResponseCode)
+	due, err = db.ListDueDelivery(nil, now, 10)
+	assert.NoError(t, err)
+	assert.Len(t, due, 0)
+	// the failed delivery is claimed for redelivery with its status
+	claimed, err = db.ClaimDelivery(nil, &models.Delivery{Namespace: "default", Name: "d1", Status: models.DeliveryPending, Attempts: 3},
+		now.Add(4*time.Minute), now.Add(5*time.Minute))
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+	claimed, err = db.ClaimDelivery(nil, res, now.Add(4*time.Minute), now.Add(5*time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+
+	list, err := db.ListDelivery(nil, "default", "cb", &models.ListOptions{Filter: models.Filter{PageNo: 1, PageSize: 1}})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, list.Total)
+	assert.Len(t, list.Items, 1)
+	assert.Equal(t, "d2", list.Items[0].Name)
+
+	assert.NoError(t, db.DeleteDeliveryByCallback(nil, "default", "cb"))
+	count, err = db.CountDeliveryByEvent(nil, "default", "cb", "e1")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"io"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/baetyl/baetyl-go/v2/errors"
@@ -183,7 +184,10 @@ func (d *DB) Rollback(tx *sqlx.Tx) {
 
 type BaetylCloudDB struct {
 	DB
-	log *log.Logger
+	// kms is nil if the encryption of secrets is disabled
+	kms      plugin.KMS
+	dataKeys sync.Map
+	log      *log.Logger
 }
 
 func NewExtDB() (plugin.Plugin, error) {
@@ -195,6 +199,13 @@ func NewExtDB() (plugin.Plugin, error) {
 		log: log.L().With(log.Any("plugin", "databaseext")),
 	}
 	dbExt.DB = *db.(*DB)
+	if dbExt.cfg.Database.Encryption.Enable {
+		kms, err := plugin.GetPlugin(dbExt.cfg.Database.Encryption.KMS)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		dbExt.kms = kms.(plugin.KMS)
+	}
 	return dbExt, nil
 }
 
@@ -9,5 +9,10 @@ type CloudConfig struct {
 		MaxConns        int    `yaml:"maxConns" json:"maxConns" default:"20"`
 		MaxIdleConns    int    `yaml:"maxIdleConns" json:"maxIdleConns" default:"5"`
 		ConnMaxLifetime int    `yaml:"connMaxLifetime" json:"connMaxLifetime" default:"150"`
+		// Encryption encrypts the data of secrets at rest with the per-namespace data keys wrapped by the kms plugin
+		Encryption struct {
+			Enable bool   `yaml:"enable" json:"enable"`
+			KMS    string `yaml:"kms" json:"kms" default:"localkms"`
+		} `yaml:"encryption" json:"encryption"`
 	} `yaml:"database" json:"database" default:"{}"`
 }
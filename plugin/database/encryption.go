@@ -0,0 +1,344 @@
+package database
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/common/util"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/database/entities"
+)
+
+// the encrypted data is saved as "enc2:<id of data key>:<base64 of ciphertext>", the namespace and name of the data
+// are bound as the additional data, so the ciphertext can't be moved to other rows. The data saved as "enc:" was
+// encrypted without the additional data, which is still decrypted until re-encrypted. The data without the prefix is plaintext
+const (
+	encryptedDataPrefix   = "enc2:"
+	encryptedDataPrefixV1 = "enc:"
+	dataKeySize           = 32
+
+	secretDataAAD     = "secret"
+	recycleContentAAD = "recycle"
+)
+
+// dataKey the unwrapped data key cached by id
+type dataKey struct {
+	namespace string
+	key       []byte
+}
+
+// ReencryptSecrets encrypts the secrets of namespace with a new data key wrapped by the current key of kms,
+// the old data keys are kept until purged by PurgeDataKeys, since other replicas may still use them.
+// The secrets of all namespaces are re-encrypted if the namespace is empty.
+// It's used to rotate the keys and to encrypt the plaintext secrets saved before the encryption is enabled.
+func (d *BaetylCloudDB) ReencryptSecrets(namespace string) (int, error) {
+	if d.kms == nil {
+		return 0, errors.New("the encryption of secrets is disabled")
+	}
+	namespaces := []string{namespace}
+	if namespace == "" {
+		var err error
+		if namespaces, err = d.listEncryptionNamespaces(); err != nil {
+			return 0, err
+		}
+	}
+	count := 0
+	for _, ns := range namespaces {
+		err := d.Transact(func(tx *sqlx.Tx) error {
+			n, err := d.reencryptNamespace(tx, ns)
+			count += n
+			return err
+		})
+		if err != nil {
+			return count, err
+		}
+		d.log.Info("secrets are re-encrypted", log.Any("namespace", ns))
+	}
+	return count, nil
+}
+
+func (d *BaetylCloudDB) reencryptNamespace(tx *sqlx.Tx, namespace string) (int, error) {
+	id, key, err := d.createDataKey(tx, namespace)
+	if err != nil {
+		return 0, err
+	}
+	var secrets []entities.Secret
+	if err = d.Query(tx, `SELECT id, name, data FROM baetyl_secret WHERE namespace=?`, &secrets, namespace); err != nil {
+		return 0, err
+	}
+	for _, s := range secrets {
+		aad := dataAAD(secretDataAAD, namespace, s.Name)
+		data, err := d.reencryptData(tx, namespace, aad, s.Data, id, key)
+		if err != nil {
+			return 0, err
+		}
+		if _, err = d.Exec(tx, `UPDATE baetyl_secret SET data=? WHERE id=?`, data, s.ID); err != nil {
+			return 0, err
+		}
+	}
+	// the deleted secrets kept in recycle bin
+	var items []entities.RecycleItem
+	if err = d.Query(tx, `SELECT id, name, content FROM baetyl_recycle_bin WHERE namespace=?`, &items, namespace); err != nil {
+		return 0, err
+	}
+	for _, item := range items {
+		if !isEncryptedData(item.Content) {
+			continue
+		}
+		aad := dataAAD(recycleContentAAD, namespace, item.Name)
+		content, err := d.reencryptData(tx, namespace, aad, item.Content, id, key)
+		if err != nil {
+			return 0, err
+		}
+		if _, err = d.Exec(tx, `UPDATE baetyl_recycle_bin SET content=? WHERE id=?`, content, item.ID); err != nil {
+			return 0, err
+		}
+	}
+	return len(secrets), nil
+}
+
+// PurgeDataKeys deletes the retired data keys which were superseded before the time and are referenced by no data,
+// the keys superseded recently are kept, since other replicas may still encrypt with them
+func (d *BaetylCloudDB) PurgeDataKeys(before time.Time) (int, error) {
+	var keys []entities.DataKey
+	if err := d.Query(nil, `SELECT id, namespace, create_time FROM baetyl_data_key ORDER BY namespace, id`, &keys); err != nil {
+		return 0, err
+	}
+	count := 0
+	for i := 0; i+1 < len(keys); i++ {
+		key, next := keys[i], keys[i+1]
+		if next.Namespace != key.Namespace || !next.CreateTime.Before(before) {
+			continue
+		}
+		used := false
+		err := d.Transact(func(tx *sqlx.Tx) error {
+			var err error
+			if used, err = d.isDataKeyUsed(tx, key.Namespace, key.ID); err != nil || used {
+				return err
+			}
+			_, err = d.Exec(tx, `DELETE FROM baetyl_data_key WHERE id=?`, key.ID)
+			return err
+		})
+		if err != nil {
+			return count, err
+		}
+		if used {
+			continue
+		}
+		d.dataKeys.Delete(key.ID)
+		count++
+	}
+	if count > 0 {
+		d.log.Info("retired data keys are purged", log.Any("count", count))
+	}
+	return count, nil
+}
+
+// isDataKeyUsed checks whether any data of namespace is encrypted by the data key
+func (d *BaetylCloudDB) isDataKeyUsed(tx *sqlx.Tx, namespace string, id int64) (bool, error) {
+	patterns := []interface{}{
+		encryptedDataPrefix + strconv.FormatInt(id, 10) + ":%",
+		encryptedDataPrefixV1 + strconv.FormatInt(id, 10) + ":%",
+	}
+	for _, q := range []string{
+		`SELECT count(id) AS count FROM baetyl_secret WHERE namespace=? AND (data LIKE ? OR data LIKE ?)`,
+		`SELECT count(id) AS count FROM baetyl_recycle_bin WHERE namespace=? AND (content LIKE ? OR content LIKE ?)`,
+	} {
+		var counts []struct {
+			Count int `db:"count"`
+		}
+		if err := d.Query(tx, q, &counts, append([]interface{}{namespace}, patterns...)...); err != nil {
+			return false, err
+		}
+		if len(counts) > 0 && counts[0].Count > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (d *BaetylCloudDB) reencryptData(tx *sqlx.Tx, namespace string, aad []byte, data string, id int64, key []byte) (string, error) {
+	plain, err := d.decryptData(tx, namespace, aad, data)
+	if err != nil {
+		return "", err
+	}
+	return sealData(plain, aad, id, key)
+}
+
+// encryptData encrypts the data with the data key of namespace, the data is returned as it is if the encryption is disabled
+func (d *BaetylCloudDB) encryptData(tx *sqlx.Tx, namespace string, aad []byte, data string) (string, error) {
+	if d.kms == nil {
+		return data, nil
+	}
+	id, key, err := d.getCurrentDataKey(tx, namespace)
+	if err != nil {
+		return "", err
+	}
+	return sealData(data, aad, id, key)
+}
+
+// decryptData decrypts the data encrypted by encryptData with the same namespace and additional data,
+// the plaintext data is returned as it is
+func (d *BaetylCloudDB) decryptData(tx *sqlx.Tx, namespace string, aad []byte, data string) (string, error) {
+	var rest string
+	switch {
+	case strings.HasPrefix(data, encryptedDataPrefix):
+		rest = strings.TrimPrefix(data, encryptedDataPrefix)
+	case strings.HasPrefix(data, encryptedDataPrefixV1):
+		rest, aad = strings.TrimPrefix(data, encryptedDataPrefixV1), nil
+	default:
+		return data, nil
+	}
+	parts := strings.SplitN(rest, ":", 2)
+	if len(parts) != 2 {
+		return "", errors.New("invalid encrypted data")
+	}
+	id, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	key, err := d.getDataKey(tx, namespace, id)
+	if err != nil {
+		return "", err
+	}
+	plain, err := util.OpenGCMWithAAD(ciphertext, key, aad)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return string(plain), nil
+}
+
+func isEncryptedData(data string) bool {
+	return strings.HasPrefix(data, encryptedDataPrefix) || strings.HasPrefix(data, encryptedDataPrefixV1)
+}
+
+// dataAAD the additional data which binds the ciphertext to the kind, namespace and name of data
+func dataAAD(kind, namespace, name string) []byte {
+	return []byte(kind + "/" + namespace + "/" + name)
+}
+
+func sealData(data string, aad []byte, id int64, key []byte) (string, error) {
+	ciphertext, err := util.SealGCMWithAAD([]byte(data), key, aad)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return encryptedDataPrefix + strconv.FormatInt(id, 10) + ":" + base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// getCurrentDataKey returns the latest data key of namespace, a new one is created if not exists
+func (d *BaetylCloudDB) getCurrentDataKey(tx *sqlx.Tx, namespace string) (int64, []byte, error) {
+	var keys []entities.DataKey
+	selectSQL := `
+SELECT id, namespace, key_id, ciphertext, create_time
+FROM baetyl_data_key WHERE namespace=? ORDER BY id DESC LIMIT 1
+`
+	if err := d.Query(tx, selectSQL, &keys, namespace); err != nil {
+		return 0, nil, err
+	}
+	if len(keys) == 0 {
+		return d.createDataKey(tx, namespace)
+	}
+	key, err := d.unwrapDataKey(&keys[0])
+	return keys[0].ID, key, err
+}
+
+// getDataKey returns the data key of id, which should belong to the namespace
+func (d *BaetylCloudDB) getDataKey(tx *sqlx.Tx, namespace string, id int64) ([]byte, error) {
+	if v, ok := d.dataKeys.Load(id); ok {
+		if k := v.(*dataKey); k.namespace == namespace {
+			return k.key, nil
+		}
+		return nil, errors.Errorf("data key (%d) doesn't belong to namespace (%s)", id, namespace)
+	}
+	if d.kms == nil {
+		return nil, errors.New("the encryption of secrets is disabled, failed to decrypt the encrypted data")
+	}
+	var keys []entities.DataKey
+	selectSQL := `
+SELECT id, namespace, key_id, ciphertext, create_time
+FROM baetyl_data_key WHERE id=? AND namespace=?
+`
+	if err := d.Query(tx, selectSQL, &keys, id, namespace); err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, errors.Errorf("data key (%d) of namespace (%s) is not found", id, namespace)
+	}
+	return d.unwrapDataKey(&keys[0])
+}
+
+func (d *BaetylCloudDB) unwrapDataKey(k *entities.DataKey) ([]byte, error) {
+	if v, ok := d.dataKeys.Load(k.ID); ok {
+		return v.(*dataKey).key, nil
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(k.Ciphertext)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	key, err := d.kms.Decrypt(k.KeyID, ciphertext)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	d.dataKeys.Store(k.ID, &dataKey{namespace: k.Namespace, key: key})
+	return key, nil
+}
+
+func (d *BaetylCloudDB) createDataKey(tx *sqlx.Tx, namespace string) (int64, []byte, error) {
+	key := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return 0, nil, errors.Trace(err)
+	}
+	keyID, ciphertext, err := d.kms.Encrypt(key)
+	if err != nil {
+		return 0, nil, errors.Trace(err)
+	}
+	insertSQL := `
+INSERT INTO baetyl_data_key (namespace, key_id, ciphertext)
+VALUES (?, ?, ?)
+`
+	res, err := d.Exec(tx, insertSQL, namespace, keyID, base64.StdEncoding.EncodeToString(ciphertext))
+	if err != nil {
+		return 0, nil, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, nil, errors.Trace(err)
+	}
+	d.dataKeys.Store(id, &dataKey{namespace: namespace, key: key})
+	return id, key, nil
+}
+
+func (d *BaetylCloudDB) listEncryptionNamespaces() ([]string, error) {
+	var res []struct {
+		Namespace string `db:"namespace"`
+	}
+	selectSQL := `
+SELECT DISTINCT namespace FROM baetyl_secret
+UNION SELECT DISTINCT namespace FROM baetyl_data_key
+`
+	if err := d.Query(nil, selectSQL, &res); err != nil {
+		return nil, err
+	}
+	var namespaces []string
+	for _, r := range res {
+		namespaces = append(namespaces, r.Namespace)
+	}
+	return namespaces, nil
+}
+
+// DeleteDataKeysTx deletes the data keys of namespace
+func (d *BaetylCloudDB) DeleteDataKeysTx(tx *sqlx.Tx, namespace string) error {
+	_, err := d.Exec(tx, `DELETE FROM baetyl_data_key WHERE namespace=?`, namespace)
+	return err
+}
@@ -0,0 +1,220 @@
+package database
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common/util"
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+var (
+	dataKeyTables = []string{
+		`
+CREATE TABLE baetyl_data_key
+(
+	id          integer       PRIMARY KEY AUTOINCREMENT,
+	namespace   varchar(64)   NOT NULL DEFAULT '',
+	key_id      varchar(128)  NOT NULL DEFAULT '',
+	ciphertext  varchar(1024) NOT NULL DEFAULT '',
+	create_time timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`,
+	}
+)
+
+func (d *BaetylCloudDB) MockCreateDataKeyTable() {
+	for _, sql := range dataKeyTables {
+		_, err := d.Exec(nil, sql)
+		if err != nil {
+			panic(fmt.Sprintf("create data key exception: %s", err.Error()))
+		}
+	}
+}
+
+// mockKMS wraps the data keys with the fixed keys
+func mockKMS(mockCtl *gomock.Controller, current *string) *mockPlugin.MockKMS {
+	keys := map[string][]byte{
+		"k1": []byte("0123456789abcdef0123456789abcdef"),
+		"k2": []byte("abcdef0123456789abcdef0123456789"),
+	}
+	kms := mockPlugin.NewMockKMS(mockCtl)
+	kms.EXPECT().Encrypt(gomock.Any()).DoAndReturn(func(plaintext []byte) (string, []byte, error) {
+		c, err := util.SealGCM(plaintext, keys[*current])
+		return *current, c, err
+	}).AnyTimes()
+	kms.EXPECT().Decrypt(gomock.Any(), gomock.Any()).DoAndReturn(func(keyID string, ciphertext []byte) ([]byte, error) {
+		return util.OpenGCM(ciphertext, keys[keyID])
+	}).AnyTimes()
+	return kms
+}
+
+func TestSecretEncryption(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateSecretTable()
+	db.MockCreateDataKeyTable()
+	db.MockCreateRecycleBinTable()
+
+	ns := "default"
+	// the plaintext secret saved before the encryption is enabled
+	plain := &specV1.Secret{Name: "plain", Namespace: ns, Data: map[string][]byte{"a": []byte("1")}}
+	_, err = db.CreateSecret(nil, ns, plain)
+	assert.NoError(t, err)
+	_, err = db.ReencryptSecrets(ns)
+	assert.Error(t, err)
+
+	current := "k1"
+	db.kms = mockKMS(mockCtl, &current)
+
+	secret := &specV1.Secret{Name: "s1", Namespace: ns, Data: map[string][]byte{"password": []byte("p")}}
+	res, err := db.CreateSecret(nil, ns, secret)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("p"), res.Data["password"])
+	assert.Equal(t, []string{"p"}, rawSecretData(t, db, ns, "s1", false))
+
+	secret.Data["password"] = []byte("q")
+	res, err = db.UpdateSecret(ns, secret)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("q"), res.Data["password"])
+
+	list, err := db.ListSecret(ns, &models.ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, list.Items, 2)
+	assert.Equal(t, []string{"1"}, rawSecretData(t, db, ns, "plain", true))
+
+	item, err := db.CreateRecycleItem(nil, &models.RecycleItem{Namespace: ns, Kind: models.RecycleKindSecret, Name: "s2",
+		Secret: &specV1.Secret{Name: "s2", Data: map[string][]byte{"token": []byte("t")}}})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("t"), item.Secret.Data["token"])
+
+	// rotate the key, the data keys are wrapped by the new key and the plaintext secrets are encrypted
+	current = "k2"
+	count, err := db.ReencryptSecrets("")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	// the retired data key is kept for the other replicas
+	var keys []struct {
+		ID    int64  `db:"id"`
+		KeyID string `db:"key_id"`
+	}
+	assert.NoError(t, db.Query(nil, "SELECT id, key_id FROM baetyl_data_key ORDER BY id", &keys))
+	assert.Len(t, keys, 2)
+	assert.Equal(t, "k1", keys[0].KeyID)
+	assert.Equal(t, "k2", keys[1].KeyID)
+	assert.Equal(t, []string{"1"}, rawSecretData(t, db, ns, "plain", false))
+
+	// the data keys are unwrapped again
+	db.dataKeys = sync.Map{}
+	res, err = db.GetSecret(nil, ns, "s1", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("q"), res.Data["password"])
+	item, err = db.GetRecycleItem(nil, ns, item.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("t"), item.Secret.Data["token"])
+
+	// the ciphertext is bound to the namespace and name of secret
+	var rows []struct {
+		Data string `db:"data"`
+	}
+	assert.NoError(t, db.Query(nil, "SELECT data FROM baetyl_secret WHERE namespace=? AND name=?", &rows, ns, "s1"))
+	assert.Len(t, rows, 1)
+	s1 := rows[0].Data
+	_, err = db.Exec(nil, "UPDATE baetyl_secret SET data=? WHERE namespace=? AND name=?", s1, ns, "plain")
+	assert.NoError(t, err)
+	_, err = db.GetSecret(nil, ns, "plain", "")
+	assert.Error(t, err)
+	_, err = db.CreateSecret(nil, "other", &specV1.Secret{Name: "s1", Namespace: "other"})
+	assert.NoError(t, err)
+	_, err = db.Exec(nil, "UPDATE baetyl_secret SET data=? WHERE namespace=? AND name=?", s1, "other", "s1")
+	assert.NoError(t, err)
+	_, err = db.GetSecret(nil, "other", "s1", "")
+	assert.Error(t, err)
+	_, err = db.decryptData(nil, "other", dataAAD(secretDataAAD, ns, "s1"), s1)
+	assert.Error(t, err)
+
+	// the data encrypted by the retired key without the additional data is still readable
+	plainData, err := db.decryptData(nil, ns, dataAAD(secretDataAAD, ns, "s1"), s1)
+	assert.NoError(t, err)
+	key, err := db.getDataKey(nil, ns, keys[0].ID)
+	assert.NoError(t, err)
+	ciphertext, err := util.SealGCM([]byte(plainData), key)
+	assert.NoError(t, err)
+	v1 := fmt.Sprintf("%s%d:%s", encryptedDataPrefixV1, keys[0].ID, base64.StdEncoding.EncodeToString(ciphertext))
+	_, err = db.Exec(nil, "UPDATE baetyl_secret SET data=? WHERE namespace=? AND name=?", v1, ns, "plain")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"q"}, rawSecretData(t, db, ns, "plain", false))
+
+	// the retired data keys are purged after the retention if no data refers to them
+	count, err = db.PurgeDataKeys(time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+	count, err = db.PurgeDataKeys(time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+	// the data is upgraded by re-encryption
+	current = "k1"
+	_, err = db.ReencryptSecrets(ns)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(rawData(t, db, ns, "plain"), encryptedDataPrefix))
+	count, err = db.PurgeDataKeys(time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	keys = nil
+	assert.NoError(t, db.Query(nil, "SELECT id, key_id FROM baetyl_data_key WHERE namespace=? ORDER BY id", &keys, ns))
+	assert.Len(t, keys, 1)
+	assert.Equal(t, "k1", keys[0].KeyID)
+	res, err = db.GetSecret(nil, ns, "s1", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("q"), res.Data["password"])
+	item, err = db.GetRecycleItem(nil, ns, item.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("t"), item.Secret.Data["token"])
+
+	// the encrypted secrets can't be read without kms
+	db.kms = nil
+	db.dataKeys = sync.Map{}
+	_, err = db.GetSecret(nil, ns, "s1", "")
+	assert.Error(t, err)
+}
+
+// rawSecretData checks whether the data saved in database is plaintext, and returns the values of the read data
+func rawSecretData(t *testing.T, db *BaetylCloudDB, ns, name string, plain bool) []string {
+	var rows []struct {
+		Data string `db:"data"`
+	}
+	assert.NoError(t, db.Query(nil, "SELECT data FROM baetyl_secret WHERE namespace=? AND name=?", &rows, ns, name))
+	assert.Len(t, rows, 1)
+	assert.Equal(t, !plain, isEncryptedData(rows[0].Data))
+	s, err := db.GetSecret(nil, ns, name, "")
+	assert.NoError(t, err)
+	var res []string
+	for _, v := range s.Data {
+		res = append(res, string(v))
+	}
+	return res
+}
+
+func rawData(t *testing.T, db *BaetylCloudDB, ns, name string) string {
+	var rows []struct {
+		Data string `db:"data"`
+	}
+	assert.NoError(t, db.Query(nil, "SELECT data FROM baetyl_secret WHERE namespace=? AND name=?", &rows, ns, name))
+	assert.Len(t, rows, 1)
+	return rows[0].Data
+}
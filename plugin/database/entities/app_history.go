@@ -0,0 +1,73 @@
+// Package entities 数据库存储基本结构与方法
+package entities
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/json"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+type AppHistory struct {
+	ID         int64     `db:"id"`
+	Namespace  string    `db:"namespace"`
+	Name       string    `db:"name"`
+	Revision   int64     `db:"revision"`
+	Version    string    `db:"version"`
+	Content    string    `db:"content"`
+	Configs    string    `db:"configs"`
+	Secrets    string    `db:"secrets"`
+	CreateTime time.Time `db:"create_time"`
+}
+
+func ToAppHistoryModel(history *AppHistory) (*models.AppHistory, error) {
+	app := new(specV1.Application)
+	if err := json.Unmarshal([]byte(history.Content), app); err != nil {
+		return nil, errors.Trace(err)
+	}
+	configs := map[string]string{}
+	if err := json.Unmarshal([]byte(history.Configs), &configs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	secrets := map[string]string{}
+	if err := json.Unmarshal([]byte(history.Secrets), &secrets); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &models.AppHistory{
+		Namespace:   history.Namespace,
+		Name:        history.Name,
+		Revision:    history.Revision,
+		Version:     history.Version,
+		Application: app,
+		Configs:     configs,
+		Secrets:     secrets,
+		CreateTime:  history.CreateTime.UTC(),
+	}, nil
+}
+
+func FromAppHistoryModel(history *models.AppHistory) (*AppHistory, error) {
+	content, err := json.Marshal(history.Application)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	configs, err := json.Marshal(history.Configs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	secrets, err := json.Marshal(history.Secrets)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &AppHistory{
+		Namespace: history.Namespace,
+		Name:      history.Name,
+		Revision:  history.Revision,
+		Version:   history.Version,
+		Content:   string(content),
+		Configs:   string(configs),
+		Secrets:   string(secrets),
+	}, nil
+}
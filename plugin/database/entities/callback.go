@@ -0,0 +1,162 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/json"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+type Callback struct {
+	ID          int64     `db:"id"`
+	Name        string    `db:"name"`
+	Namespace   string    `db:"namespace"`
+	Method      string    `db:"method"`
+	Params      string    `db:"params"`
+	Header      string    `db:"header"`
+	Body        string    `db:"body"`
+	URL         string    `db:"url"`
+	Events      string    `db:"events"`
+	Secret      string    `db:"secret"`
+	Description string    `db:"description"`
+	CreateTime  time.Time `db:"create_time"`
+	UpdateTime  time.Time `db:"update_time"`
+}
+
+type Delivery struct {
+	ID           int64     `db:"id"`
+	Name         string    `db:"name"`
+	Namespace    string    `db:"namespace"`
+	Callback     string    `db:"callback"`
+	EventID      string    `db:"event_id"`
+	Event        string    `db:"event"`
+	Status       string    `db:"status"`
+	Attempts     int       `db:"attempts"`
+	ResponseCode int       `db:"response_code"`
+	Reason       string    `db:"reason"`
+	NextTime     time.Time `db:"next_time"`
+	CreateTime   time.Time `db:"create_time"`
+	UpdateTime   time.Time `db:"update_time"`
+}
+
+func ToCallbackModel(callback *Callback) (*models.Callback, error) {
+	res := &models.Callback{
+		Name:        callback.Name,
+		Namespace:   callback.Namespace,
+		Method:      callback.Method,
+		URL:         callback.URL,
+		Params:      map[string]string{},
+		Header:      map[string]string{},
+		Body:        map[string]string{},
+		Events:      []string{},
+		Secret:      callback.Secret,
+		Description: callback.Description,
+		CreateTime:  callback.CreateTime,
+		UpdateTime:  callback.UpdateTime,
+	}
+	if err := unmarshalCallbackField(callback.Params, &res.Params); err != nil {
+		return nil, err
+	}
+	if err := unmarshalCallbackField(callback.Header, &res.Header); err != nil {
+		return nil, err
+	}
+	if err := unmarshalCallbackField(callback.Body, &res.Body); err != nil {
+		return nil, err
+	}
+	if callback.Events != "" {
+		if err := json.Unmarshal([]byte(callback.Events), &res.Events); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return res, nil
+}
+
+func FromCallbackModel(callback *models.Callback) (*Callback, error) {
+	res := &Callback{
+		Name:        callback.Name,
+		Namespace:   callback.Namespace,
+		Method:      callback.Method,
+		URL:         callback.URL,
+		Secret:      callback.Secret,
+		Description: callback.Description,
+	}
+	var err error
+	if res.Params, err = marshalCallbackField(callback.Params, "{}"); err != nil {
+		return nil, err
+	}
+	if res.Header, err = marshalCallbackField(callback.Header, "{}"); err != nil {
+		return nil, err
+	}
+	if res.Body, err = marshalCallbackField(callback.Body, "{}"); err != nil {
+		return nil, err
+	}
+	if res.Events, err = marshalCallbackField(callback.Events, "[]"); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func ToDeliveryModel(delivery *Delivery) (*models.Delivery, error) {
+	res := &models.Delivery{
+		Name:         delivery.Name,
+		Namespace:    delivery.Namespace,
+		Callback:     delivery.Callback,
+		Status:       models.DeliveryStatus(delivery.Status),
+		Attempts:     delivery.Attempts,
+		ResponseCode: delivery.ResponseCode,
+		Reason:       delivery.Reason,
+		NextTime:     delivery.NextTime.UTC(),
+		CreateTime:   delivery.CreateTime.UTC(),
+		UpdateTime:   delivery.UpdateTime.UTC(),
+	}
+	if delivery.Event != "" {
+		res.Event = new(models.Event)
+		if err := json.Unmarshal([]byte(delivery.Event), res.Event); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return res, nil
+}
+
+func FromDeliveryModel(delivery *models.Delivery) (*Delivery, error) {
+	res := &Delivery{
+		Name:         delivery.Name,
+		Namespace:    delivery.Namespace,
+		Callback:     delivery.Callback,
+		Status:       string(delivery.Status),
+		Attempts:     delivery.Attempts,
+		ResponseCode: delivery.ResponseCode,
+		Reason:       delivery.Reason,
+		NextTime:     delivery.NextTime,
+	}
+	if delivery.Event != nil {
+		event, err := json.Marshal(delivery.Event)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		res.EventID = delivery.Event.ID
+		res.Event = string(event)
+	}
+	return res, nil
+}
+
+// marshalCallbackField returns the empty value if the field is not set
+func marshalCallbackField(v interface{}, empty string) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if string(data) == "null" {
+		return empty, nil
+	}
+	return string(data), nil
+}
+
+func unmarshalCallbackField(data string, v *map[string]string) error {
+	if data == "" {
+		return nil
+	}
+	return errors.Trace(json.Unmarshal([]byte(data), v))
+}
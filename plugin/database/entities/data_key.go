@@ -0,0 +1,12 @@
+package entities
+
+import "time"
+
+// DataKey the data key of namespace, which is wrapped by the key encryption key of kms
+type DataKey struct {
+	ID         int64     `db:"id"`
+	Namespace  string    `db:"namespace"`
+	KeyID      string    `db:"key_id"`
+	Ciphertext string    `db:"ciphertext"`
+	CreateTime time.Time `db:"create_time"`
+}
@@ -0,0 +1,80 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+type InstallToken struct {
+	ID          int64     `db:"id"`
+	Name        string    `db:"name"`
+	Namespace   string    `db:"namespace"`
+	NodeName    string    `db:"node_name"`
+	SingleUse   int       `db:"single_use"`
+	Revoked     int       `db:"revoked"`
+	ConsumeIP   string    `db:"consume_ip"`
+	ConsumeTime time.Time `db:"consume_time"`
+	UseCount    int       `db:"use_count"`
+	ExpireTime  time.Time `db:"expire_time"`
+	CreateTime  time.Time `db:"create_time"`
+	UpdateTime  time.Time `db:"update_time"`
+}
+
+func ToInstallTokenModel(token *InstallToken) *models.InstallToken {
+	res := &models.InstallToken{
+		Name:       token.Name,
+		Namespace:  token.Namespace,
+		NodeName:   token.NodeName,
+		SingleUse:  token.SingleUse == 1,
+		Revoked:    token.Revoked == 1,
+		ConsumeIP:  token.ConsumeIP,
+		UseCount:   token.UseCount,
+		ExpireTime: token.ExpireTime.UTC(),
+		CreateTime: token.CreateTime.UTC(),
+		UpdateTime: token.UpdateTime.UTC(),
+	}
+	if token.ConsumeIP != "" {
+		res.ConsumeTime = token.ConsumeTime.UTC()
+	}
+	return res
+}
+
+func FromInstallTokenModel(token *models.InstallToken) *InstallToken {
+	res := &InstallToken{
+		Name:        token.Name,
+		Namespace:   token.Namespace,
+		NodeName:    token.NodeName,
+		ConsumeIP:   token.ConsumeIP,
+		ConsumeTime: time.Unix(common.DefaultActiveTime, 0),
+		UseCount:    token.UseCount,
+		ExpireTime:  token.ExpireTime,
+	}
+	if token.SingleUse {
+		res.SingleUse = 1
+	}
+	if token.Revoked {
+		res.Revoked = 1
+	}
+	return res
+}
+
+type InstallTokenConsume struct {
+	ID         int64     `db:"id"`
+	Namespace  string    `db:"namespace"`
+	Name       string    `db:"name"`
+	ConsumeIP  string    `db:"consume_ip"`
+	Accepted   int       `db:"accepted"`
+	CreateTime time.Time `db:"create_time"`
+}
+
+func ToInstallTokenConsumeModel(consume *InstallTokenConsume) *models.InstallTokenConsume {
+	return &models.InstallTokenConsume{
+		Name:       consume.Name,
+		Namespace:  consume.Namespace,
+		ConsumeIP:  consume.ConsumeIP,
+		Accepted:   consume.Accepted == 1,
+		CreateTime: consume.CreateTime.UTC(),
+	}
+}
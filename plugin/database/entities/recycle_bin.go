@@ -0,0 +1,68 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/json"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+type RecycleItem struct {
+	ID         int64     `db:"id"`
+	Namespace  string    `db:"namespace"`
+	Kind       string    `db:"kind"`
+	Name       string    `db:"name"`
+	Content    string    `db:"content"`
+	DeleteTime time.Time `db:"delete_time"`
+	ExpireTime time.Time `db:"expire_time"`
+}
+
+type recycleContent struct {
+	Application *specV1.Application    `json:"application,omitempty"`
+	Configs     []specV1.Configuration `json:"configs,omitempty"`
+	Secret      *specV1.Secret         `json:"secret,omitempty"`
+	Histories   []models.AppHistory    `json:"histories,omitempty"`
+}
+
+func ToRecycleItemModel(item *RecycleItem) (*models.RecycleItem, error) {
+	content := new(recycleContent)
+	if err := json.Unmarshal([]byte(item.Content), content); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &models.RecycleItem{
+		ID:          item.ID,
+		Namespace:   item.Namespace,
+		Kind:        item.Kind,
+		Name:        item.Name,
+		Application: content.Application,
+		Configs:     content.Configs,
+		Secret:      content.Secret,
+		Histories:   content.Histories,
+		DeleteTime:  item.DeleteTime.UTC(),
+		ExpireTime:  item.ExpireTime.UTC(),
+	}, nil
+}
+
+func FromRecycleItemModel(item *models.RecycleItem) (*RecycleItem, error) {
+	content, err := json.Marshal(&recycleContent{
+		Application: item.Application,
+		Configs:     item.Configs,
+		Secret:      item.Secret,
+		Histories:   item.Histories,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &RecycleItem{
+		ID:         item.ID,
+		Namespace:  item.Namespace,
+		Kind:       item.Kind,
+		Name:       item.Name,
+		Content:    string(content),
+		DeleteTime: item.DeleteTime,
+		ExpireTime: item.ExpireTime,
+	}, nil
+}
@@ -0,0 +1,78 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/json"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+type Rollout struct {
+	ID              int64     `db:"id"`
+	Namespace       string    `db:"namespace"`
+	Name            string    `db:"name"`
+	Version         string    `db:"version"`
+	PreviousVersion string    `db:"previous_version"`
+	Status          string    `db:"status"`
+	Reason          string    `db:"reason"`
+	Strategy        string    `db:"strategy"`
+	CurrentWave     int       `db:"current_wave"`
+	Waves           string    `db:"waves"`
+	CreateTime      time.Time `db:"create_time"`
+	UpdateTime      time.Time `db:"update_time"`
+}
+
+func ToRolloutModel(rollout *Rollout) (*models.Rollout, error) {
+	res := &models.Rollout{
+		Namespace:       rollout.Namespace,
+		Name:            rollout.Name,
+		Version:         rollout.Version,
+		PreviousVersion: rollout.PreviousVersion,
+		Status:          models.RolloutStatus(rollout.Status),
+		Reason:          rollout.Reason,
+		CurrentWave:     rollout.CurrentWave,
+		CreateTime:      rollout.CreateTime.UTC(),
+		UpdateTime:      rollout.UpdateTime.UTC(),
+	}
+	if rollout.Strategy != "" {
+		res.Strategy = new(models.RolloutStrategy)
+		if err := json.Unmarshal([]byte(rollout.Strategy), res.Strategy); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	if rollout.Waves != "" {
+		if err := json.Unmarshal([]byte(rollout.Waves), &res.Waves); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return res, nil
+}
+
+func FromRolloutModel(rollout *models.Rollout) (*Rollout, error) {
+	res := &Rollout{
+		Namespace:       rollout.Namespace,
+		Name:            rollout.Name,
+		Version:         rollout.Version,
+		PreviousVersion: rollout.PreviousVersion,
+		Status:          string(rollout.Status),
+		Reason:          rollout.Reason,
+		CurrentWave:     rollout.CurrentWave,
+	}
+	if rollout.Strategy != nil {
+		strategy, err := json.Marshal(rollout.Strategy)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		res.Strategy = string(strategy)
+	}
+	if rollout.Waves != nil {
+		waves, err := json.Marshal(rollout.Waves)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		res.Waves = string(waves)
+	}
+	return res, nil
+}
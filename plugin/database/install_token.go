@@ -0,0 +1,183 @@
+package database
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/database/entities"
+)
+
+func (d *BaetylCloudDB) GetInstallToken(tx interface{}, namespace, name string) (*models.InstallToken, error) {
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return d.GetInstallTokenTx(transaction, namespace, name)
+}
+
+func (d *BaetylCloudDB) CreateInstallToken(tx interface{}, token *models.InstallToken) error {
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return err
+	}
+	t := entities.FromInstallTokenModel(token)
+	insertSQL := `
+INSERT INTO baetyl_install_token (namespace, name, node_name, single_use, revoked, consume_ip, consume_time, use_count, expire_time)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+	_, err = d.Exec(transaction, insertSQL, t.Namespace, t.Name, t.NodeName, t.SingleUse, t.Revoked,
+		t.ConsumeIP, t.ConsumeTime, t.UseCount, t.ExpireTime)
+	return err
+}
+
+func (d *BaetylCloudDB) ListInstallToken(tx interface{}, namespace, nodeName string) ([]models.InstallToken, error) {
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	selectSQL := `
+SELECT id, namespace, name, node_name, single_use, revoked, consume_ip, consume_time, use_count, expire_time, create_time, update_time
+FROM baetyl_install_token WHERE namespace=? AND node_name=? ORDER BY id DESC
+`
+	var tokens []entities.InstallToken
+	if err = d.Query(transaction, selectSQL, &tokens, namespace, nodeName); err != nil {
+		return nil, err
+	}
+	res := make([]models.InstallToken, 0, len(tokens))
+	for i := range tokens {
+		res = append(res, *entities.ToInstallTokenModel(&tokens[i]))
+	}
+	return res, nil
+}
+
+func (d *BaetylCloudDB) ConsumeInstallToken(tx interface{}, namespace, name, ip string) (bool, error) {
+	var ok bool
+	var err error
+	if tx == nil {
+		err = d.Transact(func(tx *sqlx.Tx) error {
+			ok, err = d.ConsumeInstallTokenTx(tx, namespace, name, ip)
+			return err
+		})
+	} else {
+		transaction, errTx := d.InterfaceToTx(tx)
+		if errTx != nil {
+			return false, errTx
+		}
+		ok, err = d.ConsumeInstallTokenTx(transaction, namespace, name, ip)
+	}
+	return ok, err
+}
+
+func (d *BaetylCloudDB) ListInstallTokenConsume(tx interface{}, namespace, name string) ([]models.InstallTokenConsume, error) {
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	selectSQL := `
+SELECT id, namespace, name, consume_ip, accepted, create_time
+FROM baetyl_install_token_consume WHERE namespace=? AND name=? ORDER BY id
+`
+	var consumes []entities.InstallTokenConsume
+	if err = d.Query(transaction, selectSQL, &consumes, namespace, name); err != nil {
+		return nil, err
+	}
+	res := make([]models.InstallTokenConsume, 0, len(consumes))
+	for i := range consumes {
+		res = append(res, *entities.ToInstallTokenConsumeModel(&consumes[i]))
+	}
+	return res, nil
+}
+
+func (d *BaetylCloudDB) DeleteExpiredInstallTokens(tx interface{}, before time.Time) (int64, error) {
+	var count int64
+	var err error
+	if tx == nil {
+		err = d.Transact(func(tx *sqlx.Tx) error {
+			count, err = d.DeleteExpiredInstallTokensTx(tx, before)
+			return err
+		})
+	} else {
+		transaction, errTx := d.InterfaceToTx(tx)
+		if errTx != nil {
+			return 0, errTx
+		}
+		count, err = d.DeleteExpiredInstallTokensTx(transaction, before)
+	}
+	return count, err
+}
+
+func (d *BaetylCloudDB) RevokeInstallToken(tx interface{}, namespace, name string) error {
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return err
+	}
+	updateSQL := `UPDATE baetyl_install_token SET revoked=1, update_time=CURRENT_TIMESTAMP WHERE namespace=? AND name=?`
+	_, err = d.Exec(transaction, updateSQL, namespace, name)
+	return err
+}
+
+// ConsumeInstallTokenTx the single-use token is consumed only once, every consumption is recorded whether it's accepted or not
+func (d *BaetylCloudDB) ConsumeInstallTokenTx(tx *sqlx.Tx, namespace, name, ip string) (bool, error) {
+	// the condition and the update are in one statement, so the single-use token can't be consumed twice at the same time
+	updateSQL := `
+UPDATE baetyl_install_token SET use_count=use_count+1,
+consume_time=CASE WHEN use_count=0 THEN CURRENT_TIMESTAMP ELSE consume_time END,
+consume_ip=CASE WHEN use_count=0 THEN ? ELSE consume_ip END, update_time=CURRENT_TIMESTAMP
+WHERE namespace=? AND name=? AND revoked=0 AND (single_use=0 OR use_count=0)
+`
+	res, err := d.Exec(tx, updateSQL, ip, namespace, name)
+	if err != nil {
+		return false, err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	accepted := 0
+	if rows > 0 {
+		accepted = 1
+	}
+	insertSQL := `INSERT INTO baetyl_install_token_consume (namespace, name, consume_ip, accepted) VALUES (?, ?, ?, ?)`
+	if _, err = d.Exec(tx, insertSQL, namespace, name, ip, accepted); err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// DeleteExpiredInstallTokensTx deletes the tokens expired before the time with their consumptions
+func (d *BaetylCloudDB) DeleteExpiredInstallTokensTx(tx *sqlx.Tx, before time.Time) (int64, error) {
+	deleteSQL := `
+DELETE FROM baetyl_install_token_consume WHERE EXISTS (SELECT 1 FROM baetyl_install_token t
+WHERE t.namespace=baetyl_install_token_consume.namespace AND t.name=baetyl_install_token_consume.name AND t.expire_time<?)
+`
+	if _, err := d.Exec(tx, deleteSQL, before); err != nil {
+		return 0, err
+	}
+	res, err := d.Exec(tx, `DELETE FROM baetyl_install_token WHERE expire_time<?`, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (d *BaetylCloudDB) GetInstallTokenTx(tx *sqlx.Tx, namespace, name string) (*models.InstallToken, error) {
+	selectSQL := `
+SELECT id, namespace, name, node_name, single_use, revoked, consume_ip, consume_time, use_count, expire_time, create_time, update_time
+FROM baetyl_install_token WHERE namespace=? AND name=? LIMIT 0,1
+`
+	var tokens []entities.InstallToken
+	if err := d.Query(tx, selectSQL, &tokens, namespace, name); err != nil {
+		return nil, err
+	}
+	if len(tokens) > 0 {
+		return entities.ToInstallTokenModel(&tokens[0]), nil
+	}
+	return nil, common.Error(
+		common.ErrResourceNotFound,
+		common.Field("type", "token"),
+		common.Field("name", name),
+		common.Field("namespace", namespace))
+}
@@ -0,0 +1,153 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+var (
+	installTokenTables = []string{
+		`
+CREATE TABLE baetyl_install_token
+(
+	id           integer       PRIMARY KEY AUTOINCREMENT,
+	namespace    varchar(64)   NOT NULL DEFAULT '',
+	name         varchar(64)   NOT NULL DEFAULT '',
+	node_name    varchar(128)  NOT NULL DEFAULT '',
+	single_use   integer       NOT NULL DEFAULT 0,
+	revoked      integer       NOT NULL DEFAULT 0,
+	consume_ip   varchar(64)   NOT NULL DEFAULT '',
+	consume_time timestamp     NOT NULL DEFAULT '2007-01-01 00:00:00',
+	use_count    integer       NOT NULL DEFAULT 0,
+	expire_time  timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	create_time  timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	update_time  timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`,
+		`
+CREATE TABLE baetyl_install_token_consume
+(
+	id           integer       PRIMARY KEY AUTOINCREMENT,
+	namespace    varchar(64)   NOT NULL DEFAULT '',
+	name         varchar(64)   NOT NULL DEFAULT '',
+	consume_ip   varchar(64)   NOT NULL DEFAULT '',
+	accepted     integer       NOT NULL DEFAULT 0,
+	create_time  timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`,
+	}
+)
+
+func (d *BaetylCloudDB) MockCreateInstallTokenTable() {
+	for _, sql := range installTokenTables {
+		_, err := d.Exec(nil, sql)
+		if err != nil {
+			panic(fmt.Sprintf("create install token exception: %s", err.Error()))
+		}
+	}
+}
+
+func TestInstallToken(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateInstallTokenTable()
+
+	ns, node := "default", "n1"
+	expire := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	_, err = db.GetInstallToken(nil, ns, "t1")
+	assert.Error(t, err)
+
+	assert.NoError(t, db.CreateInstallToken(nil, &models.InstallToken{Name: "t1", Namespace: ns, NodeName: node, SingleUse: true, ExpireTime: expire}))
+	assert.NoError(t, db.CreateInstallToken(nil, &models.InstallToken{Name: "t2", Namespace: ns, NodeName: node, ExpireTime: expire}))
+	assert.NoError(t, db.CreateInstallToken(nil, &models.InstallToken{Name: "t3", Namespace: ns, NodeName: "n2", ExpireTime: expire}))
+
+	token, err := db.GetInstallToken(nil, ns, "t1")
+	assert.NoError(t, err)
+	assert.True(t, token.SingleUse)
+	assert.False(t, token.Revoked)
+	assert.Equal(t, node, token.NodeName)
+	assert.Equal(t, expire, token.ExpireTime)
+	assert.True(t, token.ConsumeTime.IsZero())
+
+	// the single-use token is consumed only once, even by the same ip
+	ok, err := db.ConsumeInstallToken(nil, ns, "t1", "10.0.0.1")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = db.ConsumeInstallToken(nil, ns, "t1", "10.0.0.1")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	ok, err = db.ConsumeInstallToken(nil, ns, "t1", "10.0.0.2")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	token, err = db.GetInstallToken(nil, ns, "t1")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", token.ConsumeIP)
+	assert.False(t, token.ConsumeTime.IsZero())
+	assert.Equal(t, 1, token.UseCount)
+
+	// every consumption is recorded
+	consumes, err := db.ListInstallTokenConsume(nil, ns, "t1")
+	assert.NoError(t, err)
+	assert.Len(t, consumes, 3)
+	assert.Equal(t, "10.0.0.1", consumes[0].ConsumeIP)
+	assert.True(t, consumes[0].Accepted)
+	assert.Equal(t, "10.0.0.1", consumes[1].ConsumeIP)
+	assert.False(t, consumes[1].Accepted)
+	assert.Equal(t, "10.0.0.2", consumes[2].ConsumeIP)
+	assert.False(t, consumes[2].Accepted)
+
+	// the reusable token keeps the first ip
+	ok, err = db.ConsumeInstallToken(nil, ns, "t2", "10.0.0.1")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = db.ConsumeInstallToken(nil, ns, "t2", "10.0.0.2")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	token, err = db.GetInstallToken(nil, ns, "t2")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", token.ConsumeIP)
+	assert.Equal(t, 2, token.UseCount)
+	consumes, err = db.ListInstallTokenConsume(nil, ns, "t2")
+	assert.NoError(t, err)
+	assert.Len(t, consumes, 2)
+	assert.Equal(t, "10.0.0.2", consumes[1].ConsumeIP)
+	assert.True(t, consumes[1].Accepted)
+
+	// the revoked token can't be consumed
+	assert.NoError(t, db.RevokeInstallToken(nil, ns, "t2"))
+	ok, err = db.ConsumeInstallToken(nil, ns, "t2", "10.0.0.1")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	ok, err = db.ConsumeInstallToken(nil, ns, "unknown", "10.0.0.1")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	tokens, err := db.ListInstallToken(nil, ns, node)
+	assert.NoError(t, err)
+	assert.Len(t, tokens, 2)
+	assert.Equal(t, "t2", tokens[0].Name)
+	assert.True(t, tokens[0].Revoked)
+	assert.Equal(t, "t1", tokens[1].Name)
+
+	// the expired tokens are purged with their consumptions
+	count, err := db.DeleteExpiredInstallTokens(nil, expire.Add(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+	_, err = db.GetInstallToken(nil, ns, "t1")
+	assert.Error(t, err)
+	consumes, err = db.ListInstallTokenConsume(nil, ns, "t1")
+	assert.NoError(t, err)
+	assert.Len(t, consumes, 0)
+	count, err = db.DeleteExpiredInstallTokens(nil, expire.Add(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+}
@@ -70,6 +70,11 @@ func (d *BaetylCloudDB) DeleteNamespace(namespace *models.Namespace) error {
 	if err != nil {
 		return err
 	}
+	if d.kms != nil {
+		if err = d.DeleteDataKeysTx(nil, namespace.Name); err != nil {
+			return err
+		}
+	}
 
 	return err
 }
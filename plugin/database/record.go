@@ -25,6 +25,10 @@ func (d *BaetylCloudDB) ListRecord(batchName, ns string, filter *models.Filter)
 	return d.ListRecordTx(nil, batchName, ns, filter)
 }
 
+func (d *BaetylCloudDB) ListRecordByBatch(batchName, ns string) ([]models.Record, error) {
+	return d.ListRecordByBatchTx(nil, batchName, ns)
+}
+
 func (d *BaetylCloudDB) CreateRecord(records []models.Record) (sql.Result, error) {
 	return d.CreateRecordTx(nil, records)
 }
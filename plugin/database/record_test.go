@@ -88,7 +88,7 @@ func TestRecord(t *testing.T) {
 	assert.NoError(t, err)
 	checkRecord(t, record, &resRecordList[0])
 
-	records, err := db.ListRecordByBatchTx(nil, record.BatchName, record.Namespace)
+	records, err := db.ListRecordByBatch(record.BatchName, record.Namespace)
 	assert.NoError(t, err)
 	assert.Equal(t, 1, len(records))
 
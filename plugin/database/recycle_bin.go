@@ -0,0 +1,170 @@
+package database
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/database/entities"
+)
+
+func (d *BaetylCloudDB) CreateRecycleItem(tx interface{}, item *models.RecycleItem) (*models.RecycleItem, error) {
+	var res *models.RecycleItem
+	var err error
+	if tx == nil {
+		err = d.Transact(func(tx *sqlx.Tx) error {
+			res, err = d.CreateRecycleItemTx(tx, item)
+			return err
+		})
+	} else {
+		transaction, errTx := d.InterfaceToTx(tx)
+		if errTx != nil {
+			return nil, errTx
+		}
+		res, err = d.CreateRecycleItemTx(transaction, item)
+	}
+	return res, err
+}
+
+func (d *BaetylCloudDB) GetRecycleItem(tx interface{}, namespace string, id int64) (*models.RecycleItem, error) {
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return d.GetRecycleItemTx(transaction, namespace, id)
+}
+
+func (d *BaetylCloudDB) ListRecycleItem(tx interface{}, namespace string, listOptions *models.RecycleListOptions) (*models.RecycleItemList, error) {
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	items, total, err := d.ListRecycleItemTx(transaction, namespace, listOptions)
+	if err != nil {
+		return nil, err
+	}
+	return &models.RecycleItemList{
+		Total:              total,
+		RecycleListOptions: listOptions,
+		Items:              items,
+	}, nil
+}
+
+func (d *BaetylCloudDB) DeleteRecycleItem(tx interface{}, namespace string, id int64) error {
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return err
+	}
+	deleteSQL := `DELETE FROM baetyl_recycle_bin WHERE namespace=? AND id=?`
+	_, err = d.Exec(transaction, deleteSQL, namespace, id)
+	return err
+}
+
+func (d *BaetylCloudDB) DeleteExpiredRecycleItems(tx interface{}, before time.Time) (int64, error) {
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return 0, err
+	}
+	deleteSQL := `DELETE FROM baetyl_recycle_bin WHERE expire_time<?`
+	res, err := d.Exec(transaction, deleteSQL, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (d *BaetylCloudDB) CreateRecycleItemTx(tx *sqlx.Tx, item *models.RecycleItem) (*models.RecycleItem, error) {
+	r, err := entities.FromRecycleItemModel(item)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// the deleted secret is encrypted as the ones in use
+	if item.Secret != nil {
+		aad := dataAAD(recycleContentAAD, item.Namespace, r.Name)
+		if r.Content, err = d.encryptData(tx, item.Namespace, aad, r.Content); err != nil {
+			return nil, err
+		}
+	}
+	insertSQL := `
+INSERT INTO baetyl_recycle_bin (namespace, kind, name, content, delete_time, expire_time)
+VALUES (?, ?, ?, ?, ?, ?)
+`
+	res, err := d.Exec(tx, insertSQL, r.Namespace, r.Kind, r.Name, r.Content, r.DeleteTime, r.ExpireTime)
+	if err != nil {
+		return nil, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return d.GetRecycleItemTx(tx, item.Namespace, id)
+}
+
+func (d *BaetylCloudDB) GetRecycleItemTx(tx *sqlx.Tx, namespace string, id int64) (*models.RecycleItem, error) {
+	selectSQL := `
+SELECT id, namespace, kind, name, content, delete_time, expire_time
+FROM baetyl_recycle_bin WHERE namespace=? AND id=?
+`
+	var items []entities.RecycleItem
+	if err := d.Query(tx, selectSQL, &items, namespace, id); err != nil {
+		return nil, err
+	}
+	if len(items) > 0 {
+		content, err := d.decryptData(tx, namespace, dataAAD(recycleContentAAD, namespace, items[0].Name), items[0].Content)
+		if err != nil {
+			return nil, err
+		}
+		items[0].Content = content
+		return entities.ToRecycleItemModel(&items[0])
+	}
+	return nil, common.Error(
+		common.ErrResourceNotFound,
+		common.Field("type", "recycleItem"),
+		common.Field("name", strconv.FormatInt(id, 10)),
+		common.Field("namespace", namespace))
+}
+
+func (d *BaetylCloudDB) ListRecycleItemTx(tx *sqlx.Tx, namespace string, listOptions *models.RecycleListOptions) ([]models.RecycleItem, int, error) {
+	where := ` WHERE namespace=? AND name LIKE ?`
+	args := []interface{}{namespace, listOptions.GetFuzzyName()}
+	if listOptions.Kind != "" {
+		where += ` AND kind=?`
+		args = append(args, listOptions.Kind)
+	}
+	countSQL := `SELECT count(id) AS count FROM baetyl_recycle_bin` + where
+	var counts []struct {
+		Count int `db:"count"`
+	}
+	if err := d.Query(tx, countSQL, &counts, args...); err != nil {
+		return nil, 0, err
+	}
+	selectSQL := `
+SELECT id, namespace, kind, name, content, delete_time, expire_time
+FROM baetyl_recycle_bin` + where + ` ORDER BY id DESC `
+	if listOptions.GetLimitNumber() > 0 {
+		selectSQL = selectSQL + "LIMIT ?,?"
+		args = append(args, listOptions.GetLimitOffset(), listOptions.GetLimitNumber())
+	}
+	var items []entities.RecycleItem
+	if err := d.Query(tx, selectSQL, &items, args...); err != nil {
+		return nil, 0, err
+	}
+	res := make([]models.RecycleItem, 0)
+	for i := range items {
+		content, err := d.decryptData(tx, namespace, dataAAD(recycleContentAAD, namespace, items[i].Name), items[i].Content)
+		if err != nil {
+			return nil, 0, err
+		}
+		items[i].Content = content
+		item, err := entities.ToRecycleItemModel(&items[i])
+		if err != nil {
+			return nil, 0, err
+		}
+		res = append(res, *item)
+	}
+	return res, counts[0].Count, nil
+}
@@ -0,0 +1,114 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+var (
+	recycleBinTables = []string{
+		`
+CREATE TABLE baetyl_recycle_bin
+(
+	id          integer       PRIMARY KEY AUTOINCREMENT,
+	namespace   varchar(64)   NOT NULL DEFAULT '',
+	kind        varchar(32)   NOT NULL DEFAULT '',
+	name        varchar(128)  NOT NULL DEFAULT '',
+	content     text          NULL,
+	delete_time timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	expire_time timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`,
+	}
+)
+
+func (d *BaetylCloudDB) MockCreateRecycleBinTable() {
+	for _, sql := range recycleBinTables {
+		_, err := d.Exec(nil, sql)
+		if err != nil {
+			panic(fmt.Sprintf("create recycle bin exception: %s", err.Error()))
+		}
+	}
+}
+
+func TestRecycleBin(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateRecycleBinTable()
+
+	ns := "default"
+	now := time.Now().UTC().Truncate(time.Second)
+	_, err = db.GetRecycleItem(nil, ns, 1)
+	assert.Error(t, err)
+
+	app, err := db.CreateRecycleItem(nil, &models.RecycleItem{
+		Namespace:   ns,
+		Kind:        "app",
+		Name:        "app1",
+		Application: &specV1.Application{Namespace: ns, Name: "app1", Selector: "a=b"},
+		Configs:     []specV1.Configuration{{Namespace: ns, Name: "baetyl-function-program-config-x"}},
+		Histories:   []models.AppHistory{{Namespace: ns, Name: "app1", Revision: 1, Version: "1"}},
+		DeleteTime:  now,
+		ExpireTime:  now.Add(time.Hour),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "a=b", app.Application.Selector)
+	assert.Len(t, app.Configs, 1)
+	assert.Len(t, app.Histories, 1)
+	assert.Nil(t, app.Secret)
+	assert.Equal(t, now.Add(time.Hour), app.ExpireTime)
+
+	tx, err := db.BeginTx()
+	assert.NoError(t, err)
+	secret, err := db.CreateRecycleItem(tx, &models.RecycleItem{
+		Namespace:  ns,
+		Kind:       "registry",
+		Name:       "reg1",
+		Secret:     &specV1.Secret{Namespace: ns, Name: "reg1", Data: map[string][]byte{"password": []byte("p")}},
+		DeleteTime: now,
+		ExpireTime: now.Add(-time.Hour),
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+	assert.Equal(t, []byte("p"), secret.Secret.Data["password"])
+
+	res, err := db.GetRecycleItem(nil, ns, app.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "app1", res.Name)
+	_, err = db.GetRecycleItem(nil, "other", app.ID)
+	assert.Error(t, err)
+
+	list, err := db.ListRecycleItem(nil, ns, &models.RecycleListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, list.Total)
+	assert.Equal(t, "reg1", list.Items[0].Name)
+
+	list, err = db.ListRecycleItem(nil, ns, &models.RecycleListOptions{Kind: "app", Filter: models.Filter{PageNo: 1, PageSize: 10}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, list.Total)
+	assert.Equal(t, "app1", list.Items[0].Name)
+
+	list, err = db.ListRecycleItem(nil, ns, &models.RecycleListOptions{Filter: models.Filter{Name: "reg"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, list.Total)
+
+	count, err := db.DeleteExpiredRecycleItems(nil, now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	err = db.DeleteRecycleItem(nil, ns, app.ID)
+	assert.NoError(t, err)
+	list, err = db.ListRecycleItem(nil, ns, &models.RecycleListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, list.Total)
+}
@@ -0,0 +1,120 @@
+package database
+
+import (
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/database/entities"
+)
+
+func (d *BaetylCloudDB) GetRollout(tx interface{}, namespace, name string) (*models.Rollout, error) {
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	return d.GetRolloutTx(transaction, namespace, name)
+}
+
+func (d *BaetylCloudDB) SaveRollout(tx interface{}, rollout *models.Rollout) (*models.Rollout, error) {
+	var res *models.Rollout
+	var err error
+	if tx == nil {
+		err = d.Transact(func(tx *sqlx.Tx) error {
+			res, err = d.SaveRolloutTx(tx, rollout)
+			return err
+		})
+	} else {
+		transaction, errTx := d.InterfaceToTx(tx)
+		if errTx != nil {
+			return nil, errTx
+		}
+		res, err = d.SaveRolloutTx(transaction, rollout)
+	}
+	return res, err
+}
+
+func (d *BaetylCloudDB) DeleteRollout(tx interface{}, namespace, name string) error {
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return err
+	}
+	deleteSQL := `DELETE FROM baetyl_app_rollout WHERE namespace=? AND name=?`
+	_, err = d.Exec(transaction, deleteSQL, namespace, name)
+	return err
+}
+
+func (d *BaetylCloudDB) ListRolloutByStatus(tx interface{}, status models.RolloutStatus) ([]models.Rollout, error) {
+	transaction, err := d.InterfaceToTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	selectSQL := `
+SELECT id, namespace, name, version, previous_version, status, reason, strategy, current_wave, waves, create_time, update_time
+FROM baetyl_app_rollout WHERE status=?
+`
+	var rollouts []entities.Rollout
+	if err = d.Query(transaction, selectSQL, &rollouts, string(status)); err != nil {
+		return nil, err
+	}
+	res := make([]models.Rollout, 0)
+	for i := range rollouts {
+		r, err := entities.ToRolloutModel(&rollouts[i])
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *r)
+	}
+	return res, nil
+}
+
+func (d *BaetylCloudDB) GetRolloutTx(tx *sqlx.Tx, namespace, name string) (*models.Rollout, error) {
+	selectSQL := `
+SELECT id, namespace, name, version, previous_version, status, reason, strategy, current_wave, waves, create_time, update_time
+FROM baetyl_app_rollout WHERE namespace=? AND name=?
+`
+	var rollouts []entities.Rollout
+	if err := d.Query(tx, selectSQL, &rollouts, namespace, name); err != nil {
+		return nil, err
+	}
+	if len(rollouts) > 0 {
+		return entities.ToRolloutModel(&rollouts[0])
+	}
+	return nil, common.Error(
+		common.ErrResourceNotFound,
+		common.Field("type", "rollout"),
+		common.Field("name", name),
+		common.Field("namespace", namespace))
+}
+
+func (d *BaetylCloudDB) SaveRolloutTx(tx *sqlx.Tx, rollout *models.Rollout) (*models.Rollout, error) {
+	r, err := entities.FromRolloutModel(rollout)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	countSQL := `SELECT count(id) AS count FROM baetyl_app_rollout WHERE namespace=? AND name=?`
+	var counts []struct {
+		Count int `db:"count"`
+	}
+	if err = d.Query(tx, countSQL, &counts, r.Namespace, r.Name); err != nil {
+		return nil, err
+	}
+	if counts[0].Count == 0 {
+		insertSQL := `
+INSERT INTO baetyl_app_rollout (namespace, name, version, previous_version, status, reason, strategy, current_wave, waves)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+		_, err = d.Exec(tx, insertSQL, r.Namespace, r.Name, r.Version, r.PreviousVersion, r.Status, r.Reason, r.Strategy, r.CurrentWave, r.Waves)
+	} else {
+		updateSQL := `
+UPDATE baetyl_app_rollout SET version=?, previous_version=?, status=?, reason=?, strategy=?, current_wave=?, waves=?, update_time=CURRENT_TIMESTAMP
+WHERE namespace=? AND name=?
+`
+		_, err = d.Exec(tx, updateSQL, r.Version, r.PreviousVersion, r.Status, r.Reason, r.Strategy, r.CurrentWave, r.Waves, r.Namespace, r.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return d.GetRolloutTx(tx, rollout.Namespace, rollout.Name)
+}
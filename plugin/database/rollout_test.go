@@ -0,0 +1,96 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+var (
+	rolloutTables = []string{
+		`
+CREATE TABLE baetyl_app_rollout
+(
+	id               integer       PRIMARY KEY AUTOINCREMENT,
+	namespace        varchar(64)   NOT NULL DEFAULT '',
+	name             varchar(128)  NOT NULL DEFAULT '',
+	version          varchar(36)   NOT NULL DEFAULT '',
+	previous_version varchar(36)   NOT NULL DEFAULT '',
+	status           varchar(32)   NOT NULL DEFAULT '',
+	reason           varchar(1024) NOT NULL DEFAULT '',
+	strategy         varchar(1024) NOT NULL DEFAULT '',
+	current_wave     integer       NOT NULL DEFAULT 0,
+	waves            text          NULL,
+	create_time      timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	update_time      timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`,
+	}
+)
+
+func (d *BaetylCloudDB) MockCreateRolloutTable() {
+	for _, sql := range rolloutTables {
+		_, err := d.Exec(nil, sql)
+		if err != nil {
+			panic(fmt.Sprintf("create rollout exception: %s", err.Error()))
+		}
+	}
+}
+
+func TestRollout(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateRolloutTable()
+
+	ns, name := "default", "app"
+	_, err = db.GetRollout(nil, ns, name)
+	assert.Error(t, err)
+
+	rollout := &models.Rollout{
+		Namespace: ns,
+		Name:      name,
+		Strategy:  &models.RolloutStrategy{BatchSize: 2, CanaryCount: 1},
+	}
+	res, err := db.SaveRollout(nil, rollout)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, res.Strategy.BatchSize)
+	assert.Equal(t, models.RolloutStatus(""), res.Status)
+	assert.Nil(t, res.Waves)
+
+	tx, err := db.BeginTx()
+	assert.NoError(t, err)
+	rollout.Version = "2"
+	rollout.PreviousVersion = "1"
+	rollout.Status = models.RolloutProgressing
+	rollout.Waves = []models.RolloutWave{
+		{Canary: true, Nodes: []string{"n1"}, Status: models.RolloutProgressing},
+		{Nodes: []string{"n2", "n3"}, Status: models.RolloutPending},
+	}
+	res, err = db.SaveRollout(tx, rollout)
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+	assert.Equal(t, "2", res.Version)
+	assert.Len(t, res.Waves, 2)
+	assert.True(t, res.Waves[0].Canary)
+
+	list, err := db.ListRolloutByStatus(nil, models.RolloutProgressing)
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Equal(t, []string{"n2", "n3"}, list[0].Waves[1].Nodes)
+
+	list, err = db.ListRolloutByStatus(nil, models.RolloutFailed)
+	assert.NoError(t, err)
+	assert.Len(t, list, 0)
+
+	err = db.DeleteRollout(nil, ns, name)
+	assert.NoError(t, err)
+	_, err = db.GetRollout(nil, ns, name)
+	assert.Error(t, err)
+}
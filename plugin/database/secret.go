@@ -100,8 +100,12 @@ FROM baetyl_secret WHERE namespace=? AND name=?
 		return nil, err
 	}
 	if len(secrets) > 0 {
+		data, err := d.decryptData(tx, namespace, dataAAD(secretDataAAD, namespace, name), secrets[0].Data)
+		if err != nil {
+			return nil, err
+		}
+		secrets[0].Data = data
 		return entities.ToSecretModel(&secrets[0])
-
 	}
 	return nil, common.Error(
 		common.ErrResourceNotFound,
@@ -118,6 +122,9 @@ VALUES (?, ?, ?, ?, ?, ?, ?)
 	if err != nil {
 		return nil, err
 	}
+	if se.Data, err = d.encryptData(tx, namespace, dataAAD(secretDataAAD, namespace, se.Name), se.Data); err != nil {
+		return nil, err
+	}
 	return d.Exec(tx, insertSQL, se.Namespace, se.Name, se.Labels, se.Data, se.Version, se.System, se.Description)
 }
 
@@ -131,6 +138,9 @@ WHERE namespace=? AND name=?
 	if err != nil {
 		return nil, err
 	}
+	if se.Data, err = d.encryptData(tx, namespace, dataAAD(secretDataAAD, namespace, se.Name), se.Data); err != nil {
+		return nil, err
+	}
 	return d.Exec(tx, updateSQL, se.Version, se.Description, se.Labels, se.Data, se.Namespace, se.Name)
 }
 
@@ -153,6 +163,11 @@ FROM baetyl_secret WHERE namespace=? AND name LIKE ? ORDER BY create_time DESC
 		if ok, err := utils.IsLabelMatch(listOptions.LabelSelector, labels); err != nil || !ok {
 			continue
 		}
+		data, err := d.decryptData(nil, namespace, dataAAD(secretDataAAD, namespace, se.Name), se.Data)
+		if err != nil {
+			return nil, 0, err
+		}
+		se.Data = data
 		s, err := entities.ToSecretModel(&se)
 		if err != nil {
 			return nil, 0, errors.Trace(err)
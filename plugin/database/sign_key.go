@@ -0,0 +1,35 @@
+package database
+
+import (
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+func (d DB) CreateSignKey(key plugin.SignKey) error {
+	insertSQL := `
+INSERT INTO baetyl_sign_key (key_id, algorithm, private_key, create_time) 
+VALUES (?,?,?,?)
+`
+	_, err := d.db.Exec(insertSQL, key.KeyID, key.Algorithm, key.PrivateKey, key.CreateTime)
+	return err
+}
+
+func (d DB) ListSignKey(algorithm string) ([]plugin.SignKey, error) {
+	selectSQL := `
+SELECT key_id, algorithm, private_key, create_time 
+FROM baetyl_sign_key 
+WHERE algorithm=? ORDER BY id DESC
+`
+	var keys []plugin.SignKey
+	if err := d.db.Select(&keys, selectSQL, algorithm); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (d DB) DeleteSignKey(algorithm, keyID string) error {
+	deleteSQL := `
+DELETE FROM baetyl_sign_key WHERE algorithm=? AND key_id=?
+`
+	_, err := d.db.Exec(deleteSQL, algorithm, keyID)
+	return err
+}
@@ -0,0 +1,83 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+var (
+	signKeyTables = []string{
+		`
+CREATE TABLE baetyl_sign_key
+(
+    id          integer       PRIMARY KEY AUTOINCREMENT,
+    key_id      varchar(64)   NOT NULL DEFAULT '',
+    algorithm   varchar(64)   NOT NULL DEFAULT '',
+    private_key text          DEFAULT '',
+    create_time timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (algorithm, key_id)
+);
+`,
+	}
+)
+
+func (d *DB) MockCreateSignKeyTable() {
+	for _, sql := range signKeyTables {
+		_, err := d.db.Exec(sql)
+		if err != nil {
+			panic(fmt.Sprintf("create table exception: %s", err.Error()))
+		}
+	}
+}
+
+func TestSignKey(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateSignKeyTable()
+
+	now := time.Unix(1000, 0).UTC()
+	keys, err := db.ListSignKey("rsa")
+	assert.NoError(t, err)
+	assert.Len(t, keys, 0)
+
+	err = db.CreateSignKey(plugin.SignKey{KeyID: "k1", Algorithm: "rsa", PrivateKey: "priv1", CreateTime: now})
+	assert.NoError(t, err)
+	err = db.CreateSignKey(plugin.SignKey{KeyID: "k2", Algorithm: "rsa", PrivateKey: "priv2", CreateTime: now})
+	assert.NoError(t, err)
+	err = db.CreateSignKey(plugin.SignKey{KeyID: "k3", Algorithm: "ed25519", PrivateKey: "priv3", CreateTime: now})
+	assert.NoError(t, err)
+	err = db.CreateSignKey(plugin.SignKey{KeyID: "k1", Algorithm: "rsa", PrivateKey: "priv", CreateTime: now})
+	assert.Error(t, err)
+
+	keys, err = db.ListSignKey("rsa")
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+	assert.Equal(t, "k2", keys[0].KeyID)
+	assert.Equal(t, "priv2", keys[0].PrivateKey)
+	assert.Equal(t, "rsa", keys[0].Algorithm)
+	assert.Equal(t, now.Unix(), keys[0].CreateTime.Unix())
+	assert.Equal(t, "k1", keys[1].KeyID)
+
+	err = db.DeleteSignKey("ed25519", "k1")
+	assert.NoError(t, err)
+	keys, err = db.ListSignKey("rsa")
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+
+	err = db.DeleteSignKey("rsa", "k1")
+	assert.NoError(t, err)
+	keys, err = db.ListSignKey("rsa")
+	assert.NoError(t, err)
+	assert.Len(t, keys, 1)
+	assert.Equal(t, "k2", keys[0].KeyID)
+}
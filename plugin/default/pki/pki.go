@@ -2,6 +2,7 @@ package pki
 
 import (
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/base64"
 	"errors"
 	"io/ioutil"
@@ -19,6 +20,8 @@ const (
 	TypeIssuingSubCert = "IssuingSubCertificate"
 	// Root cert ID
 	RootCertId = "baetyl-cloud-system-cert-root"
+	// NamespaceCertIdPrefix the prefix of the ids of the intermediate cas of namespaces, which are issued by the root cert
+	NamespaceCertIdPrefix = "baetyl-cloud-system-cert-ns-"
 )
 
 var (
@@ -87,7 +90,7 @@ func (p *defaultPkiClient) CreateRootCert(info *x509.CertificateRequest, parentI
 		return "", err
 	}
 	certId := common.UUIDPrune()
-	err = p.saveCert(certId, cert, []byte(""))
+	err = p.saveCert(certId, parentId, cert, []byte(""))
 	if err != nil {
 		return "", err
 	}
@@ -139,6 +142,47 @@ func (p *defaultPkiClient) DeleteClientCert(certId string) error {
 	return p.sto.DeleteCert(certId)
 }
 
+// namespace cert
+func (p *defaultPkiClient) GetNamespaceCertID(namespace string) (string, error) {
+	certId := NamespaceCertIdPrefix + namespace
+	if _, err := p.sto.GetCert(certId); err == nil {
+		return certId, nil
+	}
+	parent, err := p.getRootCA(RootCertId)
+	if err != nil {
+		return "", err
+	}
+	info := &x509.CertificateRequest{
+		Subject: pkix.Name{
+			Country:            []string{"CN"},
+			Organization:       []string{"Linux Foundation Edge"},
+			OrganizationalUnit: []string{"BAETYL"},
+			CommonName:         namespace + common.NamespaceCASuffix,
+		},
+	}
+	cert, err := p.pkiClient.CreateRootCert(info, (int)(p.cfg.PKI.RootDuration.Hours()/24), parent)
+	if err != nil {
+		return "", err
+	}
+	if err = p.saveCert(certId, RootCertId, cert, []byte("")); err != nil {
+		// the intermediate ca may be created by another instance at the same time
+		if _, e := p.sto.GetCert(certId); e == nil {
+			return certId, nil
+		}
+		return "", err
+	}
+	return certId, nil
+}
+
+func (p *defaultPkiClient) GetNamespaceCert(namespace string) ([]byte, error) {
+	return p.getCert(NamespaceCertIdPrefix + namespace)
+}
+
+func (p *defaultPkiClient) RevokeNamespaceCert(namespace string) error {
+	// the private key is deleted with the cert, and the issued certs are rejected since their issuer isn't found in storage any more
+	return p.sto.DeleteCert(NamespaceCertIdPrefix + namespace)
+}
+
 func (p *defaultPkiClient) Close() error {
 	return p.sto.Close()
 }
@@ -158,7 +202,7 @@ func (p *defaultPkiClient) checkRootCA() error {
 	if err != nil {
 		return err
 	}
-	return p.saveCert(RootCertId, &pki.CertPem{
+	return p.saveCert(RootCertId, "", &pki.CertPem{
 		Crt: crt,
 		Key: key,
 	}, []byte(""))
@@ -174,7 +218,7 @@ func (p *defaultPkiClient) createSubCert(csr []byte, rootId string) (string, err
 		return "", err
 	}
 	certId := common.UUIDPrune()
-	err = p.saveCert(certId, &pki.CertPem{
+	err = p.saveCert(certId, rootId, &pki.CertPem{
 		Crt: crt,
 		Key: []byte(""),
 	}, csr)
@@ -192,7 +236,7 @@ func (p *defaultPkiClient) getCert(certId string) ([]byte, error) {
 	return base64.StdEncoding.DecodeString(cert.Content)
 }
 
-func (p *defaultPkiClient) saveCert(certId string, cert *pki.CertPem, csr []byte) error {
+func (p *defaultPkiClient) saveCert(certId, parentId string, cert *pki.CertPem, csr []byte) error {
 	crtInfo, err := pki.ParseCertificates(cert.Crt)
 	if err != nil {
 		return err
@@ -206,6 +250,7 @@ func (p *defaultPkiClient) saveCert(certId string, cert *pki.CertPem, csr []byte
 	}
 	certView := plugin.Cert{
 		CertId:     certId,
+		ParentId:   parentId,
 		Type:       tp,
 		CommonName: crtInfo[0].Subject.CommonName,
 		Content:    base64.StdEncoding.EncodeToString(cert.Crt),
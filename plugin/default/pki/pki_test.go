@@ -273,3 +273,51 @@ func TestDefaultPkiClient_Close(t *testing.T) {
 	err := p.Close()
 	assert.NoError(t, err)
 }
+
+func TestDefaultPkiClient_NamespaceCert(t *testing.T) {
+	p, s := genDefaultPkiClient(t)
+	certId := NamespaceCertIdPrefix + "default"
+
+	// the intermediate ca is created at the first time
+	var ca plugin.Cert
+	s.EXPECT().GetCert(certId).Return(nil, os.ErrNotExist).Times(1)
+	s.EXPECT().GetCert(RootCertId).Return(genRootCAView(), nil).Times(1)
+	s.EXPECT().CreateCert(gomock.Any()).DoAndReturn(func(cert plugin.Cert) error {
+		ca = cert
+		return nil
+	}).Times(1)
+	res, err := p.GetNamespaceCertID("default")
+	assert.NoError(t, err)
+	assert.Equal(t, certId, res)
+	assert.Equal(t, RootCertId, ca.ParentId)
+	assert.Equal(t, TypeIssuingCA, ca.Type)
+	assert.Equal(t, "default"+common.NamespaceCASuffix, ca.CommonName)
+
+	// the node cert is issued by the intermediate ca
+	s.EXPECT().GetCert(certId).Return(&ca, nil).Times(1)
+	s.EXPECT().CreateCert(gomock.Any()).DoAndReturn(func(cert plugin.Cert) error {
+		assert.Equal(t, certId, cert.ParentId)
+		return nil
+	}).Times(1)
+	csr, err := base64.StdEncoding.DecodeString(base64CSR)
+	assert.NoError(t, err)
+	_, err = p.CreateClientCert(csr, certId)
+	assert.NoError(t, err)
+
+	s.EXPECT().GetCert(certId).Return(&ca, nil).Times(1)
+	res, err = p.GetNamespaceCertID("default")
+	assert.NoError(t, err)
+	assert.Equal(t, certId, res)
+
+	s.EXPECT().GetCert(certId).Return(&ca, nil).Times(1)
+	crt, err := p.GetNamespaceCert("default")
+	assert.NoError(t, err)
+	assert.Equal(t, ca.Content, base64.StdEncoding.EncodeToString(crt))
+
+	s.EXPECT().DeleteCert(certId).Return(nil).Times(1)
+	assert.NoError(t, p.RevokeNamespaceCert("default"))
+
+	s.EXPECT().GetCert(certId).Return(nil, os.ErrNotExist).Times(1)
+	_, err = p.GetNamespaceCert("default")
+	assert.Error(t, err)
+}
@@ -1,6 +1,15 @@
 package sign
 
+import "time"
+
 type CloudConfig struct {
 	DefaultSign struct {
+		// the keys are not persistent and can't be rotated if persistent is empty, the legacy signature is used
+		Persistent string `yaml:"persistent" json:"persistent"`
+		// the kms which encrypts the persistent keys
+		KMS string `yaml:"kms" json:"kms" default:"localkms"`
+		// the legacy signatures without key id are rejected once the legacy is retired
+		RetireLegacy    bool          `yaml:"retireLegacy" json:"retireLegacy"`
+		RefreshInterval time.Duration `yaml:"refreshInterval" json:"refreshInterval" default:"1m"`
 	} `yaml:"defaultsign" json:"defaultsign"`
 }
@@ -2,15 +2,31 @@ package sign
 
 import (
 	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/sign/keyring"
 )
 
+const hmacKeySize = 32
+
+var ErrPlugin = errors.New("plugin type conversion error")
+
 type defaultSign struct {
+	*keyring.Keyring
 	cfg CloudConfig
 }
 
+// hmacAlgorithm signs with HMAC-SHA256 of the rotated keys
+type hmacAlgorithm struct{}
+
+// plainAlgorithm the legacy signature which is the meta itself
+type plainAlgorithm struct{}
+
 func init() {
 	plugin.RegisterFactory("defaultsign", New)
 }
@@ -21,20 +37,74 @@ func New() (plugin.Plugin, error) {
 	if err := common.LoadConfig(&cfg); err != nil {
 		return nil, err
 	}
+	sto, kms, err := keyring.LoadStorage(cfg.DefaultSign.Persistent, cfg.DefaultSign.KMS)
+	if err != nil {
+		return nil, err
+	}
+	k := keyring.NewKeyring(hmacAlgorithm{}, sto, cfg.DefaultSign.RefreshInterval, plainAlgorithm{}, nil).WithKMS(kms)
+	// the plain signature can be forged, so it should be retired once the keys are rotated
+	if cfg.DefaultSign.RetireLegacy {
+		if err = k.RetireLegacy(); err != nil {
+			return nil, err
+		}
+	}
 	return &defaultSign{
-		cfg: cfg,
+		Keyring: k,
+		cfg:     cfg,
 	}, nil
 }
 
-func (d *defaultSign) Signature(meta []byte) ([]byte, error) {
-	return meta, nil
+// Close Close
+func (d *defaultSign) Close() error {
+	return nil
 }
 
-func (d *defaultSign) Verify(meta, sign []byte) bool {
-	return bytes.Equal(meta, sign)
+func (hmacAlgorithm) Name() string {
+	return "hmac-sha256"
 }
 
-// Close Close
-func (d *defaultSign) Close() error {
-	return nil
+func (hmacAlgorithm) GenerateKey() ([]byte, error) {
+	key := make([]byte, hmacKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (hmacAlgorithm) ParseKey(key []byte) (interface{}, error) {
+	if len(key) == 0 {
+		return nil, errors.New("the hmac key is empty")
+	}
+	return key, nil
+}
+
+func (hmacAlgorithm) Sign(key interface{}, meta []byte) ([]byte, error) {
+	h := hmac.New(sha256.New, key.([]byte))
+	h.Write(meta)
+	return h.Sum(nil), nil
+}
+
+func (a hmacAlgorithm) Verify(key interface{}, meta, sign []byte) bool {
+	expect, _ := a.Sign(key, meta)
+	return hmac.Equal(expect, sign)
+}
+
+func (plainAlgorithm) Name() string {
+	return "plain"
+}
+
+func (plainAlgorithm) GenerateKey() ([]byte, error) {
+	return nil, errors.New("the plain algorithm has no key")
+}
+
+func (plainAlgorithm) ParseKey(_ []byte) (interface{}, error) {
+	return nil, nil
+}
+
+func (plainAlgorithm) Sign(_ interface{}, meta []byte) ([]byte, error) {
+	return meta, nil
+}
+
+func (plainAlgorithm) Verify(_ interface{}, meta, sign []byte) bool {
+	return bytes.Equal(meta, sign)
 }
@@ -5,38 +5,86 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"strings"
 	"testing"
 
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/config"
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
 	"github.com/baetyl/baetyl-cloud/v2/plugin"
 )
 
 const (
 	confData = `
 defaultsign:
+  persistent: "{{.PERSISTENT}}"
+  kms: "{{.KMS}}"
 `
 )
 
+var (
+	persistent = common.RandString(9)
+	kms        = common.RandString(9)
+	mockSto    *mockPlugin.MockSignKeyStorage
+	mockKMS    *mockPlugin.MockKMS
+)
+
+func init() {
+	plugin.RegisterFactory(persistent, func() (plugin.Plugin, error) {
+		return mockSto, nil
+	})
+	plugin.RegisterFactory(kms, func() (plugin.Plugin, error) {
+		return mockKMS, nil
+	})
+}
+
 func setUp() *config.CloudConfig {
 	conf := &config.CloudConfig{}
 	conf.Plugin.Sign = "defaultsign"
 	return conf
 }
 
-func genConfig(workspace string) error {
+func genConfig(workspace, conf string) error {
+	return genConfigWith(workspace, conf, persistent, kms)
+}
+
+// genConfigWith generates the config with the plugins, which are created once, so the tests which
+// need new mocks register their own
+func genConfigWith(workspace, conf, persistent, kms string) error {
 	if err := os.MkdirAll(workspace, 0755); err != nil {
 		return err
 	}
-	if err := ioutil.WriteFile(path.Join(workspace, "cloud.yml"), []byte(confData), 0755); err != nil {
+	conf = strings.Replace(conf, "{{.PERSISTENT}}", persistent, -1)
+	conf = strings.Replace(conf, "{{.KMS}}", kms, -1)
+	if err := ioutil.WriteFile(path.Join(workspace, "cloud.yml"), []byte(conf), 0755); err != nil {
 		return err
 	}
 	return nil
 }
 
 func TestDefaultSign_Sign_Verify(t *testing.T) {
-	err := genConfig("etc/baetyl")
+	mockSto = mockPlugin.NewMockSignKeyStorage(gomock.NewController(t))
+	mockKMS = mockPlugin.NewMockKMS(gomock.NewController(t))
+	// the keys are stored encrypted
+	mockKMS.EXPECT().Encrypt(gomock.Any()).DoAndReturn(func(data []byte) (string, []byte, error) {
+		return "kek", append([]byte("enc"), data...), nil
+	}).AnyTimes()
+	mockKMS.EXPECT().Decrypt("kek", gomock.Any()).DoAndReturn(func(_ string, data []byte) ([]byte, error) {
+		return data[3:], nil
+	}).AnyTimes()
+	var keys []plugin.SignKey
+	mockSto.EXPECT().ListSignKey("hmac-sha256").DoAndReturn(func(_ string) ([]plugin.SignKey, error) {
+		return keys, nil
+	}).AnyTimes()
+	mockSto.EXPECT().CreateSignKey(gomock.Any()).DoAndReturn(func(key plugin.SignKey) error {
+		keys = append([]plugin.SignKey{key}, keys...)
+		return nil
+	}).AnyTimes()
+
+	err := genConfig("etc/baetyl", confData)
 	assert.NoError(t, err)
 	defer os.RemoveAll(path.Dir("etc/baetyl"))
 
@@ -53,4 +101,77 @@ func TestDefaultSign_Sign_Verify(t *testing.T) {
 
 	inc := auth.Verify(meta, sign)
 	assert.Equal(t, true, inc)
+
+	// the new signatures are signed by hmac with key id after the key is rotated
+	key, err := auth.RotateKey()
+	assert.NoError(t, err)
+	assert.Equal(t, "hmac-sha256", key.Algorithm)
+	sign2, err := auth.Signature(meta)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(sign2), "k:"+key.KeyID+":"))
+	assert.True(t, strings.HasPrefix(keys[0].PrivateKey, "kms:kek:"))
+	assert.True(t, auth.Verify(meta, sign2))
+	assert.False(t, auth.Verify([]byte("other"), sign2))
+	assert.True(t, auth.Verify(meta, sign))
+}
+
+func TestDefaultSign_RetireLegacy(t *testing.T) {
+	mockSto := mockPlugin.NewMockSignKeyStorage(gomock.NewController(t))
+	mockKMS := mockPlugin.NewMockKMS(gomock.NewController(t))
+	stoName, kmsName := common.RandString(9), common.RandString(9)
+	plugin.RegisterFactory(stoName, func() (plugin.Plugin, error) {
+		return mockSto, nil
+	})
+	plugin.RegisterFactory(kmsName, func() (plugin.Plugin, error) {
+		return mockKMS, nil
+	})
+	var keys []plugin.SignKey
+	mockSto.EXPECT().ListSignKey("hmac-sha256").DoAndReturn(func(_ string) ([]plugin.SignKey, error) {
+		return keys, nil
+	}).AnyTimes()
+	mockSto.EXPECT().CreateSignKey(gomock.Any()).DoAndReturn(func(key plugin.SignKey) error {
+		keys = append([]plugin.SignKey{key}, keys...)
+		return nil
+	}).Times(1)
+	mockKMS.EXPECT().Encrypt(gomock.Any()).Return("kek", []byte("key"), nil).Times(1)
+	mockKMS.EXPECT().Decrypt("kek", []byte("key")).Return([]byte("key"), nil).AnyTimes()
+
+	err := genConfigWith("etc/baetyl", confData+"  retireLegacy: true\n", stoName, kmsName)
+	assert.NoError(t, err)
+	defer os.RemoveAll(path.Dir("etc/baetyl"))
+
+	p, err := New()
+	assert.NoError(t, err)
+	auth := p.(plugin.Sign)
+
+	// the plain signature is rejected
+	meta := []byte("test")
+	assert.False(t, auth.Verify(meta, meta))
+	sign, err := auth.Signature(meta)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(sign), "k:"+keys[0].KeyID+":"))
+	assert.True(t, auth.Verify(meta, sign))
+}
+
+func TestDefaultSign_NotPersistent(t *testing.T) {
+	err := genConfig("etc/baetyl", "defaultsign:\n  refreshInterval: 1m\n")
+	assert.NoError(t, err)
+	defer os.RemoveAll(path.Dir("etc/baetyl"))
+
+	p, err := New()
+	assert.NoError(t, err)
+	auth := p.(plugin.Sign)
+
+	meta := []byte("test")
+	sign, err := auth.Signature(meta)
+	assert.NoError(t, err)
+	assert.Equal(t, meta, sign)
+	assert.True(t, auth.Verify(meta, sign))
+	_, err = auth.RotateKey()
+	assert.Error(t, err)
+
+	err = genConfig("etc/baetyl", "defaultsign:\n  retireLegacy: true\n")
+	assert.NoError(t, err)
+	_, err = New()
+	assert.Error(t, err)
 }
@@ -0,0 +1,8 @@
+package filesecret
+
+type CloudConfig struct {
+	FileSecret struct {
+		// the value of reference is read from the file <dir>/<namespace>/<reference>
+		Dir string `yaml:"dir" json:"dir" default:"etc/baetyl/secrets"`
+	} `yaml:"filesecret" json:"filesecret"`
+}
@@ -0,0 +1,60 @@
+package filesecret
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+type fileSecret struct {
+	cfg CloudConfig
+}
+
+func init() {
+	plugin.RegisterFactory("filesecret", New)
+}
+
+// New New
+func New() (plugin.Plugin, error) {
+	var cfg CloudConfig
+	if err := common.LoadConfig(&cfg); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &fileSecret{cfg: cfg}, nil
+}
+
+func (f *fileSecret) GetSecretValue(namespace, ref string) ([]byte, error) {
+	file, err := f.path(namespace, ref)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.Errorf("the reference (%s) is not found", ref)
+		}
+		return nil, errors.Trace(err)
+	}
+	return data, nil
+}
+
+// path returns the file of reference, the reference can't escape from the directory of namespace
+func (f *fileSecret) path(namespace, ref string) (string, error) {
+	if namespace == "" || strings.ContainsAny(namespace, `/\`) || namespace == "." || namespace == ".." {
+		return "", errors.Errorf("the namespace (%s) is invalid", namespace)
+	}
+	if ref == "" || filepath.IsAbs(ref) || filepath.Clean(ref) != ref || ref == "." || strings.HasPrefix(ref, "..") {
+		return "", errors.Errorf("the reference (%s) is invalid", ref)
+	}
+	return filepath.Join(f.cfg.FileSecret.Dir, namespace, ref), nil
+}
+
+func (f *fileSecret) Close() error {
+	return nil
+}
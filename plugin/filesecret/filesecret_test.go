@@ -0,0 +1,39 @@
+package filesecret
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileSecret(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filesecret")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "default", "db"), 0700))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "default", "db", "password"), []byte("secret"), 0600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "token"), []byte("root"), 0600))
+
+	f := &fileSecret{}
+	f.cfg.FileSecret.Dir = dir
+	data, err := f.GetSecretValue("default", "db/password")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("secret"), data)
+
+	_, err = f.GetSecretValue("default", "db/user")
+	assert.EqualError(t, err, "the reference (db/user) is not found")
+	_, err = f.GetSecretValue("other", "db/password")
+	assert.Error(t, err)
+
+	// the reference can't escape from the directory of namespace
+	for _, ref := range []string{"", ".", "../token", "db/../../token", "/token", "./db/password"} {
+		_, err = f.GetSecretValue("default", ref)
+		assert.EqualError(t, err, "the reference ("+ref+") is invalid")
+	}
+	_, err = f.GetSecretValue("..", "token")
+	assert.Error(t, err)
+	assert.NoError(t, f.Close())
+}
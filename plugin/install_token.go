@@ -0,0 +1,27 @@
+package plugin
+
+import (
+	"io"
+	"time"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+//go:generate mockgen -destination=../mock/plugin/install_token.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin InstallToken
+
+// InstallToken stores the tokens of node install commands
+type InstallToken interface {
+	GetInstallToken(tx interface{}, namespace, name string) (*models.InstallToken, error)
+	CreateInstallToken(tx interface{}, token *models.InstallToken) error
+	// ListInstallToken lists the tokens of node, the latest first
+	ListInstallToken(tx interface{}, namespace, nodeName string) ([]models.InstallToken, error)
+	// ConsumeInstallToken records the use of token by the ip, false is returned if the token is revoked,
+	// or the single-use token has been consumed, every consumption is recorded for audit
+	ConsumeInstallToken(tx interface{}, namespace, name, ip string) (bool, error)
+	// ListInstallTokenConsume lists the consumptions of token, the earliest first
+	ListInstallTokenConsume(tx interface{}, namespace, name string) ([]models.InstallTokenConsume, error)
+	RevokeInstallToken(tx interface{}, namespace, name string) error
+	// DeleteExpiredInstallTokens deletes the tokens of all namespaces expired before the time with their consumptions
+	DeleteExpiredInstallTokens(tx interface{}, before time.Time) (int64, error)
+	io.Closer
+}
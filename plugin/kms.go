@@ -0,0 +1,26 @@
+package plugin
+
+import (
+	"io"
+	"time"
+)
+
+//go:generate mockgen -destination=../mock/plugin/kms.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin KMS
+
+// KMS manages the key encryption keys, which wrap the data keys used to encrypt the secrets at rest
+type KMS interface {
+	// Encrypt encrypts the plaintext with the current key, the id of the key is returned for decryption
+	Encrypt(plaintext []byte) (keyID string, ciphertext []byte, err error)
+	// Decrypt decrypts the ciphertext with the key of the id
+	Decrypt(keyID string, ciphertext []byte) ([]byte, error)
+
+	io.Closer
+}
+
+// SecretEncryption is implemented by the secret storage which encrypts the data of secrets at rest
+type SecretEncryption interface {
+	// ReencryptSecrets re-encrypts the secrets of namespace with the current key, all namespaces if the namespace is empty
+	ReencryptSecrets(namespace string) (int, error)
+	// PurgeDataKeys deletes the retired data keys superseded before the time and referenced by no data
+	PurgeDataKeys(before time.Time) (int, error)
+}
@@ -5,6 +5,8 @@ type CloudConfig struct {
 	Kube struct {
 		OutCluster bool   `yaml:"outCluster" json:"outCluster"`
 		ConfigPath string `yaml:"configPath" json:"configPath" default:"etc/baetyl/kubeconfig.yml"`
+		// the namespace which the certificates of pki are stored in as secrets
+		PKINamespace string `yaml:"pkiNamespace" json:"pkiNamespace" default:"baetyl-cloud"`
 		// TODO Remove from plugin
 		AES struct {
 			Key string `yaml:"key" json:"key" default:"baetyl2020202020"`
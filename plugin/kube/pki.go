@@ -0,0 +1,142 @@
+package kube
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/utils"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+// the certificates are stored as secrets, labeled with the hash of parent id since the id may exceed the max length of label value
+const (
+	certSecretPrefix = "baetyl-cert-"
+	labelCert        = "baetyl-cert"
+	labelCertParent  = "baetyl-cert-parent"
+
+	certKeyId          = "certId"
+	certKeyParentId    = "parentId"
+	certKeyType        = "type"
+	certKeyCommonName  = "commonName"
+	certKeyCsr         = "csr"
+	certKeyContent     = "content"
+	certKeyPrivateKey  = "privateKey"
+	certKeyDescription = "description"
+	certKeyNotBefore   = "notBefore"
+	certKeyNotAfter    = "notAfter"
+)
+
+func certSecretName(certId string) string {
+	return certSecretPrefix + certId
+}
+
+func certParentLabel(parentId string) string {
+	sum := sha256.Sum256([]byte(parentId))
+	return hex.EncodeToString(sum[:])[:32]
+}
+
+func toCertModel(secret *v1.Secret) *plugin.Cert {
+	res := &plugin.Cert{
+		CertId:      string(secret.Data[certKeyId]),
+		ParentId:    string(secret.Data[certKeyParentId]),
+		Type:        string(secret.Data[certKeyType]),
+		CommonName:  string(secret.Data[certKeyCommonName]),
+		Csr:         string(secret.Data[certKeyCsr]),
+		Content:     string(secret.Data[certKeyContent]),
+		PrivateKey:  string(secret.Data[certKeyPrivateKey]),
+		Description: string(secret.Data[certKeyDescription]),
+	}
+	res.NotBefore, _ = time.Parse(time.RFC3339, string(secret.Data[certKeyNotBefore]))
+	res.NotAfter, _ = time.Parse(time.RFC3339, string(secret.Data[certKeyNotAfter]))
+	return res
+}
+
+func fromCertModel(cert *plugin.Cert) *v1.Secret {
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: certSecretName(cert.CertId),
+			Labels: map[string]string{
+				labelCert:       "true",
+				labelCertParent: certParentLabel(cert.ParentId),
+			},
+		},
+		Type: v1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			certKeyId:          []byte(cert.CertId),
+			certKeyParentId:    []byte(cert.ParentId),
+			certKeyType:        []byte(cert.Type),
+			certKeyCommonName:  []byte(cert.CommonName),
+			certKeyCsr:         []byte(cert.Csr),
+			certKeyContent:     []byte(cert.Content),
+			certKeyPrivateKey:  []byte(cert.PrivateKey),
+			certKeyDescription: []byte(cert.Description),
+			certKeyNotBefore:   []byte(cert.NotBefore.UTC().Format(time.RFC3339)),
+			certKeyNotAfter:    []byte(cert.NotAfter.UTC().Format(time.RFC3339)),
+		},
+	}
+}
+
+func (c *client) CreateCert(cert plugin.Cert) error {
+	defer utils.Trace(c.log.Debug, "CreateCert")()
+	_, err := c.coreV1.Secrets(c.cfg.Kube.PKINamespace).Create(c.ctx, fromCertModel(&cert), metav1.CreateOptions{})
+	return err
+}
+
+func (c *client) DeleteCert(certId string) error {
+	defer utils.Trace(c.log.Debug, "DeleteCert")()
+	err := c.coreV1.Secrets(c.cfg.Kube.PKINamespace).Delete(c.ctx, certSecretName(certId), metav1.DeleteOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func (c *client) UpdateCert(cert plugin.Cert) error {
+	defer utils.Trace(c.log.Debug, "UpdateCert")()
+	secrets := c.coreV1.Secrets(c.cfg.Kube.PKINamespace)
+	old, err := secrets.Get(c.ctx, certSecretName(cert.CertId), metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	secret := fromCertModel(&cert)
+	secret.ResourceVersion = old.ResourceVersion
+	_, err = secrets.Update(c.ctx, secret, metav1.UpdateOptions{})
+	return err
+}
+
+func (c *client) GetCert(certId string) (*plugin.Cert, error) {
+	defer utils.Trace(c.log.Debug, "GetCert")()
+	secret, err := c.coreV1.Secrets(c.cfg.Kube.PKINamespace).Get(c.ctx, certSecretName(certId), metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, os.ErrNotExist
+		}
+		return nil, err
+	}
+	return toCertModel(secret), nil
+}
+
+func (c *client) CountCertByParentId(parentId string) (int, error) {
+	defer utils.Trace(c.log.Debug, "CountCertByParentId")()
+	selector := metav1.FormatLabelSelector(&metav1.LabelSelector{MatchLabels: map[string]string{
+		labelCert:       "true",
+		labelCertParent: certParentLabel(parentId),
+	}})
+	list, err := c.coreV1.Secrets(c.cfg.Kube.PKINamespace).List(c.ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for i := range list.Items {
+		if string(list.Items[i].Data[certKeyParentId]) == parentId {
+			count++
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,86 @@
+package kube
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+func initPKIClient() *client {
+	fc := fake.NewSimpleClientset()
+	c := &client{
+		coreV1: fc.CoreV1(),
+		log:    log.With(log.Any("plugin", "kube")),
+	}
+	c.cfg.Kube.PKINamespace = "baetyl-cloud"
+	return c
+}
+
+func TestCert(t *testing.T) {
+	c := initPKIClient()
+	var _ plugin.PKIStorage = c
+
+	now := time.Now().UTC().Truncate(time.Second)
+	root := plugin.Cert{
+		CertId:     "baetyl-cloud-system-cert-root",
+		Type:       "IssuingCA",
+		CommonName: "root.ca",
+		Content:    "Y29udGVudA==",
+		PrivateKey: "a2V5",
+		NotBefore:  now,
+		NotAfter:   now.AddDate(10, 0, 0),
+	}
+	assert.NoError(t, c.CreateCert(root))
+	// the cert id is unique
+	assert.Error(t, c.CreateCert(root))
+
+	res, err := c.GetCert(root.CertId)
+	assert.NoError(t, err)
+	assert.Equal(t, root, *res)
+
+	_, err = c.GetCert("unknown")
+	assert.Equal(t, os.ErrNotExist, err)
+
+	// the parent id longer than the max length of label value
+	ns := plugin.Cert{
+		CertId:   "baetyl-cloud-system-cert-ns-a-namespace-with-a-very-long-name-exceeding-the-label-limit",
+		ParentId: root.CertId,
+		Type:     "IssuingCA",
+	}
+	assert.NoError(t, c.CreateCert(ns))
+	for _, id := range []string{"c1", "c2"} {
+		assert.NoError(t, c.CreateCert(plugin.Cert{CertId: id, ParentId: ns.CertId, Type: "IssuingSubCertificate", Csr: "Y3Ny"}))
+	}
+
+	count, err := c.CountCertByParentId(root.CertId)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	count, err = c.CountCertByParentId(ns.CertId)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	count, err = c.CountCertByParentId("c1")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	root.Description = "updated"
+	assert.NoError(t, c.UpdateCert(root))
+	res, err = c.GetCert(root.CertId)
+	assert.NoError(t, err)
+	assert.Equal(t, "updated", res.Description)
+	assert.Error(t, c.UpdateCert(plugin.Cert{CertId: "unknown"}))
+
+	assert.NoError(t, c.DeleteCert("c1"))
+	_, err = c.GetCert("c1")
+	assert.Equal(t, os.ErrNotExist, err)
+	// deleting the cert not found is ignored
+	assert.NoError(t, c.DeleteCert("c1"))
+	count, err = c.CountCertByParentId(ns.CertId)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
@@ -5,10 +5,12 @@ import (
 )
 
 type CloudConfig struct {
-	HTTPLink HTTPLinkConfig `yaml:"httplink" json:"httpLink" default:"{\"port\":\":9005\",\"readTimeout\":30000000000,\"writeTimeout\":30000000000,\"shutdownTime\":3000000000,\"commonName\":\"common-name\"}"`
+	HTTPLink HTTPLinkConfig `yaml:"httplink" json:"httpLink" default:"{\"port\":\":9005\",\"readTimeout\":30000000000,\"writeTimeout\":30000000000,\"shutdownTime\":3000000000,\"commonName\":\"common-name\",\"pki\":\"defaultpki\"}"`
 }
 
 type HTTPLinkConfig struct {
 	config.Server `yaml:",inline" json:",inline"`
 	CommonName    string `yaml:"commonName" json:"commonName" default:"common-name"`
+	// the pki plugin whose intermediate cas of namespaces verify the node certificates
+	PKI string `yaml:"pki" json:"pki" default:"defaultpki"`
 }
@@ -65,7 +65,11 @@ func NewHTTPLink() (plugin.Plugin, error) {
 		server.HeaderCommonName = cfg.HTTPLink.CommonName
 		router.Use(server.ExtractNodeCommonNameFromHeader)
 	} else {
-		router.Use(server.ExtractNodeCommonNameFromCert)
+		p, err := plugin.GetPlugin(cfg.HTTPLink.PKI)
+		if err != nil {
+			return nil, err
+		}
+		router.Use(server.NewNodeCertHandler(p.(plugin.PKI)).ExtractNodeCommonNameFromCert)
 	}
 
 	link := &httpLink{
@@ -0,0 +1,14 @@
+package localkms
+
+type CloudConfig struct {
+	LocalKMS struct {
+		// the key file is reloaded once modified, add a new key and set it as current to rotate
+		KeyFile string `yaml:"keyFile" json:"keyFile" default:"etc/baetyl/kms/keys.yml"`
+	} `yaml:"localkms" json:"localkms"`
+}
+
+// KeyFile the key encryption keys, the keys are base64 encoded and 16, 24 or 32 bytes long
+type KeyFile struct {
+	Current string            `yaml:"current" json:"current" binding:"nonzero"`
+	Keys    map[string]string `yaml:"keys" json:"keys"`
+}
@@ -0,0 +1,125 @@
+package localkms
+
+import (
+	"encoding/base64"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/baetyl/baetyl-go/v2/utils"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/common/util"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+type localKMS struct {
+	cfg     CloudConfig
+	current string
+	keys    map[string][]byte
+	modTime time.Time
+	mu      sync.Mutex
+	log     *log.Logger
+}
+
+func init() {
+	plugin.RegisterFactory("localkms", New)
+}
+
+// New New
+func New() (plugin.Plugin, error) {
+	var cfg CloudConfig
+	if err := common.LoadConfig(&cfg); err != nil {
+		return nil, errors.Trace(err)
+	}
+	k := &localKMS{
+		cfg: cfg,
+		log: log.L().With(log.Any("plugin", "localkms")),
+	}
+	if err := k.reload(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+func (k *localKMS) Encrypt(plaintext []byte) (string, []byte, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if err := k.reload(); err != nil {
+		return "", nil, err
+	}
+	ciphertext, err := util.SealGCM(plaintext, k.keys[k.current])
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	return k.current, ciphertext, nil
+}
+
+func (k *localKMS) Decrypt(keyID string, ciphertext []byte) ([]byte, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if err := k.reload(); err != nil {
+		return nil, err
+	}
+	key, ok := k.keys[keyID]
+	if !ok {
+		return nil, errors.Errorf("key (%s) is not found in key file", keyID)
+	}
+	plaintext, err := util.OpenGCM(ciphertext, key)
+	return plaintext, errors.Trace(err)
+}
+
+// reload loads the key file if it's modified, the loaded keys are kept if the key file is invalid
+func (k *localKMS) reload() error {
+	info, err := os.Stat(k.cfg.LocalKMS.KeyFile)
+	if err != nil {
+		if k.keys != nil {
+			k.log.Warn("failed to stat key file, the loaded keys are used", log.Error(err))
+			return nil
+		}
+		return errors.Trace(err)
+	}
+	if k.keys != nil && info.ModTime().Equal(k.modTime) {
+		return nil
+	}
+	current, keys, err := loadKeyFile(k.cfg.LocalKMS.KeyFile)
+	if err != nil {
+		if k.keys != nil {
+			k.log.Warn("failed to reload key file, the loaded keys are used", log.Error(err))
+			return nil
+		}
+		return err
+	}
+	k.current, k.keys, k.modTime = current, keys, info.ModTime()
+	k.log.Info("key file is loaded", log.Any("current", current))
+	return nil
+}
+
+func loadKeyFile(path string) (string, map[string][]byte, error) {
+	var kf KeyFile
+	if err := utils.LoadYAML(path, &kf); err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	keys := map[string][]byte{}
+	for id, v := range kf.Keys {
+		key, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return "", nil, errors.Errorf("key (%s) is not base64 encoded: %s", id, err.Error())
+		}
+		if l := len(key); l != 16 && l != 24 && l != 32 {
+			return "", nil, errors.Errorf("key (%s) should be 16, 24 or 32 bytes long", id)
+		}
+		keys[id] = key
+	}
+	if _, ok := keys[kf.Current]; !ok {
+		return "", nil, errors.Errorf("current key (%s) is not found in key file", kf.Current)
+	}
+	return kf.Current, keys, nil
+}
+
+// Close Close
+func (k *localKMS) Close() error {
+	return nil
+}
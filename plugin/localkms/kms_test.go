@@ -0,0 +1,73 @@
+package localkms
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testKeyFile1 = `
+current: k1
+keys:
+  k1: MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=
+`
+	testKeyFile2 = `
+current: k2
+keys:
+  k1: MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=
+  k2: YWJjZGVmMDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODk=
+`
+)
+
+func TestLocalKMS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "localkms")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := path.Join(dir, "keys.yml")
+
+	k := &localKMS{log: log.L().With(log.Any("plugin", "localkms"))}
+	k.cfg.LocalKMS.KeyFile = file
+	assert.Error(t, k.reload())
+
+	assert.NoError(t, ioutil.WriteFile(file, []byte("current: k0\nkeys:\n  k0: YWJj\n"), 0600))
+	assert.Error(t, k.reload())
+
+	assert.NoError(t, ioutil.WriteFile(file, []byte(testKeyFile1), 0600))
+	id, c1, err := k.Encrypt([]byte("data key"))
+	assert.NoError(t, err)
+	assert.Equal(t, "k1", id)
+
+	// rotate
+	assert.NoError(t, ioutil.WriteFile(file, []byte(testKeyFile2), 0600))
+	later := time.Now().Add(time.Minute)
+	assert.NoError(t, os.Chtimes(file, later, later))
+	id, c2, err := k.Encrypt([]byte("data key"))
+	assert.NoError(t, err)
+	assert.Equal(t, "k2", id)
+
+	p, err := k.Decrypt("k1", c1)
+	assert.NoError(t, err)
+	assert.Equal(t, "data key", string(p))
+	p, err = k.Decrypt("k2", c2)
+	assert.NoError(t, err)
+	assert.Equal(t, "data key", string(p))
+	_, err = k.Decrypt("k1", c2)
+	assert.Error(t, err)
+	_, err = k.Decrypt("k3", c2)
+	assert.Error(t, err)
+
+	// the loaded keys are kept if the key file is broken
+	assert.NoError(t, ioutil.WriteFile(file, []byte("current: k3\n"), 0600))
+	later = later.Add(time.Minute)
+	assert.NoError(t, os.Chtimes(file, later, later))
+	id, _, err = k.Encrypt([]byte("data key"))
+	assert.NoError(t, err)
+	assert.Equal(t, "k2", id)
+	assert.NoError(t, k.Close())
+}
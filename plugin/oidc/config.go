@@ -0,0 +1,29 @@
+package oidc
+
+import "time"
+
+type CloudConfig struct {
+	OIDC struct {
+		Issuer string `yaml:"issuer" json:"issuer" binding:"nonzero"`
+		// the jwks url is discovered from the openid configuration of issuer if empty
+		JWKSURL string `yaml:"jwksUrl" json:"jwksUrl"`
+		// the aud claim of tokens must contain the audience, which is usually the client id
+		Audience string `yaml:"audience" json:"audience" binding:"nonzero"`
+		Claims   Claims `yaml:"claims" json:"claims"`
+		// the namespace of the users whose tokens have no namespace claim, the tokens are rejected if empty
+		DefaultNamespace string `yaml:"defaultNamespace" json:"defaultNamespace"`
+		// the keys are refreshed periodically, and at most once in minRefreshInterval if the key of token is unknown
+		RefreshInterval    time.Duration `yaml:"refreshInterval" json:"refreshInterval" default:"10m"`
+		MinRefreshInterval time.Duration `yaml:"minRefreshInterval" json:"minRefreshInterval" default:"10s"`
+		// the allowed clock skew when checking the exp and nbf claims
+		Leeway time.Duration `yaml:"leeway" json:"leeway" default:"1m"`
+	} `yaml:"oidc" json:"oidc"`
+}
+
+// Claims the names of claims mapped to the user info and namespace
+type Claims struct {
+	UserID    string `yaml:"userId" json:"userId" default:"sub"`
+	UserName  string `yaml:"userName" json:"userName" default:"preferred_username"`
+	Namespace string `yaml:"namespace" json:"namespace" default:"namespace"`
+	Roles     string `yaml:"roles" json:"roles" default:"roles"`
+}
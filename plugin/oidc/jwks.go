@@ -0,0 +1,237 @@
+package oidc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/http"
+	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/baetyl/baetyl-go/v2/log"
+	"golang.org/x/sync/singleflight"
+)
+
+const discoveryPath = "/.well-known/openid-configuration"
+
+type discovery struct {
+	Issuer  string `json:"issuer"`
+	JWKSURI string `json:"jwks_uri"`
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jwkSet struct {
+	Keys []jwk `json:"keys"`
+}
+
+// keySet caches the public keys of issuer, the keys are refreshed once expired or the key of token is unknown,
+// the cached keys are kept if the refresh fails. The keys are fetched without holding the lock, and the concurrent
+// refreshes share one fetch
+type keySet struct {
+	issuer      string
+	url         string
+	refresh     time.Duration
+	minRefresh  time.Duration
+	keys        map[string]crypto.PublicKey
+	refreshTime time.Time
+	cli         *http.Client
+	mu          sync.Mutex
+	loading     singleflight.Group
+	log         *log.Logger
+}
+
+func newKeySet(issuer, url string, refresh, minRefresh time.Duration) *keySet {
+	return &keySet{
+		issuer:     strings.TrimSuffix(issuer, "/"),
+		url:        url,
+		refresh:    refresh,
+		minRefresh: minRefresh,
+		cli:        http.NewClient(http.NewClientOptions()),
+		log:        log.L().With(log.Any("plugin", "oidc")),
+	}
+}
+
+// get returns the keys matching kid, all keys are returned if kid is empty
+func (s *keySet) get(kid string) ([]crypto.PublicKey, error) {
+	s.mu.Lock()
+	expired := s.keys == nil || time.Since(s.refreshTime) >= s.refresh
+	s.mu.Unlock()
+	if expired {
+		if err := s.load(); err != nil {
+			return nil, err
+		}
+	}
+	s.mu.Lock()
+	res := s.match(kid)
+	reload := len(res) == 0 && time.Since(s.refreshTime) >= s.minRefresh
+	s.mu.Unlock()
+	if reload {
+		if err := s.load(); err != nil {
+			return nil, err
+		}
+		s.mu.Lock()
+		res = s.match(kid)
+		s.mu.Unlock()
+	}
+	if len(res) == 0 {
+		return nil, errors.Errorf("the key (%s) of token is unknown", kid)
+	}
+	return res, nil
+}
+
+func (s *keySet) match(kid string) []crypto.PublicKey {
+	var res []crypto.PublicKey
+	if kid != "" {
+		if key, ok := s.keys[kid]; ok {
+			res = append(res, key)
+		}
+		return res
+	}
+	for _, key := range s.keys {
+		res = append(res, key)
+	}
+	return res
+}
+
+// load fetches the keys, the callers at the same time wait for the same fetch
+func (s *keySet) load() error {
+	_, err, _ := s.loading.Do("keys", func() (interface{}, error) {
+		keys, err := s.fetch()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.refreshTime = time.Now()
+		if err != nil {
+			if s.keys != nil {
+				s.log.Warn("failed to refresh the keys of issuer, the cached keys are used", log.Error(err))
+				return nil, nil
+			}
+			return nil, err
+		}
+		s.keys = keys
+		return nil, nil
+	})
+	return err
+}
+
+func (s *keySet) fetch() (map[string]crypto.PublicKey, error) {
+	if s.url == "" {
+		data, err := s.cli.GetJSON(s.issuer + discoveryPath)
+		if err != nil {
+			return nil, errors.Errorf("failed to discover the openid configuration of issuer: %s", err.Error())
+		}
+		var d discovery
+		if err = json.Unmarshal(data, &d); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if strings.TrimSuffix(d.Issuer, "/") != s.issuer || d.JWKSURI == "" {
+			return nil, errors.Errorf("the openid configuration of issuer (%s) is invalid", s.issuer)
+		}
+		s.url = d.JWKSURI
+	}
+	data, err := s.cli.GetJSON(s.url)
+	if err != nil {
+		return nil, errors.Errorf("failed to get the keys of issuer: %s", err.Error())
+	}
+	var set jwkSet
+	if err = json.Unmarshal(data, &set); err != nil {
+		return nil, errors.Trace(err)
+	}
+	keys := map[string]crypto.PublicKey{}
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			s.log.Warn("ignore the invalid key of issuer", log.Any("kid", k.Kid), log.Error(err))
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	return keys, nil
+}
+
+func (k *jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("the rsa exponent is too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.Errorf("the curve (%s) is unsupported", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("the point isn't on the curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, errors.Errorf("the curve (%s) is unsupported", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("the size of ed25519 key is invalid")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, errors.Errorf("the key type (%s) is unsupported", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(data) == 0 {
+		return nil, errors.New("the key parameter is empty")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,256 @@
+package oidc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/json"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+const (
+	headerAuthorization = "Authorization"
+	bearerPrefix        = "Bearer "
+	// RoleTypeOIDC the type of the roles mapped from the roles claim
+	RoleTypeOIDC = "oidc"
+)
+
+var (
+	// the curves of the ecdsa algorithms
+	ecdsaCurveBits = map[string]int{"ES256": 256, "ES384": 384, "ES512": 521}
+
+	ErrNoBearerToken = errors.New("no bearer token in the authorization header")
+	ErrInvalidToken  = errors.New("the token is malformed")
+)
+
+// oidcAuth authenticates the requests with the JWT bearer tokens issued by the OIDC issuer,
+// the user info and namespace are mapped from the claims of token
+type oidcAuth struct {
+	cfg  CloudConfig
+	keys *keySet
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+func init() {
+	plugin.RegisterFactory("oidc", New)
+}
+
+// New New
+func New() (plugin.Plugin, error) {
+	var cfg CloudConfig
+	if err := common.LoadConfig(&cfg); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &oidcAuth{
+		cfg:  cfg,
+		keys: newKeySet(cfg.OIDC.Issuer, cfg.OIDC.JWKSURL, cfg.OIDC.RefreshInterval, cfg.OIDC.MinRefreshInterval),
+	}, nil
+}
+
+func (o *oidcAuth) Authenticate(c *common.Context) error {
+	auth := c.Request.Header.Get(headerAuthorization)
+	if len(auth) <= len(bearerPrefix) || !strings.EqualFold(auth[:len(bearerPrefix)], bearerPrefix) {
+		return ErrNoBearerToken
+	}
+	claims, err := o.verify(strings.TrimSpace(auth[len(bearerPrefix):]))
+	if err != nil {
+		return err
+	}
+	user, ns, err := o.mapClaims(claims)
+	if err != nil {
+		return err
+	}
+	c.SetNamespace(ns)
+	c.SetUserInfo(*user)
+	return nil
+}
+
+func (o *oidcAuth) AuthAndVerify(c *common.Context, pr *plugin.PermissionRequest) error {
+	return o.Authenticate(c)
+}
+
+func (o *oidcAuth) Verify(c *common.Context, pr *plugin.PermissionRequest) error {
+	return nil
+}
+
+// Close Close
+func (o *oidcAuth) Close() error {
+	return nil
+}
+
+// verify verifies the signature and the registered claims of token, returns the claims
+func (o *oidcAuth) verify(token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+	data, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var h header
+	if err = json.Unmarshal(data, &h); err != nil {
+		return nil, ErrInvalidToken
+	}
+	sign, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	keys, err := o.keys.get(h.Kid)
+	if err != nil {
+		return nil, err
+	}
+	signed := []byte(parts[0] + "." + parts[1])
+	verified := false
+	for _, key := range keys {
+		ok, err := verifySignature(h.Alg, key, signed, sign)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, errors.New("the signature of token is invalid")
+	}
+
+	data, err = base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	claims := map[string]interface{}{}
+	if err = json.Unmarshal(data, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if err = o.checkClaims(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (o *oidcAuth) checkClaims(claims map[string]interface{}) error {
+	if iss, _ := claims["iss"].(string); strings.TrimSuffix(iss, "/") != strings.TrimSuffix(o.cfg.OIDC.Issuer, "/") {
+		return errors.Errorf("the issuer (%s) of token is unexpected", iss)
+	}
+	if !containsAudience(claims["aud"], o.cfg.OIDC.Audience) {
+		return errors.New("the audience of token is unexpected")
+	}
+	now := time.Now()
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return errors.New("the token has no expiration")
+	}
+	if now.After(time.Unix(int64(exp), 0).Add(o.cfg.OIDC.Leeway)) {
+		return errors.New("the token is expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(o.cfg.OIDC.Leeway).Before(time.Unix(int64(nbf), 0)) {
+		return errors.New("the token isn't valid yet")
+	}
+	return nil
+}
+
+func (o *oidcAuth) mapClaims(claims map[string]interface{}) (*common.UserInfo, string, error) {
+	cfg := o.cfg.OIDC.Claims
+	id, _ := claims[cfg.UserID].(string)
+	if id == "" {
+		return nil, "", errors.Errorf("the token has no claim (%s) of user id", cfg.UserID)
+	}
+	name, _ := claims[cfg.UserName].(string)
+	if name == "" {
+		name = id
+	}
+	ns, _ := claims[cfg.Namespace].(string)
+	if ns == "" {
+		ns = o.cfg.OIDC.DefaultNamespace
+	}
+	if ns == "" {
+		return nil, "", errors.Errorf("the token has no claim (%s) of namespace", cfg.Namespace)
+	}
+	user := &common.UserInfo{
+		User:   common.User{ID: id, Name: name},
+		Domain: common.Domain{ID: ns, Name: ns},
+	}
+	switch roles := claims[cfg.Roles].(type) {
+	case string:
+		for _, r := range strings.Fields(roles) {
+			user.Roles = append(user.Roles, common.Role{ID: r, Type: RoleTypeOIDC})
+		}
+	case []interface{}:
+		for _, v := range roles {
+			if r, ok := v.(string); ok && r != "" {
+				user.Roles = append(user.Roles, common.Role{ID: r, Type: RoleTypeOIDC})
+			}
+		}
+	}
+	return user, ns, nil
+}
+
+func containsAudience(aud interface{}, audience string) bool {
+	switch v := aud.(type) {
+	case string:
+		return v == audience
+	case []interface{}:
+		for _, a := range v {
+			if s, ok := a.(string); ok && s == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// verifySignature verifies the JWS signature, returns false if the key doesn't match the algorithm
+func verifySignature(alg string, key crypto.PublicKey, signed, sign []byte) (bool, error) {
+	var hash crypto.Hash
+	switch alg {
+	case "RS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512", "ES512":
+		hash = crypto.SHA512
+	case "EdDSA":
+		pub, ok := key.(ed25519.PublicKey)
+		return ok && ed25519.Verify(pub, signed, sign), nil
+	default:
+		return false, errors.Errorf("the algorithm (%s) of token is unsupported", alg)
+	}
+	h := hash.New()
+	h.Write(signed)
+	digest := h.Sum(nil)
+	switch pub := key.(type) {
+	case *rsa.PublicKey:
+		if alg[0] != 'R' {
+			return false, nil
+		}
+		return rsa.VerifyPKCS1v15(pub, hash, digest, sign) == nil, nil
+	case *ecdsa.PublicKey:
+		bits := pub.Curve.Params().BitSize
+		size := (bits + 7) / 8
+		if ecdsaCurveBits[alg] != bits || len(sign) != 2*size {
+			return false, nil
+		}
+		r := new(big.Int).SetBytes(sign[:size])
+		s := new(big.Int).SetBytes(sign[size:])
+		return ecdsa.Verify(pub, digest, r, s), nil
+	default:
+		return false, nil
+	}
+}
@@ -0,0 +1,336 @@
+package oidc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+const (
+	confData = `
+oidc:
+  issuer: "{{.ISSUER}}"
+  audience: "baetyl-cloud"
+  defaultNamespace: "default"
+  claims:
+    namespace: "tenant"
+`
+	audience = "baetyl-cloud"
+)
+
+// keyServer the local issuer serving the openid configuration and keys
+type keyServer struct {
+	*httptest.Server
+	keys     []jwk
+	requests int
+	mu       sync.Mutex
+}
+
+func newKeyServer() *keyServer {
+	s := &keyServer{}
+	mux := http.NewServeMux()
+	mux.HandleFunc(discoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		data, _ := json.Marshal(discovery{Issuer: s.URL, JWKSURI: s.URL + "/keys"})
+		w.Write(data)
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.requests++
+		data, _ := json.Marshal(jwkSet{Keys: s.keys})
+		w.Write(data)
+	})
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+func (s *keyServer) setKeys(keys ...jwk) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+}
+
+func (s *keyServer) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+func encodeInt(i *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(i.Bytes())
+}
+
+func rsaJWK(kid string, key *rsa.PrivateKey) jwk {
+	return jwk{Kty: "RSA", Kid: kid, Use: "sig", N: encodeInt(key.N), E: encodeInt(big.NewInt(int64(key.E)))}
+}
+
+func ecJWK(kid string, key *ecdsa.PrivateKey) jwk {
+	return jwk{Kty: "EC", Kid: kid, Crv: "P-256", X: encodeInt(key.X), Y: encodeInt(key.Y)}
+}
+
+func genToken(t *testing.T, alg, kid string, key crypto.Signer, claims map[string]interface{}) string {
+	h, err := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	assert.NoError(t, err)
+	c, err := json.Marshal(claims)
+	assert.NoError(t, err)
+	signed := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+
+	var sign []byte
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		digest := crypto.SHA256.New()
+		digest.Write([]byte(signed))
+		sign, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest.Sum(nil))
+		assert.NoError(t, err)
+	case *ecdsa.PrivateKey:
+		digest := crypto.SHA256.New()
+		digest.Write([]byte(signed))
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest.Sum(nil))
+		assert.NoError(t, err)
+		sign = make([]byte, 64)
+		r.FillBytes(sign[:32])
+		s.FillBytes(sign[32:])
+	case ed25519.PrivateKey:
+		sign = ed25519.Sign(k, []byte(signed))
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sign)
+}
+
+func genClaims(issuer string) map[string]interface{} {
+	return map[string]interface{}{
+		"iss":                issuer,
+		"aud":                []string{"other", audience},
+		"sub":                "u1",
+		"preferred_username": "user1",
+		"tenant":             "ns1",
+		"roles":              []string{"admin", "viewer"},
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"iat":                time.Now().Unix(),
+	}
+}
+
+func genConfig(workspace, issuer string) error {
+	if err := os.MkdirAll(workspace, 0755); err != nil {
+		return err
+	}
+	conf := strings.Replace(confData, "{{.ISSUER}}", issuer, -1)
+	return ioutil.WriteFile(path.Join(workspace, "cloud.yml"), []byte(conf), 0755)
+}
+
+func newContext(token string) *common.Context {
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = req
+	return common.NewContext(c)
+}
+
+func TestOIDCAuth_Authenticate(t *testing.T) {
+	server := newKeyServer()
+	defer server.Close()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	server.setKeys(rsaJWK("rsa1", rsaKey), ecJWK("ec1", ecKey))
+
+	err = genConfig("etc/baetyl", server.URL)
+	assert.NoError(t, err)
+	defer os.RemoveAll(path.Dir("etc/baetyl"))
+
+	p, err := plugin.GetPlugin("oidc")
+	assert.NoError(t, err)
+	auth := p.(plugin.Auth)
+
+	// rsa
+	ctx := newContext(genToken(t, "RS256", "rsa1", rsaKey, genClaims(server.URL)))
+	err = auth.Authenticate(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "ns1", ctx.GetNamespace())
+	assert.Equal(t, common.UserInfo{
+		User:   common.User{ID: "u1", Name: "user1"},
+		Roles:  []common.Role{{ID: "admin", Type: RoleTypeOIDC}, {ID: "viewer", Type: RoleTypeOIDC}},
+		Domain: common.Domain{ID: "ns1", Name: "ns1"},
+	}, ctx.GetUserInfo())
+
+	// ecdsa, the default namespace is used without the namespace claim
+	claims := genClaims(server.URL)
+	delete(claims, "tenant")
+	claims["aud"] = audience
+	ctx = newContext(genToken(t, "ES256", "ec1", ecKey, claims))
+	err = auth.AuthAndVerify(ctx, &plugin.PermissionRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, "default", ctx.GetNamespace())
+	assert.Equal(t, "u1", ctx.GetUserInfo().User.ID)
+	// the keys are cached
+	assert.Equal(t, 1, server.count())
+
+	// no token
+	err = auth.Authenticate(newContext(""))
+	assert.Equal(t, ErrNoBearerToken, err)
+	err = auth.Authenticate(newContext("a.b"))
+	assert.Equal(t, ErrInvalidToken, err)
+
+	// expired
+	claims = genClaims(server.URL)
+	claims["exp"] = time.Now().Add(-time.Hour).Unix()
+	err = auth.Authenticate(newContext(genToken(t, "RS256", "rsa1", rsaKey, claims)))
+	assert.EqualError(t, err, "the token is expired")
+
+	// not valid yet
+	claims = genClaims(server.URL)
+	claims["nbf"] = time.Now().Add(time.Hour).Unix()
+	err = auth.Authenticate(newContext(genToken(t, "RS256", "rsa1", rsaKey, claims)))
+	assert.Error(t, err)
+
+	// no expiration
+	claims = genClaims(server.URL)
+	delete(claims, "exp")
+	err = auth.Authenticate(newContext(genToken(t, "RS256", "rsa1", rsaKey, claims)))
+	assert.Error(t, err)
+
+	// mis-audienced
+	claims = genClaims(server.URL)
+	claims["aud"] = "other"
+	err = auth.Authenticate(newContext(genToken(t, "RS256", "rsa1", rsaKey, claims)))
+	assert.EqualError(t, err, "the audience of token is unexpected")
+
+	// other issuer
+	claims = genClaims("https://other.example.com")
+	err = auth.Authenticate(newContext(genToken(t, "RS256", "rsa1", rsaKey, claims)))
+	assert.Error(t, err)
+
+	// no user id
+	claims = genClaims(server.URL)
+	delete(claims, "sub")
+	err = auth.Authenticate(newContext(genToken(t, "RS256", "rsa1", rsaKey, claims)))
+	assert.Error(t, err)
+
+	// signed by another key
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	err = auth.Authenticate(newContext(genToken(t, "RS256", "rsa1", otherKey, genClaims(server.URL))))
+	assert.EqualError(t, err, "the signature of token is invalid")
+
+	// the algorithm doesn't match the key
+	err = auth.Authenticate(newContext(genToken(t, "ES256", "rsa1", ecKey, genClaims(server.URL))))
+	assert.Error(t, err)
+	err = auth.Authenticate(newContext(genToken(t, "none", "rsa1", rsaKey, genClaims(server.URL))))
+	assert.Error(t, err)
+}
+
+func TestOIDCAuth_Refresh(t *testing.T) {
+	server := newKeyServer()
+	defer server.Close()
+	key1, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	_, key2, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	server.setKeys(rsaJWK("k1", key1))
+
+	o := &oidcAuth{}
+	o.cfg.OIDC.Issuer = server.URL
+	o.cfg.OIDC.Audience = audience
+	o.cfg.OIDC.Claims = Claims{UserID: "sub", UserName: "preferred_username", Namespace: "tenant", Roles: "roles"}
+	o.keys = newKeySet(server.URL, "", time.Hour, time.Hour)
+
+	_, err = o.verify(genToken(t, "RS256", "k1", key1, genClaims(server.URL)))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, server.count())
+
+	// the key rotated by issuer isn't loaded within the min refresh interval
+	edJWK := jwk{Kty: "OKP", Kid: "k2", Crv: "Ed25519", X: base64.RawURLEncoding.EncodeToString(key2.Public().(ed25519.PublicKey))}
+	server.setKeys(rsaJWK("k1", key1), edJWK)
+	token := genToken(t, "EdDSA", "k2", key2, genClaims(server.URL))
+	_, err = o.verify(token)
+	assert.Error(t, err)
+	assert.Equal(t, 1, server.count())
+
+	// the unknown key is loaded after the min refresh interval
+	o.keys.minRefresh = 0
+	claims, err := o.verify(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "u1", claims["sub"])
+	assert.Equal(t, 2, server.count())
+
+	// the keys are refreshed once expired, the removed key is rejected
+	server.setKeys(edJWK)
+	o.keys.refresh = 0
+	_, err = o.verify(genToken(t, "RS256", "k1", key1, genClaims(server.URL)))
+	assert.Error(t, err)
+
+	// the cached keys are used if the issuer is unavailable
+	server.Close()
+	_, err = o.verify(token)
+	assert.NoError(t, err)
+}
+
+func TestKeySet_ConcurrentLoad(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	release := make(chan struct{})
+	requests := 0
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		<-release
+		data, _ := json.Marshal(jwkSet{Keys: []jwk{rsaJWK("k1", key)}})
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	s := newKeySet(server.URL, server.URL, time.Hour, time.Hour)
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			keys, err := s.get("k1")
+			assert.NoError(t, err)
+			assert.Len(t, keys, 1)
+		}()
+	}
+	// the lock isn't held while fetching the keys
+	time.Sleep(100 * time.Millisecond)
+	locked := make(chan struct{})
+	go func() {
+		s.mu.Lock()
+		s.mu.Unlock()
+		close(locked)
+	}()
+	select {
+	case <-locked:
+	case <-time.After(time.Second):
+		t.Fatal("the lock is held while fetching the keys")
+	}
+	close(release)
+	wg.Wait()
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 1, requests)
+}
@@ -39,6 +39,14 @@ type PKI interface {
 	GetClientCert(certId string) ([]byte, error)
 	DeleteClientCert(certId string) error
 
+	// namespace cert
+	// GetNamespaceCertID returns the id of the intermediate ca issuing the certs of namespace, which is created at the first time
+	GetNamespaceCertID(namespace string) (string, error)
+	// GetNamespaceCert returns the intermediate ca of namespace without creating it, fails if it's not created or revoked
+	GetNamespaceCert(namespace string) ([]byte, error)
+	// RevokeNamespaceCert revokes the intermediate ca of namespace, no cert can be issued by it any more
+	RevokeNamespaceCert(namespace string) error
+
 	// close
 	io.Closer
 }
@@ -0,0 +1,24 @@
+package plugin
+
+import (
+	"database/sql"
+	"io"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+//go:generate mockgen -destination=../mock/plugin/record.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin Record
+
+// Record stores the fingerprint records of batches
+type Record interface {
+	GetRecord(batchName, recordName, ns string) (*models.Record, error)
+	GetRecordByFingerprint(batchName, ns, value string) (*models.Record, error)
+	ListRecord(batchName, ns string, filter *models.Filter) ([]models.Record, error)
+	// ListRecordByBatch lists all the records of batch without the filter
+	ListRecordByBatch(batchName, ns string) ([]models.Record, error)
+	CreateRecord(records []models.Record) (sql.Result, error)
+	UpdateRecord(record *models.Record) (sql.Result, error)
+	DeleteRecord(batchName, recordName, ns string) (sql.Result, error)
+	CountRecord(batchName, fingerprintValue, ns string) (int, error)
+	io.Closer
+}
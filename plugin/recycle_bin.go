@@ -0,0 +1,21 @@
+package plugin
+
+import (
+	"io"
+	"time"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+//go:generate mockgen -destination=../mock/plugin/recycle_bin.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin RecycleBin
+
+// RecycleBin stores the deleted resources of namespaces
+type RecycleBin interface {
+	CreateRecycleItem(tx interface{}, item *models.RecycleItem) (*models.RecycleItem, error)
+	GetRecycleItem(tx interface{}, namespace string, id int64) (*models.RecycleItem, error)
+	ListRecycleItem(tx interface{}, namespace string, listOptions *models.RecycleListOptions) (*models.RecycleItemList, error)
+	DeleteRecycleItem(tx interface{}, namespace string, id int64) error
+	// DeleteExpiredRecycleItems deletes the items expired before the time of all namespaces
+	DeleteExpiredRecycleItems(tx interface{}, before time.Time) (int64, error)
+	io.Closer
+}
@@ -0,0 +1,19 @@
+package plugin
+
+import (
+	"io"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+//go:generate mockgen -destination=../mock/plugin/rollout.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin Rollout
+
+// Rollout stores the rollout strategy and state of applications
+type Rollout interface {
+	GetRollout(tx interface{}, namespace, name string) (*models.Rollout, error)
+	// SaveRollout creates the rollout of application or updates the existing one
+	SaveRollout(tx interface{}, rollout *models.Rollout) (*models.Rollout, error)
+	DeleteRollout(tx interface{}, namespace, name string) error
+	ListRolloutByStatus(tx interface{}, status models.RolloutStatus) ([]models.Rollout, error)
+	io.Closer
+}
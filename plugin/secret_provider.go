@@ -0,0 +1,13 @@
+package plugin
+
+import "io"
+
+//go:generate mockgen -destination=../mock/plugin/secret_provider.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin SecretProvider
+
+// SecretProvider resolves the values of secrets from the external store, the values are never persisted by baetyl-cloud
+type SecretProvider interface {
+	// GetSecretValue returns the value of the reference in the namespace
+	GetSecretValue(namespace, ref string) ([]byte, error)
+
+	io.Closer
+}
@@ -2,13 +2,47 @@ package plugin
 
 import (
 	"io"
+	"time"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
 )
 
-//go:generate mockgen -destination=../mock/plugin/sign.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin Sign
+//go:generate mockgen -destination=../mock/plugin/sign.go -package=plugin -source=sign.go
+
+// SignKey the key of sign plugin, the latest created key of algorithm is active for signing,
+// the others are retired which verify the signatures only
+type SignKey struct {
+	KeyID      string    `db:"key_id"`
+	Algorithm  string    `db:"algorithm"`
+	PrivateKey string    `db:"private_key"` // base64
+	CreateTime time.Time `db:"create_time"`
+}
 
-// Sign interfaces of Sign
+// Sign interfaces of Sign, the id of key is embedded in the signature,
+// the signature without key id is signed and verified by the legacy key of plugin if any
 type Sign interface {
+	// Signature signs the meta with the active key
 	Signature(meta []byte) ([]byte, error)
+	// SignatureWithKey signs the meta with the key of id which can be a retired one, the empty id means the legacy key
+	SignatureWithKey(keyID string, meta []byte) ([]byte, error)
+	// Verify verifies the signature with the key whose id is embedded in it, the retired keys verify until they are removed
 	Verify(meta, sign []byte) bool
+	// ActiveKeyID returns the id of the active key, which is empty if no key is created and the legacy key is active
+	ActiveKeyID() (string, error)
+	// RotateKey creates a new key as the active one, the previous active key is retired
+	RotateKey() (*models.SignKey, error)
+	// ListKeys lists the keys except the legacy one, the active first
+	ListKeys() ([]models.SignKey, error)
+	// RemoveKey removes the retired key, the signatures of it fail to verify afterwards
+	RemoveKey(keyID string) error
+	io.Closer
+}
+
+// SignKeyStorage stores the keys of sign plugins
+type SignKeyStorage interface {
+	CreateSignKey(key SignKey) error
+	// ListSignKey lists the keys of algorithm, the latest created first
+	ListSignKey(algorithm string) ([]SignKey, error)
+	DeleteSignKey(algorithm, keyID string) error
 	io.Closer
 }
@@ -1,7 +1,21 @@
 package sign
 
+import "time"
+
 type CloudConfig struct {
 	RSASign struct {
 		KeyFile string `yaml:"keyFile" json:"keyFile" default:"etc/baetyl/token.key"`
+		// the keys are not persistent and can't be rotated if persistent is empty, the key of keyFile is used
+		Persistent string `yaml:"persistent" json:"persistent"`
+		// the kms which encrypts the persistent keys
+		KMS string `yaml:"kms" json:"kms" default:"localkms"`
+		// the signatures of keyFile are rejected once the legacy is retired
+		RetireLegacy    bool          `yaml:"retireLegacy" json:"retireLegacy"`
+		RefreshInterval time.Duration `yaml:"refreshInterval" json:"refreshInterval" default:"1m"`
 	} `yaml:"rsasign" json:"rsasign"`
+	Ed25519Sign struct {
+		Persistent      string        `yaml:"persistent" json:"persistent" default:"database"`
+		KMS             string        `yaml:"kms" json:"kms" default:"localkms"`
+		RefreshInterval time.Duration `yaml:"refreshInterval" json:"refreshInterval" default:"1m"`
+	} `yaml:"ed25519sign" json:"ed25519sign"`
 }
@@ -0,0 +1,78 @@
+package sign
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/sign/keyring"
+)
+
+// ed25519Sign signs with ed25519 whose signatures are much shorter than rsa, it has no legacy key,
+// so a key is created on startup if there is none
+type ed25519Sign struct {
+	*keyring.Keyring
+	cfg CloudConfig
+}
+
+// ed25519Algorithm the keys are the seeds of private keys
+type ed25519Algorithm struct{}
+
+func init() {
+	plugin.RegisterFactory("ed25519sign", NewEd25519)
+}
+
+// NewEd25519 NewEd25519
+func NewEd25519() (plugin.Plugin, error) {
+	var cfg CloudConfig
+	if err := common.LoadConfig(&cfg); err != nil {
+		return nil, err
+	}
+	sto, kms, err := keyring.LoadStorage(cfg.Ed25519Sign.Persistent, cfg.Ed25519Sign.KMS)
+	if err != nil {
+		return nil, err
+	}
+	s := &ed25519Sign{
+		Keyring: keyring.NewKeyring(ed25519Algorithm{}, sto, cfg.Ed25519Sign.RefreshInterval, nil, nil).WithKMS(kms),
+		cfg:     cfg,
+	}
+	if err = s.EnsureKey(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Close Close
+func (e *ed25519Sign) Close() error {
+	return nil
+}
+
+func (ed25519Algorithm) Name() string {
+	return "ed25519"
+}
+
+func (ed25519Algorithm) GenerateKey() ([]byte, error) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return priv.Seed(), nil
+}
+
+func (ed25519Algorithm) ParseKey(key []byte) (interface{}, error) {
+	if len(key) != ed25519.SeedSize {
+		return nil, fmt.Errorf("the size of ed25519 seed should be %d", ed25519.SeedSize)
+	}
+	return ed25519.NewKeyFromSeed(key), nil
+}
+
+func (ed25519Algorithm) Sign(key interface{}, meta []byte) ([]byte, error) {
+	return ed25519.Sign(key.(ed25519.PrivateKey), meta), nil
+}
+
+func (ed25519Algorithm) Verify(key interface{}, meta, sign []byte) bool {
+	pub := key.(ed25519.PrivateKey).Public().(ed25519.PublicKey)
+	return ed25519.Verify(pub, meta, sign)
+}
@@ -0,0 +1,59 @@
+package sign
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+func TestEd25519Sign_Sign_Verify(t *testing.T) {
+	mockStorage(t)
+	err := genConfig("etc/baetyl")
+	assert.NoError(t, err)
+	defer os.RemoveAll(path.Dir("etc/baetyl"))
+
+	p, err := plugin.GetPlugin("ed25519sign")
+	assert.NoError(t, err)
+	s := p.(plugin.Sign)
+
+	// a key is created on startup
+	keys, err := s.ListKeys()
+	assert.NoError(t, err)
+	assert.Len(t, keys, 1)
+	assert.Equal(t, "ed25519", keys[0].Algorithm)
+	assert.True(t, keys[0].Active)
+	id, err := s.ActiveKeyID()
+	assert.NoError(t, err)
+	assert.Equal(t, keys[0].KeyID, id)
+
+	meta := []byte("test")
+	sign, err := s.Signature(meta)
+	assert.NoError(t, err)
+	// "k:" + key id + ":" + 64 bytes signature
+	assert.Len(t, sign, 2+len(id)+1+64)
+	assert.True(t, s.Verify(meta, sign))
+	assert.False(t, s.Verify([]byte("other"), sign))
+	assert.False(t, s.Verify(meta, meta))
+
+	_, err = s.SignatureWithKey("", meta)
+	assert.Error(t, err)
+
+	key, err := s.RotateKey()
+	assert.NoError(t, err)
+	assert.True(t, s.Verify(meta, sign))
+	sign2, err := s.Signature(meta)
+	assert.NoError(t, err)
+	assert.NotEqual(t, sign, sign2)
+	assert.True(t, s.Verify(meta, sign2))
+
+	err = s.RemoveKey(key.KeyID)
+	assert.Error(t, err)
+	err = s.RemoveKey(id)
+	assert.NoError(t, err)
+	assert.False(t, s.Verify(meta, sign))
+	assert.True(t, s.Verify(meta, sign2))
+}
@@ -0,0 +1,364 @@
+package keyring
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+const (
+	// the signature with key id is formatted as "k:<key id>:<signature>"
+	keyIDPrefix    = "k:"
+	keyIDSeparator = ':'
+	keyIDLength    = 10
+	maxKeyIDLength = 64
+	// the private key encrypted by kms is stored as "kms:<kms key id>:<base64 ciphertext>"
+	kmsKeyPrefix = "kms:"
+	// the min interval of reloading the keys when the key id of signature is unknown
+	minReloadInterval = 10 * time.Second
+)
+
+// Algorithm signs and verifies with the keys of one algorithm
+type Algorithm interface {
+	Name() string
+	// GenerateKey generates a new private key, which is encoded as bytes
+	GenerateKey() ([]byte, error)
+	// ParseKey parses the private key encoded by GenerateKey
+	ParseKey(key []byte) (interface{}, error)
+	Sign(key interface{}, meta []byte) ([]byte, error)
+	Verify(key interface{}, meta, sign []byte) bool
+}
+
+// Keyring signs with the active key and verifies with all keys of algorithm in storage, the keys are reloaded
+// periodically to pick up the keys rotated by other instances. The signatures without key id are signed and
+// verified by the legacy key, which is optional and can be retired once the keys are rotated. The storage is
+// optional too, only the legacy key is used without it
+type Keyring struct {
+	alg       Algorithm
+	sto       plugin.SignKeyStorage
+	kms       plugin.KMS
+	refresh   time.Duration
+	legacy    Algorithm
+	legacyKey interface{}
+	stored    []plugin.SignKey
+	keys      map[string]interface{}
+	loadTime  time.Time
+	mu        sync.Mutex
+	log       *log.Logger
+}
+
+// NewKeyring new keyring, sto is nil if the keys are not persistent, legacy is nil if there is no legacy key
+func NewKeyring(alg Algorithm, sto plugin.SignKeyStorage, refresh time.Duration, legacy Algorithm, legacyKey interface{}) *Keyring {
+	return &Keyring{
+		alg:       alg,
+		sto:       sto,
+		refresh:   refresh,
+		legacy:    legacy,
+		legacyKey: legacyKey,
+		log:       log.L().With(log.Any("keyring", alg.Name())),
+	}
+}
+
+// LoadStorage loads the storage of keys and the kms which encrypts them, both are nil if persistent is empty
+func LoadStorage(persistent, kms string) (plugin.SignKeyStorage, plugin.KMS, error) {
+	if persistent == "" {
+		return nil, nil, nil
+	}
+	p, err := plugin.GetPlugin(persistent)
+	if err != nil {
+		return nil, nil, err
+	}
+	sto, ok := p.(plugin.SignKeyStorage)
+	if !ok {
+		return nil, nil, errors.Errorf("plugin (%s) is not a sign key storage", persistent)
+	}
+	p, err = plugin.GetPlugin(kms)
+	if err != nil {
+		return nil, nil, err
+	}
+	k, ok := p.(plugin.KMS)
+	if !ok {
+		return nil, nil, errors.Errorf("plugin (%s) is not a kms", kms)
+	}
+	return sto, k, nil
+}
+
+// WithKMS encrypts the private keys in storage by kms
+func (k *Keyring) WithKMS(kms plugin.KMS) *Keyring {
+	k.kms = kms
+	return k
+}
+
+// RetireLegacy stops signing and verifying by the legacy key, a key is created if there is none
+func (k *Keyring) RetireLegacy() error {
+	k.mu.Lock()
+	k.legacy, k.legacyKey = nil, nil
+	k.mu.Unlock()
+	return k.EnsureKey()
+}
+
+// EnsureKey creates a key if there is none
+func (k *Keyring) EnsureKey() error {
+	keys, err := k.ListKeys()
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		_, err = k.RotateKey()
+	}
+	return err
+}
+
+func (k *Keyring) Signature(meta []byte) ([]byte, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if err := k.load(); err != nil {
+		return nil, err
+	}
+	id, err := k.activeKeyID()
+	if err != nil {
+		return nil, err
+	}
+	return k.sign(id, meta)
+}
+
+func (k *Keyring) SignatureWithKey(keyID string, meta []byte) ([]byte, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if err := k.load(); err != nil {
+		return nil, err
+	}
+	return k.sign(keyID, meta)
+}
+
+func (k *Keyring) Verify(meta, sign []byte) bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if err := k.load(); err != nil {
+		k.log.Warn("failed to load keys", log.Error(err))
+		return false
+	}
+	// the signature with key id never falls back to the legacy key, otherwise it can be forged by the legacy one
+	if id, raw, ok := parseSignature(sign); ok {
+		key, ok := k.getKey(id)
+		return ok && k.alg.Verify(key, meta, raw)
+	}
+	if k.legacy != nil {
+		return k.legacy.Verify(k.legacyKey, meta, sign)
+	}
+	return false
+}
+
+func (k *Keyring) ActiveKeyID() (string, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if err := k.load(); err != nil {
+		return "", err
+	}
+	return k.activeKeyID()
+}
+
+func (k *Keyring) RotateKey() (*models.SignKey, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.sto == nil {
+		return nil, errNotPersistent()
+	}
+	data, err := k.alg.GenerateKey()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	priv, err := k.encryptKey(data)
+	if err != nil {
+		return nil, err
+	}
+	key := plugin.SignKey{
+		KeyID:      common.RandString(keyIDLength),
+		Algorithm:  k.alg.Name(),
+		PrivateKey: priv,
+		CreateTime: time.Now().UTC(),
+	}
+	if err = k.sto.CreateSignKey(key); err != nil {
+		return nil, errors.Trace(err)
+	}
+	k.loadTime = time.Time{}
+	if err = k.load(); err != nil {
+		return nil, err
+	}
+	k.log.Info("sign key is rotated", log.Any("keyID", key.KeyID))
+	return &models.SignKey{KeyID: key.KeyID, Algorithm: key.Algorithm, Active: true, CreateTime: key.CreateTime}, nil
+}
+
+func (k *Keyring) ListKeys() ([]models.SignKey, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if err := k.load(); err != nil {
+		return nil, err
+	}
+	res := make([]models.SignKey, 0, len(k.stored))
+	for i, key := range k.stored {
+		res = append(res, models.SignKey{KeyID: key.KeyID, Algorithm: key.Algorithm, Active: i == 0, CreateTime: key.CreateTime})
+	}
+	return res, nil
+}
+
+func (k *Keyring) RemoveKey(keyID string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.sto == nil {
+		return errNotPersistent()
+	}
+	k.loadTime = time.Time{}
+	if err := k.load(); err != nil {
+		return err
+	}
+	if _, ok := k.keys[keyID]; !ok {
+		return common.Error(common.ErrResourceNotFound, common.Field("type", "signKey"), common.Field("name", keyID))
+	}
+	if k.stored[0].KeyID == keyID {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", "the active key can't be removed, rotate the key first"))
+	}
+	if err := k.sto.DeleteSignKey(k.alg.Name(), keyID); err != nil {
+		return errors.Trace(err)
+	}
+	k.loadTime = time.Time{}
+	k.log.Info("sign key is removed", log.Any("keyID", keyID))
+	return k.load()
+}
+
+func (k *Keyring) activeKeyID() (string, error) {
+	if len(k.stored) > 0 {
+		return k.stored[0].KeyID, nil
+	}
+	if k.legacy != nil {
+		return "", nil
+	}
+	return "", errors.Errorf("no key of algorithm (%s) is created", k.alg.Name())
+}
+
+func (k *Keyring) sign(keyID string, meta []byte) ([]byte, error) {
+	if keyID == "" {
+		if k.legacy == nil {
+			return nil, errors.Errorf("no legacy key of algorithm (%s)", k.alg.Name())
+		}
+		return k.legacy.Sign(k.legacyKey, meta)
+	}
+	key, ok := k.getKey(keyID)
+	if !ok {
+		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "signKey"), common.Field("name", keyID))
+	}
+	sign, err := k.alg.Sign(key, meta)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return append([]byte(keyIDPrefix+keyID+string(keyIDSeparator)), sign...), nil
+}
+
+// getKey returns the key of id, the keys are reloaded if the id is unknown, at most once in minReloadInterval
+func (k *Keyring) getKey(keyID string) (interface{}, bool) {
+	if key, ok := k.keys[keyID]; ok {
+		return key, true
+	}
+	if time.Since(k.loadTime) < minReloadInterval {
+		return nil, false
+	}
+	k.loadTime = time.Time{}
+	if err := k.load(); err != nil {
+		k.log.Warn("failed to reload keys", log.Error(err))
+		return nil, false
+	}
+	key, ok := k.keys[keyID]
+	return key, ok
+}
+
+// load loads the keys from storage if they are expired, the loaded keys are kept if the storage fails
+func (k *Keyring) load() error {
+	if k.keys != nil && time.Since(k.loadTime) < k.refresh {
+		return nil
+	}
+	if k.sto == nil {
+		k.keys, k.loadTime = map[string]interface{}{}, time.Now()
+		return nil
+	}
+	stored, err := k.sto.ListSignKey(k.alg.Name())
+	if err != nil {
+		if k.keys != nil {
+			k.log.Warn("failed to reload keys, the loaded keys are used", log.Error(err))
+			k.loadTime = time.Now()
+			return nil
+		}
+		return errors.Trace(err)
+	}
+	keys := map[string]interface{}{}
+	for _, s := range stored {
+		data, err := k.decryptKey(s.PrivateKey)
+		if err != nil {
+			return errors.Errorf("key (%s) is invalid: %s", s.KeyID, err.Error())
+		}
+		key, err := k.alg.ParseKey(data)
+		if err != nil {
+			return errors.Errorf("key (%s) is invalid: %s", s.KeyID, err.Error())
+		}
+		keys[s.KeyID] = key
+	}
+	k.stored, k.keys, k.loadTime = stored, keys, time.Now()
+	return nil
+}
+
+// encryptKey encodes the private key, which is encrypted by kms if it's set
+func (k *Keyring) encryptKey(data []byte) (string, error) {
+	if k.kms == nil {
+		return base64.StdEncoding.EncodeToString(data), nil
+	}
+	id, ciphertext, err := k.kms.Encrypt(data)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return kmsKeyPrefix + id + ":" + base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// decryptKey decodes the private key encoded by encryptKey, the keys stored in plaintext are still loaded
+func (k *Keyring) decryptKey(priv string) ([]byte, error) {
+	if !strings.HasPrefix(priv, kmsKeyPrefix) {
+		return base64.StdEncoding.DecodeString(priv)
+	}
+	if k.kms == nil {
+		return nil, errors.New("the key is encrypted by kms, which is not configured")
+	}
+	rest := priv[len(kmsKeyPrefix):]
+	// the base64 ciphertext never contains the separator
+	i := strings.LastIndexByte(rest, ':')
+	if i <= 0 {
+		return nil, errors.New("the encrypted key is malformed")
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(rest[i+1:])
+	if err != nil {
+		return nil, err
+	}
+	return k.kms.Decrypt(rest[:i], ciphertext)
+}
+
+func errNotPersistent() error {
+	return common.Error(common.ErrRequestParamInvalid, common.Field("error", "the sign keys are not persistent, configure the persistent storage first"))
+}
+
+func parseSignature(sign []byte) (string, []byte, bool) {
+	if !bytes.HasPrefix(sign, []byte(keyIDPrefix)) {
+		return "", nil, false
+	}
+	rest := sign[len(keyIDPrefix):]
+	i := bytes.IndexByte(rest, keyIDSeparator)
+	if i <= 0 || i > maxKeyIDLength {
+		return "", nil, false
+	}
+	return string(rest[:i]), rest[i+1:], true
+}
@@ -0,0 +1,341 @@
+package keyring
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+type memStorage struct {
+	keys []plugin.SignKey
+	err  error
+}
+
+func (m *memStorage) CreateSignKey(key plugin.SignKey) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.keys = append([]plugin.SignKey{key}, m.keys...)
+	return nil
+}
+
+func (m *memStorage) ListSignKey(algorithm string) ([]plugin.SignKey, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var res []plugin.SignKey
+	for _, k := range m.keys {
+		if k.Algorithm == algorithm {
+			res = append(res, k)
+		}
+	}
+	return res, nil
+}
+
+func (m *memStorage) DeleteSignKey(algorithm, keyID string) error {
+	for i, k := range m.keys {
+		if k.Algorithm == algorithm && k.KeyID == keyID {
+			m.keys = append(m.keys[:i], m.keys[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *memStorage) Close() error {
+	return nil
+}
+
+// xorKMS encrypts by xor with the key id
+type xorKMS struct {
+	id string
+}
+
+func (x *xorKMS) Encrypt(plaintext []byte) (string, []byte, error) {
+	return x.id, xor(plaintext, x.id), nil
+}
+
+func (x *xorKMS) Decrypt(keyID string, ciphertext []byte) ([]byte, error) {
+	return xor(ciphertext, keyID), nil
+}
+
+func (x *xorKMS) Close() error {
+	return nil
+}
+
+func xor(data []byte, key string) []byte {
+	res := make([]byte, len(data))
+	for i := range data {
+		res[i] = data[i] ^ key[i%len(key)]
+	}
+	return res
+}
+
+// appendAlg signs by appending the key to meta
+type appendAlg struct {
+	n int
+}
+
+func (a *appendAlg) Name() string {
+	return "test"
+}
+
+func (a *appendAlg) GenerateKey() ([]byte, error) {
+	a.n++
+	return []byte(fmt.Sprintf("key%d", a.n)), nil
+}
+
+func (a *appendAlg) ParseKey(key []byte) (interface{}, error) {
+	if len(key) == 0 {
+		return nil, fmt.Errorf("empty key")
+	}
+	return key, nil
+}
+
+func (a *appendAlg) Sign(key interface{}, meta []byte) ([]byte, error) {
+	return append(append([]byte{}, meta...), key.([]byte)...), nil
+}
+
+func (a *appendAlg) Verify(key interface{}, meta, sign []byte) bool {
+	expect, _ := a.Sign(key, meta)
+	return bytes.Equal(expect, sign)
+}
+
+func TestKeyring_Legacy(t *testing.T) {
+	sto := &memStorage{}
+	alg := &appendAlg{}
+	k := NewKeyring(alg, sto, time.Minute, alg, []byte("legacy"))
+	meta := []byte("meta")
+
+	id, err := k.ActiveKeyID()
+	assert.NoError(t, err)
+	assert.Equal(t, "", id)
+
+	sign, err := k.Signature(meta)
+	assert.NoError(t, err)
+	assert.Equal(t, "metalegacy", string(sign))
+	assert.True(t, k.Verify(meta, sign))
+	assert.False(t, k.Verify([]byte("other"), sign))
+
+	keys, err := k.ListKeys()
+	assert.NoError(t, err)
+	assert.Len(t, keys, 0)
+
+	// the legacy signature still verifies after rotation
+	key, err := k.RotateKey()
+	assert.NoError(t, err)
+	assert.True(t, key.Active)
+	assert.True(t, k.Verify(meta, sign))
+
+	sign, err = k.SignatureWithKey("", meta)
+	assert.NoError(t, err)
+	assert.Equal(t, "metalegacy", string(sign))
+}
+
+func TestKeyring_NoFallback(t *testing.T) {
+	sto := &memStorage{}
+	alg := &appendAlg{}
+	// the legacy signature is the meta itself, which is the worst case
+	k := NewKeyring(alg, sto, time.Minute, alg, []byte{})
+	forged := []byte("k:unknown:sig")
+	assert.False(t, k.Verify(forged, forged))
+	assert.True(t, k.Verify([]byte("meta"), []byte("meta")))
+
+	_, err := k.RotateKey()
+	assert.NoError(t, err)
+	assert.False(t, k.Verify(forged, forged))
+}
+
+func TestKeyring_RetireLegacy(t *testing.T) {
+	sto := &memStorage{}
+	alg := &appendAlg{}
+	k := NewKeyring(alg, sto, time.Minute, alg, []byte("legacy"))
+	meta := []byte("meta")
+	legacy, err := k.Signature(meta)
+	assert.NoError(t, err)
+	assert.True(t, k.Verify(meta, legacy))
+
+	// a key is created once the legacy is retired
+	err = k.RetireLegacy()
+	assert.NoError(t, err)
+	keys, err := k.ListKeys()
+	assert.NoError(t, err)
+	assert.Len(t, keys, 1)
+	assert.False(t, k.Verify(meta, legacy))
+	_, err = k.SignatureWithKey("", meta)
+	assert.Error(t, err)
+	sign, err := k.Signature(meta)
+	assert.NoError(t, err)
+	assert.Equal(t, "k:"+keys[0].KeyID+":metakey1", string(sign))
+
+	// the existing key is kept
+	err = k.RetireLegacy()
+	assert.NoError(t, err)
+	keys, err = k.ListKeys()
+	assert.NoError(t, err)
+	assert.Len(t, keys, 1)
+}
+
+func TestKeyring_NotPersistent(t *testing.T) {
+	alg := &appendAlg{}
+	k := NewKeyring(alg, nil, time.Minute, alg, []byte("legacy"))
+	meta := []byte("meta")
+	sign, err := k.Signature(meta)
+	assert.NoError(t, err)
+	assert.Equal(t, "metalegacy", string(sign))
+	assert.True(t, k.Verify(meta, sign))
+	keys, err := k.ListKeys()
+	assert.NoError(t, err)
+	assert.Len(t, keys, 0)
+
+	_, err = k.RotateKey()
+	assert.Error(t, err)
+	err = k.RemoveKey("unknown")
+	assert.Error(t, err)
+	err = k.RetireLegacy()
+	assert.Error(t, err)
+}
+
+func TestKeyring_KMS(t *testing.T) {
+	sto := &memStorage{}
+	alg := &appendAlg{}
+	k := NewKeyring(alg, sto, time.Minute, nil, nil).WithKMS(&xorKMS{id: "kek1"})
+	meta := []byte("meta")
+
+	key, err := k.RotateKey()
+	assert.NoError(t, err)
+	assert.Len(t, sto.keys, 1)
+	assert.True(t, strings.HasPrefix(sto.keys[0].PrivateKey, "kms:kek1:"))
+	assert.NotContains(t, sto.keys[0].PrivateKey, base64.StdEncoding.EncodeToString([]byte("key1")))
+	sign, err := k.Signature(meta)
+	assert.NoError(t, err)
+	assert.Equal(t, "k:"+key.KeyID+":metakey1", string(sign))
+
+	// the keys stored in plaintext are still loaded
+	sto.keys = append(sto.keys, plugin.SignKey{KeyID: "plain", Algorithm: "test", PrivateKey: base64.StdEncoding.EncodeToString([]byte("old"))})
+	k2 := NewKeyring(alg, sto, time.Minute, nil, nil).WithKMS(&xorKMS{id: "kek2"})
+	assert.True(t, k2.Verify(meta, sign))
+	assert.True(t, k2.Verify(meta, []byte("k:plain:metaold")))
+
+	// the encrypted keys can't be loaded without kms
+	k3 := NewKeyring(alg, sto, time.Minute, nil, nil)
+	assert.False(t, k3.Verify(meta, sign))
+}
+
+func TestKeyring_Rotate(t *testing.T) {
+	sto := &memStorage{}
+	k := NewKeyring(&appendAlg{}, sto, time.Minute, nil, nil)
+	meta := []byte("meta")
+
+	_, err := k.ActiveKeyID()
+	assert.Error(t, err)
+	_, err = k.Signature(meta)
+	assert.Error(t, err)
+	_, err = k.SignatureWithKey("", meta)
+	assert.Error(t, err)
+	assert.False(t, k.Verify(meta, meta))
+
+	key1, err := k.RotateKey()
+	assert.NoError(t, err)
+	assert.Len(t, key1.KeyID, keyIDLength)
+	assert.Equal(t, "test", key1.Algorithm)
+	sign1, err := k.Signature(meta)
+	assert.NoError(t, err)
+	assert.Equal(t, "k:"+key1.KeyID+":metakey1", string(sign1))
+	assert.True(t, k.Verify(meta, sign1))
+
+	key2, err := k.RotateKey()
+	assert.NoError(t, err)
+	id, err := k.ActiveKeyID()
+	assert.NoError(t, err)
+	assert.Equal(t, key2.KeyID, id)
+	sign2, err := k.Signature(meta)
+	assert.NoError(t, err)
+	assert.Equal(t, "k:"+key2.KeyID+":metakey2", string(sign2))
+
+	// the retired key verifies and signs with its id
+	assert.True(t, k.Verify(meta, sign1))
+	sign, err := k.SignatureWithKey(key1.KeyID, meta)
+	assert.NoError(t, err)
+	assert.Equal(t, sign1, sign)
+	_, err = k.SignatureWithKey("unknown", meta)
+	assert.Error(t, err)
+
+	// the signature of one key doesn't verify with another
+	forged := []byte("k:" + key2.KeyID + ":metakey1")
+	assert.False(t, k.Verify(meta, forged))
+
+	keys, err := k.ListKeys()
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+	assert.Equal(t, key2.KeyID, keys[0].KeyID)
+	assert.True(t, keys[0].Active)
+	assert.Equal(t, key1.KeyID, keys[1].KeyID)
+	assert.False(t, keys[1].Active)
+
+	err = k.RemoveKey(key2.KeyID)
+	assert.Error(t, err)
+	err = k.RemoveKey("unknown")
+	assert.Error(t, err)
+	err = k.RemoveKey(key1.KeyID)
+	assert.NoError(t, err)
+	assert.False(t, k.Verify(meta, sign1))
+	assert.True(t, k.Verify(meta, sign2))
+}
+
+func TestKeyring_Reload(t *testing.T) {
+	sto := &memStorage{}
+	alg := &appendAlg{}
+	k1 := NewKeyring(alg, sto, time.Minute, nil, nil)
+	k2 := NewKeyring(alg, sto, time.Minute, nil, nil)
+	meta := []byte("meta")
+
+	key1, err := k1.RotateKey()
+	assert.NoError(t, err)
+	sign1, err := k1.Signature(meta)
+	assert.NoError(t, err)
+	assert.True(t, k2.Verify(meta, sign1))
+
+	// the key rotated by another instance is loaded when it's unknown
+	key2, err := k1.RotateKey()
+	assert.NoError(t, err)
+	sign2, err := k1.Signature(meta)
+	assert.NoError(t, err)
+	k2.loadTime = time.Now().Add(-minReloadInterval)
+	assert.True(t, k2.Verify(meta, sign2))
+	id, err := k2.ActiveKeyID()
+	assert.NoError(t, err)
+	assert.Equal(t, key2.KeyID, id)
+	assert.NotEqual(t, key1.KeyID, id)
+
+	// the loaded keys are used if storage fails
+	sto.err = fmt.Errorf("failed")
+	k2.loadTime = time.Time{}
+	assert.True(t, k2.Verify(meta, sign1))
+	_, err = k2.RotateKey()
+	assert.Error(t, err)
+
+	k3 := NewKeyring(alg, sto, time.Minute, nil, nil)
+	assert.False(t, k3.Verify(meta, sign1))
+	_, err = k3.ListKeys()
+	assert.Error(t, err)
+}
+
+func TestParseSignature(t *testing.T) {
+	id, raw, ok := parseSignature([]byte("k:abc:sig:with:colon"))
+	assert.True(t, ok)
+	assert.Equal(t, "abc", id)
+	assert.Equal(t, "sig:with:colon", string(raw))
+
+	for _, s := range []string{"", "k:", "k::sig", "abc:sig", "k:abc"} {
+		_, _, ok = parseSignature([]byte(s))
+		assert.False(t, ok, s)
+	}
+}
@@ -2,18 +2,27 @@ package sign
 
 import (
 	"crypto/rsa"
+	"errors"
 	"io/ioutil"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/common/util"
 	"github.com/baetyl/baetyl-cloud/v2/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/sign/keyring"
 )
 
+const rsaKeyBits = 2048
+
+var ErrPlugin = errors.New("plugin type conversion error")
+
 type rsaSign struct {
-	cfg  CloudConfig
-	priv *rsa.PrivateKey
+	*keyring.Keyring
+	cfg CloudConfig
 }
 
+// rsaAlgorithm signs with PKCS1v15, the keys are PEM encoded
+type rsaAlgorithm struct{}
+
 func init() {
 	plugin.RegisterFactory("rsasign", New)
 }
@@ -24,6 +33,19 @@ func New() (plugin.Plugin, error) {
 	if err := common.LoadConfig(&cfg); err != nil {
 		return nil, err
 	}
+	sto, kms, err := keyring.LoadStorage(cfg.RSASign.Persistent, cfg.RSASign.KMS)
+	if err != nil {
+		return nil, err
+	}
+	alg := rsaAlgorithm{}
+	k := keyring.NewKeyring(alg, sto, cfg.RSASign.RefreshInterval, nil, nil).WithKMS(kms)
+	if cfg.RSASign.RetireLegacy {
+		if err = k.EnsureKey(); err != nil {
+			return nil, err
+		}
+		return &rsaSign{Keyring: k, cfg: cfg}, nil
+	}
+	// the key of keyFile signs the tokens without key id, which were issued before the keys were rotated
 	key, err := ioutil.ReadFile(cfg.RSASign.KeyFile)
 	if err != nil {
 		return nil, err
@@ -33,20 +55,36 @@ func New() (plugin.Plugin, error) {
 		return nil, err
 	}
 	return &rsaSign{
-		cfg:  cfg,
-		priv: priv,
+		Keyring: keyring.NewKeyring(alg, sto, cfg.RSASign.RefreshInterval, alg, priv).WithKMS(kms),
+		cfg:     cfg,
 	}, nil
 }
 
-func (r *rsaSign) Signature(meta []byte) ([]byte, error) {
-	return util.SignPKCS1v15(meta, r.priv)
+// Close Close
+func (r *rsaSign) Close() error {
+	return nil
 }
 
-func (r *rsaSign) Verify(meta, sign []byte) bool {
-	return util.VerifyPKCS1v15(meta, sign, &r.priv.PublicKey)
+func (rsaAlgorithm) Name() string {
+	return "rsa"
 }
 
-// Close Close
-func (r *rsaSign) Close() error {
-	return nil
+func (rsaAlgorithm) GenerateKey() ([]byte, error) {
+	priv, _, err := util.GenerateKeyPair(rsaKeyBits)
+	if err != nil {
+		return nil, err
+	}
+	return util.PrivateKeyToBytes(priv), nil
+}
+
+func (rsaAlgorithm) ParseKey(key []byte) (interface{}, error) {
+	return util.BytesToPrivateKey(key)
+}
+
+func (rsaAlgorithm) Sign(key interface{}, meta []byte) ([]byte, error) {
+	return util.SignPKCS1v15(meta, key.(*rsa.PrivateKey))
+}
+
+func (rsaAlgorithm) Verify(key interface{}, meta, sign []byte) bool {
+	return util.VerifyPKCS1v15(meta, sign, &key.(*rsa.PrivateKey).PublicKey)
 }
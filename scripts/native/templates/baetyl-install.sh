@@ -5,6 +5,7 @@ DEPLOYYML="{{.InitApplyYaml}}"
 DB_PATH='{{.DBPath}}'
 TOKEN="{{.Token}}"
 MODE='{{.Mode}}'
+OFFLINE='{{.Offline}}'
 SUDO=sudo
 
 exec_cmd_nobail() {	
@@ -54,14 +55,24 @@ kube_apply() {
 
 install_baetyl() {
   dbfile_clean
+  # the offline bundle carries the init resource beside this script
+  APPLY_FILE="$(cd "$(dirname "$0")" && pwd)/$DEPLOYYML"
   if [ $MODE = "kube" ]; then
     print_status "baetyl install in k8s mode"
     kube_clean
-    kube_apply "$ADDR/v1/init/$DEPLOYYML?token=$TOKEN"
+    if [ "$OFFLINE" = "true" ]; then
+      exec_cmd_nobail "kubectl apply -f $APPLY_FILE" $SUDO
+    else
+      kube_apply "$ADDR/v1/init/$DEPLOYYML?token=$TOKEN"
+    fi
   elif [ $MODE = "native" ]; then
     print_status "baetyl install in native mode"
     exec_cmd_nobail "baetyl delete" $SUDO
-    exec_cmd_nobail "baetyl apply -f '$ADDR/v1/init/$DEPLOYYML?token=$TOKEN' --skip-verify=true" $SUDO
+    if [ "$OFFLINE" = "true" ]; then
+      exec_cmd_nobail "baetyl apply -f '$APPLY_FILE'" $SUDO
+    else
+      exec_cmd_nobail "baetyl apply -f '$ADDR/v1/init/$DEPLOYYML?token=$TOKEN' --skip-verify=true" $SUDO
+    fi
   else
     print_status "Not supported install mode $MODE"
     exit 0
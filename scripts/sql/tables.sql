@@ -48,6 +48,8 @@ CREATE TABLE IF NOT EXISTS `baetyl_callback` (
   `body` varchar(2048) NOT NULL DEFAULT '{}' COMMENT 'body',
   `header` varchar(1024) NOT NULL DEFAULT '{}' COMMENT 'header',
   `url` varchar(1024) NOT NULL DEFAULT '' COMMENT 'url',
+  `events` varchar(1024) NOT NULL DEFAULT '[]' COMMENT '订阅的事件',
+  `secret` varchar(128) NOT NULL DEFAULT '' COMMENT '请求签名的密钥',
   `description` varchar(1024) NOT NULL DEFAULT '' COMMENT '描述信息',
   `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '记录创建时间',
   `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '记录更新时间',
@@ -55,6 +57,26 @@ CREATE TABLE IF NOT EXISTS `baetyl_callback` (
   UNIQUE KEY `unique_ns_name` (`namespace`,`name`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='回调';
 
+CREATE TABLE IF NOT EXISTS `baetyl_callback_delivery` (
+  `id` bigint(20) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'ID,主键',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT '投递记录uuid',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `callback` varchar(128) NOT NULL DEFAULT '' COMMENT '回调名称',
+  `event_id` varchar(255) NOT NULL DEFAULT '' COMMENT '事件ID',
+  `event` text COMMENT '事件内容',
+  `status` varchar(32) NOT NULL DEFAULT '' COMMENT 'Pending/Succeeded/Failed',
+  `attempts` int(11) NOT NULL DEFAULT '0' COMMENT '投递次数',
+  `response_code` int(11) NOT NULL DEFAULT '0' COMMENT '最近一次投递的响应码',
+  `reason` varchar(1024) NOT NULL DEFAULT '' COMMENT '最近一次投递的错误',
+  `next_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '下次投递时间',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_name` (`namespace`,`name`),
+  KEY `idx_event` (`namespace`,`callback`,`event_id`),
+  KEY `idx_next_time` (`status`,`next_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='回调投递记录';
+
 CREATE TABLE IF NOT EXISTS `baetyl_index_application_config` (
   `id` bigint(20) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'ID,主键',
   `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
@@ -162,4 +184,99 @@ CREATE TABLE IF NOT EXISTS `baetyl_cron_app` (
   PRIMARY KEY (`id`),
   UNIQUE KEY `unique_name` (`namespace`,`name`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='cron app table';
+
+CREATE TABLE IF NOT EXISTS `baetyl_install_token` (
+  `id` bigint(20) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'ID,主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `name` varchar(64) NOT NULL DEFAULT '' COMMENT 'token id',
+  `node_name` varchar(128) NOT NULL DEFAULT '' COMMENT '节点名称',
+  `single_use` tinyint(4) NOT NULL DEFAULT '0' COMMENT '是否一次性使用',
+  `revoked` tinyint(4) NOT NULL DEFAULT '0' COMMENT '是否已吊销',
+  `consume_ip` varchar(64) NOT NULL DEFAULT '' COMMENT '首次使用的IP',
+  `consume_time` timestamp NOT NULL DEFAULT '2007-01-01 00:00:00' COMMENT '首次使用时间',
+  `use_count` int(11) NOT NULL DEFAULT '0' COMMENT '使用次数',
+  `expire_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '过期时间',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_name` (`namespace`,`name`),
+  KEY `idx_node` (`namespace`,`node_name`),
+  KEY `idx_expire_time` (`expire_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='节点安装token';
+
+CREATE TABLE IF NOT EXISTS `baetyl_install_token_consume` (
+  `id` bigint(20) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'ID,主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `name` varchar(64) NOT NULL DEFAULT '' COMMENT 'token id',
+  `consume_ip` varchar(64) NOT NULL DEFAULT '' COMMENT '使用的IP',
+  `accepted` tinyint(4) NOT NULL DEFAULT '0' COMMENT '是否接受',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '使用时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_token` (`namespace`,`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='节点安装token使用记录';
+
+CREATE TABLE IF NOT EXISTS `baetyl_app_history` (
+  `id` bigint(20) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'ID,主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT '应用名称',
+  `revision` bigint(20) NOT NULL DEFAULT '0' COMMENT '修订号',
+  `version` varchar(36) NOT NULL DEFAULT '' COMMENT '应用版本',
+  `content` mediumtext COMMENT '应用内容',
+  `configs` text COMMENT '引用的配置版本',
+  `secrets` text COMMENT '引用的secret版本',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_revision` (`namespace`,`name`,`revision`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='应用历史版本';
+
+CREATE TABLE IF NOT EXISTS `baetyl_app_rollout` (
+  `id` bigint(20) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'ID,主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT '应用名称',
+  `version` varchar(36) NOT NULL DEFAULT '' COMMENT '发布的应用版本',
+  `previous_version` varchar(36) NOT NULL DEFAULT '' COMMENT '发布前的应用版本',
+  `status` varchar(32) NOT NULL DEFAULT '' COMMENT '发布状态',
+  `reason` varchar(1024) NOT NULL DEFAULT '' COMMENT '失败原因',
+  `strategy` varchar(1024) NOT NULL DEFAULT '' COMMENT '分批发布策略',
+  `current_wave` int(10) NOT NULL DEFAULT '0' COMMENT '当前批次',
+  `waves` mediumtext COMMENT '批次信息',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_name` (`namespace`,`name`),
+  KEY `idx_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='应用分批发布';
+
+CREATE TABLE IF NOT EXISTS `baetyl_recycle_bin` (
+  `id` bigint(20) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'ID,主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `kind` varchar(32) NOT NULL DEFAULT '' COMMENT '资源类型',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT '资源名称',
+  `content` mediumtext COMMENT '被删除的资源内容',
+  `delete_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '删除时间',
+  `expire_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '过期时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_namespace_kind` (`namespace`,`kind`),
+  KEY `idx_expire_time` (`expire_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='资源回收站';
+
+CREATE TABLE IF NOT EXISTS `baetyl_data_key` (
+  `id` bigint(20) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'ID,主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `key_id` varchar(128) NOT NULL DEFAULT '' COMMENT '主密钥ID',
+  `ciphertext` varchar(1024) NOT NULL DEFAULT '' COMMENT '主密钥加密的数据密钥',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_namespace` (`namespace`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='命名空间数据密钥';
+
+CREATE TABLE IF NOT EXISTS `baetyl_sign_key` (
+  `id` bigint(20) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'ID,主键',
+  `key_id` varchar(64) NOT NULL DEFAULT '' COMMENT '签名密钥ID',
+  `algorithm` varchar(64) NOT NULL DEFAULT '' COMMENT '签名算法',
+  `private_key` text COMMENT '签名私钥',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_algorithm_key_id` (`algorithm`,`key_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='签名密钥';
 COMMIT;
@@ -2,13 +2,16 @@ package server
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/baetyl/baetyl-go/v2/cache"
 	"github.com/baetyl/baetyl-go/v2/cache/persist"
 	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/json"
 	"github.com/baetyl/baetyl-go/v2/log"
 	"github.com/gin-gonic/gin"
 
@@ -114,11 +117,17 @@ func (s *AdminServer) InitRoute() {
 		configs.POST("", common.WrapperRaw(s.api.ValidateResourceForCreating, true), common.Wrapper(s.api.CreateConfig))
 		configs.GET("", s.WrapperCache(s.api.ListConfig))
 		configs.GET("/:name/apps", common.Wrapper(s.api.GetAppByConfig))
+		configs.POST("/:name/objects", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.UploadConfigObject))
+		configs.POST("/:name/uploads", common.Wrapper(s.api.InitConfigUpload))
+		configs.GET("/:name/uploads/:id", common.Wrapper(s.api.GetConfigUpload))
+		configs.PUT("/:name/uploads/:id/chunks/:index", common.Wrapper(s.api.PutConfigUploadChunk))
+		configs.POST("/:name/uploads/:id/complete", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.CompleteConfigUpload))
+		configs.DELETE("/:name/uploads/:id", common.Wrapper(s.api.AbortConfigUpload))
 	}
 	{
 		registry := v1.Group("/registries")
 		registry.GET("/:name", common.Wrapper(s.api.GetRegistry))
-		registry.PUT("/:name", common.Wrapper(s.api.UpdateRegistry))
+		registry.PUT("/:name", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.UpdateRegistry))
 		registry.POST("/:name/refresh", common.Wrapper(s.api.RefreshRegistryPassword))
 		registry.DELETE("/:name", common.WrapperRaw(s.api.ValidateResourceForDeleting, true), common.Wrapper(s.api.DeleteRegistry))
 		registry.POST("", common.WrapperRaw(s.api.ValidateResourceForCreating, true), common.Wrapper(s.api.CreateRegistry))
@@ -133,11 +142,12 @@ func (s *AdminServer) InitRoute() {
 		certificate.POST("", common.WrapperRaw(s.api.ValidateResourceForCreating, true), common.Wrapper(s.api.CreateCertificate))
 		certificate.GET("", s.WrapperCache(s.api.ListCertificate))
 		certificate.GET("/:name/apps", common.Wrapper(s.api.GetAppByCertificate))
+		certificate.GET("/expiring", common.Wrapper(s.api.ListExpiringCertificates))
 	}
 	{
 		secrets := v1.Group("/secrets")
 		secrets.GET("/:name", common.Wrapper(s.api.GetSecret))
-		secrets.PUT("/:name", common.Wrapper(s.api.UpdateSecret))
+		secrets.PUT("/:name", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.UpdateSecret))
 		secrets.DELETE("/:name", common.WrapperRaw(s.api.ValidateResourceForDeleting, true), common.Wrapper(s.api.DeleteSecret))
 		secrets.POST("", common.WrapperRaw(s.api.ValidateResourceForCreating, true), common.Wrapper(s.api.CreateSecret))
 		secrets.GET("", s.WrapperCache(s.api.ListSecret))
@@ -155,7 +165,11 @@ func (s *AdminServer) InitRoute() {
 		nodes.POST("", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), s.NodeQuotaHandler, common.Wrapper(s.api.CreateNode))
 		nodes.GET("", s.WrapperCache(s.api.ListNode))
 		nodes.GET("/:name/deploys", s.WrapperCache(s.api.GetNodeDeployHistory))
-		nodes.GET("/:name/init", s.WrapperCache(s.api.GenInitCmdFromNode))
+		nodes.GET("/:name/init", common.Wrapper(s.api.GenInitCmdFromNode))
+		nodes.GET("/:name/tokens", common.Wrapper(s.api.ListNodeInstallTokens))
+		nodes.GET("/:name/bundle", common.WrapperRaw(s.api.GetNodeOfflineBundle, true))
+		nodes.POST("/:name/tokens/:token/revoke", common.Wrapper(s.api.RevokeNodeInstallToken))
+		nodes.GET("/:name/tokens/:token/consumes", common.Wrapper(s.api.ListNodeInstallTokenConsumes))
 		nodes.PUT("/:name/mode", common.Wrapper(s.api.UpdateNodeMode))
 		nodes.PUT("/:name/properties", common.Wrapper(s.api.UpdateNodeProperties))
 		nodes.GET("/:name/properties", s.WrapperCache(s.api.GetNodeProperties))
@@ -163,6 +177,29 @@ func (s *AdminServer) InitRoute() {
 		nodes.GET("/:name/core/configs", s.WrapperCache(s.api.GetCoreAppConfigs))
 		nodes.GET("/:name/core/versions", s.WrapperCache(s.api.GetCoreAppVersions))
 	}
+	{
+		batches := v1.Group("/batches")
+		batches.GET("/:name", common.Wrapper(s.api.GetBatch))
+		batches.PUT("/:name", common.Wrapper(s.api.UpdateBatch))
+		batches.DELETE("/:name", common.Wrapper(s.api.DeleteBatch))
+		batches.POST("", common.WrapperRaw(s.api.ValidateResourceForCreating, true), common.Wrapper(s.api.CreateBatch))
+		batches.GET("", common.Wrapper(s.api.ListBatch))
+		batches.GET("/:name/records", common.Wrapper(s.api.ListBatchRecords))
+		batches.POST("/:name/records", common.Wrapper(s.api.ImportBatchRecords))
+		batches.GET("/:name/records/:record", common.Wrapper(s.api.GetBatchRecord))
+		batches.DELETE("/:name/records/:record", common.Wrapper(s.api.DeleteBatchRecord))
+	}
+	{
+		callbacks := v1.Group("/callbacks")
+		callbacks.GET("/:name", common.Wrapper(s.api.GetCallback))
+		callbacks.PUT("/:name", common.Wrapper(s.api.UpdateCallback))
+		callbacks.DELETE("/:name", common.Wrapper(s.api.DeleteCallback))
+		callbacks.POST("", common.WrapperRaw(s.api.ValidateResourceForCreating, true), common.Wrapper(s.api.CreateCallback))
+		callbacks.GET("", common.Wrapper(s.api.ListCallback))
+		callbacks.GET("/:name/deliveries", common.Wrapper(s.api.ListCallbackDeliveries))
+		callbacks.GET("/:name/deliveries/:delivery", common.Wrapper(s.api.GetCallbackDelivery))
+		callbacks.POST("/:name/deliveries/:delivery/redeliver", common.Wrapper(s.api.RedeliverCallbackDelivery))
+	}
 	{
 		apps := v1.Group("/apps")
 		apps.GET("/:name", s.WrapperCache(s.api.GetApplication))
@@ -172,9 +209,25 @@ func (s *AdminServer) InitRoute() {
 		apps.GET("/:name/registries", s.WrapperCache(s.api.GetSysAppRegistries))
 		apps.PUT("/:name", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.UpdateApplication))
 		apps.DELETE("/:name", common.WrapperRaw(s.api.ValidateResourceForDeleting, true), common.Wrapper(s.api.DeleteApplication))
+		apps.GET("/:name/revisions", common.Wrapper(s.api.ListAppRevisions))
+		apps.GET("/:name/revisions/diff", common.Wrapper(s.api.DiffAppRevisions))
+		apps.POST("/:name/rollback", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.RollbackApplication))
+		apps.GET("/:name/rollout", common.Wrapper(s.api.GetAppRollout))
+		apps.PUT("/:name/rollout", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.SetAppRolloutStrategy))
+		apps.DELETE("/:name/rollout", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.DeleteAppRolloutStrategy))
+		apps.GET("/:name/status", common.Wrapper(s.api.GetAppNodeStatus))
+		apps.POST("/status", common.Wrapper(s.api.GetAppsNodeStatus))
+		apps.GET("/:name/bundle", common.Wrapper(s.api.ExportAppBundle))
+		apps.POST("/bundle", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.ImportAppBundle))
 		apps.POST("", common.WrapperRaw(s.api.ValidateResourceForCreating, true), common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.CreateApplication))
 		apps.GET("", s.WrapperCache(s.api.ListApplication))
 	}
+	{
+		recycleBin := v1.Group("/recyclebin")
+		recycleBin.GET("", common.Wrapper(s.api.ListRecycleItems))
+		recycleBin.POST("/:id/restore", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.RestoreRecycleItem))
+		recycleBin.DELETE("/:id", common.Wrapper(s.api.DeleteRecycleItem))
+	}
 	{
 		namespace := v1.Group("/namespace")
 		namespace.POST("", common.Wrapper(s.api.CreateNamespace))
@@ -256,6 +309,9 @@ func (s *AdminServer) InitRoute() {
 		yaml.POST("", common.Wrapper(s.api.CreateYamlResource))
 		yaml.PUT("", common.Wrapper(s.api.UpdateYamlResource))
 		yaml.POST("/delete", common.Wrapper(s.api.DeleteYamlResource))
+		yaml.GET("/apps/:name", common.WrapperRaw(s.api.ExportYamlApplication, true))
+		yaml.POST("/apply", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.ApplyYamlResource))
+		yaml.POST("/helm", common.WrapperWithLock(s.api.Locker.Lock, s.api.Locker.Unlock), common.Wrapper(s.api.ImportHelmChart))
 	}
 
 	v2 := s.GetV2RouterGroup()
@@ -295,15 +351,41 @@ func (s *AdminServer) AuthHandler(c *gin.Context) {
 	cc := common.NewContext(c)
 	err := s.Auth.Authenticate(cc)
 	if err != nil {
+		// the credential isn't logged, only its scheme and the key id of token
+		scheme, kid := parseAuthorization(c.Request.Header.Get("Authorization"))
 		s.log.Error("request authenticate failed",
 			log.Any(cc.GetTrace()),
 			log.Any("namespace", cc.GetNamespace()),
-			log.Any("authorization", c.Request.Header.Get("Authorization")),
+			log.Any("scheme", scheme),
+			log.Any("kid", kid),
 			log.Error(err))
 		common.PopulateFailedResponse(cc, common.Error(common.ErrRequestAccessDenied, common.Field("error", err)), true)
 	}
 }
 
+// parseAuthorization returns the scheme of authorization header, and the key id in the header of token if it's a jwt
+func parseAuthorization(header string) (string, string) {
+	parts := strings.SplitN(strings.TrimSpace(header), " ", 2)
+	if len(parts) < 2 {
+		return "", ""
+	}
+	segments := strings.Split(strings.TrimSpace(parts[1]), ".")
+	if len(segments) != 3 {
+		return parts[0], ""
+	}
+	data, err := base64.RawURLEncoding.DecodeString(segments[0])
+	if err != nil {
+		return parts[0], ""
+	}
+	var h struct {
+		Kid string `json:"kid"`
+	}
+	if err = json.Unmarshal(data, &h); err != nil {
+		return parts[0], ""
+	}
+	return parts[0], h.Kid
+}
+
 func (s *AdminServer) NodeQuotaHandler(c *gin.Context) {
 	cc := common.NewContext(c)
 	namespace := cc.GetNamespace()
@@ -2,6 +2,7 @@ package server
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -44,6 +45,12 @@ func initAdminServerMock(t *testing.T) (*AdminServer, *mockPlugin.MockAuth, *moc
 	c.Plugin.Tx = common.RandString(9)
 	c.Plugin.Sign = common.RandString(9)
 	c.Plugin.Cron = common.RandString(9)
+	c.Plugin.Rollout = common.RandString(9)
+	c.Plugin.RecycleBin = common.RandString(9)
+	c.Plugin.Callback = common.RandString(9)
+	c.Plugin.InstallToken = common.RandString(9)
+	c.Plugin.Batch = common.RandString(9)
+	c.Plugin.Record = common.RandString(9)
 	c.Plugin.Cache = common.RandString(9)
 	mockCtl := gomock.NewController(t)
 
@@ -127,6 +134,34 @@ func initAdminServerMock(t *testing.T) (*AdminServer, *mockPlugin.MockAuth, *moc
 	plugin.RegisterFactory(c.Plugin.Cron, func() (plugin.Plugin, error) {
 		return mockCronApp, nil
 	})
+	mockAppHistory := mockPlugin.NewMockAppHistory(mockCtl)
+	plugin.RegisterFactory(c.Plugin.AppHistory, func() (plugin.Plugin, error) {
+		return mockAppHistory, nil
+	})
+	mockRollout := mockPlugin.NewMockRollout(mockCtl)
+	plugin.RegisterFactory(c.Plugin.Rollout, func() (plugin.Plugin, error) {
+		return mockRollout, nil
+	})
+	mockRecycleBin := mockPlugin.NewMockRecycleBin(mockCtl)
+	plugin.RegisterFactory(c.Plugin.RecycleBin, func() (plugin.Plugin, error) {
+		return mockRecycleBin, nil
+	})
+	mockCallback := mockPlugin.NewMockCallback(mockCtl)
+	plugin.RegisterFactory(c.Plugin.Callback, func() (plugin.Plugin, error) {
+		return mockCallback, nil
+	})
+	mockInstallToken := mockPlugin.NewMockInstallToken(mockCtl)
+	plugin.RegisterFactory(c.Plugin.InstallToken, func() (plugin.Plugin, error) {
+		return mockInstallToken, nil
+	})
+	mockBatch := mockPlugin.NewMockBatch(mockCtl)
+	plugin.RegisterFactory(c.Plugin.Batch, func() (plugin.Plugin, error) {
+		return mockBatch, nil
+	})
+	mockRecord := mockPlugin.NewMockRecord(mockCtl)
+	plugin.RegisterFactory(c.Plugin.Record, func() (plugin.Plugin, error) {
+		return mockRecord, nil
+	})
 	mockCache := mockPlugin.NewMockDataCache(mockCtl)
 	plugin.RegisterFactory(c.Plugin.Cache, func() (plugin.Plugin, error) {
 		return mockCache, nil
@@ -294,3 +329,16 @@ func TestAdminServer_Handler(t *testing.T) {
 	go s.Run()
 	defer s.Close()
 }
+
+func TestParseAuthorization(t *testing.T) {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","kid":"k1"}`))
+	scheme, kid := parseAuthorization("Bearer " + header + ".eyJzdWIiOiJ1MSJ9.c2lnbg")
+	assert.Equal(t, "Bearer", scheme)
+	assert.Equal(t, "k1", kid)
+	scheme, kid = parseAuthorization("Basic dXNlcjpwYXNz")
+	assert.Equal(t, "Basic", scheme)
+	assert.Equal(t, "", kid)
+	scheme, kid = parseAuthorization("secret")
+	assert.Equal(t, "", scheme)
+	assert.Equal(t, "", kid)
+}
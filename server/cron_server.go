@@ -0,0 +1,122 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+
+	"github.com/baetyl/baetyl-cloud/v2/api"
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+)
+
+// CronJobFunc is run periodically, the failed job is retried in next period
+type CronJobFunc func() error
+
+type cronJob struct {
+	name string
+	gap  time.Duration
+	run  CronJobFunc
+}
+
+// CronServer runs the cron jobs, each job runs on only one replica at a time through the locker
+type CronServer struct {
+	cfg  *config.CloudConfig
+	api  *api.API
+	jobs map[string]CronJobFunc
+	stop chan struct{}
+	wg   sync.WaitGroup
+	log  *log.Logger
+}
+
+// NewCronServer create cron server
+func NewCronServer(config *config.CloudConfig) (*CronServer, error) {
+	for _, c := range config.CronJobs {
+		if _, err := time.ParseDuration(c.CronGap); err != nil {
+			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "invalid cronGap of cron job "+c.CronName))
+		}
+	}
+	return &CronServer{
+		cfg:  config,
+		jobs: map[string]CronJobFunc{},
+		stop: make(chan struct{}),
+		log:  log.L().With(log.Any("server", "cron")),
+	}, nil
+}
+
+func (s *CronServer) SetAPI(api *api.API) {
+	s.api = api
+}
+
+// InitJobs registers the builtin cron jobs
+func (s *CronServer) InitJobs() {
+	s.AddJob(api.CronApp, s.api.CronApp)
+	s.AddJob(api.AppRollout, s.api.RolloutApp)
+	s.AddJob(api.RecycleBin, s.api.PurgeRecycleBin)
+	s.AddJob(api.CertificateExpiry, s.api.NotifyCertificateExpiry)
+	s.AddJob(api.WebhookDelivery, s.api.DeliverWebhook)
+	s.AddJob(api.NodeOffline, s.api.NotifyNodeOffline)
+	s.AddJob(api.InstallTokenExpiry, s.api.PurgeInstallToken)
+	s.AddJob(api.DataKeyExpiry, s.api.PurgeDataKeys)
+}
+
+// AddJob registers a cron job, it only runs if configured in cronJobs
+func (s *CronServer) AddJob(name string, job CronJobFunc) {
+	s.jobs[name] = job
+}
+
+// Run starts all configured cron jobs
+func (s *CronServer) Run() {
+	for _, c := range s.cfg.CronJobs {
+		job, ok := s.jobs[c.CronName]
+		if !ok {
+			s.log.Warn("cron job not found", log.Any("name", c.CronName))
+			continue
+		}
+		gap, _ := time.ParseDuration(c.CronGap)
+		s.wg.Add(1)
+		go s.loop(&cronJob{name: c.CronName, gap: gap, run: job})
+		s.log.Info("cron job starting", log.Any("name", c.CronName), log.Any("gap", c.CronGap))
+	}
+}
+
+// Close stops all cron jobs
+func (s *CronServer) Close() {
+	close(s.stop)
+	s.wg.Wait()
+}
+
+func (s *CronServer) loop(job *cronJob) {
+	defer s.wg.Done()
+	ticker := time.NewTicker(job.gap)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.runOnce(job)
+		}
+	}
+}
+
+func (s *CronServer) runOnce(job *cronJob) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.log.Error("cron job panic", log.Any("name", job.name), log.Any("panic", r))
+		}
+	}()
+	ctx := context.Background()
+	lockName := "cron_" + job.name
+	version, err := s.api.Locker.Lock(ctx, lockName, int64(job.gap/time.Second))
+	if err != nil {
+		s.log.Debug("failed to lock cron job", log.Any("name", job.name), log.Error(err))
+		return
+	}
+	defer s.api.Locker.Unlock(ctx, lockName, version)
+	if err = job.run(); err != nil {
+		s.log.Error("failed to run cron job, retry later", log.Any("name", job.name), log.Error(err))
+	}
+}
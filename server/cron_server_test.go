@@ -0,0 +1,55 @@
+package server
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/api"
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+)
+
+func TestCronServer(t *testing.T) {
+	c := &config.CloudConfig{}
+	c.CronJobs = []config.CronJob{{CronName: "invalid", CronGap: "x"}}
+	_, err := NewCronServer(c)
+	assert.Error(t, err)
+
+	c.CronJobs = []config.CronJob{
+		{CronName: "test", CronGap: "10ms"},
+		{CronName: "unknown", CronGap: "10ms"},
+	}
+	s, err := NewCronServer(c)
+	assert.NoError(t, err)
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	mLocker := ms.NewMockLockerService(mockCtl)
+	s.SetAPI(&api.API{Locker: mLocker})
+
+	mLocker.EXPECT().Lock(gomock.Any(), "cron_test", int64(0)).Return("", common.Error(common.ErrDatabase)).Times(1)
+	mLocker.EXPECT().Lock(gomock.Any(), "cron_test", int64(0)).Return("v1", nil).MinTimes(2)
+	mLocker.EXPECT().Unlock(gomock.Any(), "cron_test", "v1").MinTimes(2)
+
+	var count int32
+	done := make(chan struct{})
+	s.AddJob("test", func() error {
+		if atomic.AddInt32(&count, 1) == 2 {
+			close(done)
+		}
+		// the failed job is retried in next period
+		return common.Error(common.ErrDatabase)
+	})
+	s.Run()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("cron job not retried")
+	}
+	s.Close()
+}
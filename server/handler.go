@@ -2,15 +2,19 @@ package server
 
 import (
 	"bytes"
+	"crypto/x509"
 	"io"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/baetyl/baetyl-go/v2/pki"
 	"github.com/gin-gonic/gin"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
 )
 
 var (
@@ -64,16 +68,91 @@ func Health(c *gin.Context) {
 	c.JSON(common.PackageResponse(nil))
 }
 
-func ExtractNodeCommonNameFromCert(c *gin.Context) {
+// NodeCertHandler extracts the node name from the client certificate, the issuers of the certificates issued by the
+// intermediate cas of namespaces are checked against the cas in pki, which are cached for caCacheTTL, so the
+// certificates of a revoked or recreated namespace are rejected within caCacheTTL
+type NodeCertHandler struct {
+	pki   plugin.PKI
+	cache map[string]cachedNamespaceCA
+	mu    sync.Mutex
+}
+
+type cachedNamespaceCA struct {
+	ca   *x509.Certificate
+	time time.Time
+}
+
+const caCacheTTL = time.Minute
+
+// NewNodeCertHandler new node cert handler
+func NewNodeCertHandler(p plugin.PKI) *NodeCertHandler {
+	return &NodeCertHandler{
+		pki:   p,
+		cache: map[string]cachedNamespaceCA{},
+	}
+}
+
+func (h *NodeCertHandler) ExtractNodeCommonNameFromCert(c *gin.Context) {
 	cc := common.NewContext(c)
 	if len(c.Request.TLS.PeerCertificates) == 0 {
 		common.PopulateFailedResponse(cc, common.Error(common.ErrRequestAccessDenied), true)
 		return
 	}
 	cert := c.Request.TLS.PeerCertificates[0]
+	if !checkNodeIssuer(cert, h.getNamespaceCA) {
+		log.L().Error("the node certificate is not issued by the ca of its namespace",
+			log.Any(cc.GetTrace()),
+			log.Any("commonName", cert.Subject.CommonName),
+			log.Any("issuer", cert.Issuer.CommonName))
+		common.PopulateFailedResponse(cc, common.Error(common.ErrRequestAccessDenied), true)
+		return
+	}
 	extractNodeCommonName(cc, cert.Subject.CommonName)
 }
 
+// getNamespaceCA returns the intermediate ca of namespace in pki, nil if it's not found
+func (h *NodeCertHandler) getNamespaceCA(namespace string) *x509.Certificate {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if c, ok := h.cache[namespace]; ok && time.Since(c.time) < caCacheTTL {
+		return c.ca
+	}
+	var ca *x509.Certificate
+	data, err := h.pki.GetNamespaceCert(namespace)
+	if err == nil {
+		var certs []*x509.Certificate
+		if certs, err = pki.ParseCertificates(data); err == nil && len(certs) == 1 {
+			ca = certs[0]
+		}
+	}
+	if ca == nil {
+		log.L().Warn("the intermediate ca of namespace is not found", log.Any("namespace", namespace), log.Error(err))
+	}
+	h.cache[namespace] = cachedNamespaceCA{ca: ca, time: time.Now()}
+	return ca
+}
+
+// checkNodeIssuer checks the node certificate issued by the intermediate ca of namespace belongs to the same namespace,
+// and is signed by the current ca of namespace in pki rather than the chain sent by client, so the certificates of the
+// revoked cas are rejected. The certificates issued by the root ca directly are not restricted
+func checkNodeIssuer(cert *x509.Certificate, getCA func(namespace string) *x509.Certificate) bool {
+	if !strings.HasSuffix(cert.Issuer.CommonName, common.NamespaceCASuffix) {
+		return true
+	}
+	ns := strings.TrimSuffix(cert.Issuer.CommonName, common.NamespaceCASuffix)
+	if !strings.HasPrefix(cert.Subject.CommonName, ns+".") {
+		return false
+	}
+	ca := getCA(ns)
+	if ca == nil || ca.Subject.CommonName != cert.Issuer.CommonName {
+		return false
+	}
+	if len(cert.AuthorityKeyId) > 0 && len(ca.SubjectKeyId) > 0 && !bytes.Equal(cert.AuthorityKeyId, ca.SubjectKeyId) {
+		return false
+	}
+	return cert.CheckSignatureFrom(ca) == nil
+}
+
 func ExtractNodeCommonNameFromHeader(c *gin.Context) {
 	cc := common.NewContext(c)
 	extractNodeCommonName(cc, c.GetHeader(HeaderCommonName))
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
+)
+
+func genHandlerCert(t *testing.T, cn string, isCA bool, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	tpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	if parent == nil {
+		parent, parentKey = tpl, priv
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, parent, &priv.PublicKey, parentKey)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return cert, priv
+}
+
+func pemCert(cert *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+}
+
+func TestCheckNodeIssuer(t *testing.T) {
+	root, rootKey := genHandlerCert(t, "root.ca", true, nil, nil)
+	caA, caAKey := genHandlerCert(t, "a"+common.NamespaceCASuffix, true, root, rootKey)
+	caB, _ := genHandlerCert(t, "b"+common.NamespaceCASuffix, true, root, rootKey)
+	cas := map[string]*x509.Certificate{"a": caA, "b": caB}
+	getCA := func(ns string) *x509.Certificate {
+		return cas[ns]
+	}
+
+	// the certificate issued by the root ca
+	legacy, _ := genHandlerCert(t, "a.n1", false, root, rootKey)
+	assert.True(t, checkNodeIssuer(legacy, getCA))
+
+	node, _ := genHandlerCert(t, "a.n1", false, caA, caAKey)
+	assert.True(t, checkNodeIssuer(node, getCA))
+
+	// the certificate of namespace a authenticates as namespace b
+	other, _ := genHandlerCert(t, "b.n1", false, caA, caAKey)
+	assert.False(t, checkNodeIssuer(other, getCA))
+
+	// the ca of namespace is revoked
+	delete(cas, "a")
+	assert.False(t, checkNodeIssuer(node, getCA))
+
+	// the namespace is recreated with a new ca of the same name
+	cas["a"], _ = genHandlerCert(t, "a"+common.NamespaceCASuffix, true, root, rootKey)
+	assert.False(t, checkNodeIssuer(node, getCA))
+}
+
+func TestNodeCertHandler(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	mPKI := mockPlugin.NewMockPKI(mockCtl)
+	h := NewNodeCertHandler(mPKI)
+
+	root, rootKey := genHandlerCert(t, "root.ca", true, nil, nil)
+	caA, caAKey := genHandlerCert(t, "a"+common.NamespaceCASuffix, true, root, rootKey)
+	node, _ := genHandlerCert(t, "a.n1", false, caA, caAKey)
+
+	router := gin.New()
+	router.Use(h.ExtractNodeCommonNameFromCert)
+	router.GET("/node", func(c *gin.Context) {
+		cc := common.NewContext(c)
+		c.String(http.StatusOK, cc.GetNamespace()+"/"+cc.GetName())
+	})
+	request := func(certs ...*x509.Certificate) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodGet, "/node", nil)
+		req.TLS = &tls.ConnectionState{PeerCertificates: certs}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	mPKI.EXPECT().GetNamespaceCert("a").Return(pemCert(caA), nil).Times(1)
+	w := request(node, caA)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "a/n1", w.Body.String())
+	// the ca is cached
+	w = request(node, caA)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// the namespace is deleted, the old certificate is rejected even with its chain
+	h.cache = map[string]cachedNamespaceCA{}
+	mPKI.EXPECT().GetNamespaceCert("a").Return(nil, os.ErrNotExist).Times(1)
+	w = request(node, caA)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// the namespace is recreated, the certificate issued before is rejected
+	newCA, newKey := genHandlerCert(t, "a"+common.NamespaceCASuffix, true, root, rootKey)
+	h.cache = map[string]cachedNamespaceCA{}
+	mPKI.EXPECT().GetNamespaceCert("a").Return(pemCert(newCA), nil).Times(1)
+	w = request(node, caA)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	renewed, _ := genHandlerCert(t, "a.n1", false, newCA, newKey)
+	w = request(renewed, newCA)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// no certificate
+	w = request()
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
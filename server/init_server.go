@@ -94,4 +94,7 @@ func (s *InitServer) InitRoute() {
 		initz := v1.Group("/init")
 		initz.GET("/:resource", common.WrapperRaw(s.api.GetResource, true))
 	}
+	{
+		v1.POST("/activate", common.Wrapper(s.api.Activate))
+	}
 }
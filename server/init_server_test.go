@@ -30,6 +30,8 @@ func initInitServerMock(t *testing.T) (*InitServer, *gomock.Controller, *config.
 	c.Plugin.License = common.RandString(9)
 	c.Plugin.Sign = common.RandString(9)
 	c.Plugin.Property = common.RandString(9)
+	c.Plugin.Batch = common.RandString(9)
+	c.Plugin.Record = common.RandString(9)
 	c.InitServer.Certificate.CA = "../scripts/demo/native/certs/client_ca.crt"
 	c.InitServer.Certificate.Cert = "../scripts/demo/native/certs/server.crt"
 	c.InitServer.Certificate.Key = "../scripts/demo/native/certs/server.key"
@@ -84,6 +86,15 @@ func initInitServerMock(t *testing.T) (*InitServer, *gomock.Controller, *config.
 		return mockIndex, nil
 	})
 
+	mockBatch := mockPlugin.NewMockBatch(mockCtl)
+	plugin.RegisterFactory(c.Plugin.Batch, func() (plugin.Plugin, error) {
+		return mockBatch, nil
+	})
+	mockRecord := mockPlugin.NewMockRecord(mockCtl)
+	plugin.RegisterFactory(c.Plugin.Record, func() (plugin.Plugin, error) {
+		return mockRecord, nil
+	})
+
 	mockInitAPI, err := api.NewInitAPI(c)
 	assert.NoError(t, err)
 
@@ -97,6 +97,18 @@ func (s *MisServer) InitRoute() {
 		module.DELETE("/:name", common.WrapperMis(s.api.DeleteModules))
 		module.DELETE("/:name/version/:version", common.WrapperMis(s.api.DeleteModules))
 	}
+	{
+		secret := v1.Group("/secrets")
+
+		secret.POST("/reencrypt", common.WrapperMis(s.api.ReencryptSecrets))
+	}
+	{
+		signKey := v1.Group("/signkeys")
+
+		signKey.GET("", common.WrapperMis(s.api.ListSignKeys))
+		signKey.POST("/rotate", common.WrapperMis(s.api.RotateSignKey))
+		signKey.DELETE("/:id", common.WrapperMis(s.api.RemoveSignKey))
+	}
 }
 
 // auth handler
@@ -36,6 +36,12 @@ func initMisServerMock(t *testing.T) (*MisServer, *gomock.Controller) {
 	c.Plugin.Locker = common.RandString(9)
 	c.Plugin.Tx = common.RandString(9)
 	c.Plugin.Cron = common.RandString(9)
+	c.Plugin.Rollout = common.RandString(9)
+	c.Plugin.RecycleBin = common.RandString(9)
+	c.Plugin.Callback = common.RandString(9)
+	c.Plugin.InstallToken = common.RandString(9)
+	c.Plugin.Batch = common.RandString(9)
+	c.Plugin.Record = common.RandString(9)
 	c.Plugin.Cache = common.RandString(9)
 	mockCtl := gomock.NewController(t)
 
@@ -130,6 +136,34 @@ func initMisServerMock(t *testing.T) (*MisServer, *gomock.Controller) {
 	plugin.RegisterFactory(c.Plugin.Cron, func() (plugin.Plugin, error) {
 		return mockCronApp, nil
 	})
+	mockAppHistory := mockPlugin.NewMockAppHistory(mockCtl)
+	plugin.RegisterFactory(c.Plugin.AppHistory, func() (plugin.Plugin, error) {
+		return mockAppHistory, nil
+	})
+	mockRollout := mockPlugin.NewMockRollout(mockCtl)
+	plugin.RegisterFactory(c.Plugin.Rollout, func() (plugin.Plugin, error) {
+		return mockRollout, nil
+	})
+	mockRecycleBin := mockPlugin.NewMockRecycleBin(mockCtl)
+	plugin.RegisterFactory(c.Plugin.RecycleBin, func() (plugin.Plugin, error) {
+		return mockRecycleBin, nil
+	})
+	mockCallback := mockPlugin.NewMockCallback(mockCtl)
+	plugin.RegisterFactory(c.Plugin.Callback, func() (plugin.Plugin, error) {
+		return mockCallback, nil
+	})
+	mockInstallToken := mockPlugin.NewMockInstallToken(mockCtl)
+	plugin.RegisterFactory(c.Plugin.InstallToken, func() (plugin.Plugin, error) {
+		return mockInstallToken, nil
+	})
+	mockBatch := mockPlugin.NewMockBatch(mockCtl)
+	plugin.RegisterFactory(c.Plugin.Batch, func() (plugin.Plugin, error) {
+		return mockBatch, nil
+	})
+	mockRecord := mockPlugin.NewMockRecord(mockCtl)
+	plugin.RegisterFactory(c.Plugin.Record, func() (plugin.Plugin, error) {
+		return mockRecord, nil
+	})
 
 	mockCache := mockPlugin.NewMockDataCache(mockCtl)
 	plugin.RegisterFactory(c.Plugin.Cache, func() (plugin.Plugin, error) {
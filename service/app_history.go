@@ -0,0 +1,141 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/app_history.go -package=service github.com/baetyl/baetyl-cloud/v2/service AppHistoryService
+
+// AppHistoryService records the revisions of applications
+type AppHistoryService interface {
+	Record(tx interface{}, app *specV1.Application) (*models.AppHistory, error)
+	Get(namespace, name string, revision int64) (*models.AppHistory, error)
+	GetByVersion(namespace, name, version string) (*models.AppHistory, error)
+	List(namespace, name string, listOptions *models.ListOptions) (*models.AppHistoryList, error)
+	Delete(tx interface{}, namespace, name string) error
+	// Restore recreates the revisions of the deleted application in order
+	Restore(tx interface{}, histories []models.AppHistory) error
+	Diff(namespace, name string, from, to int64) (*models.AppRevisionDiff, error)
+}
+
+type AppHistoryServiceImpl struct {
+	History plugin.AppHistory
+}
+
+// NewAppHistoryService New AppHistory Service
+func NewAppHistoryService(config *config.CloudConfig) (AppHistoryService, error) {
+	history, err := plugin.GetPlugin(config.Plugin.AppHistory)
+	if err != nil {
+		return nil, err
+	}
+	return &AppHistoryServiceImpl{
+		History: history.(plugin.AppHistory),
+	}, nil
+}
+
+// Record saves the application as a new revision, nothing is saved if the version of application is unchanged
+func (h *AppHistoryServiceImpl) Record(tx interface{}, app *specV1.Application) (*models.AppHistory, error) {
+	latest, err := h.History.GetLatestAppHistory(tx, app.Namespace, app.Name)
+	if err != nil {
+		if e, ok := err.(errors.Coder); !ok || e.Code() != common.ErrResourceNotFound {
+			return nil, err
+		}
+	}
+	if latest != nil && latest.Version == app.Version {
+		return latest, nil
+	}
+
+	history := &models.AppHistory{
+		Namespace:   app.Namespace,
+		Name:        app.Name,
+		Version:     app.Version,
+		Application: app,
+		Configs:     map[string]string{},
+		Secrets:     map[string]string{},
+	}
+	for _, v := range app.Volumes {
+		if v.Config != nil {
+			history.Configs[v.Config.Name] = v.Config.Version
+		}
+		if v.Secret != nil {
+			history.Secrets[v.Secret.Name] = v.Secret.Version
+		}
+	}
+	return h.History.CreateAppHistory(tx, history)
+}
+
+func (h *AppHistoryServiceImpl) Get(namespace, name string, revision int64) (*models.AppHistory, error) {
+	return h.History.GetAppHistory(nil, namespace, name, revision)
+}
+
+func (h *AppHistoryServiceImpl) GetByVersion(namespace, name, version string) (*models.AppHistory, error) {
+	return h.History.GetAppHistoryByVersion(nil, namespace, name, version)
+}
+
+func (h *AppHistoryServiceImpl) List(namespace, name string, listOptions *models.ListOptions) (*models.AppHistoryList, error) {
+	return h.History.ListAppHistory(nil, namespace, name, listOptions)
+}
+
+func (h *AppHistoryServiceImpl) Delete(tx interface{}, namespace, name string) error {
+	return h.History.DeleteAppHistory(tx, namespace, name)
+}
+
+func (h *AppHistoryServiceImpl) Restore(tx interface{}, histories []models.AppHistory) error {
+	sorted := make([]models.AppHistory, len(histories))
+	copy(sorted, histories)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Revision < sorted[j].Revision
+	})
+	for i := range sorted {
+		if _, err := h.History.CreateAppHistory(tx, &sorted[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Diff returns the changed fields of application spec and referenced versions from one revision to another
+func (h *AppHistoryServiceImpl) Diff(namespace, name string, from, to int64) (*models.AppRevisionDiff, error) {
+	f, err := h.Get(namespace, name, from)
+	if err != nil {
+		return nil, err
+	}
+	t, err := h.Get(namespace, name, to)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := common.Diff(revisionContent(f), revisionContent(t))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &models.AppRevisionDiff{
+		Name:    name,
+		From:    from,
+		To:      to,
+		Changes: changes,
+	}, nil
+}
+
+// revisionContent omits the fields changed by every revision
+func revisionContent(history *models.AppHistory) map[string]interface{} {
+	var app specV1.Application
+	if history.Application != nil {
+		app = *history.Application
+	}
+	app.Version = ""
+	app.UpdateTime = time.Time{}
+	return map[string]interface{}{
+		"application": app,
+		"configs":     history.Configs,
+		"secrets":     history.Secrets,
+	}
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"testing"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+func TestAppHistoryService(t *testing.T) {
+	conf := &config.CloudConfig{}
+	conf.Plugin.AppHistory = common.RandString(9)
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	mHistory := mockPlugin.NewMockAppHistory(mockCtl)
+	plugin.RegisterFactory(conf.Plugin.AppHistory, func() (plugin.Plugin, error) {
+		return mHistory, nil
+	})
+
+	hs, err := NewAppHistoryService(conf)
+	assert.NoError(t, err)
+
+	ns, name := "default", "app"
+	app := &specV1.Application{
+		Namespace: ns,
+		Name:      name,
+		Version:   "2",
+		Selector:  "a=c",
+		Volumes: []specV1.Volume{
+			{Name: "cfg", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "cfg", Version: "10"}}},
+			{Name: "sec", VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "sec", Version: "11"}}},
+		},
+	}
+	notFound := common.Error(common.ErrResourceNotFound, common.Field("type", "appHistory"), common.Field("name", name))
+
+	mHistory.EXPECT().GetLatestAppHistory(nil, ns, name).Return(nil, notFound).Times(1)
+	mHistory.EXPECT().CreateAppHistory(nil, gomock.Any()).DoAndReturn(func(_ interface{}, h *models.AppHistory) (*models.AppHistory, error) {
+		assert.Equal(t, map[string]string{"cfg": "10"}, h.Configs)
+		assert.Equal(t, map[string]string{"sec": "11"}, h.Secrets)
+		h.Revision = 2
+		return h, nil
+	}).Times(1)
+	res, err := hs.Record(nil, app)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), res.Revision)
+
+	// unchanged version is not recorded again
+	mHistory.EXPECT().GetLatestAppHistory(nil, ns, name).Return(res, nil).Times(1)
+	res, err = hs.Record(nil, app)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), res.Revision)
+
+	mHistory.EXPECT().GetLatestAppHistory(nil, ns, name).Return(nil, common.Error(common.ErrRequestParamInvalid)).Times(1)
+	_, err = hs.Record(nil, app)
+	assert.Error(t, err)
+
+	old := &models.AppHistory{
+		Namespace: ns,
+		Name:      name,
+		Revision:  1,
+		Version:   "1",
+		Application: &specV1.Application{
+			Namespace: ns,
+			Name:      name,
+			Version:   "1",
+			Selector:  "a=b",
+		},
+		Configs: map[string]string{"cfg": "9"},
+		Secrets: map[string]string{"sec": "11"},
+	}
+	mHistory.EXPECT().GetAppHistory(nil, ns, name, int64(1)).Return(old, nil).Times(1)
+	mHistory.EXPECT().GetAppHistory(nil, ns, name, int64(2)).Return(res, nil).Times(1)
+	diff, err := hs.Diff(ns, name, 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []common.FieldDiff{
+		{Path: "application.selector", From: "a=b", To: "a=c"},
+		{Path: "application.volumes", From: nil, To: []interface{}{
+			map[string]interface{}{"name": "cfg", "config": map[string]interface{}{"name": "cfg", "version": "10"}},
+			map[string]interface{}{"name": "sec", "secret": map[string]interface{}{"name": "sec", "version": "11"}},
+		}},
+		{Path: "configs.cfg", From: "9", To: "10"},
+	}, diff.Changes)
+
+	mHistory.EXPECT().GetAppHistory(nil, ns, name, int64(1)).Return(old, nil).Times(1)
+	mHistory.EXPECT().GetAppHistory(nil, ns, name, int64(3)).Return(nil, notFound).Times(1)
+	_, err = hs.Diff(ns, name, 1, 3)
+	assert.Error(t, err)
+
+	mHistory.EXPECT().GetAppHistoryByVersion(nil, ns, name, "1").Return(old, nil).Times(1)
+	res, err = hs.GetByVersion(ns, name, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), res.Revision)
+
+	mHistory.EXPECT().ListAppHistory(nil, ns, name, gomock.Any()).Return(&models.AppHistoryList{Total: 2}, nil).Times(1)
+	list, err := hs.List(ns, name, &models.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, list.Total)
+
+	mHistory.EXPECT().DeleteAppHistory(nil, ns, name).Return(nil).Times(1)
+	assert.NoError(t, hs.Delete(nil, ns, name))
+
+	// the revisions are recreated in order
+	var revisions []int64
+	mHistory.EXPECT().CreateAppHistory(nil, gomock.Any()).DoAndReturn(func(_ interface{}, h *models.AppHistory) (*models.AppHistory, error) {
+		revisions = append(revisions, h.Revision)
+		return h, nil
+	}).Times(2)
+	assert.NoError(t, hs.Restore(nil, []models.AppHistory{{Revision: 2, Version: "2"}, *old}))
+	assert.Equal(t, []int64{1, 2}, revisions)
+
+	mHistory.EXPECT().CreateAppHistory(nil, gomock.Any()).Return(nil, notFound).Times(1)
+	assert.Error(t, hs.Restore(nil, []models.AppHistory{*old}))
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/app_status.go -package=service github.com/baetyl/baetyl-cloud/v2/service AppStatusService
+
+// the node is offline if it doesn't report in the core frequency plus this duration
+const appStatusOfflineDuration = 20
+
+// AppStatusService aggregates the status of application on the matched nodes
+type AppStatusService interface {
+	// ListNodeStatus lists the status of app on the given nodes, or all matched nodes if not set
+	ListNodeStatus(namespace string, app *models.AppItem, nodes []string, filter *models.AppNodeStatusFilter) (*models.AppNodeStatusItem, error)
+}
+
+type AppStatusServiceImpl struct {
+	Index  IndexService
+	Node   plugin.Node
+	Shadow plugin.Shadow
+}
+
+// NewAppStatusService new app status service
+func NewAppStatusService(config *config.CloudConfig) (AppStatusService, error) {
+	is, err := NewIndexService(config)
+	if err != nil {
+		return nil, err
+	}
+	res, err := plugin.GetPlugin(config.Plugin.Resource)
+	if err != nil {
+		return nil, err
+	}
+	shadow, err := plugin.GetPlugin(config.Plugin.Shadow)
+	if err != nil {
+		return nil, err
+	}
+	return &AppStatusServiceImpl{
+		Index:  is,
+		Node:   res.(plugin.Node),
+		Shadow: shadow.(plugin.Shadow),
+	}, nil
+}
+
+func (s *AppStatusServiceImpl) ListNodeStatus(namespace string, app *models.AppItem, nodes []string, filter *models.AppNodeStatusFilter) (*models.AppNodeStatusItem, error) {
+	matched, err := s.Index.ListNodesByApp(namespace, app.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) > 0 {
+		set := map[string]bool{}
+		for _, n := range matched {
+			set[n] = true
+		}
+		var res []string
+		for _, n := range nodes {
+			if set[n] {
+				res = append(res, n)
+			}
+		}
+		matched = res
+	}
+	sort.Strings(matched)
+
+	statuses := make([]models.AppNodeStatus, 0, len(matched))
+	reports := map[string]*specV1.AppStats{}
+	if len(matched) > 0 {
+		nodeList, err := s.Node.GetNodeByNames(nil, namespace, matched)
+		if err != nil {
+			return nil, err
+		}
+		timeouts := map[string]time.Duration{}
+		for _, n := range nodeList {
+			timeouts[n.Name] = nodeOfflineTimeout(&n)
+		}
+		shadows, err := s.Shadow.ListShadowByNames(nil, namespace, matched)
+		if err != nil {
+			return nil, err
+		}
+		shadowMap := map[string]*models.Shadow{}
+		for _, sd := range shadows {
+			shadowMap[sd.Name] = sd
+		}
+		for _, n := range matched {
+			timeout, ok := timeouts[n]
+			if !ok {
+				// the node is deleted but the index is not refreshed yet
+				continue
+			}
+			status, stat := genAppNodeStatus(app, n, shadowMap[n], timeout)
+			statuses = append(statuses, *status)
+			if stat != nil {
+				reports[n] = stat
+			}
+		}
+	}
+
+	res := &models.AppNodeStatusItem{
+		AppInfo:    app,
+		NodeReport: map[string]*specV1.AppStats{},
+		Nodes:      []models.AppNodeStatus{},
+	}
+	var filtered []models.AppNodeStatus
+	for _, st := range statuses {
+		switch st.Status {
+		case models.AppNodeConverged:
+			res.Summary.Converged++
+		case models.AppNodePending:
+			res.Summary.Pending++
+		case models.AppNodeFailed:
+			res.Summary.Failed++
+		case models.AppNodeOffline:
+			res.Summary.Offline++
+		}
+		if filter.Status == "" || filter.Status == st.Status {
+			filtered = append(filtered, st)
+		}
+	}
+
+	res.Total = len(filtered)
+	res.PageNo, res.PageSize = filter.PageNo, filter.PageSize
+	start, end := models.GetPagingParam(&models.ListOptions{Filter: filter.Filter}, len(filtered))
+	for _, st := range filtered[start:end] {
+		res.Nodes = append(res.Nodes, st)
+		if stat, ok := reports[st.Node]; ok {
+			res.NodeReport[st.Node] = stat
+		}
+	}
+	return res, nil
+}
+
+// genAppNodeStatus compares the desired version with the reported one and returns the reported stats of app
+func genAppNodeStatus(app *models.AppItem, node string, shadow *models.Shadow, timeout time.Duration) (*models.AppNodeStatus, *specV1.AppStats) {
+	res := &models.AppNodeStatus{
+		Node:          node,
+		DesireVersion: app.Version,
+		Status:        models.AppNodeOffline,
+	}
+	if shadow == nil {
+		return res, nil
+	}
+	for _, info := range shadow.Desire.AppInfos(app.System) {
+		if info.Name == app.Name {
+			res.DesireVersion = info.Version
+			break
+		}
+	}
+	if shadow.Report == nil || shadow.Time.IsZero() {
+		return res, nil
+	}
+	reportTime := shadow.Time
+	res.ReportTime = &reportTime
+	for _, info := range shadow.Report.AppInfos(app.System) {
+		if info.Name == app.Name {
+			res.ReportVersion = info.Version
+			break
+		}
+	}
+	var stat *specV1.AppStats
+	var failed bool
+	stats := shadow.Report.AppStats(app.System)
+	for i := range stats {
+		if stats[i].Name == app.Name {
+			stat = &stats[i]
+			break
+		}
+	}
+	if stat != nil {
+		res.AppStatus = stat.Status
+		res.Cause = stat.Cause
+		failed = stat.Status == specV1.Failed
+		for name, ins := range stat.InstanceStats {
+			if ins.Status == specV1.Failed {
+				failed = true
+			}
+			if ins.Cause != "" {
+				if res.InstanceErrors == nil {
+					res.InstanceErrors = map[string]string{}
+				}
+				res.InstanceErrors[name] = ins.Cause
+			}
+		}
+	}
+
+	switch {
+	case time.Now().UTC().After(shadow.Time.Add(timeout)):
+		res.Status = models.AppNodeOffline
+	case failed:
+		res.Status = models.AppNodeFailed
+	case stat != nil && res.ReportVersion == res.DesireVersion && stat.Version == res.DesireVersion &&
+		(stat.Status == specV1.Running || stat.Status == specV1.Succeeded):
+		res.Status = models.AppNodeConverged
+	default:
+		res.Status = models.AppNodePending
+	}
+	return res, stat
+}
+
+func nodeOfflineTimeout(node *specV1.Node) time.Duration {
+	freq := common.DefaultCoreFrequency
+	if v, ok := node.Attributes[specV1.BaetylCoreFrequency].(string); ok {
+		freq = v
+	}
+	t, err := strconv.Atoi(freq)
+	if err != nil {
+		t, _ = strconv.Atoi(common.DefaultCoreFrequency)
+	}
+	return time.Duration(t+appStatusOfflineDuration) * time.Second
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func genAppStatusShadow(name, desire, report string, status specV1.Status, reportTime time.Time) *models.Shadow {
+	d := specV1.Desire{}
+	d.SetAppInfos(false, []specV1.AppInfo{{Name: "app", Version: desire}})
+	r := specV1.Report{}
+	r.SetAppInfos(false, []specV1.AppInfo{{Name: "app", Version: report}})
+	stats := specV1.AppStats{
+		AppInfo: specV1.AppInfo{Name: "app", Version: report},
+		Status:  status,
+	}
+	if status == specV1.Failed {
+		stats.Cause = "crash"
+		stats.InstanceStats = map[string]specV1.InstanceStats{
+			"app-0": {Name: "app-0", Status: specV1.Failed, Cause: "back-off restarting failed container"},
+		}
+	}
+	r.SetAppStats(false, []specV1.AppStats{stats})
+	return &models.Shadow{Name: name, Desire: d, Report: r, Time: reportTime}
+}
+
+func TestListAppNodeStatus(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sIndex := ms.NewMockIndexService(mockCtl)
+	mNode := mockPlugin.NewMockNode(mockCtl)
+	mShadow := mockPlugin.NewMockShadow(mockCtl)
+	ss := &AppStatusServiceImpl{
+		Index:  sIndex,
+		Node:   mNode,
+		Shadow: mShadow,
+	}
+
+	ns := "default"
+	app := &models.AppItem{Name: "app", Namespace: ns, Version: "2"}
+	now := time.Now().UTC()
+	names := []string{"n1", "n2", "n3", "n4", "n5"}
+	var nodes []specV1.Node
+	for _, n := range names {
+		nodes = append(nodes, specV1.Node{Name: n, Attributes: map[string]interface{}{specV1.BaetylCoreFrequency: "20"}})
+	}
+	shadows := []*models.Shadow{
+		genAppStatusShadow("n1", "2", "2", specV1.Running, now),
+		genAppStatusShadow("n2", "2", "1", specV1.Running, now),
+		genAppStatusShadow("n3", "2", "2", specV1.Failed, now),
+		genAppStatusShadow("n4", "2", "2", specV1.Running, now.Add(-time.Hour)),
+	}
+
+	sIndex.EXPECT().ListNodesByApp(ns, "app").Return([]string{"n5", "n4", "n3", "n2", "n1"}, nil).AnyTimes()
+	mNode.EXPECT().GetNodeByNames(nil, ns, names).Return(nodes, nil).AnyTimes()
+	mShadow.EXPECT().ListShadowByNames(nil, ns, names).Return(shadows, nil).AnyTimes()
+
+	res, err := ss.ListNodeStatus(ns, app, nil, &models.AppNodeStatusFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, res.Total)
+	assert.Equal(t, models.AppStatusSummary{Converged: 1, Pending: 1, Failed: 1, Offline: 2}, res.Summary)
+	assert.Equal(t, models.AppNodeConverged, res.Nodes[0].Status)
+	assert.Equal(t, models.AppNodePending, res.Nodes[1].Status)
+	assert.Equal(t, "2", res.Nodes[1].DesireVersion)
+	assert.Equal(t, "1", res.Nodes[1].ReportVersion)
+	assert.Equal(t, models.AppNodeFailed, res.Nodes[2].Status)
+	assert.Equal(t, "crash", res.Nodes[2].Cause)
+	assert.Equal(t, map[string]string{"app-0": "back-off restarting failed container"}, res.Nodes[2].InstanceErrors)
+	assert.Equal(t, models.AppNodeOffline, res.Nodes[3].Status)
+	assert.Equal(t, models.AppNodeOffline, res.Nodes[4].Status)
+	assert.Nil(t, res.Nodes[4].ReportTime)
+	assert.Len(t, res.NodeReport, 4)
+
+	filter := &models.AppNodeStatusFilter{Status: models.AppNodeOffline}
+	filter.PageNo, filter.PageSize = 2, 1
+	res, err = ss.ListNodeStatus(ns, app, nil, filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, res.Total)
+	assert.Equal(t, models.AppStatusSummary{Converged: 1, Pending: 1, Failed: 1, Offline: 2}, res.Summary)
+	assert.Len(t, res.Nodes, 1)
+	assert.Equal(t, "n5", res.Nodes[0].Node)
+	assert.Len(t, res.NodeReport, 0)
+
+	// only the given nodes which match the app
+	mNode.EXPECT().GetNodeByNames(nil, ns, []string{"n1"}).Return(nodes[:1], nil).Times(1)
+	mShadow.EXPECT().ListShadowByNames(nil, ns, []string{"n1"}).Return(shadows[:1], nil).Times(1)
+	res, err = ss.ListNodeStatus(ns, app, []string{"n1", "n6"}, &models.AppNodeStatusFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, res.Total)
+	assert.Equal(t, models.AppStatusSummary{Converged: 1}, res.Summary)
+}
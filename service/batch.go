@@ -0,0 +1,273 @@
+package service
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/batch.go -package=service github.com/baetyl/baetyl-cloud/v2/service BatchService
+
+const recordNameLength = 12
+
+// BatchService manages the batches and their fingerprint records which the nodes are provisioned from
+type BatchService interface {
+	Get(namespace, name string) (*models.Batch, error)
+	List(namespace string, params *models.ListOptions) (*models.BatchList, error)
+	Create(batch *models.Batch) (*models.Batch, error)
+	Update(batch *models.Batch) (*models.Batch, error)
+	// Delete deletes the batch and all its records, the activated nodes are kept
+	Delete(namespace, name string) error
+	// Count counts the batches of namespace for the quota
+	Count(namespace string) (map[string]int, error)
+
+	GetRecord(namespace, batchName, name string) (*models.Record, error)
+	ListRecord(namespace, batchName string, params *models.Filter) (*models.RecordList, error)
+	// CreateRecords imports the fingerprints into the batch, the duplicated fingerprints are rejected
+	CreateRecords(namespace, batchName string, records []models.Record) ([]models.Record, error)
+	UpdateRecord(record *models.Record) (*models.Record, error)
+	DeleteRecord(namespace, batchName, name string) error
+	// GetActivationRecord returns the record of the fingerprint which the node is activated with,
+	// the record is created if the whitelist of batch is disabled, and the quota of batch is checked for the inactivated one,
+	// the caller should hold the lock of namespace until the record is activated, otherwise the quota can be exceeded
+	GetActivationRecord(batch *models.Batch, fingerprintValue string) (*models.Record, error)
+}
+
+type BatchServiceImpl struct {
+	Batch  plugin.Batch
+	Record plugin.Record
+}
+
+// NewBatchService new batch service
+func NewBatchService(config *config.CloudConfig) (BatchService, error) {
+	batch, err := plugin.GetPlugin(config.Plugin.Batch)
+	if err != nil {
+		return nil, err
+	}
+	record, err := plugin.GetPlugin(config.Plugin.Record)
+	if err != nil {
+		return nil, err
+	}
+	return &BatchServiceImpl{
+		Batch:  batch.(plugin.Batch),
+		Record: record.(plugin.Record),
+	}, nil
+}
+
+func (s *BatchServiceImpl) Get(namespace, name string) (*models.Batch, error) {
+	batch, err := s.Batch.GetBatch(name, namespace)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if batch == nil {
+		return nil, common.Error(common.ErrResourceNotFound,
+			common.Field("type", "batch"),
+			common.Field("name", name),
+			common.Field("namespace", namespace))
+	}
+	return batch, nil
+}
+
+func (s *BatchServiceImpl) List(namespace string, params *models.ListOptions) (*models.BatchList, error) {
+	batches, err := s.Batch.ListBatch(namespace, params)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	total := len(batches)
+	// the batches are filtered by labels, so they are paged in memory
+	if params.GetLimitNumber() > 0 {
+		start := params.GetLimitOffset()
+		if start > total {
+			start = total
+		}
+		end := start + params.GetLimitNumber()
+		if end > total {
+			end = total
+		}
+		batches = batches[start:end]
+	}
+	return &models.BatchList{
+		Total:       total,
+		ListOptions: params,
+		Items:       batches,
+	}, nil
+}
+
+func (s *BatchServiceImpl) Create(batch *models.Batch) (*models.Batch, error) {
+	if _, err := s.Batch.CreateBatch(batch); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return s.Get(batch.Namespace, batch.Name)
+}
+
+func (s *BatchServiceImpl) Update(batch *models.Batch) (*models.Batch, error) {
+	if _, err := s.Batch.UpdateBatch(batch); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return s.Get(batch.Namespace, batch.Name)
+}
+
+func (s *BatchServiceImpl) Delete(namespace, name string) error {
+	records, err := s.Record.ListRecordByBatch(name, namespace)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, r := range records {
+		if _, err = s.Record.DeleteRecord(name, r.Name, namespace); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	_, err = s.Batch.DeleteBatch(name, namespace)
+	return errors.Trace(err)
+}
+
+func (s *BatchServiceImpl) Count(namespace string) (map[string]int, error) {
+	count, err := s.Batch.CountBatch(namespace, "%")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return map[string]int{
+		plugin.QuotaBatch: count,
+	}, nil
+}
+
+func (s *BatchServiceImpl) GetRecord(namespace, batchName, name string) (*models.Record, error) {
+	record, err := s.Record.GetRecord(batchName, name, namespace)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if record == nil {
+		return nil, common.Error(common.ErrResourceNotFound,
+			common.Field("type", "record"),
+			common.Field("name", name),
+			common.Field("namespace", namespace))
+	}
+	return record, nil
+}
+
+func (s *BatchServiceImpl) ListRecord(namespace, batchName string, params *models.Filter) (*models.RecordList, error) {
+	records, err := s.Record.ListRecord(batchName, namespace, params)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	total, err := s.Record.CountRecord(batchName, params.GetFuzzyName(), namespace)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &models.RecordList{
+		Total:  total,
+		Filter: params,
+		Items:  records,
+	}, nil
+}
+
+func (s *BatchServiceImpl) CreateRecords(namespace, batchName string, records []models.Record) ([]models.Record, error) {
+	values := map[string]bool{}
+	for _, r := range records {
+		if r.FingerprintValue == "" {
+			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "fingerprintValue is required"))
+		}
+		if values[r.FingerprintValue] {
+			return nil, common.Error(common.ErrResourceConflict, common.Field("type", "fingerprint"), common.Field("name", r.FingerprintValue))
+		}
+		values[r.FingerprintValue] = true
+	}
+	for i := range records {
+		r := &records[i]
+		count, err := s.Record.CountRecord(batchName, r.FingerprintValue, namespace)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if count > 0 {
+			return nil, common.Error(common.ErrResourceConflict, common.Field("type", "fingerprint"), common.Field("name", r.FingerprintValue))
+		}
+		r.Namespace = namespace
+		r.BatchName = batchName
+		r.Active = common.Inactivated
+		r.ActiveIP = ""
+		r.ActiveTime = time.Unix(common.DefaultActiveTime, 0)
+		if r.Name == "" {
+			r.Name = genRecordName()
+		}
+		if r.NodeName == "" {
+			r.NodeName = r.Name
+		}
+	}
+	if _, err := s.Record.CreateRecord(records); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return records, nil
+}
+
+func (s *BatchServiceImpl) UpdateRecord(record *models.Record) (*models.Record, error) {
+	if _, err := s.Record.UpdateRecord(record); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return s.GetRecord(record.Namespace, record.BatchName, record.Name)
+}
+
+func (s *BatchServiceImpl) DeleteRecord(namespace, batchName, name string) error {
+	_, err := s.Record.DeleteRecord(batchName, name, namespace)
+	return errors.Trace(err)
+}
+
+func (s *BatchServiceImpl) GetActivationRecord(batch *models.Batch, fingerprintValue string) (*models.Record, error) {
+	record, err := s.Record.GetRecordByFingerprint(batch.Name, batch.Namespace, fingerprintValue)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if record != nil && record.Active == common.Activated {
+		return record, nil
+	}
+	if record == nil && batch.EnableWhitelist == common.EnableWhitelist {
+		return nil, common.Error(common.ErrRequestAccessDenied, common.Field("error", "the fingerprint isn't in the whitelist of batch"))
+	}
+
+	if err = s.checkBatchQuota(batch); err != nil {
+		return nil, err
+	}
+	if record != nil {
+		return record, nil
+	}
+	records, err := s.CreateRecords(batch.Namespace, batch.Name, []models.Record{{FingerprintValue: fingerprintValue}})
+	if err != nil {
+		return nil, err
+	}
+	return &records[0], nil
+}
+
+// checkBatchQuota checks the activated nodes of batch against the quota number, empty or zero means no limit
+func (s *BatchServiceImpl) checkBatchQuota(batch *models.Batch) error {
+	if batch.QuotaNum == "" {
+		return nil
+	}
+	limit, err := strconv.Atoi(batch.QuotaNum)
+	if err != nil || limit <= 0 {
+		return nil
+	}
+	records, err := s.Record.ListRecordByBatch(batch.Name, batch.Namespace)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	active := 0
+	for _, r := range records {
+		if r.Active == common.Activated {
+			active++
+		}
+	}
+	if active >= limit {
+		return common.Error(common.ErrLicenseQuota, common.Field("name", "batch "+batch.Name), common.Field("limit", limit))
+	}
+	return nil
+}
+
+func genRecordName() string {
+	return strings.ToLower(common.RandString(recordNameLength))
+}
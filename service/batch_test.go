@@ -0,0 +1,117 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+func initBatchService(t *testing.T) (*BatchServiceImpl, *mockPlugin.MockBatch, *mockPlugin.MockRecord, *gomock.Controller) {
+	mockCtl := gomock.NewController(t)
+	mBatch := mockPlugin.NewMockBatch(mockCtl)
+	mRecord := mockPlugin.NewMockRecord(mockCtl)
+	return &BatchServiceImpl{Batch: mBatch, Record: mRecord}, mBatch, mRecord, mockCtl
+}
+
+func TestBatchService_Batch(t *testing.T) {
+	bs, mBatch, mRecord, mockCtl := initBatchService(t)
+	defer mockCtl.Finish()
+
+	mBatch.EXPECT().GetBatch("b1", "default").Return(nil, nil).Times(1)
+	_, err := bs.Get("default", "b1")
+	assert.True(t, isNotFound(err))
+
+	batch := &models.Batch{Name: "b1", Namespace: "default"}
+	mBatch.EXPECT().CreateBatch(batch).Return(nil, nil).Times(1)
+	mBatch.EXPECT().GetBatch("b1", "default").Return(batch, nil).Times(1)
+	res, err := bs.Create(batch)
+	assert.NoError(t, err)
+	assert.Equal(t, batch, res)
+
+	params := &models.ListOptions{Filter: models.Filter{PageNo: 2, PageSize: 2}}
+	mBatch.EXPECT().ListBatch("default", params).Return([]models.Batch{{Name: "b1"}, {Name: "b2"}, {Name: "b3"}}, nil).Times(1)
+	list, err := bs.List("default", params)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, list.Total)
+	assert.Equal(t, []models.Batch{{Name: "b3"}}, list.Items)
+
+	mBatch.EXPECT().CountBatch("default", "%").Return(3, nil).Times(1)
+	count, err := bs.Count("default")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{plugin.QuotaBatch: 3}, count)
+
+	mRecord.EXPECT().ListRecordByBatch("b1", "default").Return([]models.Record{{Name: "r1"}}, nil).Times(1)
+	mRecord.EXPECT().DeleteRecord("b1", "r1", "default").Return(nil, nil).Times(1)
+	mBatch.EXPECT().DeleteBatch("b1", "default").Return(nil, nil).Times(1)
+	assert.NoError(t, bs.Delete("default", "b1"))
+}
+
+func TestBatchService_CreateRecords(t *testing.T) {
+	bs, _, mRecord, mockCtl := initBatchService(t)
+	defer mockCtl.Finish()
+
+	mRecord.EXPECT().CountRecord("b1", "sn-1", "default").Return(0, nil).Times(1)
+	mRecord.EXPECT().CountRecord("b1", "sn-2", "default").Return(0, nil).Times(1)
+	mRecord.EXPECT().CreateRecord(gomock.Any()).Return(nil, nil).Times(1)
+	records, err := bs.CreateRecords("default", "b1", []models.Record{{FingerprintValue: "sn-1"}, {FingerprintValue: "sn-2", NodeName: "n2"}})
+	assert.NoError(t, err)
+	assert.Len(t, records[0].Name, recordNameLength)
+	assert.Equal(t, records[0].Name, records[0].NodeName)
+	assert.Equal(t, "n2", records[1].NodeName)
+	assert.Equal(t, "b1", records[1].BatchName)
+	assert.Equal(t, common.Inactivated, records[1].Active)
+
+	// the duplicated fingerprints
+	_, err = bs.CreateRecords("default", "b1", []models.Record{{FingerprintValue: "sn-3"}, {FingerprintValue: "sn-3"}})
+	assert.Error(t, err)
+	mRecord.EXPECT().CountRecord("b1", "sn-1", "default").Return(1, nil).Times(1)
+	_, err = bs.CreateRecords("default", "b1", []models.Record{{FingerprintValue: "sn-1"}})
+	assert.Error(t, err)
+}
+
+func TestBatchService_GetActivationRecord(t *testing.T) {
+	bs, _, mRecord, mockCtl := initBatchService(t)
+	defer mockCtl.Finish()
+
+	batch := &models.Batch{Name: "b1", Namespace: "default", EnableWhitelist: common.EnableWhitelist, QuotaNum: "2"}
+
+	// the activated record
+	activated := &models.Record{Name: "r1", Active: common.Activated}
+	mRecord.EXPECT().GetRecordByFingerprint("b1", "default", "sn-1").Return(activated, nil).Times(1)
+	res, err := bs.GetActivationRecord(batch, "sn-1")
+	assert.NoError(t, err)
+	assert.Equal(t, activated, res)
+
+	// the fingerprint not in the whitelist
+	mRecord.EXPECT().GetRecordByFingerprint("b1", "default", "sn-2").Return(nil, nil).Times(1)
+	_, err = bs.GetActivationRecord(batch, "sn-2")
+	assert.Error(t, err)
+
+	// the quota of batch is used up
+	inactivated := &models.Record{Name: "r3"}
+	mRecord.EXPECT().GetRecordByFingerprint("b1", "default", "sn-3").Return(inactivated, nil).Times(2)
+	mRecord.EXPECT().ListRecordByBatch("b1", "default").Return([]models.Record{{Active: common.Activated}, {Active: common.Activated}, {}}, nil).Times(1)
+	_, err = bs.GetActivationRecord(batch, "sn-3")
+	assert.Error(t, err)
+	mRecord.EXPECT().ListRecordByBatch("b1", "default").Return([]models.Record{{Active: common.Activated}, {}}, nil).Times(1)
+	res, err = bs.GetActivationRecord(batch, "sn-3")
+	assert.NoError(t, err)
+	assert.Equal(t, inactivated, res)
+
+	// the record is created without the whitelist
+	batch.EnableWhitelist = common.DisableWhitelist
+	batch.QuotaNum = ""
+	mRecord.EXPECT().GetRecordByFingerprint("b1", "default", "sn-4").Return(nil, nil).Times(1)
+	mRecord.EXPECT().CountRecord("b1", "sn-4", "default").Return(0, nil).Times(1)
+	mRecord.EXPECT().CreateRecord(gomock.Any()).Return(nil, nil).Times(1)
+	res, err = bs.GetActivationRecord(batch, "sn-4")
+	assert.NoError(t, err)
+	assert.Equal(t, "sn-4", res.FingerprintValue)
+	assert.NotEmpty(t, res.NodeName)
+}
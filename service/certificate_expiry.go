@@ -0,0 +1,241 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/http"
+	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/certificate_expiry.go -package=service github.com/baetyl/baetyl-cloud/v2/service CertificateExpiryService
+
+// CertificateExpiryService finds the certificates and node certificates expiring soon
+type CertificateExpiryService interface {
+	// ListExpiring lists the certificates of namespace expiring within the days, including the expired ones
+	ListExpiring(namespace string, days int) (*models.ExpiringCertificateList, error)
+	// NotifyExpiring sends the expiry events to the configured callback of each namespace,
+	// and emits them to the webhooks subscribing the certificate expiring event, once a day
+	NotifyExpiring() error
+}
+
+type CertificateExpiryServiceImpl struct {
+	Secret       SecretService
+	Index        IndexService
+	Callback     plugin.Callback
+	Webhook      WebhookService
+	Days         int
+	CallbackName string
+	client       *http.Client
+	log          *log.Logger
+}
+
+// NewCertificateExpiryService new certificate expiry service
+func NewCertificateExpiryService(config *config.CloudConfig) (CertificateExpiryService, error) {
+	secret, err := NewSecretService(config)
+	if err != nil {
+		return nil, err
+	}
+	index, err := NewIndexService(config)
+	if err != nil {
+		return nil, err
+	}
+	callback, err := plugin.GetPlugin(config.Plugin.Callback)
+	if err != nil {
+		return nil, err
+	}
+	webhook, err := NewWebhookService(config)
+	if err != nil {
+		return nil, err
+	}
+	return &CertificateExpiryServiceImpl{
+		Secret:       secret,
+		Index:        index,
+		Callback:     callback.(plugin.Callback),
+		Webhook:      webhook,
+		Days:         config.CertificateExpiry.Days,
+		CallbackName: config.CertificateExpiry.Callback,
+		client:       http.NewClient(http.NewClientOptions()),
+		log:          log.L().With(log.Any("service", "certificateExpiry")),
+	}, nil
+}
+
+func (c *CertificateExpiryServiceImpl) ListExpiring(namespace string, days int) (*models.ExpiringCertificateList, error) {
+	secrets, err := c.Secret.List(namespace, &models.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	deadline := now.AddDate(0, 0, days)
+	res := &models.ExpiringCertificateList{Days: days, Items: []models.ExpiringCertificate{}}
+	for i := range secrets.Items {
+		item := toExpiringCertificate(&secrets.Items[i])
+		if item == nil || item.NotAfter.After(deadline) {
+			continue
+		}
+		item.Expired = item.NotAfter.Before(now)
+		apps, err := c.Index.ListAppIndexBySecret(namespace, item.Name)
+		if err != nil {
+			return nil, err
+		}
+		item.Apps = append([]string{}, apps...)
+		sort.Strings(item.Apps)
+		res.Items = append(res.Items, *item)
+	}
+	sort.SliceStable(res.Items, func(i, j int) bool {
+		return res.Items[i].NotAfter.Before(res.Items[j].NotAfter)
+	})
+	res.Total = len(res.Items)
+	return res, nil
+}
+
+func (c *CertificateExpiryServiceImpl) NotifyExpiring() error {
+	if err := c.emitExpiring(); err != nil {
+		return err
+	}
+	if c.CallbackName == "" {
+		return nil
+	}
+	callbacks, err := c.Callback.ListCallbackByName(nil, c.CallbackName)
+	if err != nil {
+		return err
+	}
+	for i := range callbacks {
+		callback := &callbacks[i]
+		list, err := c.ListExpiring(callback.Namespace, c.Days)
+		if err != nil {
+			c.log.Error("failed to list expiring certificates", log.Any("namespace", callback.Namespace), log.Error(err))
+			continue
+		}
+		if list.Total == 0 {
+			continue
+		}
+		event := &models.CertificateExpiryEvent{
+			Type:      models.CertificateExpiryEventType,
+			Namespace: callback.Namespace,
+			Time:      time.Now().UTC(),
+			Days:      c.Days,
+			Items:     list.Items,
+		}
+		if err = c.sendEvent(callback, event); err != nil {
+			c.log.Error("failed to send certificate expiry event", log.Any("namespace", callback.Namespace),
+				log.Any("callback", callback.Name), log.Error(err))
+			continue
+		}
+		c.log.Info("certificate expiry event sent", log.Any("namespace", callback.Namespace), log.Any("count", list.Total))
+	}
+	return nil
+}
+
+func (c *CertificateExpiryServiceImpl) emitExpiring() error {
+	if c.Webhook == nil {
+		return nil
+	}
+	namespaces, err := c.Webhook.ListSubscribedNamespaces(models.EventCertificateExpiring)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	for _, ns := range namespaces {
+		list, err := c.ListExpiring(ns, c.Days)
+		if err != nil {
+			c.log.Error("failed to list expiring certificates", log.Any("namespace", ns), log.Error(err))
+			continue
+		}
+		if list.Total == 0 {
+			continue
+		}
+		event := &models.Event{
+			ID:        fmt.Sprintf("%s/%s", models.EventCertificateExpiring, now.Format("20060102")),
+			Type:      models.EventCertificateExpiring,
+			Namespace: ns,
+			Time:      now,
+			Data: map[string]interface{}{
+				"days":  c.Days,
+				"total": list.Total,
+				"items": list.Items,
+			},
+		}
+		if err = c.Webhook.Emit(event); err != nil {
+			c.log.Error("failed to emit certificate expiry event", log.Any("namespace", ns), log.Error(err))
+		}
+	}
+	return nil
+}
+
+// sendEvent sends the event with the params, header and body of callback, the fields of event take precedence over the body
+func (c *CertificateExpiryServiceImpl) sendEvent(callback *models.Callback, event *models.CertificateExpiryEvent) error {
+	payload := map[string]interface{}{}
+	for k, v := range callback.Body {
+		payload[k] = v
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err = json.Unmarshal(data, &payload); err != nil {
+		return errors.Trace(err)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	u, err := url.Parse(callback.URL)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	query := u.Query()
+	for k, v := range callback.Params {
+		query.Set(k, v)
+	}
+	u.RawQuery = query.Encode()
+
+	header := map[string]string{"Content-Type": "application/json"}
+	for k, v := range callback.Header {
+		header[k] = v
+	}
+	resp, err := c.client.SendUrl(callback.Method, u.String(), bytes.NewReader(body), header)
+	if err != nil {
+		return err
+	}
+	_, err = http.HandleResponse(resp)
+	return err
+}
+
+// toExpiringCertificate returns the certificate uploaded by user or issued for node, nil if the secret is neither
+func toExpiringCertificate(secret *specV1.Secret) *models.ExpiringCertificate {
+	item := &models.ExpiringCertificate{Name: secret.Name, Namespace: secret.Namespace}
+	var data []byte
+	switch {
+	case secret.Labels[specV1.SecretLabel] == specV1.SecretCertificate || secret.Labels[specV1.SecretLabel] == specV1.SecretCustomCertificate:
+		item.Kind = models.CertificateKindUser
+		data = secret.Data["certificate"]
+	case secret.Labels[common.LabelNodeName] != "" && secret.Annotations[common.AnnotationPkiCertID] != "":
+		item.Kind = models.CertificateKindNode
+		item.Node = secret.Labels[common.LabelNodeName]
+		data = secret.Data["client.pem"]
+	default:
+		return nil
+	}
+	cert, err := models.ParseCertificatePEM(data)
+	if err != nil {
+		return nil
+	}
+	item.Issuer = cert.Issuer.CommonName
+	item.NotBefore = cert.NotBefore
+	item.NotAfter = cert.NotAfter
+	item.SANs = models.CertificateSANs(cert)
+	return item
+}
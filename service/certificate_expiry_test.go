@@ -0,0 +1,149 @@
+package service
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	gohttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/http"
+	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func genExpiryCertPEM(t *testing.T, notAfter time.Time) []byte {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    notAfter.AddDate(-1, 0, 0),
+		NotAfter:     notAfter,
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, &priv.PublicKey, priv)
+	assert.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func genExpirySecrets(t *testing.T, ns string) *models.SecretList {
+	now := time.Now()
+	return &models.SecretList{Items: []specV1.Secret{
+		{Namespace: ns, Name: "cert-soon", Labels: map[string]string{specV1.SecretLabel: specV1.SecretCertificate},
+			Data: map[string][]byte{"certificate": genExpiryCertPEM(t, now.AddDate(0, 0, 10))}},
+		{Namespace: ns, Name: "cert-later", Labels: map[string]string{specV1.SecretLabel: specV1.SecretCertificate},
+			Data: map[string][]byte{"certificate": genExpiryCertPEM(t, now.AddDate(0, 0, 100))}},
+		{Namespace: ns, Name: "crt-n1", Labels: map[string]string{specV1.SecretLabel: specV1.SecretConfig, common.LabelNodeName: "n1"},
+			Annotations: map[string]string{common.AnnotationPkiCertID: "id"},
+			Data:        map[string][]byte{"client.pem": genExpiryCertPEM(t, now.AddDate(0, 0, -1))}},
+		{Namespace: ns, Name: "cert-broken", Labels: map[string]string{specV1.SecretLabel: specV1.SecretCertificate},
+			Data: map[string][]byte{"certificate": []byte("broken")}},
+		{Namespace: ns, Name: "registry", Labels: map[string]string{specV1.SecretLabel: specV1.SecretRegistry}},
+	}}
+}
+
+func TestCertificateExpiryListExpiring(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sSecret := ms.NewMockSecretService(mockCtl)
+	sIndex := ms.NewMockIndexService(mockCtl)
+	cs := &CertificateExpiryServiceImpl{Secret: sSecret, Index: sIndex}
+
+	ns := "default"
+	sSecret.EXPECT().List(ns, &models.ListOptions{}).Return(genExpirySecrets(t, ns), nil).Times(2)
+	sIndex.EXPECT().ListAppIndexBySecret(ns, "cert-soon").Return([]string{"b", "a"}, nil).Times(2)
+	sIndex.EXPECT().ListAppIndexBySecret(ns, "crt-n1").Return(nil, nil).Times(2)
+	sIndex.EXPECT().ListAppIndexBySecret(ns, "cert-later").Return([]string{"c"}, nil).Times(1)
+
+	list, err := cs.ListExpiring(ns, 30)
+	assert.NoError(t, err)
+	assert.Equal(t, 30, list.Days)
+	assert.Equal(t, 2, list.Total)
+	assert.Equal(t, "crt-n1", list.Items[0].Name)
+	assert.Equal(t, models.CertificateKindNode, list.Items[0].Kind)
+	assert.Equal(t, "n1", list.Items[0].Node)
+	assert.True(t, list.Items[0].Expired)
+	assert.Equal(t, []string{}, list.Items[0].Apps)
+	assert.Equal(t, "cert-soon", list.Items[1].Name)
+	assert.Equal(t, models.CertificateKindUser, list.Items[1].Kind)
+	assert.False(t, list.Items[1].Expired)
+	assert.Equal(t, "test", list.Items[1].Issuer)
+	assert.Equal(t, []string{"DNS:localhost", "IP:127.0.0.1"}, list.Items[1].SANs)
+	assert.Equal(t, []string{"a", "b"}, list.Items[1].Apps)
+
+	list, err = cs.ListExpiring(ns, 365)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, list.Total)
+	assert.Equal(t, "cert-later", list.Items[2].Name)
+}
+
+func TestCertificateExpiryNotifyExpiring(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sSecret := ms.NewMockSecretService(mockCtl)
+	sIndex := ms.NewMockIndexService(mockCtl)
+	mCallback := mockPlugin.NewMockCallback(mockCtl)
+
+	var received []map[string]interface{}
+	svr := httptest.NewServer(gohttp.HandlerFunc(func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		assert.Equal(t, gohttp.MethodPost, r.Method)
+		assert.Equal(t, "1", r.URL.Query().Get("a"))
+		assert.Equal(t, "token", r.Header.Get("Authorization"))
+		data, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		payload := map[string]interface{}{}
+		assert.NoError(t, json.Unmarshal(data, &payload))
+		received = append(received, payload)
+	}))
+	defer svr.Close()
+
+	cs := &CertificateExpiryServiceImpl{
+		Secret:       sSecret,
+		Index:        sIndex,
+		Callback:     mCallback,
+		Days:         30,
+		CallbackName: "certificate-expiry",
+		client:       http.NewClient(http.NewClientOptions()),
+		log:          log.L(),
+	}
+
+	mCallback.EXPECT().ListCallbackByName(nil, "certificate-expiry").Return([]models.Callback{
+		{Name: "certificate-expiry", Namespace: "default", Method: gohttp.MethodPost, URL: svr.URL,
+			Params: map[string]string{"a": "1"}, Header: map[string]string{"Authorization": "token"},
+			Body: map[string]string{"source": "baetyl", "type": "ignored"}},
+		{Name: "certificate-expiry", Namespace: "empty", Method: gohttp.MethodPost, URL: svr.URL},
+	}, nil).Times(1)
+	sSecret.EXPECT().List("default", &models.ListOptions{}).Return(genExpirySecrets(t, "default"), nil).Times(1)
+	sSecret.EXPECT().List("empty", &models.ListOptions{}).Return(&models.SecretList{}, nil).Times(1)
+	sIndex.EXPECT().ListAppIndexBySecret("default", gomock.Any()).Return([]string{"app"}, nil).Times(2)
+
+	err := cs.NotifyExpiring()
+	assert.NoError(t, err)
+	assert.Len(t, received, 1)
+	assert.Equal(t, "baetyl", received[0]["source"])
+	assert.Equal(t, models.CertificateExpiryEventType, received[0]["type"])
+	assert.Equal(t, "default", received[0]["namespace"])
+	assert.Len(t, received[0]["items"], 2)
+
+	// nothing is sent without the callback name
+	cs.CallbackName = ""
+	assert.NoError(t, cs.NotifyExpiring())
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"sort"
+	"strings"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+// the version of resource rendered for node is the original version followed by the hash of the rendered data
+const renderedVersionSeparator = "-"
+
+// baseVersion returns the original version of the resource rendered for node
+func baseVersion(version string) string {
+	if i := strings.Index(version, renderedVersionSeparator); i >= 0 {
+		return version[:i]
+	}
+	return version
+}
+
+func genPlaceholderContext(node *specV1.Node, desire specV1.Desire, report specV1.Report) *common.NodePlaceholderContext {
+	props := map[string]interface{}{}
+	if p, ok := report[common.NodeProps].(map[string]interface{}); ok {
+		for k, v := range p {
+			props[k] = v
+		}
+	}
+	// the desired properties take precedence over the reported ones
+	if p, ok := desire[common.NodeProps].(map[string]interface{}); ok {
+		for k, v := range p {
+			props[k] = v
+		}
+	}
+	return &common.NodePlaceholderContext{
+		Name:       node.Name,
+		Labels:     node.Labels,
+		Properties: props,
+	}
+}
+
+func hasConfigPlaceholders(cfg *specV1.Configuration) bool {
+	for k, v := range cfg.Data {
+		if !strings.HasPrefix(k, common.ConfigObjectPrefix) && common.HasNodePlaceholders(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// renderConfig returns a copy of config whose placeholders are resolved for node, the original config is returned if no placeholder
+func renderConfig(cfg *specV1.Configuration, ctx *common.NodePlaceholderContext) *specV1.Configuration {
+	if !hasConfigPlaceholders(cfg) {
+		return cfg
+	}
+	res := *cfg
+	res.Data = map[string]string{}
+	keys := make([]string, 0, len(cfg.Data))
+	for k, v := range cfg.Data {
+		if !strings.HasPrefix(k, common.ConfigObjectPrefix) {
+			v = common.RenderNodePlaceholders(v, ctx)
+		}
+		res.Data[k] = v
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		parts = append(parts, k, res.Data[k])
+	}
+	res.Version = baseVersion(cfg.Version) + renderedVersionSeparator + shortHash(parts...)
+	return &res
+}
+
+// renderedAppVersion returns the version of app for node, which changes if any rendered config changes
+func renderedAppVersion(version string, configs []specV1.Configuration, ctx *common.NodePlaceholderContext) string {
+	var parts []string
+	for i := range configs {
+		if !hasConfigPlaceholders(&configs[i]) {
+			continue
+		}
+		parts = append(parts, renderConfig(&configs[i], ctx).Version)
+	}
+	if len(parts) == 0 {
+		return baseVersion(version)
+	}
+	sort.Strings(parts)
+	return baseVersion(version) + renderedVersionSeparator + shortHash(parts...)
+}
+
+func shortHash(parts ...string) string {
+	sum := md5.Sum([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(sum[:])[:8]
+}
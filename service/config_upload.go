@@ -0,0 +1,337 @@
+package service
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/baetyl/baetyl-go/v2/log"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+//go:generate mockgen -destination=../mock/service/config_upload.go -package=service github.com/baetyl/baetyl-cloud/v2/service ConfigUploadService
+
+const (
+	// the property of the object source which the files are uploaded to
+	objectSourceProperty = "object-source"
+	configUploadIDLength = 16
+)
+
+var configUploadIDRegex = regexp.MustCompile("^[a-zA-Z0-9]+$")
+
+// ConfigUploadService uploads the files of config objects into the internal bucket of namespace
+type ConfigUploadService interface {
+	// Upload writes the file uploaded in one request, the size is limited by the max size
+	Upload(userID, namespace, config, filename string, r io.Reader) (*models.ConfigObjectUpload, error)
+	// InitUpload starts the resumable upload of the file in chunks
+	InitUpload(userID, namespace string, upload *models.ConfigUpload) (*models.ConfigUpload, error)
+	// GetUpload returns the upload with the indexes of the chunks uploaded
+	GetUpload(userID, namespace, config, id string) (*models.ConfigUpload, error)
+	PutChunk(userID, namespace, config, id string, index int, r io.Reader) error
+	// CompleteUpload assembles the chunks into the object and verifies the md5, the upload is removed
+	CompleteUpload(userID, namespace, config, id string) (*models.ConfigUpload, *models.ConfigObjectUpload, error)
+	AbortUpload(userID, namespace, config, id string) error
+}
+
+type ConfigUploadServiceImpl struct {
+	Object         ObjectService
+	Property       PropertyService
+	MaxSize        int64
+	MaxChunkedSize int64
+	ChunkSize      int64
+	log            *log.Logger
+}
+
+// NewConfigUploadService new config upload service
+func NewConfigUploadService(config *config.CloudConfig) (ConfigUploadService, error) {
+	object, err := NewObjectService(config)
+	if err != nil {
+		return nil, err
+	}
+	property, err := NewPropertyService(config)
+	if err != nil {
+		return nil, err
+	}
+	return &ConfigUploadServiceImpl{
+		Object:         object,
+		Property:       property,
+		MaxSize:        config.ConfigUpload.MaxSize,
+		MaxChunkedSize: config.ConfigUpload.MaxChunkedSize,
+		ChunkSize:      config.ConfigUpload.ChunkSize,
+		log:            log.L().With(log.Any("service", "configUpload")),
+	}, nil
+}
+
+func (u *ConfigUploadServiceImpl) Upload(userID, namespace, config, filename string, r io.Reader) (*models.ConfigObjectUpload, error) {
+	if err := validateUploadFilename(filename); err != nil {
+		return nil, err
+	}
+	// the file is streamed into the temp file, the size is limited while copying
+	tmp, sum, size, err := saveTempFile(r, u.MaxSize)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp)
+	if size > u.MaxSize {
+		return nil, common.Error(common.ErrDataTooLarge, common.Field("name", filename),
+			common.Field("size", fmt.Sprintf(">%d", u.MaxSize)), common.Field("max", u.MaxSize))
+	}
+	source, bucket, err := u.prepareBucket(userID, namespace)
+	if err != nil {
+		return nil, err
+	}
+	res := &models.ConfigObjectUpload{
+		Source: source,
+		Bucket: bucket,
+		Object: configObjectName(config, filename),
+		MD5:    sum,
+		Size:   size,
+	}
+	if err = u.Object.PutInternalObjectFromFile(userID, bucket, res.Object, source, tmp); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (u *ConfigUploadServiceImpl) InitUpload(userID, namespace string, upload *models.ConfigUpload) (*models.ConfigUpload, error) {
+	if err := validateUploadFilename(upload.Filename); err != nil {
+		return nil, err
+	}
+	if upload.Size > u.MaxChunkedSize {
+		return nil, common.Error(common.ErrDataTooLarge, common.Field("name", upload.Filename),
+			common.Field("size", upload.Size), common.Field("max", u.MaxChunkedSize))
+	}
+	source, bucket, err := u.prepareBucket(userID, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if upload.ChunkSize <= 0 || upload.ChunkSize > u.ChunkSize {
+		upload.ChunkSize = u.ChunkSize
+	}
+	upload.ID = common.RandString(configUploadIDLength)
+	upload.Chunks = int((upload.Size + upload.ChunkSize - 1) / upload.ChunkSize)
+	upload.Uploaded = []int{}
+	upload.CreateTime = time.Now().UTC()
+	data, err := json.Marshal(upload)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err = u.Object.PutInternalObject(userID, bucket, uploadManifestName(upload.ID), source, data); err != nil {
+		return nil, err
+	}
+	return upload, nil
+}
+
+func (u *ConfigUploadServiceImpl) GetUpload(userID, namespace, config, id string) (*models.ConfigUpload, error) {
+	source, bucket, upload, err := u.getUpload(userID, namespace, config, id)
+	if err != nil {
+		return nil, err
+	}
+	upload.Uploaded = []int{}
+	for i := 0; i < upload.Chunks; i++ {
+		meta, err := u.Object.HeadInternalObject(userID, bucket, uploadChunkName(id, i), source)
+		if err == nil && meta != nil && meta.ContentLength == chunkLength(upload, i) {
+			upload.Uploaded = append(upload.Uploaded, i)
+		}
+	}
+	return upload, nil
+}
+
+func (u *ConfigUploadServiceImpl) PutChunk(userID, namespace, config, id string, index int, r io.Reader) error {
+	source, bucket, upload, err := u.getUpload(userID, namespace, config, id)
+	if err != nil {
+		return err
+	}
+	if index < 0 || index >= upload.Chunks {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("the index of chunk should be in [0, %d)", upload.Chunks)))
+	}
+	expected := chunkLength(upload, index)
+	tmp, _, size, err := saveTempFile(r, expected)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+	if size != expected {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("the size of chunk %d should be %d", index, expected)))
+	}
+	return u.Object.PutInternalObjectFromFile(userID, bucket, uploadChunkName(id, index), source, tmp)
+}
+
+func (u *ConfigUploadServiceImpl) CompleteUpload(userID, namespace, config, id string) (*models.ConfigUpload, *models.ConfigObjectUpload, error) {
+	source, bucket, upload, err := u.getUpload(userID, namespace, config, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	file, err := ioutil.TempFile("", "config-upload-")
+	if err != nil {
+		return nil, nil, common.Error(common.ErrIO, common.Field("error", err.Error()))
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	hasher := md5.New()
+	for i := 0; i < upload.Chunks; i++ {
+		if err = u.appendChunk(userID, bucket, source, upload, i, io.MultiWriter(file, hasher)); err != nil {
+			return nil, nil, err
+		}
+	}
+	sum := hex.EncodeToString(hasher.Sum(nil))
+	if upload.MD5 != "" && !strings.EqualFold(upload.MD5, sum) {
+		return nil, nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("the md5 (%s) of the uploaded file mismatches %s", sum, upload.MD5)))
+	}
+	if err = file.Close(); err != nil {
+		return nil, nil, common.Error(common.ErrIO, common.Field("error", err.Error()))
+	}
+	res := &models.ConfigObjectUpload{
+		Source: source,
+		Bucket: bucket,
+		Object: configObjectName(upload.Config, upload.Filename),
+		MD5:    sum,
+		Size:   upload.Size,
+	}
+	if err = u.Object.PutInternalObjectFromFile(userID, bucket, res.Object, source, file.Name()); err != nil {
+		return nil, nil, err
+	}
+	u.removeUpload(userID, bucket, source, upload)
+	return upload, res, nil
+}
+
+func (u *ConfigUploadServiceImpl) AbortUpload(userID, namespace, config, id string) error {
+	source, bucket, upload, err := u.getUpload(userID, namespace, config, id)
+	if err != nil {
+		return err
+	}
+	u.removeUpload(userID, bucket, source, upload)
+	return nil
+}
+
+// appendChunk writes the chunk into the writer, the chunk must be uploaded completely
+func (u *ConfigUploadServiceImpl) appendChunk(userID, bucket, source string, upload *models.ConfigUpload, index int, w io.Writer) error {
+	object, err := u.Object.GetInternalObject(userID, bucket, uploadChunkName(upload.ID, index), source)
+	if err != nil {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("the chunk %d is not uploaded", index)))
+	}
+	defer object.Body.Close()
+	n, err := io.Copy(w, object.Body)
+	if err != nil {
+		return common.Error(common.ErrIO, common.Field("error", err.Error()))
+	}
+	if n != chunkLength(upload, index) {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("the chunk %d is not uploaded completely", index)))
+	}
+	return nil
+}
+
+func (u *ConfigUploadServiceImpl) getUpload(userID, namespace, config, id string) (string, string, *models.ConfigUpload, error) {
+	if !configUploadIDRegex.MatchString(id) {
+		return "", "", nil, common.Error(common.ErrResourceNotFound, common.Field("type", "upload"), common.Field("name", id))
+	}
+	source, err := u.Property.GetPropertyValue(objectSourceProperty)
+	if err != nil {
+		return "", "", nil, err
+	}
+	bucket := configUploadBucket(namespace)
+	object, err := u.Object.GetInternalObject(userID, bucket, uploadManifestName(id), source)
+	if err != nil {
+		return "", "", nil, common.Error(common.ErrResourceNotFound, common.Field("type", "upload"), common.Field("name", id))
+	}
+	defer object.Body.Close()
+	data, err := ioutil.ReadAll(object.Body)
+	if err != nil {
+		return "", "", nil, common.Error(common.ErrIO, common.Field("error", err.Error()))
+	}
+	upload := new(models.ConfigUpload)
+	if err = json.Unmarshal(data, upload); err != nil {
+		return "", "", nil, errors.Trace(err)
+	}
+	if upload.Config != config {
+		return "", "", nil, common.Error(common.ErrResourceNotFound, common.Field("type", "upload"), common.Field("name", id))
+	}
+	return source, bucket, upload, nil
+}
+
+// removeUpload deletes the chunks and the manifest of upload, the failures are only logged
+func (u *ConfigUploadServiceImpl) removeUpload(userID, bucket, source string, upload *models.ConfigUpload) {
+	names := []string{uploadManifestName(upload.ID)}
+	for i := 0; i < upload.Chunks; i++ {
+		names = append(names, uploadChunkName(upload.ID, i))
+	}
+	for _, name := range names {
+		if err := u.Object.DeleteInternalObject(userID, bucket, name, source); err != nil {
+			u.log.Warn("failed to delete the object of upload", log.Any("bucket", bucket), log.Any("object", name), log.Error(err))
+		}
+	}
+}
+
+func (u *ConfigUploadServiceImpl) prepareBucket(userID, namespace string) (string, string, error) {
+	source, err := u.Property.GetPropertyValue(objectSourceProperty)
+	if err != nil {
+		return "", "", err
+	}
+	bucket := configUploadBucket(namespace)
+	if _, err = u.Object.CreateInternalBucketIfNotExist(userID, bucket, common.AWSS3PrivatePermission, source); err != nil {
+		return "", "", err
+	}
+	return source, bucket, nil
+}
+
+// saveTempFile copies the data into a temp file while hashing it, at most limit+1 bytes are copied
+// so that the oversized data is detected without reading all of it, the file should be removed by the caller
+func saveTempFile(r io.Reader, limit int64) (string, string, int64, error) {
+	file, err := ioutil.TempFile("", "config-upload-")
+	if err != nil {
+		return "", "", 0, common.Error(common.ErrIO, common.Field("error", err.Error()))
+	}
+	hasher := md5.New()
+	n, err := io.Copy(io.MultiWriter(file, hasher), io.LimitReader(r, limit+1))
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return "", "", 0, common.Error(common.ErrIO, common.Field("error", err.Error()))
+	}
+	return file.Name(), hex.EncodeToString(hasher.Sum(nil)), n, nil
+}
+
+func validateUploadFilename(filename string) error {
+	if filename == "." || filename == ".." {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", "the filename is invalid"))
+	}
+	return common.ValidateKeyValue(filename)
+}
+
+// chunkLength returns the length of the chunk, the last one may be shorter
+func chunkLength(upload *models.ConfigUpload, index int) int64 {
+	if rest := upload.Size - int64(index)*upload.ChunkSize; rest < upload.ChunkSize {
+		return rest
+	}
+	return upload.ChunkSize
+}
+
+func configUploadBucket(namespace string) string {
+	return fmt.Sprintf("%s-%s", common.BaetylCloud, namespace)
+}
+
+func configObjectName(config, filename string) string {
+	return fmt.Sprintf("configs/%s/%s", config, filename)
+}
+
+func uploadManifestName(id string) string {
+	return fmt.Sprintf("uploads/%s/upload.json", id)
+}
+
+func uploadChunkName(id string, index int) string {
+	return fmt.Sprintf("uploads/%s/%d", id, index)
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// mockUploadObjects keeps the objects of the internal bucket in memory
+func mockUploadObjects(sObject *ms.MockObjectService) map[string][]byte {
+	objects := map[string][]byte{}
+	sObject.EXPECT().CreateInternalBucketIfNotExist("user", "baetyl-cloud-default", common.AWSS3PrivatePermission, "minio").Return(nil, nil).AnyTimes()
+	sObject.EXPECT().PutInternalObject("user", "baetyl-cloud-default", gomock.Any(), "minio", gomock.Any()).DoAndReturn(
+		func(_, _, name, _ string, b []byte) error {
+			objects[name] = b
+			return nil
+		}).AnyTimes()
+	sObject.EXPECT().PutInternalObjectFromFile("user", "baetyl-cloud-default", gomock.Any(), "minio", gomock.Any()).DoAndReturn(
+		func(_, _, name, _, filename string) error {
+			b, err := ioutil.ReadFile(filename)
+			objects[name] = b
+			return err
+		}).AnyTimes()
+	sObject.EXPECT().GetInternalObject("user", "baetyl-cloud-default", gomock.Any(), "minio").DoAndReturn(
+		func(_, _, name, _ string) (*models.Object, error) {
+			b, ok := objects[name]
+			if !ok {
+				return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "object"), common.Field("name", name))
+			}
+			return &models.Object{Body: ioutil.NopCloser(bytes.NewReader(b))}, nil
+		}).AnyTimes()
+	sObject.EXPECT().HeadInternalObject("user", "baetyl-cloud-default", gomock.Any(), "minio").DoAndReturn(
+		func(_, _, name, _ string) (*models.ObjectMeta, error) {
+			b, ok := objects[name]
+			if !ok {
+				return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "object"), common.Field("name", name))
+			}
+			return &models.ObjectMeta{ContentLength: int64(len(b))}, nil
+		}).AnyTimes()
+	sObject.EXPECT().DeleteInternalObject("user", "baetyl-cloud-default", gomock.Any(), "minio").DoAndReturn(
+		func(_, _, name, _ string) error {
+			delete(objects, name)
+			return nil
+		}).AnyTimes()
+	return objects
+}
+
+func TestConfigUploadUpload(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sObject := ms.NewMockObjectService(mockCtl)
+	sProperty := ms.NewMockPropertyService(mockCtl)
+	sProperty.EXPECT().GetPropertyValue("object-source").Return("minio", nil).AnyTimes()
+	objects := mockUploadObjects(sObject)
+	us := &ConfigUploadServiceImpl{Object: sObject, Property: sProperty, MaxSize: 5, log: log.L()}
+
+	res, err := us.Upload("user", "default", "cfg", "a.bin", strings.NewReader("hello"))
+	assert.NoError(t, err)
+	sum := md5.Sum([]byte("hello"))
+	assert.Equal(t, &models.ConfigObjectUpload{Source: "minio", Bucket: "baetyl-cloud-default",
+		Object: "configs/cfg/a.bin", MD5: hex.EncodeToString(sum[:]), Size: 5}, res)
+	assert.Equal(t, []byte("hello"), objects["configs/cfg/a.bin"])
+
+	_, err = us.Upload("user", "default", "cfg", "a.bin", strings.NewReader("hello!"))
+	assert.Error(t, err)
+	// the oversized data isn't read completely
+	r := &countReader{}
+	_, err = us.Upload("user", "default", "cfg", "b.bin", r)
+	assert.Error(t, err)
+	assert.Equal(t, int64(6), r.n)
+	assert.NotContains(t, objects, "configs/cfg/b.bin")
+	_, err = us.Upload("user", "default", "cfg", "../a.bin", strings.NewReader("hello"))
+	assert.Error(t, err)
+	_, err = us.Upload("user", "default", "cfg", "..", strings.NewReader("hello"))
+	assert.Error(t, err)
+}
+
+func TestConfigUploadChunked(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sObject := ms.NewMockObjectService(mockCtl)
+	sProperty := ms.NewMockPropertyService(mockCtl)
+	sProperty.EXPECT().GetPropertyValue("object-source").Return("minio", nil).AnyTimes()
+	objects := mockUploadObjects(sObject)
+	us := &ConfigUploadServiceImpl{Object: sObject, Property: sProperty, MaxChunkedSize: 100, ChunkSize: 4, log: log.L()}
+
+	content := "hello world"
+	sum := md5.Sum([]byte(content))
+	_, err := us.InitUpload("user", "default", &models.ConfigUpload{Config: "cfg", Key: "model", Filename: "m.bin", Size: 101})
+	assert.Error(t, err)
+
+	upload, err := us.InitUpload("user", "default", &models.ConfigUpload{Config: "cfg", Key: "model", Filename: "m.bin",
+		Size: int64(len(content)), ChunkSize: 8, MD5: hex.EncodeToString(sum[:])})
+	assert.NoError(t, err)
+	assert.Len(t, upload.ID, configUploadIDLength)
+	assert.Equal(t, int64(4), upload.ChunkSize)
+	assert.Equal(t, 3, upload.Chunks)
+
+	// the last chunk is shorter, the chunk of wrong size is rejected
+	assert.NoError(t, us.PutChunk("user", "default", "cfg", upload.ID, 2, strings.NewReader("rld")))
+	assert.Error(t, us.PutChunk("user", "default", "cfg", upload.ID, 0, strings.NewReader("hel")))
+	assert.Error(t, us.PutChunk("user", "default", "cfg", upload.ID, 3, strings.NewReader("hell")))
+	assert.NoError(t, us.PutChunk("user", "default", "cfg", upload.ID, 0, strings.NewReader("hell")))
+
+	// resume with the missing chunks
+	got, err := us.GetUpload("user", "default", "cfg", upload.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 2}, got.Uploaded)
+	_, err = us.GetUpload("user", "default", "other", upload.ID)
+	assert.Error(t, err)
+	_, _, err = us.CompleteUpload("user", "default", "cfg", upload.ID)
+	assert.Error(t, err)
+	assert.NoError(t, us.PutChunk("user", "default", "cfg", upload.ID, 1, strings.NewReader("o wo")))
+
+	got, res, err := us.CompleteUpload("user", "default", "cfg", upload.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "model", got.Key)
+	assert.Equal(t, "configs/cfg/m.bin", res.Object)
+	assert.Equal(t, hex.EncodeToString(sum[:]), res.MD5)
+	assert.Equal(t, []byte(content), objects["configs/cfg/m.bin"])
+	assert.Len(t, objects, 1)
+
+	_, err = us.GetUpload("user", "default", "cfg", upload.ID)
+	assert.Error(t, err)
+	_, err = us.GetUpload("user", "default", "cfg", "../x")
+
+	assert.Error(t, err)
+
+	// the md5 mismatched
+	upload, err = us.InitUpload("user", "default", &models.ConfigUpload{Config: "cfg", Key: "model", Filename: "m.bin", Size: 2, MD5: "wrong"})
+	assert.NoError(t, err)
+	assert.NoError(t, us.PutChunk("user", "default", "cfg", upload.ID, 0, strings.NewReader("hi")))
+	_, _, err = us.CompleteUpload("user", "default", "cfg", upload.ID)
+	assert.Error(t, err)
+	assert.NoError(t, us.AbortUpload("user", "default", "cfg", upload.ID))
+	assert.Len(t, objects, 1)
+}
+
+// countReader is an endless reader which counts the bytes read
+type countReader struct {
+	n int64
+}
+
+func (r *countReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 'a'
+	}
+	r.n += int64(len(p))
+	return len(p), nil
+}
@@ -22,6 +22,10 @@ const (
 	InfoName      = "n"
 	InfoNamespace = "ns"
 	InfoExpiry    = "e"
+	// InfoTokenID the name of install token registered by InstallTokenService
+	InfoTokenID = "t"
+	// InfoKeyID the id of sign key which signs the token, the tokens without it are signed by the legacy key
+	InfoKeyID = "k"
 )
 
 const (
@@ -54,6 +58,8 @@ type PopulateExtParams func(ns string, node *specV1.Node, app *specV1.Applicatio
 // InitService
 type InitService interface {
 	GetResource(ns, nodeName, resourceName string, params map[string]interface{}) (interface{}, error)
+	// GetNodeCertificate returns the certificate which the node syncs with
+	GetNodeCertificate(ns, nodeName string) (*specV1.Secret, error)
 }
 
 type InitServiceImpl struct {
@@ -64,6 +70,7 @@ type InitServiceImpl struct {
 	TemplateService TemplateService
 	*AppCombinedService
 	PKI             PKIService
+	InstallToken    InstallTokenService
 	ResourceMapFunc map[string]GetInitResource
 	Hooks           map[string]interface{}
 	log             *log.Logger
@@ -105,6 +112,10 @@ func NewInitService(config *config.CloudConfig) (InitService, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	installToken, err := NewInstallTokenService(config)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	initService := &InitServiceImpl{
 		cfg:                config,
 		SignService:        signService,
@@ -113,6 +124,7 @@ func NewInitService(config *config.CloudConfig) (InitService, error) {
 		TemplateService:    templateService,
 		AppCombinedService: acs,
 		PKI:                pki,
+		InstallToken:       installToken,
 		ResourceMapFunc:    map[string]GetInitResource{},
 		Hooks:              map[string]interface{}{},
 		log:                log.L().With(log.Any("service", "init")),
@@ -142,23 +154,9 @@ func (s *InitServiceImpl) GetResource(ns, nodeName, resourceName string, params
 }
 
 func (s *InitServiceImpl) getInitDeploymentYaml(ns, nodeName string, params map[string]interface{}) ([]byte, error) {
-	init, err := s.GetAppFromDesire(ns, nodeName, specV1.BaetylInit, true)
+	init, err := s.getInitApp(ns, nodeName)
 	if err != nil {
-		s.log.Warn("failed to get init app from desire, the init module will use the default name and version",
-			log.Any("InitAppName", "baetyl-init"), log.Any("InitVersion", "1"), log.Error(err))
-
-		core, err := s.GetAppFromDesire(ns, nodeName, specV1.BaetylCore, true)
-		if err != nil {
-			return nil, errors.Trace(err)
-		}
-
-		// for node cert
-		init = &specV1.Application{
-			Name:      specV1.BaetylInit,
-			Namespace: core.Namespace,
-			Version:   "1",
-			Volumes:   core.Volumes,
-		}
+		return nil, errors.Trace(err)
 	}
 	cert, err := s.GetNodeCert(init)
 	if err != nil {
@@ -202,6 +200,37 @@ func (s *InitServiceImpl) getInitDeploymentYaml(ns, nodeName string, params map[
 	return s.TemplateService.ParseTemplate(templateInitDeploymentYaml, params)
 }
 
+// GetNodeCertificate returns the certificate of node which the init app mounts
+func (s *InitServiceImpl) GetNodeCertificate(ns, nodeName string) (*specV1.Secret, error) {
+	init, err := s.getInitApp(ns, nodeName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return s.GetNodeCert(init)
+}
+
+func (s *InitServiceImpl) getInitApp(ns, nodeName string) (*specV1.Application, error) {
+	init, err := s.GetAppFromDesire(ns, nodeName, specV1.BaetylInit, true)
+	if err == nil {
+		return init, nil
+	}
+	s.log.Warn("failed to get init app from desire, the init module will use the default name and version",
+		log.Any("InitAppName", "baetyl-init"), log.Any("InitVersion", "1"), log.Error(err))
+
+	core, err := s.GetAppFromDesire(ns, nodeName, specV1.BaetylCore, true)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// for node cert
+	return &specV1.Application{
+		Name:      specV1.BaetylInit,
+		Namespace: core.Namespace,
+		Version:   "1",
+		Volumes:   core.Volumes,
+	}, nil
+}
+
 // GetRegistryAuth add system registry auth if property exist
 func (s *InitServiceImpl) GetRegistryAuth() (string, error) {
 	registryAuth, err := s.Property.GetPropertyValue(common.RegistryAuth)
@@ -237,6 +266,9 @@ func (s *InitServiceImpl) GetNodeCert(app *specV1.Application) (*specV1.Secret,
 }
 
 func (s *InitServiceImpl) getWindowsInstallShell(ns, nodeName string, params map[string]interface{}) ([]byte, error) {
+	if err := s.renewSingleUseToken(ns, nodeName, params); err != nil {
+		return nil, err
+	}
 	params["DBPath"] = context.DefaultWindowsHostPathLib
 	data, err := s.TemplateService.ParseTemplate(templateBaetylWindowsInstallShell, params)
 	if err != nil {
@@ -246,7 +278,14 @@ func (s *InitServiceImpl) getWindowsInstallShell(ns, nodeName string, params map
 }
 
 func (s *InitServiceImpl) getInstallShell(ns, nodeName string, params map[string]interface{}) ([]byte, error) {
+	if err := s.renewSingleUseToken(ns, nodeName, params); err != nil {
+		return nil, err
+	}
 	params["DBPath"] = "/var/lib/baetyl"
+	// the shell of offline bundle applies the local init resource
+	if _, ok := params["Offline"]; !ok {
+		params["Offline"] = false
+	}
 	data, err := s.TemplateService.ParseTemplate(templateBaetylInstallShell, params)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -254,17 +293,15 @@ func (s *InitServiceImpl) getInstallShell(ns, nodeName string, params map[string
 	return data, nil
 }
 
+// GetInitCommand registers an install token for the node and renders the install command carrying it,
+// the token is single-use if params["singleUse"] is true
 func (s *InitServiceImpl) GetInitCommand(ns, nodeName string, params map[string]interface{}) ([]byte, error) {
-	info := map[string]interface{}{
-		InfoNamespace: ns,
-		InfoName:      nodeName,
-		InfoExpiry:    time.Now().Unix() + CmdExpirationInSeconds,
-	}
 	initCommand, err := s.Property.GetPropertyValue(params["template"].(string))
 	if err != nil {
 		return nil, err
 	}
-	token, err := s.SignService.GenToken(info)
+	singleUse, _ := params["singleUse"].(bool)
+	token, err := s.genInstallToken(ns, nodeName, singleUse)
 	if err != nil {
 		return nil, err
 	}
@@ -276,6 +313,36 @@ func (s *InitServiceImpl) GetInitCommand(ns, nodeName string, params map[string]
 	return data, nil
 }
 
+// renewSingleUseToken the single-use token which fetches the install shell is consumed already,
+// so the shell carries a new one to fetch the following resources if params["SingleUse"] is true
+func (s *InitServiceImpl) renewSingleUseToken(ns, nodeName string, params map[string]interface{}) error {
+	if singleUse, _ := params["SingleUse"].(bool); !singleUse {
+		return nil
+	}
+	token, err := s.genInstallToken(ns, nodeName, true)
+	if err != nil {
+		return err
+	}
+	params["Token"] = token
+	return nil
+}
+
+// genInstallToken registers an install token for the node and signs the token string carrying it
+func (s *InitServiceImpl) genInstallToken(ns, nodeName string, singleUse bool) (string, error) {
+	expiry := time.Now().Unix() + CmdExpirationInSeconds
+	installToken, err := s.InstallToken.Create(ns, nodeName, singleUse, time.Unix(expiry, 0))
+	if err != nil {
+		return "", err
+	}
+	info := map[string]interface{}{
+		InfoNamespace: ns,
+		InfoName:      nodeName,
+		InfoExpiry:    expiry,
+		InfoTokenID:   installToken.Name,
+	}
+	return s.SignService.GenToken(info)
+}
+
 func (s *InitServiceImpl) GetAppFromDesire(ns, nodeName, moduleName string, isSys bool) (*specV1.Application, error) {
 	shadowDesire, err := s.NodeService.GetDesire(ns, nodeName)
 	if err != nil {
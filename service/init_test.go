@@ -1,6 +1,7 @@
 package service
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
 )
 
 func TestInitService_GetResource(t *testing.T) {
@@ -166,28 +168,87 @@ func TestInitService_GenCmd(t *testing.T) {
 	sSign := service.NewMockSignService(mockCtl)
 	sTemplate := service.NewMockTemplateService(mockCtl)
 	sProp := service.NewMockPropertyService(mockCtl)
+	sInstallToken := service.NewMockInstallTokenService(mockCtl)
 	as := InitServiceImpl{}
 	as.SignService = sSign
 	as.TemplateService = sTemplate
 	as.Property = sProp
-	info := map[string]interface{}{
-		InfoName:      "name",
-		InfoNamespace: "ns",
-		InfoExpiry:    time.Now().Unix() + CmdExpirationInSeconds,
-	}
+	as.InstallToken = sInstallToken
 	expect := "curl -skfL 'https://1.2.3.4:9003/v1/active/setup.sh?token=tokenexpect' -osetup.sh && sh setup.sh"
 	params := map[string]interface{}{
 		"InitApplyYaml": "baetyl-init-deployment.yml",
 		"template":      TemplateBaetylInitCommand,
 		"mode":          "",
+		"singleUse":     true,
 	}
-	sSign.EXPECT().GenToken(info).Return("tokenexpect", nil).Times(1)
+	var expireTime time.Time
 	sProp.EXPECT().GetPropertyValue(TemplateBaetylInitCommand).Return(TemplateBaetylInitCommand, nil)
+	sInstallToken.EXPECT().Create("ns", "name", true, gomock.Any()).DoAndReturn(func(ns, node string, singleUse bool, expire time.Time) (*models.InstallToken, error) {
+		expireTime = expire
+		return &models.InstallToken{Name: "t1", Namespace: ns, NodeName: node, SingleUse: singleUse, ExpireTime: expire}, nil
+	}).Times(1)
+	sSign.EXPECT().GenToken(gomock.Any()).DoAndReturn(func(info map[string]interface{}) (string, error) {
+		assert.Equal(t, map[string]interface{}{
+			InfoName:      "name",
+			InfoNamespace: "ns",
+			InfoExpiry:    expireTime.Unix(),
+			InfoTokenID:   "t1",
+		}, info)
+		return "tokenexpect", nil
+	}).Times(1)
 	sTemplate.EXPECT().Execute("setup-command", TemplateBaetylInitCommand, gomock.Any()).Return([]byte(expect), nil).Times(1)
 
 	res, err := as.GetInitCommand("ns", "name", params)
 	assert.NoError(t, err)
 	assert.Equal(t, string(res), expect)
+
+	// failed to register the token
+	sProp.EXPECT().GetPropertyValue(TemplateBaetylInitCommand).Return(TemplateBaetylInitCommand, nil)
+	sInstallToken.EXPECT().Create("ns", "name", false, gomock.Any()).Return(nil, fmt.Errorf("error")).Times(1)
+	delete(params, "singleUse")
+	_, err = as.GetInitCommand("ns", "name", params)
+	assert.Error(t, err)
+}
+
+func TestInitService_getInstallShell(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	sSign := service.NewMockSignService(mockCtl)
+	sTemplate := service.NewMockTemplateService(mockCtl)
+	sInstallToken := service.NewMockInstallTokenService(mockCtl)
+	as := InitServiceImpl{}
+	as.SignService = sSign
+	as.TemplateService = sTemplate
+	as.InstallToken = sInstallToken
+
+	// the consumed single-use token is renewed for the following resources
+	params := map[string]interface{}{"Token": "consumed", "SingleUse": true}
+	sInstallToken.EXPECT().Create("ns", "name", true, gomock.Any()).Return(&models.InstallToken{Name: "t2"}, nil).Times(1)
+	sSign.EXPECT().GenToken(gomock.Any()).DoAndReturn(func(info map[string]interface{}) (string, error) {
+		assert.Equal(t, "t2", info[InfoTokenID])
+		return "renewed", nil
+	}).Times(1)
+	sTemplate.EXPECT().ParseTemplate(templateBaetylInstallShell, gomock.Any()).DoAndReturn(func(_ string, params map[string]interface{}) ([]byte, error) {
+		return []byte(params["Token"].(string)), nil
+	}).Times(1)
+	res, err := as.getInstallShell("ns", "name", params)
+	assert.NoError(t, err)
+	assert.Equal(t, "renewed", string(res))
+
+	// the reusable token is kept
+	params = map[string]interface{}{"Token": "reusable", "SingleUse": false}
+	sTemplate.EXPECT().ParseTemplate(templateBaetylWindowsInstallShell, gomock.Any()).DoAndReturn(func(_ string, params map[string]interface{}) ([]byte, error) {
+		return []byte(params["Token"].(string)), nil
+	}).Times(1)
+	res, err = as.getWindowsInstallShell("ns", "name", params)
+	assert.NoError(t, err)
+	assert.Equal(t, "reusable", string(res))
+
+	params = map[string]interface{}{"Token": "consumed", "SingleUse": true}
+	sInstallToken.EXPECT().Create("ns", "name", true, gomock.Any()).Return(nil, fmt.Errorf("error")).Times(1)
+	_, err = as.getInstallShell("ns", "name", params)
+	assert.Error(t, err)
 }
 
 func TestInitService_getDesireAppInfo(t *testing.T) {
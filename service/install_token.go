@@ -0,0 +1,145 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/install_token.go -package=service github.com/baetyl/baetyl-cloud/v2/service InstallTokenService
+
+const (
+	installTokenNameLength = 16
+	// the expired tokens are kept for a while with their consumptions for audit
+	installTokenRetention = 7 * 24 * time.Hour
+)
+
+// InstallTokenService registers the tokens of node install commands, the init server accepts the registered tokens only
+type InstallTokenService interface {
+	Get(namespace, name string) (*models.InstallToken, error)
+	Create(namespace, nodeName string, singleUse bool, expireTime time.Time) (*models.InstallToken, error)
+	// List lists the outstanding tokens of node, which are neither revoked nor expired
+	List(namespace, nodeName string) (*models.InstallTokenList, error)
+	Revoke(namespace, name string) error
+	// Consume records the use of token by the ip, the token is rejected if it doesn't belong to the node,
+	// or is revoked, expired, or single-use and consumed already
+	Consume(namespace, nodeName, name, ip string) (*models.InstallToken, error)
+	// ListConsumes lists the consumptions of token, the rejected ones included
+	ListConsumes(namespace, name string) (*models.InstallTokenConsumeList, error)
+	// PurgeExpired deletes the tokens of all namespaces which expired before the retention
+	PurgeExpired() error
+}
+
+type InstallTokenServiceImpl struct {
+	InstallToken plugin.InstallToken
+	log          *log.Logger
+}
+
+// NewInstallTokenService new install token service
+func NewInstallTokenService(config *config.CloudConfig) (InstallTokenService, error) {
+	token, err := plugin.GetPlugin(config.Plugin.InstallToken)
+	if err != nil {
+		return nil, err
+	}
+	return &InstallTokenServiceImpl{
+		InstallToken: token.(plugin.InstallToken),
+		log:          log.L().With(log.Any("service", "install_token")),
+	}, nil
+}
+
+func (s *InstallTokenServiceImpl) Get(namespace, name string) (*models.InstallToken, error) {
+	return s.InstallToken.GetInstallToken(nil, namespace, name)
+}
+
+func (s *InstallTokenServiceImpl) Create(namespace, nodeName string, singleUse bool, expireTime time.Time) (*models.InstallToken, error) {
+	token := &models.InstallToken{
+		Name:       strings.ToLower(common.RandString(installTokenNameLength)),
+		Namespace:  namespace,
+		NodeName:   nodeName,
+		SingleUse:  singleUse,
+		ExpireTime: expireTime,
+	}
+	if err := s.InstallToken.CreateInstallToken(nil, token); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return token, nil
+}
+
+func (s *InstallTokenServiceImpl) List(namespace, nodeName string) (*models.InstallTokenList, error) {
+	tokens, err := s.InstallToken.ListInstallToken(nil, namespace, nodeName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	now := time.Now()
+	items := []models.InstallToken{}
+	for _, token := range tokens {
+		if token.Revoked || token.ExpireTime.Before(now) {
+			continue
+		}
+		items = append(items, token)
+	}
+	return &models.InstallTokenList{Total: len(items), Items: items}, nil
+}
+
+func (s *InstallTokenServiceImpl) Revoke(namespace, name string) error {
+	return s.InstallToken.RevokeInstallToken(nil, namespace, name)
+}
+
+func (s *InstallTokenServiceImpl) Consume(namespace, nodeName, name, ip string) (*models.InstallToken, error) {
+	token, err := s.InstallToken.GetInstallToken(nil, namespace, name)
+	if err != nil {
+		if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+			s.log.Info("install token not registered", log.Any("namespace", namespace), log.Any("name", name))
+			return nil, common.Error(common.ErrInvalidToken)
+		}
+		return nil, errors.Trace(err)
+	}
+	if token.NodeName != nodeName {
+		s.log.Info("install token of other node", log.Any("namespace", namespace), log.Any("name", name), log.Any("node", nodeName))
+		return nil, common.Error(common.ErrInvalidToken)
+	}
+	if token.Revoked {
+		s.log.Info("install token revoked", log.Any("namespace", namespace), log.Any("name", name), log.Any("ip", ip))
+		return nil, common.Error(common.ErrInvalidToken)
+	}
+	if token.ExpireTime.Before(time.Now()) {
+		s.log.Info("install token expired", log.Any("namespace", namespace), log.Any("name", name), log.Any("ip", ip))
+		return nil, common.Error(common.ErrInvalidToken)
+	}
+	ok, err := s.InstallToken.ConsumeInstallToken(nil, namespace, name, ip)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !ok {
+		s.log.Warn("install token consumed already", log.Any("namespace", namespace), log.Any("name", name),
+			log.Any("ip", ip), log.Any("consumeIP", token.ConsumeIP))
+		return nil, common.Error(common.ErrInvalidToken)
+	}
+	return token, nil
+}
+
+func (s *InstallTokenServiceImpl) ListConsumes(namespace, name string) (*models.InstallTokenConsumeList, error) {
+	consumes, err := s.InstallToken.ListInstallTokenConsume(nil, namespace, name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &models.InstallTokenConsumeList{Total: len(consumes), Items: consumes}, nil
+}
+
+func (s *InstallTokenServiceImpl) PurgeExpired() error {
+	count, err := s.InstallToken.DeleteExpiredInstallTokens(nil, time.Now().Add(-installTokenRetention))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if count > 0 {
+		s.log.Info("purge expired install tokens", log.Any("count", count))
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestInstallTokenService_Create(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	is, err := NewInstallTokenService(mockObject.conf)
+	assert.NoError(t, err)
+
+	expire := time.Now().Add(time.Hour)
+	mockObject.installToken.EXPECT().CreateInstallToken(nil, gomock.Any()).Return(nil).Times(1)
+	token, err := is.Create("default", "n1", true, expire)
+	assert.NoError(t, err)
+	assert.Len(t, token.Name, installTokenNameLength)
+	assert.Equal(t, "n1", token.NodeName)
+	assert.True(t, token.SingleUse)
+	assert.Equal(t, expire, token.ExpireTime)
+
+	mockObject.installToken.EXPECT().CreateInstallToken(nil, gomock.Any()).Return(fmt.Errorf("error")).Times(1)
+	_, err = is.Create("default", "n1", false, expire)
+	assert.Error(t, err)
+}
+
+func TestInstallTokenService_List(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	is, err := NewInstallTokenService(mockObject.conf)
+	assert.NoError(t, err)
+
+	tokens := []models.InstallToken{
+		{Name: "t1", ExpireTime: time.Now().Add(time.Hour)},
+		{Name: "t2", ExpireTime: time.Now().Add(time.Hour), Revoked: true},
+		{Name: "t3", ExpireTime: time.Now().Add(-time.Hour)},
+	}
+	mockObject.installToken.EXPECT().ListInstallToken(nil, "default", "n1").Return(tokens, nil).Times(1)
+	res, err := is.List("default", "n1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, res.Total)
+	assert.Equal(t, "t1", res.Items[0].Name)
+}
+
+func TestInstallTokenService_Consume(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	is, err := NewInstallTokenService(mockObject.conf)
+	assert.NoError(t, err)
+
+	token := &models.InstallToken{Name: "t1", Namespace: "default", NodeName: "n1", SingleUse: true, ExpireTime: time.Now().Add(time.Hour)}
+	mockObject.installToken.EXPECT().GetInstallToken(nil, "default", "t1").Return(token, nil).Times(1)
+	mockObject.installToken.EXPECT().ConsumeInstallToken(nil, "default", "t1", "10.0.0.1").Return(true, nil).Times(1)
+	res, err := is.Consume("default", "n1", "t1", "10.0.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, token, res)
+
+	// consumed already
+	mockObject.installToken.EXPECT().GetInstallToken(nil, "default", "t1").Return(token, nil).Times(1)
+	mockObject.installToken.EXPECT().ConsumeInstallToken(nil, "default", "t1", "10.0.0.2").Return(false, nil).Times(1)
+	_, err = is.Consume("default", "n1", "t1", "10.0.0.2")
+	assert.Equal(t, common.Error(common.ErrInvalidToken).Error(), err.Error())
+
+	// the token of other node
+	mockObject.installToken.EXPECT().GetInstallToken(nil, "default", "t1").Return(token, nil).Times(1)
+	_, err = is.Consume("default", "n2", "t1", "10.0.0.1")
+	assert.Equal(t, common.Error(common.ErrInvalidToken).Error(), err.Error())
+
+	// revoked
+	revoked := *token
+	revoked.Revoked = true
+	mockObject.installToken.EXPECT().GetInstallToken(nil, "default", "t1").Return(&revoked, nil).Times(1)
+	_, err = is.Consume("default", "n1", "t1", "10.0.0.1")
+	assert.Equal(t, common.Error(common.ErrInvalidToken).Error(), err.Error())
+
+	// expired
+	expired := *token
+	expired.ExpireTime = time.Now().Add(-time.Minute)
+	mockObject.installToken.EXPECT().GetInstallToken(nil, "default", "t1").Return(&expired, nil).Times(1)
+	_, err = is.Consume("default", "n1", "t1", "10.0.0.1")
+	assert.Equal(t, common.Error(common.ErrInvalidToken).Error(), err.Error())
+
+	// not registered
+	mockObject.installToken.EXPECT().GetInstallToken(nil, "default", "t2").
+		Return(nil, common.Error(common.ErrResourceNotFound, common.Field("type", "token"))).Times(1)
+	_, err = is.Consume("default", "n1", "t2", "10.0.0.1")
+	assert.Equal(t, common.Error(common.ErrInvalidToken).Error(), err.Error())
+
+	mockObject.installToken.EXPECT().GetInstallToken(nil, "default", "t2").Return(nil, fmt.Errorf("error")).Times(1)
+	_, err = is.Consume("default", "n1", "t2", "10.0.0.1")
+	assert.Error(t, err)
+}
+
+func TestInstallTokenService_ListConsumes(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	is, err := NewInstallTokenService(mockObject.conf)
+	assert.NoError(t, err)
+
+	consumes := []models.InstallTokenConsume{{Name: "t1", Namespace: "default", ConsumeIP: "10.0.0.1", Accepted: true},
+		{Name: "t1", Namespace: "default", ConsumeIP: "10.0.0.2"}}
+	mockObject.installToken.EXPECT().ListInstallTokenConsume(nil, "default", "t1").Return(consumes, nil).Times(1)
+	res, err := is.ListConsumes("default", "t1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, res.Total)
+	assert.Equal(t, consumes, res.Items)
+
+	mockObject.installToken.EXPECT().ListInstallTokenConsume(nil, "default", "t1").Return(nil, fmt.Errorf("error")).Times(1)
+	_, err = is.ListConsumes("default", "t1")
+	assert.Error(t, err)
+}
+
+func TestInstallTokenService_PurgeExpired(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	is, err := NewInstallTokenService(mockObject.conf)
+	assert.NoError(t, err)
+
+	mockObject.installToken.EXPECT().DeleteExpiredInstallTokens(nil, gomock.Any()).DoAndReturn(func(_ interface{}, before time.Time) (int64, error) {
+		assert.WithinDuration(t, time.Now().Add(-installTokenRetention), before, time.Minute)
+		return 2, nil
+	}).Times(1)
+	assert.NoError(t, is.PurgeExpired())
+
+	mockObject.installToken.EXPECT().DeleteExpiredInstallTokens(nil, gomock.Any()).Return(int64(0), fmt.Errorf("error")).Times(1)
+	assert.Error(t, is.PurgeExpired())
+}
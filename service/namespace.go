@@ -29,6 +29,7 @@ type NamespaceService interface {
 
 type NamespaceServiceImpl struct {
 	namespace plugin.Namespace
+	PKI       PKIService
 	Hooks     map[string]interface{}
 }
 
@@ -38,8 +39,13 @@ func NewNamespaceService(config *config.CloudConfig) (NamespaceService, error) {
 	if err != nil {
 		return nil, err
 	}
+	pki, err := NewPKIService(config)
+	if err != nil {
+		return nil, err
+	}
 	return &NamespaceServiceImpl{
 		namespace: ms.(plugin.Namespace),
+		PKI:       pki,
 		Hooks:     make(map[string]interface{}),
 	}, nil
 }
@@ -69,12 +75,15 @@ func (s *NamespaceServiceImpl) List(listOptions *models.ListOptions) (*models.Na
 	return s.namespace.ListNamespace(listOptions)
 }
 
-// Delete Delete the namespace
+// Delete Delete the namespace, the intermediate ca of namespace is revoked
 func (s *NamespaceServiceImpl) Delete(namespace *models.Namespace) error {
 	if deleteFunc, ok := s.Hooks[KeyDeleteExtraNamespaceResources].(DeleteExtraNamespaceResourcesFunc); ok {
 		if err := deleteFunc(namespace.Name); err != nil {
 			return errors.Trace(err)
 		}
 	}
+	if err := s.PKI.RevokeNamespaceCA(namespace.Name); err != nil {
+		return errors.Trace(err)
+	}
 	return s.namespace.DeleteNamespace(namespace)
 }
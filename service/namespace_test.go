@@ -74,9 +74,15 @@ func TestNamespaceService_Delete(t *testing.T) {
 
 	name := "user-id-test"
 	ns := &models.Namespace{Name: name}
+	mockObject.pki.EXPECT().RevokeNamespaceCert(name).Return(nil)
 	mockObject.namespace.EXPECT().DeleteNamespace(ns).Return(nil)
 	cs, err := NewNamespaceService(mockObject.conf)
 	assert.NoError(t, err)
 	err = cs.Delete(ns)
 	assert.NoError(t, err)
+
+	// the namespace is kept if its ca is not revoked
+	mockObject.pki.EXPECT().RevokeNamespaceCert(name).Return(fmt.Errorf("error"))
+	err = cs.Delete(ns)
+	assert.Error(t, err)
 }
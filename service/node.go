@@ -2,6 +2,7 @@ package service
 
 import (
 	"fmt"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
@@ -63,6 +64,8 @@ type NodeService interface {
 type NodeServiceImpl struct {
 	IndexService  IndexService
 	App           plugin.Application
+	Config        plugin.Configuration
+	AppHistory    plugin.AppHistory
 	Node          plugin.Node
 	Shadow        plugin.Shadow
 	Cache         plugin.DataCache
@@ -92,6 +95,11 @@ func NewNodeService(config *config.CloudConfig) (NodeService, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	history, err := plugin.GetPlugin(config.Plugin.AppHistory)
+	if err != nil {
+		return nil, err
+	}
 	system, err := NewSystemAppService(config)
 	if err != nil {
 		return nil, err
@@ -125,6 +133,8 @@ func NewNodeService(config *config.CloudConfig) (NodeService, error) {
 		Node:          node.(plugin.Node),
 		Shadow:        shadow.(plugin.Shadow),
 		App:           app.(plugin.Application),
+		Config:        app.(plugin.Configuration),
+		AppHistory:    history.(plugin.AppHistory),
 		Hooks:         make(map[string]interface{}),
 		Cache:         cache.(plugin.DataCache),
 		logger:        log.With(log.Any("service", "node")),
@@ -175,6 +185,10 @@ func (n *NodeServiceImpl) Create(tx interface{}, namespace string, node *specV1.
 
 // Update update node
 func (n *NodeServiceImpl) Update(namespace string, node *specV1.Node) (*specV1.Node, error) {
+	oldNode, err := n.Node.GetNode(nil, namespace, node.Name)
+	if err != nil {
+		return nil, err
+	}
 	list, err := n.Node.UpdateNode(nil, namespace, []*specV1.Node{node})
 	if err != nil || len(list) < 1 {
 		return nil, err
@@ -194,6 +208,12 @@ func (n *NodeServiceImpl) Update(namespace string, node *specV1.Node) (*specV1.N
 	if err = n.InsertOrUpdateNodeAndAppIndex(nil, namespace, res, shadow, false); err != nil {
 		return nil, err
 	}
+
+	oldCtx := genPlaceholderContext(oldNode, shadow.Desire, shadow.Report)
+	newCtx := genPlaceholderContext(res, shadow.Desire, shadow.Report)
+	if err = n.refreshRenderedApps(namespace, shadow, oldCtx, newCtx); err != nil {
+		return nil, err
+	}
 	return res, nil
 }
 
@@ -1002,11 +1022,16 @@ func (n *NodeServiceImpl) UpdateNodeProperties(namespace, name string, props *mo
 	props.Meta.ReportMeta = meta.ReportMeta
 	props.Meta.DesireMeta = meta.DesireMeta
 	// cast to map[string]interface{} should not omit
+	oldCtx := genPlaceholderContext(node, shadow.Desire, shadow.Report)
 	shadow.Desire[common.NodeProps] = map[string]interface{}(newDesire)
 	err = n.Shadow.UpdateDesire(nil, shadow)
 	if err != nil {
 		return nil, err
 	}
+	newCtx := genPlaceholderContext(node, shadow.Desire, shadow.Report)
+	if err = n.refreshRenderedApps(namespace, shadow, oldCtx, newCtx); err != nil {
+		return nil, err
+	}
 	updateNodePropertiesMeta(node, meta)
 	if _, err := n.Node.UpdateNode(nil, namespace, []*specV1.Node{node}); err != nil {
 		return nil, err
@@ -1112,3 +1137,72 @@ func filterNodeListByNodeSelector(list *models.NodeList) *models.NodeList {
 		Items:       items[start:end],
 	}
 }
+
+// refreshRenderedApps changes the desired versions of apps whose configs are rendered differently for the node,
+// so that the node fetches the apps and the rendered configs again. The versions desired by the node are kept,
+// the node which is held back by rollout isn't moved onto the latest version of app
+func (n *NodeServiceImpl) refreshRenderedApps(namespace string, shadow *models.Shadow, oldCtx, newCtx *common.NodePlaceholderContext) error {
+	if reflect.DeepEqual(oldCtx, newCtx) || shadow.Desire == nil {
+		return nil
+	}
+	changed := false
+	for _, isSys := range []bool{false, true} {
+		infos := shadow.Desire.AppInfos(isSys)
+		for i := range infos {
+			version := baseVersion(infos[i].Version)
+			app, err := n.getDesiredApp(namespace, infos[i].Name, version)
+			if err != nil {
+				if isNotFound(err) {
+					continue
+				}
+				return err
+			}
+			var configs []specV1.Configuration
+			for _, v := range app.Volumes {
+				if v.Config == nil {
+					continue
+				}
+				cfg, err := n.Config.GetConfig(nil, namespace, v.Config.Name, v.Config.Version)
+				if err != nil {
+					if isNotFound(err) {
+						continue
+					}
+					return err
+				}
+				configs = append(configs, *cfg)
+			}
+			oldVersion := renderedAppVersion(version, configs, oldCtx)
+			newVersion := renderedAppVersion(version, configs, newCtx)
+			if oldVersion != newVersion {
+				infos[i].Version = newVersion
+				changed = true
+			}
+		}
+		if changed {
+			shadow.Desire.SetAppInfos(isSys, infos)
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return n.Shadow.UpdateDesire(nil, shadow)
+}
+
+// getDesiredApp returns the app of the version desired by node, the revision is used if the version isn't the latest
+func (n *NodeServiceImpl) getDesiredApp(namespace, name, version string) (*specV1.Application, error) {
+	app, err := n.App.GetApplication(nil, namespace, name, version)
+	if err != nil {
+		return nil, err
+	}
+	if version == "" || app.Version == version {
+		return app, nil
+	}
+	h, err := n.AppHistory.GetAppHistoryByVersion(nil, namespace, name, version)
+	if err != nil {
+		return nil, err
+	}
+	if h.Application == nil {
+		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "app"), common.Field("name", name))
+	}
+	return h.Application, nil
+}
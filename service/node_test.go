@@ -3,6 +3,7 @@ package service
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -327,10 +328,15 @@ func TestDefaultNodeService_Update(t *testing.T) {
 	mockObject.shadow.EXPECT().UpdateDesire(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 	mockObject.shadow.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(shadow, nil).AnyTimes()
 
-	mockObject.node.EXPECT().UpdateNode(nil, node.Namespace, []*specV1.Node{node}).Return(nil, fmt.Errorf("error"))
+	mockObject.node.EXPECT().GetNode(nil, node.Namespace, node.Name).Return(nil, fmt.Errorf("error")).Times(1)
 	_, err := ns.Update(node.Namespace, node)
 	assert.NotNil(t, err)
 
+	mockObject.node.EXPECT().GetNode(nil, node.Namespace, node.Name).Return(node, nil).AnyTimes()
+	mockObject.node.EXPECT().UpdateNode(nil, node.Namespace, []*specV1.Node{node}).Return(nil, fmt.Errorf("error"))
+	_, err = ns.Update(node.Namespace, node)
+	assert.NotNil(t, err)
+
 	mockObject.node.EXPECT().UpdateNode(nil, node.Namespace, []*specV1.Node{node}).Return([]*specV1.Node{node}, nil).AnyTimes()
 	mockIndexService.EXPECT().RefreshAppsIndexByNode(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("error"))
 	_, err = ns.Update(node.Namespace, node)
@@ -371,6 +377,131 @@ func TestDefaultNodeService_Update(t *testing.T) {
 	assert.Equal(t, node.Name, shad.Name)
 }
 
+func TestRefreshRenderedApps(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	ns := NodeServiceImpl{
+		Shadow:     mockObject.shadow,
+		Node:       mockObject.node,
+		App:        mockObject.app,
+		Config:     mockObject.configuration,
+		AppHistory: mockObject.appHistory,
+		logger:     log.With(log.Any("service", "node")),
+	}
+
+	shadow := &models.Shadow{
+		Namespace: "default",
+		Name:      "node01",
+		Desire: specV1.Desire{
+			"apps": []interface{}{
+				map[string]interface{}{"name": "app01", "version": "1"},
+				map[string]interface{}{"name": "app02", "version": "2"},
+			},
+		},
+	}
+	app01 := &specV1.Application{
+		Name:    "app01",
+		Version: "1",
+		Volumes: []specV1.Volume{
+			{Name: "cfg", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "cfg01", Version: "10"}}},
+		},
+	}
+	app02 := &specV1.Application{
+		Name:    "app02",
+		Version: "2",
+		Volumes: []specV1.Volume{
+			{Name: "cfg", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "cfg02", Version: "20"}}},
+		},
+	}
+	cfg01 := &specV1.Configuration{Name: "cfg01", Version: "10", Data: map[string]string{"conf.yml": "site: ${node.labels.site}"}}
+	cfg02 := &specV1.Configuration{Name: "cfg02", Version: "20", Data: map[string]string{"conf.yml": "name: ${node.name}"}}
+
+	oldCtx := &common.NodePlaceholderContext{Name: "node01", Labels: map[string]string{"site": "a"}}
+	newCtx := &common.NodePlaceholderContext{Name: "node01", Labels: map[string]string{"site": "b"}}
+
+	// nothing changed
+	assert.NoError(t, ns.refreshRenderedApps("default", shadow, oldCtx, oldCtx))
+
+	mockObject.app.EXPECT().GetApplication(nil, "default", "app01", "1").Return(app01, nil).Times(1)
+	mockObject.app.EXPECT().GetApplication(nil, "default", "app02", "2").Return(app02, nil).Times(1)
+	mockObject.configuration.EXPECT().GetConfig(nil, "default", "cfg01", "10").Return(cfg01, nil).Times(1)
+	mockObject.configuration.EXPECT().GetConfig(nil, "default", "cfg02", "20").Return(cfg02, nil).Times(1)
+	mockObject.shadow.EXPECT().UpdateDesire(nil, shadow).Return(nil).Times(1)
+	assert.NoError(t, ns.refreshRenderedApps("default", shadow, oldCtx, newCtx))
+
+	infos := shadow.Desire.AppInfos(false)
+	assert.Equal(t, renderedAppVersion("1", []specV1.Configuration{*cfg01}, newCtx), infos[0].Version)
+	assert.True(t, strings.HasPrefix(infos[0].Version, "1-"))
+	assert.Equal(t, "2", infos[1].Version)
+}
+
+func TestRefreshRenderedApps_HeldBackByRollout(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	ns := NodeServiceImpl{
+		Shadow:     mockObject.shadow,
+		Node:       mockObject.node,
+		App:        mockObject.app,
+		Config:     mockObject.configuration,
+		AppHistory: mockObject.appHistory,
+		logger:     log.With(log.Any("service", "node")),
+	}
+
+	// the node isn't released by the rollout of version 2 yet
+	shadow := &models.Shadow{
+		Namespace: "default",
+		Name:      "node01",
+		Desire: specV1.Desire{
+			"apps": []interface{}{
+				map[string]interface{}{"name": "app01", "version": "1"},
+			},
+		},
+	}
+	latest := &specV1.Application{
+		Name:    "app01",
+		Version: "2",
+		Volumes: []specV1.Volume{
+			{Name: "cfg", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "cfg01", Version: "11"}}},
+		},
+	}
+	previous := &specV1.Application{
+		Name:    "app01",
+		Version: "1",
+		Volumes: []specV1.Volume{
+			{Name: "cfg", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "cfg01", Version: "10"}}},
+		},
+	}
+	cfg01 := &specV1.Configuration{Name: "cfg01", Version: "10", Data: map[string]string{"conf.yml": "site: ${node.labels.site}"}}
+	oldCtx := &common.NodePlaceholderContext{Name: "node01", Labels: map[string]string{"site": "a"}}
+	newCtx := &common.NodePlaceholderContext{Name: "node01", Labels: map[string]string{"site": "b"}}
+
+	mockObject.app.EXPECT().GetApplication(nil, "default", "app01", "1").Return(latest, nil).Times(1)
+	mockObject.appHistory.EXPECT().GetAppHistoryByVersion(nil, "default", "app01", "1").Return(&models.AppHistory{Version: "1", Application: previous}, nil).Times(1)
+	mockObject.configuration.EXPECT().GetConfig(nil, "default", "cfg01", "10").Return(cfg01, nil).Times(1)
+	mockObject.shadow.EXPECT().UpdateDesire(nil, shadow).Return(nil).Times(1)
+	assert.NoError(t, ns.refreshRenderedApps("default", shadow, oldCtx, newCtx))
+
+	infos := shadow.Desire.AppInfos(false)
+	assert.Equal(t, renderedAppVersion("1", []specV1.Configuration{*cfg01}, newCtx), infos[0].Version)
+	assert.True(t, strings.HasPrefix(infos[0].Version, "1-"))
+
+	// the label changes again, the rendered version is still based on the held back version
+	renderedCtx := &common.NodePlaceholderContext{Name: "node01", Labels: map[string]string{"site": "c"}}
+	mockObject.app.EXPECT().GetApplication(nil, "default", "app01", "1").Return(latest, nil).Times(1)
+	mockObject.appHistory.EXPECT().GetAppHistoryByVersion(nil, "default", "app01", "1").Return(&models.AppHistory{Version: "1", Application: previous}, nil).Times(1)
+	mockObject.configuration.EXPECT().GetConfig(nil, "default", "cfg01", "10").Return(cfg01, nil).Times(1)
+	mockObject.shadow.EXPECT().UpdateDesire(nil, shadow).Return(nil).Times(1)
+	assert.NoError(t, ns.refreshRenderedApps("default", shadow, newCtx, renderedCtx))
+	infos = shadow.Desire.AppInfos(false)
+	assert.Equal(t, renderedAppVersion("1", []specV1.Configuration{*cfg01}, renderedCtx), infos[0].Version)
+
+	// the revision of held back version isn't found, the desire is kept
+	mockObject.app.EXPECT().GetApplication(nil, "default", "app01", "1").Return(latest, nil).Times(1)
+	mockObject.appHistory.EXPECT().GetAppHistoryByVersion(nil, "default", "app01", "1").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	assert.NoError(t, ns.refreshRenderedApps("default", shadow, renderedCtx, oldCtx))
+	assert.Equal(t, infos, shadow.Desire.AppInfos(false))
+}
+
 func TestUpdateNodeAppVersion(t *testing.T) {
 	mockObject := InitMockEnvironment(t)
 	defer mockObject.Close()
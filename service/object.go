@@ -21,7 +21,10 @@ type ObjectService interface {
 	GenInternalObjectURL(userID string, bucket, object, source string) (*models.ObjectURL, error)
 	GenInternalObjectPutURL(userID string, bucket, object, source string) (*models.ObjectURL, error)
 	PutInternalObject(userID, bucket, name, source string, b []byte) error
+	PutInternalObjectFromFile(userID, bucket, name, source, filename string) error
+	GetInternalObject(userID, bucket, name, source string) (*models.Object, error)
 	HeadInternalObject(userID, bucket, name, source string) (*models.ObjectMeta, error)
+	DeleteInternalObject(userID, bucket, name, source string) error
 
 	ListExternalBuckets(info models.ExternalObjectInfo, source string) ([]models.Bucket, error)
 	ListExternalBucketObjects(info models.ExternalObjectInfo, bucket, source string) (*models.ListObjectsResult, error)
@@ -166,6 +169,30 @@ func (c *objectService) PutInternalObject(userID, bucket, name, source string, b
 	return objectPlugin.PutInternalObject(userID, bucket, name, b)
 }
 
+func (c *objectService) PutInternalObjectFromFile(userID, bucket, name, source, filename string) error {
+	objectPlugin, ok := c.objects[source]
+	if !ok {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("the source (%s) is not supported", source)))
+	}
+	return objectPlugin.PutInternalObjectFromFile(userID, bucket, name, filename)
+}
+
+func (c *objectService) GetInternalObject(userID, bucket, name, source string) (*models.Object, error) {
+	objectPlugin, ok := c.objects[source]
+	if !ok {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("the source (%s) is not supported", source)))
+	}
+	return objectPlugin.GetInternalObject(userID, bucket, name)
+}
+
+func (c *objectService) DeleteInternalObject(userID, bucket, name, source string) error {
+	objectPlugin, ok := c.objects[source]
+	if !ok {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("the source (%s) is not supported", source)))
+	}
+	return objectPlugin.DeleteInternalObject(userID, bucket, name)
+}
+
 func (c *objectService) HeadInternalObject(userID, bucket, name, source string) (*models.ObjectMeta, error) {
 	objectPlugin, ok := c.objects[source]
 	if !ok {
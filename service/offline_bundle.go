@@ -0,0 +1,456 @@
+package service
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	gohttp "net/http"
+	"os"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/context"
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/http"
+	"github.com/baetyl/baetyl-go/v2/json"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"gopkg.in/yaml.v2"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+//go:generate mockgen -destination=../mock/service/offline_bundle.go -package=service github.com/baetyl/baetyl-cloud/v2/service OfflineBundleService
+
+// the files of offline bundle
+const (
+	OfflineBundleManifest  = "manifest.json"
+	OfflineBundleChecksums = "SHA256SUMS"
+	OfflineBundleImages    = "images.txt"
+)
+
+var (
+	initApplyResources = map[string]string{
+		context.RunModeKube:   templateInitDeploymentYaml,
+		context.RunModeNative: "baetyl-init-apply.json",
+	}
+)
+
+// OfflineBundleService builds the self-contained install bundle of node for the sites without network
+type OfflineBundleService interface {
+	// Build packs the install script, the init resource, the node certificate, the manifests of system apps
+	// and the list of images and programs into a tar.gz, which is installed by running baetyl-install.sh in it.
+	// The tar.gz is streamed by the returned reader, which must be closed to remove the downloaded programs
+	Build(namespace, nodeName string, options *models.OfflineBundleOptions) (io.ReadCloser, error)
+}
+
+type OfflineBundleServiceImpl struct {
+	Init       InitService
+	Node       NodeService
+	Module     ModuleService
+	AppHistory AppHistoryService
+	*AppCombinedService
+	client *http.Client
+}
+
+// bundleFile the file of bundle, the content is either in data or in the temp file if it's large
+type bundleFile struct {
+	path   string
+	mode   int64
+	data   []byte
+	file   string
+	size   int64
+	sha256 string
+}
+
+// bundleReader streams the tar.gz of bundle files, which is written by another goroutine
+type bundleReader struct {
+	*io.PipeReader
+	files []bundleFile
+	done  chan struct{}
+}
+
+// NewOfflineBundleService new offline bundle service
+func NewOfflineBundleService(config *config.CloudConfig) (OfflineBundleService, error) {
+	initService, err := NewInitService(config)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	nodeService, err := NewNodeService(config)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	moduleService, err := NewModuleService(config)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	historyService, err := NewAppHistoryService(config)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	acs, err := NewAppCombinedService(config)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &OfflineBundleServiceImpl{
+		Init:               initService,
+		Node:               nodeService,
+		Module:             moduleService,
+		AppHistory:         historyService,
+		AppCombinedService: acs,
+		client:             http.NewClient(http.NewClientOptions()),
+	}, nil
+}
+
+func (s *OfflineBundleServiceImpl) Build(namespace, nodeName string, options *models.OfflineBundleOptions) (io.ReadCloser, error) {
+	node, err := s.Node.Get(nil, namespace, nodeName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	mode := options.Mode
+	if mode == "" {
+		mode = node.NodeMode
+	}
+	if mode == "" {
+		mode = context.RunModeKube
+	}
+	initApply, ok := initApplyResources[mode]
+	if !ok {
+		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("mode", mode))
+	}
+
+	var files []bundleFile
+	shell, err := s.Init.GetResource(namespace, nodeName, templateBaetylInstallShell, s.resourceParams(mode, initApply, true))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	files = append(files, bundleFile{path: templateBaetylInstallShell, mode: 0755, data: shell.([]byte)})
+	apply, err := s.Init.GetResource(namespace, nodeName, initApply, s.resourceParams(mode, initApply, false))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	files = append(files, bundleFile{path: initApply, mode: 0600, data: apply.([]byte)})
+
+	cert, err := s.Init.GetNodeCertificate(namespace, nodeName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var certFiles []string
+	for k := range cert.Data {
+		certFiles = append(certFiles, k)
+	}
+	sort.Strings(certFiles)
+	for _, k := range certFiles {
+		files = append(files, bundleFile{path: path.Join("certs", k), mode: 0600, data: cert.Data[k]})
+	}
+
+	apps, manifests, err := s.getSystemApps(namespace, nodeName, cert.Name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	files = append(files, manifests...)
+
+	manifest := &models.OfflineBundleManifest{
+		Namespace:  namespace,
+		NodeName:   nodeName,
+		Mode:       mode,
+		Platform:   options.Platform,
+		CreateTime: time.Now().UTC(),
+		Images:     []models.OfflineBundleImage{},
+		Programs:   []models.OfflineBundleProgram{},
+	}
+	programs, err := s.collectModules(apps, manifest)
+	if err != nil {
+		removeBundleFiles(programs)
+		return nil, errors.Trace(err)
+	}
+	files = append(files, programs...)
+	images := &bytes.Buffer{}
+	for _, image := range manifest.Images {
+		images.WriteString(image.Image + "\n")
+	}
+	files = append(files, bundleFile{path: OfflineBundleImages, mode: 0644, data: images.Bytes()})
+
+	checksums := &bytes.Buffer{}
+	manifest.Files = []models.OfflineBundleFile{}
+	for i, f := range files {
+		if f.file == "" {
+			sum := sha256.Sum256(f.data)
+			f.size, f.sha256 = int64(len(f.data)), hex.EncodeToString(sum[:])
+			files[i] = f
+		}
+		manifest.Files = append(manifest.Files, models.OfflineBundleFile{Path: f.path, Size: int(f.size), SHA256: f.sha256})
+		checksums.WriteString(fmt.Sprintf("%s  %s\n", f.sha256, f.path))
+	}
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		removeBundleFiles(programs)
+		return nil, errors.Trace(err)
+	}
+	sum := sha256.Sum256(data)
+	checksums.WriteString(fmt.Sprintf("%x  %s\n", sum, OfflineBundleManifest))
+	files = append(files,
+		bundleFile{path: OfflineBundleManifest, mode: 0644, data: data},
+		bundleFile{path: OfflineBundleChecksums, mode: 0644, data: checksums.Bytes()})
+	return newBundleReader(files, manifest.CreateTime), nil
+}
+
+// resourceParams returns the params of init resources, the install shell applies the init resource beside it if offline
+func (s *OfflineBundleServiceImpl) resourceParams(mode, initApply string, offline bool) map[string]interface{} {
+	return map[string]interface{}{
+		"Token":          "",
+		"KubeNodeName":   "",
+		"InitApplyYaml":  initApply,
+		"Mode":           mode,
+		"BaetylHostPath": "",
+		"Offline":        offline,
+	}
+}
+
+// getSystemApps returns the system apps of the versions desired by node, and the manifests of them
+// and the configs and secrets they reference, the node certificate is packed separately
+func (s *OfflineBundleServiceImpl) getSystemApps(namespace, nodeName, certName string) ([]*specV1.Application, []bundleFile, error) {
+	desire, err := s.Node.GetDesire(namespace, nodeName)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	var apps []*specV1.Application
+	var files []bundleFile
+	visited := map[string]bool{string(common.Secret) + "/" + certName: true}
+	for _, info := range desire.AppInfos(true) {
+		app, err := s.getDesiredApp(namespace, info.Name, baseVersion(info.Version))
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		data, err := yaml.Marshal(app)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		apps = append(apps, app)
+		files = append(files, bundleFile{path: path.Join("apps", app.Name+".yml"), mode: 0644, data: data})
+		for _, v := range app.Volumes {
+			if v.Config != nil && !visited[string(common.Config)+"/"+v.Config.Name] {
+				visited[string(common.Config)+"/"+v.Config.Name] = true
+				cfg, err := s.Config.Get(nil, namespace, v.Config.Name, v.Config.Version)
+				if err != nil {
+					return nil, nil, errors.Trace(err)
+				}
+				if err = checkDesiredVersion(common.Config, v.Config, cfg.Version); err != nil {
+					return nil, nil, err
+				}
+				data, err = yaml.Marshal(cfg)
+				if err != nil {
+					return nil, nil, errors.Trace(err)
+				}
+				files = append(files, bundleFile{path: path.Join("configs", cfg.Name+".yml"), mode: 0644, data: data})
+			}
+			if v.Secret != nil && !visited[string(common.Secret)+"/"+v.Secret.Name] {
+				visited[string(common.Secret)+"/"+v.Secret.Name] = true
+				secret, err := s.Secret.Get(namespace, v.Secret.Name, v.Secret.Version)
+				if err != nil {
+					return nil, nil, errors.Trace(err)
+				}
+				if err = checkDesiredVersion(common.Secret, v.Secret, secret.Version); err != nil {
+					return nil, nil, err
+				}
+				data, err = yaml.Marshal(secret)
+				if err != nil {
+					return nil, nil, errors.Trace(err)
+				}
+				files = append(files, bundleFile{path: path.Join("secrets", secret.Name+".yml"), mode: 0600, data: data})
+			}
+		}
+	}
+	return apps, files, nil
+}
+
+// getDesiredApp returns the app of the version desired by node, the revision is used if the version isn't the latest
+func (s *OfflineBundleServiceImpl) getDesiredApp(namespace, name, version string) (*specV1.Application, error) {
+	app, err := s.App.Get(namespace, name, version)
+	if err != nil {
+		return nil, err
+	}
+	if version == "" || app.Version == version {
+		return app, nil
+	}
+	h, err := s.AppHistory.GetByVersion(namespace, name, version)
+	if err != nil {
+		return nil, err
+	}
+	if h.Application == nil {
+		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "app"), common.Field("name", name))
+	}
+	return h.Application, nil
+}
+
+// checkDesiredVersion returns an error if the referenced resource is modified after the app desired by node,
+// since the former versions of configs and secrets aren't kept
+func checkDesiredVersion(typ common.Resource, ref *specV1.ObjectReference, version string) error {
+	if ref.Version != "" && ref.Version != version {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error",
+			fmt.Sprintf("the %s (%s) is modified since the version (%s) desired by node", typ, ref.Name, ref.Version)))
+	}
+	return nil
+}
+
+// collectModules fills the images of apps and the programs of the modules matching the images into manifest,
+// the program packages of the platform of manifest are downloaded and returned
+func (s *OfflineBundleServiceImpl) collectModules(apps []*specV1.Application, manifest *models.OfflineBundleManifest) ([]bundleFile, error) {
+	modules, err := s.Module.ListModules(&models.Filter{}, "")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	moduleByImage := map[string]models.Module{}
+	for _, m := range modules {
+		if m.Image != "" {
+			moduleByImage[m.Image] = m
+		}
+	}
+
+	var files []bundleFile
+	visited := map[string]bool{}
+	for _, app := range apps {
+		services := append(append([]specV1.Service{}, app.InitServices...), app.Services...)
+		for _, svc := range services {
+			if svc.Image == "" || visited[svc.Image] {
+				continue
+			}
+			visited[svc.Image] = true
+			module, ok := moduleByImage[svc.Image]
+			if !ok {
+				manifest.Images = append(manifest.Images, models.OfflineBundleImage{Image: svc.Image})
+				continue
+			}
+			manifest.Images = append(manifest.Images, models.OfflineBundleImage{Image: svc.Image, Module: module.Name, Version: module.Version})
+			var platforms []string
+			for platform := range module.Programs {
+				platforms = append(platforms, platform)
+			}
+			sort.Strings(platforms)
+			for _, platform := range platforms {
+				if manifest.Platform != "" && platform != manifest.Platform {
+					continue
+				}
+				program := models.OfflineBundleProgram{
+					Module:   module.Name,
+					Version:  module.Version,
+					Platform: platform,
+					URL:      module.Programs[platform],
+				}
+				if manifest.Platform != "" {
+					program.File = path.Join("programs", path.Base(program.URL))
+					f, err := s.download(program.URL, program.File)
+					if err != nil {
+						return files, errors.Trace(err)
+					}
+					program.SHA256 = f.sha256
+					files = append(files, *f)
+				}
+				manifest.Programs = append(manifest.Programs, program)
+			}
+		}
+	}
+	return files, nil
+}
+
+// download saves the program package into a temp file while hashing it
+func (s *OfflineBundleServiceImpl) download(url, filePath string) (*bundleFile, error) {
+	resp, err := s.client.GetURL(url)
+	if err != nil {
+		return nil, common.Error(common.ErrIO, common.Field("error", err.Error()))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != gohttp.StatusOK {
+		return nil, common.Error(common.ErrIO, common.Field("error", fmt.Sprintf("failed to download %s: %s", url, resp.Status)))
+	}
+	file, err := ioutil.TempFile("", "offline-bundle-")
+	if err != nil {
+		return nil, common.Error(common.ErrIO, common.Field("error", err.Error()))
+	}
+	hasher := sha256.New()
+	n, err := io.Copy(io.MultiWriter(file, hasher), resp.Body)
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return nil, common.Error(common.ErrIO, common.Field("error", err.Error()))
+	}
+	return &bundleFile{path: filePath, mode: 0644, file: file.Name(), size: n, sha256: hex.EncodeToString(hasher.Sum(nil))}, nil
+}
+
+func newBundleReader(files []bundleFile, modTime time.Time) *bundleReader {
+	pr, pw := io.Pipe()
+	r := &bundleReader{PipeReader: pr, files: files, done: make(chan struct{})}
+	go func() {
+		defer close(r.done)
+		pw.CloseWithError(packBundle(pw, files, modTime))
+	}()
+	return r
+}
+
+// Close stops packing and removes the temp files once the packing goroutine exits
+func (r *bundleReader) Close() error {
+	err := r.PipeReader.Close()
+	<-r.done
+	removeBundleFiles(r.files)
+	return err
+}
+
+func removeBundleFiles(files []bundleFile) {
+	for _, f := range files {
+		if f.file != "" {
+			os.Remove(f.file)
+		}
+	}
+}
+
+func packBundle(w io.Writer, files []bundleFile, modTime time.Time) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	for _, f := range files {
+		hdr := &tar.Header{
+			Name:    f.path,
+			Mode:    f.mode,
+			Size:    f.size,
+			ModTime: modTime,
+		}
+		if f.file == "" {
+			hdr.Size = int64(len(f.data))
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return common.Error(common.ErrIO, common.Field("error", err.Error()))
+		}
+		if err := writeBundleFile(tw, f); err != nil {
+			return common.Error(common.ErrIO, common.Field("error", err.Error()))
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return common.Error(common.ErrIO, common.Field("error", err.Error()))
+	}
+	if err := gw.Close(); err != nil {
+		return common.Error(common.ErrIO, common.Field("error", err.Error()))
+	}
+	return nil
+}
+
+func writeBundleFile(w io.Writer, f bundleFile) error {
+	if f.file == "" {
+		_, err := w.Write(f.data)
+		return err
+	}
+	file, err := os.Open(f.file)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(w, file)
+	return err
+}
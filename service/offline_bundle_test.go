@@ -0,0 +1,232 @@
+package service
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/context"
+	bhttp "github.com/baetyl/baetyl-go/v2/http"
+	"github.com/baetyl/baetyl-go/v2/json"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func unpackBundle(t *testing.T, bundle io.ReadCloser) map[string][]byte {
+	defer bundle.Close()
+	data, err := io.ReadAll(bundle)
+	assert.NoError(t, err)
+	gr, err := gzip.NewReader(bytes.NewReader(data))
+	assert.NoError(t, err)
+	tr := tar.NewReader(gr)
+	files := map[string][]byte{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		content, err := io.ReadAll(tr)
+		assert.NoError(t, err)
+		files[hdr.Name] = content
+	}
+	return files
+}
+
+func TestOfflineBundleService_Build(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	program := []byte("program package")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/baetyl_linux-amd64.zip" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(program)
+	}))
+	defer server.Close()
+
+	sInit := service.NewMockInitService(mockCtl)
+	sNode := service.NewMockNodeService(mockCtl)
+	sModule := service.NewMockModuleService(mockCtl)
+	sApp := service.NewMockApplicationService(mockCtl)
+	sConfig := service.NewMockConfigService(mockCtl)
+	sSecret := service.NewMockSecretService(mockCtl)
+	sHistory := service.NewMockAppHistoryService(mockCtl)
+	bs := &OfflineBundleServiceImpl{
+		Init:               sInit,
+		Node:               sNode,
+		Module:             sModule,
+		AppHistory:         sHistory,
+		AppCombinedService: &AppCombinedService{App: sApp, Config: sConfig, Secret: sSecret},
+		client:             bhttp.NewClient(bhttp.NewClientOptions()),
+	}
+
+	node := &specV1.Node{Name: "n1", Namespace: "default", NodeMode: context.RunModeNative}
+	desire := specV1.Desire{}
+	desire.SetAppInfos(true, []specV1.AppInfo{{Name: "baetyl-core-1", Version: "1"}, {Name: "baetyl-init-1", Version: "2-r1"}})
+	core := &specV1.Application{
+		Name:      "baetyl-core-1",
+		Namespace: "default",
+		Version:   "1",
+		Services:  []specV1.Service{{Name: "baetyl-core", Image: "baetyltech/baetyl:v2.4.3"}},
+		Volumes: []specV1.Volume{
+			{Name: "core-conf", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "baetyl-core-conf-1", Version: "11"}}},
+			{Name: "node-cert", VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "sync-cert"}}},
+			{Name: "core-secret", VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "core-secret", Version: "12"}}},
+		},
+	}
+	// the init app desired by node is older than the latest one
+	init := &specV1.Application{
+		Name:      "baetyl-init-1",
+		Namespace: "default",
+		Version:   "2",
+		Services:  []specV1.Service{{Name: "baetyl-init", Image: "baetyltech/baetyl:v2.4.3"}, {Name: "other", Image: "other:v1"}},
+		Volumes: []specV1.Volume{
+			{Name: "core-conf", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "baetyl-core-conf-1", Version: "11"}}},
+		},
+	}
+	latestInit := &specV1.Application{Name: "baetyl-init-1", Namespace: "default", Version: "3"}
+	coreConf := &specV1.Configuration{Name: "baetyl-core-conf-1", Version: "11", Data: map[string]string{"conf.yml": "a: b"}}
+	coreSecret := &specV1.Secret{Name: "core-secret", Version: "12", Data: map[string][]byte{"token": []byte("t")}}
+	modules := []models.Module{{
+		Name:    "baetyl",
+		Version: "v2.4.3",
+		Image:   "baetyltech/baetyl:v2.4.3",
+		Programs: map[string]string{
+			"linux-amd64": server.URL + "/baetyl_linux-amd64.zip",
+			"linux-arm64": server.URL + "/baetyl_linux-arm64.zip",
+		},
+	}}
+	cert := &specV1.Secret{Name: "sync-cert", Data: map[string][]byte{"ca.pem": []byte("ca"), "client.key": []byte("key"), "client.pem": []byte("pem")}}
+
+	sNode.EXPECT().Get(nil, "default", "n1").Return(node, nil).Times(2)
+	sInit.EXPECT().GetResource("default", "n1", templateBaetylInstallShell, gomock.Any()).DoAndReturn(func(ns, name, resource string, params map[string]interface{}) (interface{}, error) {
+		assert.Equal(t, true, params["Offline"])
+		assert.Equal(t, "baetyl-init-apply.json", params["InitApplyYaml"])
+		return []byte("install"), nil
+	}).Times(2)
+	sInit.EXPECT().GetResource("default", "n1", "baetyl-init-apply.json", gomock.Any()).Return([]byte("apply"), nil).Times(2)
+	sInit.EXPECT().GetNodeCertificate("default", "n1").Return(cert, nil).Times(2)
+	sNode.EXPECT().GetDesire("default", "n1").Return(&desire, nil).Times(2)
+	sApp.EXPECT().Get("default", "baetyl-core-1", "1").Return(core, nil).Times(2)
+	sApp.EXPECT().Get("default", "baetyl-init-1", "2").Return(latestInit, nil).Times(2)
+	sHistory.EXPECT().GetByVersion("default", "baetyl-init-1", "2").Return(&models.AppHistory{Application: init}, nil).Times(2)
+	sConfig.EXPECT().Get(nil, "default", "baetyl-core-conf-1", "11").Return(coreConf, nil).Times(2)
+	sSecret.EXPECT().Get("default", "core-secret", "12").Return(coreSecret, nil).Times(2)
+	sModule.EXPECT().ListModules(&models.Filter{}, common.ModuleType("")).Return(modules, nil).Times(2)
+
+	// the programs of all platforms are listed
+	bundle, err := bs.Build("default", "n1", &models.OfflineBundleOptions{})
+	assert.NoError(t, err)
+	files := unpackBundle(t, bundle)
+	assert.Equal(t, []byte("install"), files["baetyl-install.sh"])
+	assert.Equal(t, []byte("apply"), files["baetyl-init-apply.json"])
+	assert.Equal(t, []byte("pem"), files["certs/client.pem"])
+	assert.Contains(t, files, "apps/baetyl-core-1.yml")
+	assert.Contains(t, files, "apps/baetyl-init-1.yml")
+	assert.Contains(t, string(files["apps/baetyl-init-1.yml"]), "version: \"2\"")
+	assert.Contains(t, string(files["configs/baetyl-core-conf-1.yml"]), "a: b")
+	assert.Contains(t, files, "secrets/core-secret.yml")
+	assert.NotContains(t, files, "secrets/sync-cert.yml")
+	assert.Equal(t, "baetyltech/baetyl:v2.4.3\nother:v1\n", string(files[OfflineBundleImages]))
+	manifest := &models.OfflineBundleManifest{}
+	assert.NoError(t, json.Unmarshal(files[OfflineBundleManifest], manifest))
+	assert.Equal(t, context.RunModeNative, manifest.Mode)
+	assert.Equal(t, []models.OfflineBundleImage{
+		{Image: "baetyltech/baetyl:v2.4.3", Module: "baetyl", Version: "v2.4.3"},
+		{Image: "other:v1"},
+	}, manifest.Images)
+	assert.Len(t, manifest.Programs, 2)
+	assert.Equal(t, "", manifest.Programs[0].SHA256)
+	// the checksums cover all files
+	sums := strings.Split(strings.TrimSpace(string(files[OfflineBundleChecksums])), "\n")
+	assert.Len(t, sums, len(files)-1)
+	for _, line := range sums {
+		parts := strings.SplitN(line, "  ", 2)
+		assert.Equal(t, fmt.Sprintf("%x", sha256.Sum256(files[parts[1]])), parts[0], parts[1])
+	}
+
+	// the program packages of platform are downloaded
+	bundle, err = bs.Build("default", "n1", &models.OfflineBundleOptions{Platform: "linux-amd64"})
+	assert.NoError(t, err)
+	var programFile string
+	for _, f := range bundle.(*bundleReader).files {
+		if f.file != "" {
+			programFile = f.file
+		}
+	}
+	assert.FileExists(t, programFile)
+	files = unpackBundle(t, bundle)
+	assert.Equal(t, program, files["programs/baetyl_linux-amd64.zip"])
+	// the downloaded program is removed once the bundle is closed
+	assert.NoFileExists(t, programFile)
+	manifest = &models.OfflineBundleManifest{}
+	assert.NoError(t, json.Unmarshal(files[OfflineBundleManifest], manifest))
+	assert.Len(t, manifest.Programs, 1)
+	assert.Equal(t, "programs/baetyl_linux-amd64.zip", manifest.Programs[0].File)
+	assert.Equal(t, fmt.Sprintf("%x", sha256.Sum256(program)), manifest.Programs[0].SHA256)
+
+	// failed to download the program package
+	sNode.EXPECT().Get(nil, "default", "n1").Return(node, nil).Times(1)
+	sInit.EXPECT().GetResource("default", "n1", gomock.Any(), gomock.Any()).Return([]byte("res"), nil).Times(2)
+	sInit.EXPECT().GetNodeCertificate("default", "n1").Return(cert, nil).Times(1)
+	sNode.EXPECT().GetDesire("default", "n1").Return(&desire, nil).Times(1)
+	sApp.EXPECT().Get("default", "baetyl-core-1", "1").Return(core, nil).Times(1)
+	sApp.EXPECT().Get("default", "baetyl-init-1", "2").Return(init, nil).Times(1)
+	sConfig.EXPECT().Get(nil, "default", "baetyl-core-conf-1", "11").Return(coreConf, nil).Times(1)
+	sSecret.EXPECT().Get("default", "core-secret", "12").Return(coreSecret, nil).Times(1)
+	sModule.EXPECT().ListModules(&models.Filter{}, common.ModuleType("")).Return(modules, nil).Times(1)
+	_, err = bs.Build("default", "n1", &models.OfflineBundleOptions{Platform: "linux-arm64"})
+	assert.Error(t, err)
+
+	// the config is modified since the version desired by node
+	sNode.EXPECT().Get(nil, "default", "n1").Return(node, nil).Times(1)
+	sInit.EXPECT().GetResource("default", "n1", gomock.Any(), gomock.Any()).Return([]byte("res"), nil).Times(2)
+	sInit.EXPECT().GetNodeCertificate("default", "n1").Return(cert, nil).Times(1)
+	sNode.EXPECT().GetDesire("default", "n1").Return(&desire, nil).Times(1)
+	sApp.EXPECT().Get("default", "baetyl-core-1", "1").Return(core, nil).Times(1)
+	sConfig.EXPECT().Get(nil, "default", "baetyl-core-conf-1", "11").Return(&specV1.Configuration{Name: "baetyl-core-conf-1", Version: "13"}, nil).Times(1)
+	_, err = bs.Build("default", "n1", &models.OfflineBundleOptions{})
+	assert.Error(t, err)
+
+	// the invalid mode
+	sNode.EXPECT().Get(nil, "default", "n1").Return(node, nil).Times(1)
+	_, err = bs.Build("default", "n1", &models.OfflineBundleOptions{Mode: "unknown"})
+	assert.Error(t, err)
+}
+
+func TestInitService_GetInstallShell(t *testing.T) {
+	mocks := InitMockEnvironment(t)
+	defer mocks.Close()
+	sTemplate, err := NewTemplateService(mocks.conf, map[string]interface{}{
+		"GetProperty": func(in string) string {
+			return "https://init.baetyl.io"
+		},
+	})
+	assert.NoError(t, err)
+	as := InitServiceImpl{TemplateService: sTemplate}
+
+	params := map[string]interface{}{"Token": "token", "Mode": "kube", "InitApplyYaml": "baetyl-init-deployment.yml"}
+	data, err := as.getInstallShell("default", "n1", params)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "OFFLINE='false'")
+
+	params = map[string]interface{}{"Token": "", "Mode": "kube", "InitApplyYaml": "baetyl-init-deployment.yml", "Offline": true}
+	data, err = as.getInstallShell("default", "n1", params)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "OFFLINE='true'")
+}
@@ -21,6 +21,10 @@ type PKIService interface {
 	SignServerCertificate(cn string, altNames models.AltNames) (*models.PEMCredential, error)
 	// SignNodeCertificate sign a certificate which can be used to connect to cloud
 	SignClientCertificate(cn string, altNames models.AltNames) (*models.PEMCredential, error)
+	// SignNodeCertificate sign a node certificate by the intermediate ca of namespace, the ca is appended to the certificate as the chain
+	SignNodeCertificate(namespace, cn string, altNames models.AltNames) (*models.PEMCredential, error)
+	// RevokeNamespaceCA revoke the intermediate ca of namespace
+	RevokeNamespaceCA(namespace string) error
 	// DeleteServerCertificate delete a server certificate by certId
 	DeleteServerCertificate(certId string) error
 	// DeleteClientCertificate delete a server certificate by certId
@@ -63,14 +67,35 @@ func (p *pkiService) DeleteClientCertificate(certId string) error {
 }
 
 func (p *pkiService) SignServerCertificate(cn string, altNames models.AltNames) (*models.PEMCredential, error) {
-	return p.signCertificate(cn, altNames, p.pki.CreateServerCert, p.pki.GetServerCert)
+	return p.signCertificate(cn, altNames, p.pki.GetRootCertID(), p.pki.CreateServerCert, p.pki.GetServerCert)
 }
 
 func (p *pkiService) SignClientCertificate(cn string, altNames models.AltNames) (*models.PEMCredential, error) {
-	return p.signCertificate(cn, altNames, p.pki.CreateClientCert, p.pki.GetClientCert)
+	return p.signCertificate(cn, altNames, p.pki.GetRootCertID(), p.pki.CreateClientCert, p.pki.GetClientCert)
 }
 
-func (p *pkiService) signCertificate(cn string, altNames models.AltNames, create func(csr []byte, rootId string) (string, error), get func(certId string) ([]byte, error)) (*models.PEMCredential, error) {
+func (p *pkiService) SignNodeCertificate(namespace, cn string, altNames models.AltNames) (*models.PEMCredential, error) {
+	caId, err := p.pki.GetNamespaceCertID(namespace)
+	if err != nil {
+		return nil, err
+	}
+	res, err := p.signCertificate(cn, altNames, caId, p.pki.CreateClientCert, p.pki.GetClientCert)
+	if err != nil {
+		return nil, err
+	}
+	ca, err := p.pki.GetRootCert(caId)
+	if err != nil {
+		return nil, err
+	}
+	res.CertPEM = append(append([]byte{}, res.CertPEM...), ca...)
+	return res, nil
+}
+
+func (p *pkiService) RevokeNamespaceCA(namespace string) error {
+	return p.pki.RevokeNamespaceCert(namespace)
+}
+
+func (p *pkiService) signCertificate(cn string, altNames models.AltNames, rootId string, create func(csr []byte, rootId string) (string, error), get func(certId string) ([]byte, error)) (*models.PEMCredential, error) {
 	csrInfo := p.genDefaultCSR(cn)
 	csrInfo.DNSNames = altNames.DNSNames
 	csrInfo.EmailAddresses = altNames.Emails
@@ -92,7 +117,7 @@ func (p *pkiService) signCertificate(cn string, altNames models.AltNames, create
 		return nil, err
 	}
 
-	certId, err := create(csr, p.pki.GetRootCertID())
+	certId, err := create(csr, rootId)
 	if err != nil {
 		return nil, err
 	}
@@ -65,6 +65,33 @@ func TestPkiService_SignClientCertificate(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestPkiService_SignNodeCertificate(t *testing.T) {
+	mc := InitMockEnvironment(t)
+	defer mc.Close()
+
+	ps, err := NewPKIService(mc.conf)
+	assert.NoError(t, err)
+	caId := "ns-ca"
+	certId := "132"
+
+	// the node certificate is issued by the ca of namespace with the chain
+	mc.pki.EXPECT().GetNamespaceCertID("default").Return(caId, nil).Times(1)
+	mc.pki.EXPECT().CreateClientCert(gomock.Any(), caId).Return(certId, nil).Times(1)
+	mc.pki.EXPECT().GetClientCert(certId).Return([]byte("pem"), nil).Times(1)
+	mc.pki.EXPECT().GetRootCert(caId).Return([]byte("ca"), nil).Times(1)
+	res, err := ps.SignNodeCertificate("default", "default.n1", models.AltNames{})
+	assert.NoError(t, err)
+	assert.Equal(t, "pemca", string(res.CertPEM))
+	assert.Equal(t, certId, res.CertId)
+
+	mc.pki.EXPECT().GetNamespaceCertID("default").Return("", os.ErrNotExist).Times(1)
+	_, err = ps.SignNodeCertificate("default", "default.n1", models.AltNames{})
+	assert.Error(t, err)
+
+	mc.pki.EXPECT().RevokeNamespaceCert("default").Return(nil).Times(1)
+	assert.NoError(t, ps.RevokeNamespaceCA("default"))
+}
+
 func TestPkiService_SignServerCertificate(t *testing.T) {
 	mc := InitMockEnvironment(t)
 	defer mc.Close()
@@ -0,0 +1,292 @@
+package service
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/preview.go -package=service github.com/baetyl/baetyl-cloud/v2/service PreviewService
+
+// PreviewService computes the impact of the change of resource on the desire of nodes without persisting anything
+type PreviewService interface {
+	// PreviewApp previews the creation (oldApp is nil) or update of app
+	PreviewApp(namespace string, oldApp, app *specV1.Application) (*models.ChangeImpact, error)
+	// PreviewConfig previews the creation (oldConfig is nil) or update of config, the apps referencing the config are updated
+	PreviewConfig(namespace string, oldConfig, config *specV1.Configuration) (*models.ChangeImpact, error)
+	// PreviewSecret previews the creation (oldSecret is nil) or update of secret, the values of data are not exposed
+	PreviewSecret(namespace string, oldSecret, secret *specV1.Secret) (*models.ChangeImpact, error)
+}
+
+type PreviewServiceImpl struct {
+	App    ApplicationService
+	Index  IndexService
+	Node   plugin.Node
+	Shadow plugin.Shadow
+}
+
+// NewPreviewService new preview service
+func NewPreviewService(config *config.CloudConfig) (PreviewService, error) {
+	app, err := NewApplicationService(config)
+	if err != nil {
+		return nil, err
+	}
+	is, err := NewIndexService(config)
+	if err != nil {
+		return nil, err
+	}
+	res, err := plugin.GetPlugin(config.Plugin.Resource)
+	if err != nil {
+		return nil, err
+	}
+	shadow, err := plugin.GetPlugin(config.Plugin.Shadow)
+	if err != nil {
+		return nil, err
+	}
+	return &PreviewServiceImpl{
+		App:    app,
+		Index:  is,
+		Node:   res.(plugin.Node),
+		Shadow: shadow.(plugin.Shadow),
+	}, nil
+}
+
+func (p *PreviewServiceImpl) PreviewApp(namespace string, oldApp, app *specV1.Application) (*models.ChangeImpact, error) {
+	impact := &models.ChangeImpact{Apps: []string{app.Name}}
+	var oldNodes []string
+	if oldApp != nil {
+		changes, err := common.Diff(previewAppContent(oldApp), previewAppContent(app))
+		if err != nil {
+			return nil, err
+		}
+		impact.Changes = changes
+		if oldNodes, err = p.matchNodes(namespace, oldApp); err != nil {
+			return nil, err
+		}
+	}
+	newNodes, err := p.matchNodes(namespace, app)
+	if err != nil {
+		return nil, err
+	}
+	olds, news := map[string]bool{}, map[string]bool{}
+	nodes := append([]string{}, oldNodes...)
+	for _, n := range oldNodes {
+		olds[n] = true
+	}
+	for _, n := range newNodes {
+		if !olds[n] {
+			nodes = append(nodes, n)
+		}
+	}
+	desires, err := p.listDesires(namespace, nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, n := range newNodes {
+		news[n] = true
+		diff := models.NodeDesireDiff{Node: n, Changes: []common.FieldDiff{desireAppDiff(desires[n], app, models.PendingVersion)}}
+		if olds[n] {
+			impact.Changed = append(impact.Changed, diff)
+		} else {
+			impact.Gained = append(impact.Gained, diff)
+		}
+	}
+	for _, n := range oldNodes {
+		if !news[n] {
+			impact.Lost = append(impact.Lost, models.NodeDesireDiff{Node: n, Changes: []common.FieldDiff{desireAppDiff(desires[n], oldApp, nil)}})
+		}
+	}
+	return sortChangeImpact(impact), nil
+}
+
+func (p *PreviewServiceImpl) PreviewConfig(namespace string, oldConfig, config *specV1.Configuration) (*models.ChangeImpact, error) {
+	if oldConfig == nil {
+		return sortChangeImpact(&models.ChangeImpact{}), nil
+	}
+	oc, nc := *oldConfig, *config
+	oc.Version, oc.UpdateTimestamp, oc.CreationTimestamp = "", time.Time{}, time.Time{}
+	nc.Version, nc.UpdateTimestamp, nc.CreationTimestamp = "", time.Time{}, time.Time{}
+	changes, err := common.Diff(oc, nc)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return sortChangeImpact(&models.ChangeImpact{}), nil
+	}
+	appNames, err := p.Index.ListAppIndexByConfig(namespace, config.Name)
+	if err != nil {
+		return nil, err
+	}
+	impact, err := p.previewReferencingApps(namespace, appNames, func(v specV1.Volume) bool {
+		return v.Config != nil && v.Config.Name == config.Name
+	})
+	if err != nil {
+		return nil, err
+	}
+	impact.Changes = changes
+	return impact, nil
+}
+
+func (p *PreviewServiceImpl) PreviewSecret(namespace string, oldSecret, secret *specV1.Secret) (*models.ChangeImpact, error) {
+	if oldSecret == nil {
+		return sortChangeImpact(&models.ChangeImpact{}), nil
+	}
+	old, s := *oldSecret, *secret
+	old.Version, old.UpdateTimestamp, old.CreationTimestamp = "", time.Time{}, time.Time{}
+	s.Version, s.UpdateTimestamp, s.CreationTimestamp = "", time.Time{}, time.Time{}
+	changes, err := common.Diff(old, s)
+	if err != nil {
+		return nil, err
+	}
+	// only the changed keys of data are exposed
+	for i := range changes {
+		if strings.HasPrefix(changes[i].Path, "data") {
+			changes[i].From, changes[i].To = nil, nil
+		}
+	}
+	if len(changes) == 0 {
+		return sortChangeImpact(&models.ChangeImpact{}), nil
+	}
+	appNames, err := p.Index.ListAppIndexBySecret(namespace, secret.Name)
+	if err != nil {
+		return nil, err
+	}
+	impact, err := p.previewReferencingApps(namespace, appNames, func(v specV1.Volume) bool {
+		return v.Secret != nil && v.Secret.Name == secret.Name
+	})
+	if err != nil {
+		return nil, err
+	}
+	impact.Changes = changes
+	return impact, nil
+}
+
+// previewReferencingApps returns the nodes of the apps referencing the resource, whose versions will be updated
+func (p *PreviewServiceImpl) previewReferencingApps(namespace string, appNames []string, referenced func(specV1.Volume) bool) (*models.ChangeImpact, error) {
+	impact := &models.ChangeImpact{}
+	nodeApps := map[string][]*specV1.Application{}
+	var nodes []string
+	for _, name := range appNames {
+		app, err := p.App.Get(namespace, name, "")
+		if err != nil {
+			if isNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		ref := false
+		for _, v := range app.Volumes {
+			if referenced(v) {
+				ref = true
+				break
+			}
+		}
+		if !ref {
+			continue
+		}
+		impact.Apps = append(impact.Apps, app.Name)
+		matched, err := p.matchNodes(namespace, app)
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range matched {
+			if _, ok := nodeApps[n]; !ok {
+				nodes = append(nodes, n)
+			}
+			nodeApps[n] = append(nodeApps[n], app)
+		}
+	}
+	desires, err := p.listDesires(namespace, nodes)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range nodes {
+		diff := models.NodeDesireDiff{Node: n}
+		for _, app := range nodeApps[n] {
+			diff.Changes = append(diff.Changes, desireAppDiff(desires[n], app, models.PendingVersion))
+		}
+		impact.Changed = append(impact.Changed, diff)
+	}
+	sort.Strings(impact.Apps)
+	return sortChangeImpact(impact), nil
+}
+
+// matchNodes returns the nodes matched by the selector of app, the cron app waiting to be deployed matches nothing
+func (p *PreviewServiceImpl) matchNodes(namespace string, app *specV1.Application) ([]string, error) {
+	if app.Selector == "" || app.CronStatus == specV1.CronWait {
+		return nil, nil
+	}
+	list, err := p.Node.ListNode(nil, namespace, &models.ListOptions{LabelSelector: app.Selector})
+	if err != nil {
+		return nil, err
+	}
+	var nodes []string
+	for _, node := range list.Items {
+		nodes = append(nodes, node.Name)
+	}
+	return nodes, nil
+}
+
+func (p *PreviewServiceImpl) listDesires(namespace string, nodes []string) (map[string]specV1.Desire, error) {
+	desires := map[string]specV1.Desire{}
+	if len(nodes) == 0 {
+		return desires, nil
+	}
+	shadows, err := p.Shadow.ListShadowByNames(nil, namespace, nodes)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range shadows {
+		desires[s.Name] = s.Desire
+	}
+	return desires, nil
+}
+
+// desireAppDiff returns the change of the app in the desire of node, from the current version to the given one
+func desireAppDiff(desire specV1.Desire, app *specV1.Application, to interface{}) common.FieldDiff {
+	key := common.DesiredApplications
+	if app.System {
+		key = common.DesiredSysApplications
+	}
+	diff := common.FieldDiff{Path: key + "." + app.Name, To: to}
+	if desire != nil {
+		for _, info := range desire.AppInfos(app.System) {
+			if info.Name == app.Name {
+				diff.From = info.Version
+			}
+		}
+	}
+	return diff
+}
+
+func previewAppContent(app *specV1.Application) specV1.Application {
+	res := *app
+	res.Version = ""
+	res.CreationTimestamp = time.Time{}
+	res.UpdateTime = time.Time{}
+	return res
+}
+
+func sortChangeImpact(impact *models.ChangeImpact) *models.ChangeImpact {
+	for _, l := range [][]models.NodeDesireDiff{impact.Gained, impact.Lost, impact.Changed} {
+		sort.Slice(l, func(i, j int) bool { return l[i].Node < l[j].Node })
+	}
+	if impact.Gained == nil {
+		impact.Gained = []models.NodeDesireDiff{}
+	}
+	if impact.Lost == nil {
+		impact.Lost = []models.NodeDesireDiff{}
+	}
+	if impact.Changed == nil {
+		impact.Changed = []models.NodeDesireDiff{}
+	}
+	return impact
+}
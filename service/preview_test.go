@@ -0,0 +1,133 @@
+package service
+
+import (
+	"testing"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func genPreviewShadow(name string, apps ...specV1.AppInfo) *models.Shadow {
+	d := specV1.Desire{}
+	d.SetAppInfos(false, apps)
+	return &models.Shadow{Name: name, Desire: d}
+}
+
+func TestPreviewApp(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	mNode := mockPlugin.NewMockNode(mockCtl)
+	mShadow := mockPlugin.NewMockShadow(mockCtl)
+	ps := &PreviewServiceImpl{Node: mNode, Shadow: mShadow}
+
+	ns := "default"
+	oldApp := &specV1.Application{Namespace: ns, Name: "app", Version: "1", Selector: "site=bj",
+		Services: []specV1.Service{{Name: "svc", Image: "nginx:1.0"}}}
+	app := &specV1.Application{Namespace: ns, Name: "app", Version: "1", Selector: "env=prod",
+		Services: []specV1.Service{{Name: "svc", Image: "nginx:2.0"}}}
+
+	mNode.EXPECT().ListNode(nil, ns, &models.ListOptions{LabelSelector: "site=bj"}).
+		Return(&models.NodeList{Items: []specV1.Node{{Name: "n1"}, {Name: "n2"}}}, nil).Times(1)
+	mNode.EXPECT().ListNode(nil, ns, &models.ListOptions{LabelSelector: "env=prod"}).
+		Return(&models.NodeList{Items: []specV1.Node{{Name: "n3"}, {Name: "n2"}}}, nil).Times(2)
+	mShadow.EXPECT().ListShadowByNames(nil, ns, []string{"n1", "n2", "n3"}).Return([]*models.Shadow{
+		genPreviewShadow("n1", specV1.AppInfo{Name: "app", Version: "1"}),
+		genPreviewShadow("n2", specV1.AppInfo{Name: "app", Version: "1-abc"}),
+		genPreviewShadow("n3"),
+	}, nil).Times(1)
+
+	impact, err := ps.PreviewApp(ns, oldApp, app)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"app"}, impact.Apps)
+	assert.Equal(t, []common.FieldDiff{
+		{Path: "selector", From: "site=bj", To: "env=prod"},
+		{Path: "services[0].image", From: "nginx:1.0", To: "nginx:2.0"},
+	}, impact.Changes)
+	assert.Equal(t, []models.NodeDesireDiff{
+		{Node: "n3", Changes: []common.FieldDiff{{Path: "apps.app", To: models.PendingVersion}}},
+	}, impact.Gained)
+	assert.Equal(t, []models.NodeDesireDiff{
+		{Node: "n1", Changes: []common.FieldDiff{{Path: "apps.app", From: "1"}}},
+	}, impact.Lost)
+	assert.Equal(t, []models.NodeDesireDiff{
+		{Node: "n2", Changes: []common.FieldDiff{{Path: "apps.app", From: "1-abc", To: models.PendingVersion}}},
+	}, impact.Changed)
+
+	// create app
+	mShadow.EXPECT().ListShadowByNames(nil, ns, []string{"n3", "n2"}).Return(nil, nil).Times(1)
+	impact, err = ps.PreviewApp(ns, nil, app)
+	assert.NoError(t, err)
+	assert.Nil(t, impact.Changes)
+	assert.Len(t, impact.Gained, 2)
+	assert.Len(t, impact.Lost, 0)
+	assert.Len(t, impact.Changed, 0)
+
+	// the cron app matches nothing until deployed
+	cronApp := *app
+	cronApp.CronStatus = specV1.CronWait
+	impact, err = ps.PreviewApp(ns, nil, &cronApp)
+	assert.NoError(t, err)
+	assert.Len(t, impact.Gained, 0)
+}
+
+func TestPreviewConfigAndSecret(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	sApp := ms.NewMockApplicationService(mockCtl)
+	sIndex := ms.NewMockIndexService(mockCtl)
+	mNode := mockPlugin.NewMockNode(mockCtl)
+	mShadow := mockPlugin.NewMockShadow(mockCtl)
+	ps := &PreviewServiceImpl{App: sApp, Index: sIndex, Node: mNode, Shadow: mShadow}
+
+	ns := "default"
+	oldCfg := &specV1.Configuration{Namespace: ns, Name: "cfg", Version: "1", Data: map[string]string{"a": "1"}}
+	cfg := &specV1.Configuration{Namespace: ns, Name: "cfg", Data: map[string]string{"a": "2"}}
+	app1 := &specV1.Application{Namespace: ns, Name: "app1", Version: "5", Selector: "site=bj", Volumes: []specV1.Volume{
+		{Name: "v", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "cfg"}}},
+		{Name: "s", VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "sec"}}},
+	}}
+	app2 := &specV1.Application{Namespace: ns, Name: "app2", Version: "6", Selector: "site=bj"}
+
+	impact, err := ps.PreviewConfig(ns, nil, cfg)
+	assert.NoError(t, err)
+	assert.Len(t, impact.Changed, 0)
+
+	// unchanged config impacts nothing
+	impact, err = ps.PreviewConfig(ns, oldCfg, oldCfg)
+	assert.NoError(t, err)
+	assert.Len(t, impact.Changed, 0)
+	assert.Len(t, impact.Apps, 0)
+
+	sIndex.EXPECT().ListAppIndexByConfig(ns, "cfg").Return([]string{"app1", "app2", "app3"}, nil).Times(1)
+	sApp.EXPECT().Get(ns, "app1", "").Return(app1, nil).Times(2)
+	sApp.EXPECT().Get(ns, "app2", "").Return(app2, nil).Times(1)
+	sApp.EXPECT().Get(ns, "app3", "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	mNode.EXPECT().ListNode(nil, ns, &models.ListOptions{LabelSelector: "site=bj"}).
+		Return(&models.NodeList{Items: []specV1.Node{{Name: "n1"}}}, nil).Times(2)
+	mShadow.EXPECT().ListShadowByNames(nil, ns, []string{"n1"}).Return([]*models.Shadow{
+		genPreviewShadow("n1", specV1.AppInfo{Name: "app1", Version: "5"}),
+	}, nil).Times(2)
+	impact, err = ps.PreviewConfig(ns, oldCfg, cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, []common.FieldDiff{{Path: "data.a", From: "1", To: "2"}}, impact.Changes)
+	assert.Equal(t, []string{"app1"}, impact.Apps)
+	assert.Equal(t, []models.NodeDesireDiff{
+		{Node: "n1", Changes: []common.FieldDiff{{Path: "apps.app1", From: "5", To: models.PendingVersion}}},
+	}, impact.Changed)
+
+	// the values of secret are not exposed
+	oldSecret := &specV1.Secret{Namespace: ns, Name: "sec", Version: "1", Data: map[string][]byte{"password": []byte("a")}}
+	secret := &specV1.Secret{Namespace: ns, Name: "sec", Data: map[string][]byte{"password": []byte("b")}}
+	sIndex.EXPECT().ListAppIndexBySecret(ns, "sec").Return([]string{"app1"}, nil).Times(1)
+	impact, err = ps.PreviewSecret(ns, oldSecret, secret)
+	assert.NoError(t, err)
+	assert.Equal(t, []common.FieldDiff{{Path: "data.password"}}, impact.Changes)
+	assert.Equal(t, []string{"app1"}, impact.Apps)
+	assert.Len(t, impact.Changed, 1)
+}
@@ -1,8 +1,14 @@
 package service
 
 import (
+	"fmt"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+
 	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
 	"github.com/baetyl/baetyl-cloud/v2/plugin"
 )
 
@@ -14,6 +20,7 @@ type QuotaService interface {
 
 type QuotaServiceImpl struct {
 	plugin.Quota
+	Webhook WebhookService
 }
 
 func NewQuotaService(config *config.CloudConfig) (QuotaService, error) {
@@ -22,8 +29,14 @@ func NewQuotaService(config *config.CloudConfig) (QuotaService, error) {
 		return nil, err
 	}
 
+	webhook, err := NewWebhookService(config)
+	if err != nil {
+		return nil, err
+	}
+
 	return &QuotaServiceImpl{
-		l.(plugin.Quota),
+		Quota:   l.(plugin.Quota),
+		Webhook: webhook,
 	}, nil
 }
 
@@ -44,6 +57,7 @@ func (l *QuotaServiceImpl) CheckQuota(namespace string, collector plugin.QuotaCo
 
 	for k, v := range counts {
 		if limits[k] != 0 && v >= limits[k] {
+			l.notifyExceeded(namespace, k, limits[k])
 			return common.Error(
 				common.ErrLicenseQuota,
 				common.Field("name", k),
@@ -52,3 +66,24 @@ func (l *QuotaServiceImpl) CheckQuota(namespace string, collector plugin.QuotaCo
 	}
 	return nil
 }
+
+// notifyExceeded emits the quota exceeded event, once an hour for each quota
+func (l *QuotaServiceImpl) notifyExceeded(namespace, name string, limit int) {
+	if l.Webhook == nil {
+		return
+	}
+	now := time.Now().UTC()
+	event := &models.Event{
+		ID:        fmt.Sprintf("%s/%s/%s", models.EventQuotaExceeded, name, now.Format("2006010215")),
+		Type:      models.EventQuotaExceeded,
+		Namespace: namespace,
+		Time:      now,
+		Data: map[string]interface{}{
+			"name":  name,
+			"limit": limit,
+		},
+	}
+	if err := l.Webhook.Emit(event); err != nil {
+		log.L().Error("failed to emit quota exceeded event", log.Any("namespace", namespace), log.Error(err))
+	}
+}
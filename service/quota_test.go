@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/baetyl/baetyl-cloud/v2/models"
 	"github.com/baetyl/baetyl-cloud/v2/plugin"
 )
 
@@ -51,6 +53,18 @@ func TestLicenseService_CheckQuota(t *testing.T) {
 	assert.Equal(t, err, errGetNodeCount)
 
 	services.quota.EXPECT().GetQuota(namespace).Return(quotas, nil)
+	services.callback.EXPECT().ListCallback(nil, namespace).Return([]models.Callback{
+		{Name: "c1", Namespace: namespace, Events: []string{models.EventQuotaExceeded}},
+		{Name: "c2", Namespace: namespace, Events: []string{models.EventNodeOffline}},
+	}, nil)
+	services.callback.EXPECT().CountDeliveryByEvent(nil, namespace, "c1", gomock.Any()).Return(0, nil)
+	services.callback.EXPECT().CreateDelivery(nil, gomock.Any()).DoAndReturn(func(_ interface{}, d *models.Delivery) error {
+		assert.Equal(t, "c1", d.Callback)
+		assert.Equal(t, models.EventQuotaExceeded, d.Event.Type)
+		assert.Equal(t, plugin.QuotaNode, d.Event.Data["name"])
+		assert.Equal(t, models.DeliveryPending, d.Status)
+		return nil
+	})
 	err = ls.CheckQuota(namespace, func(namespace string) (map[string]int, error) {
 		return map[string]int{plugin.QuotaNode: 11}, nil
 	})
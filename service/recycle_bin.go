@@ -0,0 +1,97 @@
+package service
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/recycle_bin.go -package=service github.com/baetyl/baetyl-cloud/v2/service RecycleBinService
+
+// RecycleBinService keeps the deleted resources of namespace for the configured retention
+type RecycleBinService interface {
+	// Recycle keeps the deleted resource, the system or invisible resources are not kept, nor anything if the retention is 0
+	Recycle(tx interface{}, item *models.RecycleItem) error
+	Get(namespace string, id int64) (*models.RecycleItem, error)
+	List(namespace string, listOptions *models.RecycleListOptions) (*models.RecycleItemList, error)
+	Delete(tx interface{}, namespace string, id int64) error
+	// PurgeExpired deletes the expired items of all namespaces
+	PurgeExpired() error
+}
+
+type RecycleBinServiceImpl struct {
+	RecycleBin plugin.RecycleBin
+	Retention  time.Duration
+	log        *log.Logger
+}
+
+// NewRecycleBinService new recycle bin service
+func NewRecycleBinService(config *config.CloudConfig) (RecycleBinService, error) {
+	recycleBin, err := plugin.GetPlugin(config.Plugin.RecycleBin)
+	if err != nil {
+		return nil, err
+	}
+	return &RecycleBinServiceImpl{
+		RecycleBin: recycleBin.(plugin.RecycleBin),
+		Retention:  config.RecycleBin.Retention,
+		log:        log.L().With(log.Any("service", "recycleBin")),
+	}, nil
+}
+
+func (r *RecycleBinServiceImpl) Recycle(tx interface{}, item *models.RecycleItem) error {
+	if r.Retention <= 0 || !recyclable(item) {
+		return nil
+	}
+	item.DeleteTime = time.Now().UTC()
+	item.ExpireTime = item.DeleteTime.Add(r.Retention)
+	_, err := r.RecycleBin.CreateRecycleItem(tx, item)
+	return err
+}
+
+func (r *RecycleBinServiceImpl) Get(namespace string, id int64) (*models.RecycleItem, error) {
+	return r.RecycleBin.GetRecycleItem(nil, namespace, id)
+}
+
+func (r *RecycleBinServiceImpl) List(namespace string, listOptions *models.RecycleListOptions) (*models.RecycleItemList, error) {
+	return r.RecycleBin.ListRecycleItem(nil, namespace, listOptions)
+}
+
+func (r *RecycleBinServiceImpl) Delete(tx interface{}, namespace string, id int64) error {
+	return r.RecycleBin.DeleteRecycleItem(tx, namespace, id)
+}
+
+func (r *RecycleBinServiceImpl) PurgeExpired() error {
+	count, err := r.RecycleBin.DeleteExpiredRecycleItems(nil, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		r.log.Info("purge expired items of recycle bin", log.Any("count", count))
+	}
+	return nil
+}
+
+func recyclable(item *models.RecycleItem) bool {
+	var labels map[string]string
+	switch {
+	case item.Application != nil:
+		if item.Application.System {
+			return false
+		}
+		labels = item.Application.Labels
+	case item.Secret != nil:
+		labels = item.Secret.Labels
+	case len(item.Configs) > 0:
+		labels = item.Configs[0].Labels
+	default:
+		return false
+	}
+	system, _ := strconv.ParseBool(labels[common.LabelSystem])
+	return !system && !common.ValidIsInvisible(labels)
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestRecycle(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	mRecycleBin := mockPlugin.NewMockRecycleBin(mockCtl)
+	rs := &RecycleBinServiceImpl{RecycleBin: mRecycleBin, Retention: time.Hour}
+
+	item := &models.RecycleItem{
+		Namespace:   "default",
+		Kind:        models.RecycleKindApp,
+		Name:        "app",
+		Application: &specV1.Application{Namespace: "default", Name: "app"},
+	}
+	mRecycleBin.EXPECT().CreateRecycleItem(nil, item).Return(item, nil).Times(1)
+	err := rs.Recycle(nil, item)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Hour, item.ExpireTime.Sub(item.DeleteTime))
+
+	// the system and invisible resources are not kept
+	sysApp := &models.RecycleItem{Kind: models.RecycleKindApp, Application: &specV1.Application{Name: "sys", System: true}}
+	assert.NoError(t, rs.Recycle(nil, sysApp))
+	sysSecret := &models.RecycleItem{Kind: models.RecycleKindRegistry, Secret: &specV1.Secret{Name: "reg",
+		Labels: map[string]string{common.LabelSystem: "true"}}}
+	assert.NoError(t, rs.Recycle(nil, sysSecret))
+	invisible := &models.RecycleItem{Kind: models.RecycleKindConfig, Configs: []specV1.Configuration{{Name: "cfg",
+		Labels: map[string]string{common.ResourceInvisible: "true"}}}}
+	assert.NoError(t, rs.Recycle(nil, invisible))
+
+	// nothing is kept if the recycle bin is disabled
+	rs.Retention = 0
+	assert.NoError(t, rs.Recycle(nil, item))
+}
+
+func TestRecycleBinPurgeExpired(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	mRecycleBin := mockPlugin.NewMockRecycleBin(mockCtl)
+	rs := &RecycleBinServiceImpl{RecycleBin: mRecycleBin, Retention: time.Hour,
+		log: log.L().With(log.Any("test", "recycleBin"))}
+
+	mRecycleBin.EXPECT().DeleteExpiredRecycleItems(nil, gomock.Any()).Return(int64(2), nil).Times(1)
+	assert.NoError(t, rs.PurgeExpired())
+	mRecycleBin.EXPECT().DeleteExpiredRecycleItems(nil, gomock.Any()).Return(int64(0), common.Error(common.ErrDatabase)).Times(1)
+	assert.Error(t, rs.PurgeExpired())
+}
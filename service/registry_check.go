@@ -0,0 +1,352 @@
+package service
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/json"
+
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+//go:generate mockgen -destination=../mock/service/registry_check.go -package=service github.com/baetyl/baetyl-cloud/v2/service RegistryCheckService
+
+const (
+	dockerHubDomain   = "docker.io"
+	dockerHubRegistry = "registry-1.docker.io"
+	// the token realm of docker hub, which isn't on the host of registry
+	dockerHubRealm = "auth.docker.io"
+	// the max size of the token response read from the realm
+	registryTokenMaxSize = 1 << 20
+)
+
+// the accepted manifests, including the multi-arch manifest list and image index
+var manifestMediaTypes = []string{
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.v1+prettyjws",
+}
+
+// RegistryCheckService checks the credentials of registries and the images against the registries
+type RegistryCheckService interface {
+	// CheckCredential checks the credential of registry by the /v2/ auth handshake
+	CheckCredential(registry *models.Registry) error
+	// CheckImage checks the manifest of image exists, the credential of the registry matching the domain of image is used
+	CheckImage(image string, registries []models.Registry) error
+}
+
+type RegistryCheckServiceImpl struct {
+	client *http.Client
+	// the hosts trusted as the token realms besides the hosts of registries
+	realms map[string]bool
+}
+
+// NewRegistryCheckService new registry check service
+func NewRegistryCheckService(config *config.CloudConfig) (RegistryCheckService, error) {
+	cfg := config.RegistryCheck
+	// the addresses of registries and realms are checked on every dial as the webhooks, since they are given by users
+	dest := newWebhookDestination(cfg.AllowedHosts, cfg.Timeout)
+	transport := &http.Transport{
+		DialContext:         dest.dialContext,
+		ForceAttemptHTTP2:   true,
+		MaxIdleConns:        100,
+		IdleConnTimeout:     90 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+	if cfg.Insecure {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	realms := map[string]bool{dockerHubRealm: true}
+	for _, h := range cfg.TokenRealms {
+		realms[strings.ToLower(strings.TrimSpace(h))] = true
+	}
+	return &RegistryCheckServiceImpl{
+		client: &http.Client{Transport: transport, Timeout: cfg.Timeout},
+		realms: realms,
+	}, nil
+}
+
+func (r *RegistryCheckServiceImpl) CheckCredential(registry *models.Registry) error {
+	endpoint, err := registryEndpoint(normalizeRegistryDomain(registry.Address), registry)
+	if err != nil {
+		return err
+	}
+	resp, err := r.do(http.MethodHead, endpoint+"/v2/", nil, registry)
+	if err != nil {
+		return err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return errors.Errorf("the credential of registry (%s) is unauthorized", registry.Address)
+	default:
+		return errors.Errorf("the registry (%s) responds with unexpected status %d", registry.Address, resp.StatusCode)
+	}
+}
+
+func (r *RegistryCheckServiceImpl) CheckImage(image string, registries []models.Registry) error {
+	domain, repo, ref, err := parseImageReference(image)
+	if err != nil {
+		return err
+	}
+	var registry *models.Registry
+	for i := range registries {
+		if normalizeRegistryDomain(registries[i].Address) == domain {
+			registry = &registries[i]
+			break
+		}
+	}
+	endpoint, err := registryEndpoint(domain, registry)
+	if err != nil {
+		return err
+	}
+	header := map[string]string{"Accept": strings.Join(manifestMediaTypes, ", ")}
+	resp, err := r.do(http.MethodHead, fmt.Sprintf("%s/v2/%s/manifests/%s", endpoint, repo, ref), header, registry)
+	if err != nil {
+		return err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return errors.Errorf("the image (%s) is not found", image)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		if registry == nil {
+			return errors.Errorf("the image (%s) is unauthorized, no registry of the app matches %s", image, domain)
+		}
+		return errors.Errorf("the image (%s) is unauthorized with the registry (%s)", image, registry.Name)
+	default:
+		return errors.Errorf("the image (%s) can't be resolved, the registry responds with unexpected status %d", image, resp.StatusCode)
+	}
+}
+
+// do sends the request, the basic or bearer token challenge of registry is answered with the credential if any
+func (r *RegistryCheckServiceImpl) do(method, u string, header map[string]string, registry *models.Registry) (*http.Response, error) {
+	resp, err := r.send(method, u, header, "")
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	scheme, params := parseAuthChallenge(resp.Header.Get("WWW-Authenticate"))
+	var auth string
+	switch scheme {
+	case "basic":
+		if registry == nil {
+			return resp, nil
+		}
+		req := &http.Request{Header: http.Header{}}
+		req.SetBasicAuth(registry.Username, registry.Password)
+		auth = req.Header.Get("Authorization")
+	case "bearer":
+		token, err := r.fetchToken(u, params, registry)
+		if err != nil {
+			return nil, err
+		}
+		if token == "" {
+			return &http.Response{StatusCode: http.StatusUnauthorized}, nil
+		}
+		auth = "Bearer " + token
+	default:
+		return resp, nil
+	}
+	return r.send(method, u, header, auth)
+}
+
+func (r *RegistryCheckServiceImpl) send(method, u string, header map[string]string, auth string) (*http.Response, error) {
+	req, err := http.NewRequest(method, u, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+// fetchToken requests the token from the realm of challenge, empty is returned if the credential is rejected.
+// The credential is sent to the realm, so the realm should be https on the host of registry or a trusted host
+func (r *RegistryCheckServiceImpl) fetchToken(u string, params map[string]string, registry *models.Registry) (string, error) {
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Host == "" {
+		return "", errors.Errorf("the realm (%s) of registry is invalid", params["realm"])
+	}
+	if err = r.checkRealm(u, realm); err != nil {
+		return "", err
+	}
+	query := realm.Query()
+	for _, k := range []string{"service", "scope"} {
+		if v, ok := params[k]; ok {
+			query.Set(k, v)
+		}
+	}
+	realm.RawQuery = query.Encode()
+	req, err := http.NewRequest(http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if registry != nil && registry.Username != "" {
+		req.SetBasicAuth(registry.Username, registry.Password)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return "", nil
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, registryTokenMaxSize))
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("failed to get the token of registry, status %d", resp.StatusCode)
+	}
+	token := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err = json.Unmarshal(data, &token); err != nil {
+		return "", errors.Trace(err)
+	}
+	if token.Token != "" {
+		return token.Token, nil
+	}
+	return token.AccessToken, nil
+}
+
+// checkRealm checks the realm is https, and on the host of the requested registry or a trusted host
+func (r *RegistryCheckServiceImpl) checkRealm(u string, realm *url.URL) error {
+	if realm.Scheme != "https" {
+		return errors.Errorf("the realm (%s) of registry isn't https", realm.String())
+	}
+	host := strings.ToLower(realm.Hostname())
+	if r.realms[host] {
+		return nil
+	}
+	if endpoint, err := url.Parse(u); err == nil && strings.ToLower(endpoint.Hostname()) == host {
+		return nil
+	}
+	return errors.Errorf("the realm (%s) isn't on the host of registry", realm.String())
+}
+
+// registryEndpoint returns the base url of the registry domain, https is used unless the address of registry has the scheme
+func registryEndpoint(domain string, registry *models.Registry) (string, error) {
+	if domain == "" {
+		return "", errors.New("the address of registry is empty")
+	}
+	if domain == dockerHubDomain {
+		domain = dockerHubRegistry
+	}
+	scheme := "https"
+	if registry != nil {
+		if i := strings.Index(registry.Address, "://"); i > 0 {
+			scheme = strings.ToLower(registry.Address[:i])
+		}
+	}
+	return scheme + "://" + domain, nil
+}
+
+// normalizeRegistryDomain returns the domain of the registry address, such as "https://index.docker.io/v1/" to "docker.io"
+func normalizeRegistryDomain(address string) string {
+	address = strings.TrimSpace(address)
+	if i := strings.Index(address, "://"); i >= 0 {
+		address = address[i+3:]
+	}
+	if i := strings.Index(address, "/"); i >= 0 {
+		address = address[:i]
+	}
+	switch address {
+	case "index.docker.io", dockerHubRegistry:
+		return dockerHubDomain
+	}
+	return address
+}
+
+// parseImageReference splits the image into the domain, repository and tag (or digest), such as "nginx" to "docker.io", "library/nginx" and "latest"
+func parseImageReference(image string) (domain, repo, ref string, err error) {
+	image = strings.TrimSpace(image)
+	if image == "" || strings.ContainsAny(image, " \t") {
+		return "", "", "", errors.Errorf("the image (%s) is invalid", image)
+	}
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, ref = name[:i], name[i+1:]
+	}
+	if i := strings.LastIndex(name, ":"); i >= 0 && !strings.Contains(name[i+1:], "/") {
+		if ref == "" {
+			ref = name[i+1:]
+		}
+		name = name[:i]
+	}
+	if ref == "" {
+		ref = "latest"
+	}
+	domain = dockerHubDomain
+	if i := strings.Index(name, "/"); i >= 0 {
+		first := name[:i]
+		if strings.ContainsAny(first, ".:") || first == "localhost" {
+			domain, name = normalizeRegistryDomain(first), name[i+1:]
+		}
+	}
+	if domain == dockerHubDomain && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+	if name == "" {
+		return "", "", "", errors.Errorf("the image (%s) is invalid", image)
+	}
+	return domain, name, ref, nil
+}
+
+// parseAuthChallenge parses the header such as `Bearer realm="https://auth.docker.io/token",service="registry.docker.io"`
+func parseAuthChallenge(header string) (string, map[string]string) {
+	params := map[string]string{}
+	parts := strings.SplitN(strings.TrimSpace(header), " ", 2)
+	scheme := strings.ToLower(parts[0])
+	if len(parts) < 2 {
+		return scheme, params
+	}
+	// the quoted value may contain commas, such as scope="repository:a:pull,push"
+	rest := parts[1]
+	for rest != "" {
+		i := strings.Index(rest, "=")
+		if i < 0 {
+			break
+		}
+		key := strings.ToLower(strings.Trim(rest[:i], " ,"))
+		rest = strings.TrimLeft(rest[i+1:], " ")
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else if end := strings.Index(rest, ","); end >= 0 {
+			value, rest = rest[:end], rest[end:]
+		} else {
+			value, rest = rest, ""
+		}
+		params[key] = value
+	}
+	return scheme, params
+}
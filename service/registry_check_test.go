@@ -0,0 +1,180 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// newFakeRegistry starts a registry which issues the bearer token to user:pass
+func newFakeRegistry(t *testing.T) *httptest.Server {
+	var svr *httptest.Server
+	manifests := map[string]string{
+		"team/app:1.0":          "application/vnd.docker.distribution.manifest.list.v2+json",
+		"team/app:latest":       "application/vnd.oci.image.manifest.v1+json",
+		"team/app:sha256:abcd0": "application/vnd.docker.distribution.manifest.v2+json",
+	}
+	challenge := func(w http.ResponseWriter, scope string) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="`+svr.URL+`/token",service="fake",scope="`+scope+`"`)
+		w.WriteHeader(http.StatusUnauthorized)
+	}
+	svr = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		switch {
+		case r.URL.Path == "/token":
+			assert.Equal(t, "fake", r.URL.Query().Get("service"))
+			if user, pass, ok := r.BasicAuth(); !ok || user != "user" || pass != "pass" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"access_token":"granted"}`))
+		case r.URL.Path == "/v2/":
+			if auth != "Bearer granted" {
+				challenge(w, "")
+				return
+			}
+		case strings.HasPrefix(r.URL.Path, "/v2/"):
+			assert.Equal(t, http.MethodHead, r.Method)
+			assert.Contains(t, r.Header.Get("Accept"), "application/vnd.oci.image.index.v1+json")
+			parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/v2/"), "/manifests/", 2)
+			if auth != "Bearer granted" {
+				challenge(w, "repository:"+parts[0]+":pull,push")
+				return
+			}
+			mediaType, ok := manifests[parts[0]+":"+parts[1]]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", mediaType)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return svr
+}
+
+func TestRegistryCheckCredential(t *testing.T) {
+	svr := newFakeRegistry(t)
+	defer svr.Close()
+	cfg := &config.CloudConfig{}
+	cfg.RegistryCheck.Timeout = time.Second * 5
+	cfg.RegistryCheck.Insecure = true
+	// the registry on the private address isn't requested unless allowed
+	rs, err := NewRegistryCheckService(cfg)
+	assert.NoError(t, err)
+	err = rs.CheckCredential(&models.Registry{Address: svr.URL, Username: "user", Password: "pass"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "isn't allowed")
+
+	cfg.RegistryCheck.AllowedHosts = []string{"127.0.0.1"}
+	rs, err = NewRegistryCheckService(cfg)
+	assert.NoError(t, err)
+	err = rs.CheckCredential(&models.Registry{Address: svr.URL, Username: "user", Password: "pass"})
+	assert.NoError(t, err)
+	err = rs.CheckCredential(&models.Registry{Address: svr.URL, Username: "user", Password: "wrong"})
+	assert.EqualError(t, err, "the credential of registry ("+svr.URL+") is unauthorized")
+	err = rs.CheckCredential(&models.Registry{Address: ""})
+	assert.Error(t, err)
+
+	// basic auth
+	basic := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "user" || pass != "pass" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="fake"`)
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer basic.Close()
+	err = rs.CheckCredential(&models.Registry{Address: basic.URL, Username: "user", Password: "pass"})
+	assert.NoError(t, err)
+	err = rs.CheckCredential(&models.Registry{Address: basic.URL, Username: "user"})
+	assert.Error(t, err)
+}
+
+func TestRegistryCheckImage(t *testing.T) {
+	svr := newFakeRegistry(t)
+	defer svr.Close()
+	rs := &RegistryCheckServiceImpl{client: svr.Client()}
+	domain := strings.TrimPrefix(svr.URL, "https://")
+	registries := []models.Registry{{Name: "reg", Address: svr.URL + "/team", Username: "user", Password: "pass"}}
+
+	// the multi-arch manifest list is accepted
+	assert.NoError(t, rs.CheckImage(domain+"/team/app:1.0", registries))
+	assert.NoError(t, rs.CheckImage(domain+"/team/app@sha256:abcd0", registries))
+	assert.EqualError(t, rs.CheckImage(domain+"/team/app:2.0", registries), "the image ("+domain+"/team/app:2.0) is not found")
+
+	// the tag defaults to latest, the scheme of the registry address is used
+	assert.NoError(t, rs.CheckImage(domain+"/team/app", registries))
+
+	wrong := []models.Registry{{Name: "wrong", Address: svr.URL, Username: "user", Password: "wrong"}}
+	assert.EqualError(t, rs.CheckImage(domain+"/team/app:1.0", wrong), "the image ("+domain+"/team/app:1.0) is unauthorized with the registry (wrong)")
+	assert.Error(t, rs.CheckImage("nginx latest", registries))
+}
+
+func TestRegistryCheckRealm(t *testing.T) {
+	cfg := &config.CloudConfig{}
+	cfg.RegistryCheck.TokenRealms = []string{"Auth.Example.com"}
+	rs, err := NewRegistryCheckService(cfg)
+	assert.NoError(t, err)
+	r := rs.(*RegistryCheckServiceImpl)
+
+	tests := []struct {
+		registry, realm string
+		valid           bool
+	}{
+		{"https://harbor.example.com/v2/", "https://harbor.example.com:8443/service/token", true},
+		{"https://registry-1.docker.io/v2/", "https://auth.docker.io/token", true},
+		{"https://harbor.example.com/v2/", "https://auth.example.com/token", true},
+		{"https://harbor.example.com/v2/", "http://harbor.example.com/service/token", false},
+		{"https://harbor.example.com/v2/", "https://169.254.169.254/latest/meta-data", false},
+		{"https://harbor.example.com/v2/", "https://other.example.com/token", false},
+	}
+	for _, tt := range tests {
+		realm, err := url.Parse(tt.realm)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.valid, r.checkRealm(tt.registry, realm) == nil, tt.realm)
+	}
+
+	// the token response is read within the limit
+	svr := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"token":"` + strings.Repeat("a", registryTokenMaxSize) + `"}`))
+	}))
+	defer svr.Close()
+	r.client = svr.Client()
+	_, err = r.fetchToken(svr.URL+"/v2/", map[string]string{"realm": svr.URL + "/token"}, nil)
+	assert.Error(t, err)
+}
+
+func TestParseImageReference(t *testing.T) {
+	tests := []struct {
+		image, domain, repo, ref string
+	}{
+		{"nginx", "docker.io", "library/nginx", "latest"},
+		{"nginx:1.19", "docker.io", "library/nginx", "1.19"},
+		{"baetyl/core:v2", "docker.io", "baetyl/core", "v2"},
+		{"docker.io/baetyl/core:v2", "docker.io", "baetyl/core", "v2"},
+		{"index.docker.io/nginx", "docker.io", "library/nginx", "latest"},
+		{"localhost/app", "localhost", "app", "latest"},
+		{"127.0.0.1:5000/team/app", "127.0.0.1:5000", "team/app", "latest"},
+		{"registry.baidubce.com/baetyl/core:v2@sha256:abc", "registry.baidubce.com", "baetyl/core", "sha256:abc"},
+	}
+	for _, tt := range tests {
+		domain, repo, ref, err := parseImageReference(tt.image)
+		assert.NoError(t, err, tt.image)
+		assert.Equal(t, []string{tt.domain, tt.repo, tt.ref}, []string{domain, repo, ref}, tt.image)
+	}
+	_, _, _, err := parseImageReference("")
+	assert.Error(t, err)
+
+	scheme, params := parseAuthChallenge(`Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:a:pull,push"`)
+	assert.Equal(t, "bearer", scheme)
+	assert.Equal(t, map[string]string{"realm": "https://auth.docker.io/token", "service": "registry.docker.io", "scope": "repository:a:pull,push"}, params)
+}
@@ -0,0 +1,384 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/baetyl/baetyl-go/v2/utils"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/rollout.go -package=service github.com/baetyl/baetyl-cloud/v2/service RolloutService
+
+const defaultRolloutHealthTimeout = 600
+
+// RolloutService releases the new version of application to the matched nodes wave by wave
+type RolloutService interface {
+	Get(namespace, name string) (*models.Rollout, error)
+	SetStrategy(namespace, name string, strategy *models.RolloutStrategy) (*models.Rollout, error)
+	Delete(tx interface{}, namespace, name string) error
+	// Start updates the desire of the first wave nodes and returns all matched nodes,
+	// nil is returned if the app has no rollout strategy and should be released to all nodes at once
+	Start(tx interface{}, namespace string, oldApp, app *specV1.Application) ([]string, error)
+	// Progress checks the health of the current waves and moves the progressing rollouts forward
+	Progress() error
+}
+
+type RolloutServiceImpl struct {
+	Rollout     plugin.Rollout
+	App         plugin.Application
+	Node        plugin.Node
+	Shadow      plugin.Shadow
+	NodeService NodeService
+	Webhook     WebhookService
+	Locker      LockerService
+	log         *log.Logger
+}
+
+// NewRolloutService new rollout service
+func NewRolloutService(config *config.CloudConfig) (RolloutService, error) {
+	rollout, err := plugin.GetPlugin(config.Plugin.Rollout)
+	if err != nil {
+		return nil, err
+	}
+	res, err := plugin.GetPlugin(config.Plugin.Resource)
+	if err != nil {
+		return nil, err
+	}
+	shadow, err := plugin.GetPlugin(config.Plugin.Shadow)
+	if err != nil {
+		return nil, err
+	}
+	ns, err := NewNodeService(config)
+	if err != nil {
+		return nil, err
+	}
+	webhook, err := NewWebhookService(config)
+	if err != nil {
+		return nil, err
+	}
+	locker, err := NewLockerService(config)
+	if err != nil {
+		return nil, err
+	}
+	return &RolloutServiceImpl{
+		Rollout:     rollout.(plugin.Rollout),
+		App:         res.(plugin.Application),
+		Node:        res.(plugin.Node),
+		Shadow:      shadow.(plugin.Shadow),
+		NodeService: ns,
+		Webhook:     webhook,
+		Locker:      locker,
+		log:         log.L().With(log.Any("service", "rollout")),
+	}, nil
+}
+
+func (r *RolloutServiceImpl) Get(namespace, name string) (*models.Rollout, error) {
+	return r.Rollout.GetRollout(nil, namespace, name)
+}
+
+// SetStrategy sets the strategy used by the next release, the progressing rollout is not affected
+func (r *RolloutServiceImpl) SetStrategy(namespace, name string, strategy *models.RolloutStrategy) (*models.Rollout, error) {
+	rollout, err := r.Rollout.GetRollout(nil, namespace, name)
+	if err != nil {
+		if !isNotFound(err) {
+			return nil, err
+		}
+		rollout = &models.Rollout{Namespace: namespace, Name: name}
+	}
+	rollout.Strategy = strategy
+	return r.Rollout.SaveRollout(nil, rollout)
+}
+
+func (r *RolloutServiceImpl) Delete(tx interface{}, namespace, name string) error {
+	return r.Rollout.DeleteRollout(tx, namespace, name)
+}
+
+func (r *RolloutServiceImpl) Start(tx interface{}, namespace string, oldApp, app *specV1.Application) ([]string, error) {
+	if oldApp == nil || app.Selector == "" || oldApp.Selector != app.Selector ||
+		oldApp.Version == app.Version || app.CronStatus == specV1.CronWait {
+		return nil, nil
+	}
+	rollout, err := r.Rollout.GetRollout(tx, namespace, app.Name)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if rollout.Strategy == nil {
+		return nil, nil
+	}
+
+	nodeList, err := r.Node.ListNode(tx, namespace, &models.ListOptions{LabelSelector: app.Selector})
+	if err != nil {
+		return nil, err
+	}
+	if len(nodeList.Items) == 0 {
+		return nil, nil
+	}
+	waves, err := planRolloutWaves(rollout.Strategy, nodeList.Items)
+	if err != nil {
+		return nil, err
+	}
+	waves[0].Status = models.RolloutProgressing
+	waves[0].StartTime = time.Now().UTC()
+	if err = r.NodeService.UpdateDesire(tx, namespace, waves[0].Nodes, app, RefreshNodeDesireByApp); err != nil {
+		return nil, err
+	}
+
+	rollout.Version = app.Version
+	rollout.PreviousVersion = oldApp.Version
+	rollout.Status = models.RolloutProgressing
+	rollout.Reason = ""
+	rollout.CurrentWave = 0
+	rollout.Waves = waves
+	if _, err = r.Rollout.SaveRollout(tx, rollout); err != nil {
+		return nil, err
+	}
+
+	var nodes []string
+	for _, n := range nodeList.Items {
+		nodes = append(nodes, n.Name)
+	}
+	return nodes, nil
+}
+
+func (r *RolloutServiceImpl) Progress() error {
+	rollouts, err := r.Rollout.ListRolloutByStatus(nil, models.RolloutProgressing)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for i := range rollouts {
+		rollout, err := r.progress(rollouts[i].Namespace, rollouts[i].Name)
+		if err != nil {
+			r.log.Error("failed to progress rollout, retry later",
+				log.Any(common.KeyContextNamespace, rollouts[i].Namespace),
+				log.Any("name", rollouts[i].Name),
+				log.Error(err))
+			continue
+		}
+		if rollout != nil && rollout.Status != models.RolloutProgressing {
+			r.notifyFinished(rollout)
+		}
+	}
+	return nil
+}
+
+// progress steps the rollout under the lock of namespace as the requests updating the apps, the rollout is read
+// again in the lock since it may be restarted or stopped in between, nil is returned if it isn't progressing any more
+func (r *RolloutServiceImpl) progress(namespace, name string) (*models.Rollout, error) {
+	ctx := context.Background()
+	lockName := "namespace_" + namespace
+	version, err := r.Locker.Lock(ctx, lockName, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Locker.Unlock(ctx, lockName, version)
+
+	rollout, err := r.Rollout.GetRollout(nil, namespace, name)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if rollout.Status != models.RolloutProgressing {
+		return nil, nil
+	}
+	if err = r.step(rollout); err != nil {
+		return nil, err
+	}
+	return rollout, nil
+}
+
+// notifyFinished emits the event of the rollout succeeded or failed
+func (r *RolloutServiceImpl) notifyFinished(rollout *models.Rollout) {
+	if r.Webhook == nil {
+		return
+	}
+	event := &models.Event{
+		ID:        fmt.Sprintf("%s/%s/%s", models.EventRolloutFinished, rollout.Name, rollout.Version),
+		Type:      models.EventRolloutFinished,
+		Namespace: rollout.Namespace,
+		Data: map[string]interface{}{
+			"app":     rollout.Name,
+			"version": rollout.Version,
+			"status":  string(rollout.Status),
+			"reason":  rollout.Reason,
+		},
+	}
+	if err := r.Webhook.Emit(event); err != nil {
+		r.log.Error("failed to emit rollout finished event", log.Any(common.KeyContextNamespace, rollout.Namespace),
+			log.Any("name", rollout.Name), log.Error(err))
+	}
+}
+
+func (r *RolloutServiceImpl) step(rollout *models.Rollout) error {
+	app, err := r.App.GetApplication(nil, rollout.Namespace, rollout.Name, "")
+	if err != nil {
+		if isNotFound(err) {
+			return r.Rollout.DeleteRollout(nil, rollout.Namespace, rollout.Name)
+		}
+		return err
+	}
+	if app.Version != rollout.Version {
+		r.fail(rollout, nil, fmt.Sprintf("the version of application is changed to %s", app.Version))
+		_, err = r.Rollout.SaveRollout(nil, rollout)
+		return err
+	}
+	if rollout.CurrentWave >= len(rollout.Waves) {
+		rollout.Status = models.RolloutSucceeded
+		_, err = r.Rollout.SaveRollout(nil, rollout)
+		return err
+	}
+
+	now := time.Now().UTC()
+	wave := &rollout.Waves[rollout.CurrentWave]
+	if wave.Status == models.RolloutProgressing {
+		unhealthy, failed, err := r.checkWave(app, wave.Nodes)
+		if err != nil {
+			return err
+		}
+		timeout := defaultRolloutHealthTimeout * time.Second
+		if rollout.Strategy != nil && rollout.Strategy.HealthTimeoutSeconds > 0 {
+			timeout = time.Duration(rollout.Strategy.HealthTimeoutSeconds) * time.Second
+		}
+		switch {
+		case len(failed) > 0:
+			r.fail(rollout, failed, "the application failed on nodes of the wave")
+		case len(unhealthy) > 0 && now.Sub(wave.StartTime) > timeout:
+			r.fail(rollout, unhealthy, "the nodes of the wave didn't become healthy in time")
+		case len(unhealthy) == 0:
+			wave.Status = models.RolloutSucceeded
+			wave.HealthyTime = now
+			if rollout.CurrentWave == len(rollout.Waves)-1 {
+				rollout.Status = models.RolloutSucceeded
+			}
+		}
+	}
+
+	if rollout.Status == models.RolloutProgressing && wave.Status == models.RolloutSucceeded {
+		var pause time.Duration
+		if rollout.Strategy != nil {
+			pause = time.Duration(rollout.Strategy.PauseSeconds) * time.Second
+		}
+		if now.Sub(wave.HealthyTime) >= pause {
+			next := &rollout.Waves[rollout.CurrentWave+1]
+			if err = r.NodeService.UpdateDesire(nil, rollout.Namespace, next.Nodes, app, RefreshNodeDesireByApp); err != nil {
+				return err
+			}
+			next.Status = models.RolloutProgressing
+			next.StartTime = now
+			rollout.CurrentWave++
+		}
+	}
+	_, err = r.Rollout.SaveRollout(nil, rollout)
+	return err
+}
+
+// fail stops the rollout, the nodes of the pending waves are left on the previous version
+func (r *RolloutServiceImpl) fail(rollout *models.Rollout, nodes []string, reason string) {
+	rollout.Status = models.RolloutFailed
+	rollout.Reason = reason
+	if rollout.CurrentWave < len(rollout.Waves) {
+		wave := &rollout.Waves[rollout.CurrentWave]
+		wave.Status = models.RolloutFailed
+		wave.FailedNodes = nodes
+	}
+}
+
+// checkWave returns the nodes which have not reported the healthy status of the new version, and the failed ones
+func (r *RolloutServiceImpl) checkWave(app *specV1.Application, nodes []string) ([]string, []string, error) {
+	shadows, err := r.Shadow.ListShadowByNames(nil, app.Namespace, nodes)
+	if err != nil {
+		return nil, nil, err
+	}
+	reports := map[string]specV1.Report{}
+	for _, s := range shadows {
+		reports[s.Name] = s.Report
+	}
+
+	var unhealthy, failed []string
+	for _, n := range nodes {
+		var stat *specV1.AppStats
+		if report, ok := reports[n]; ok && report != nil {
+			stats := report.AppStats(app.System)
+			for i := range stats {
+				if stats[i].Name == app.Name {
+					stat = &stats[i]
+					break
+				}
+			}
+		}
+		switch {
+		case stat == nil || baseVersion(stat.Version) != app.Version:
+			unhealthy = append(unhealthy, n)
+		case stat.Status == specV1.Failed:
+			failed = append(failed, n)
+		case stat.Status != specV1.Running && stat.Status != specV1.Succeeded:
+			unhealthy = append(unhealthy, n)
+		}
+	}
+	return unhealthy, failed, nil
+}
+
+// planRolloutWaves splits the nodes into the canary wave and the batches
+func planRolloutWaves(strategy *models.RolloutStrategy, nodes []specV1.Node) ([]models.RolloutWave, error) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+
+	var waves []models.RolloutWave
+	var canary, rest []string
+	for i, n := range nodes {
+		switch {
+		case strategy.CanarySelector != "":
+			ok, err := utils.IsLabelMatch(strategy.CanarySelector, n.Labels)
+			if err != nil {
+				return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+			}
+			if ok {
+				canary = append(canary, n.Name)
+				continue
+			}
+		case i < strategy.CanaryCount:
+			canary = append(canary, n.Name)
+			continue
+		}
+		rest = append(rest, n.Name)
+	}
+	if len(canary) > 0 {
+		waves = append(waves, models.RolloutWave{Canary: true, Nodes: canary, Status: models.RolloutPending})
+	}
+
+	size := strategy.BatchSize
+	if size <= 0 && strategy.BatchPercent > 0 {
+		size = (len(nodes)*strategy.BatchPercent + 99) / 100
+	}
+	if size <= 0 {
+		size = len(rest)
+	}
+	for i := 0; i < len(rest); i += size {
+		end := i + size
+		if end > len(rest) {
+			end = len(rest)
+		}
+		waves = append(waves, models.RolloutWave{Nodes: rest[i:end], Status: models.RolloutPending})
+	}
+	return waves, nil
+}
+
+func isNotFound(err error) bool {
+	e, ok := err.(errors.Coder)
+	return ok && e.Code() == common.ErrResourceNotFound
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
+	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func initRolloutService(t *testing.T) (*RolloutServiceImpl, *mockPlugin.MockRollout, *mockPlugin.MockResource, *mockPlugin.MockShadow, *ms.MockNodeService, *gomock.Controller) {
+	mockCtl := gomock.NewController(t)
+	mRollout := mockPlugin.NewMockRollout(mockCtl)
+	mResource := mockPlugin.NewMockResource(mockCtl)
+	mShadow := mockPlugin.NewMockShadow(mockCtl)
+	sNode := ms.NewMockNodeService(mockCtl)
+	sLocker := ms.NewMockLockerService(mockCtl)
+	sLocker.EXPECT().Lock(gomock.Any(), gomock.Any(), int64(0)).Return("version", nil).AnyTimes()
+	sLocker.EXPECT().Unlock(gomock.Any(), gomock.Any(), "version").AnyTimes()
+	rs := &RolloutServiceImpl{
+		Rollout:     mRollout,
+		App:         mResource,
+		Node:        mResource,
+		Shadow:      mShadow,
+		NodeService: sNode,
+		Locker:      sLocker,
+		log:         log.L().With(log.Any("test", "rollout")),
+	}
+	return rs, mRollout, mResource, mShadow, sNode, mockCtl
+}
+
+func genRolloutNodes(names ...string) []specV1.Node {
+	var nodes []specV1.Node
+	for _, n := range names {
+		nodes = append(nodes, specV1.Node{Name: n, Labels: map[string]string{"name": n}})
+	}
+	return nodes
+}
+
+func genRolloutShadow(name, app, version string, status specV1.Status) *models.Shadow {
+	report := specV1.Report{}
+	report.SetAppStats(false, []specV1.AppStats{{
+		AppInfo: specV1.AppInfo{Name: app, Version: version},
+		Status:  status,
+	}})
+	return &models.Shadow{Name: name, Report: report}
+}
+
+func TestPlanRolloutWaves(t *testing.T) {
+	nodes := genRolloutNodes("n5", "n4", "n3", "n2", "n1")
+
+	waves, err := planRolloutWaves(&models.RolloutStrategy{CanaryCount: 1, BatchSize: 2}, nodes)
+	assert.NoError(t, err)
+	assert.Len(t, waves, 3)
+	assert.True(t, waves[0].Canary)
+	assert.Equal(t, []string{"n1"}, waves[0].Nodes)
+	assert.Equal(t, []string{"n2", "n3"}, waves[1].Nodes)
+	assert.Equal(t, []string{"n4", "n5"}, waves[2].Nodes)
+
+	waves, err = planRolloutWaves(&models.RolloutStrategy{CanarySelector: "name in (n3,n5)", BatchPercent: 40}, nodes)
+	assert.NoError(t, err)
+	assert.Len(t, waves, 3)
+	assert.Equal(t, []string{"n3", "n5"}, waves[0].Nodes)
+	assert.Equal(t, []string{"n1", "n2"}, waves[1].Nodes)
+	assert.Equal(t, []string{"n4"}, waves[2].Nodes)
+
+	waves, err = planRolloutWaves(&models.RolloutStrategy{}, nodes)
+	assert.NoError(t, err)
+	assert.Len(t, waves, 1)
+	assert.False(t, waves[0].Canary)
+	assert.Len(t, waves[0].Nodes, 5)
+
+	_, err = planRolloutWaves(&models.RolloutStrategy{CanarySelector: "name in ("}, nodes)
+	assert.Error(t, err)
+}
+
+func TestRolloutStart(t *testing.T) {
+	rs, mRollout, mResource, _, sNode, mockCtl := initRolloutService(t)
+	defer mockCtl.Finish()
+
+	ns := "default"
+	oldApp := &specV1.Application{Namespace: ns, Name: "app", Version: "1", Selector: "a=b"}
+	app := &specV1.Application{Namespace: ns, Name: "app", Version: "2", Selector: "a=b"}
+	notFound := common.Error(common.ErrResourceNotFound, common.Field("type", "rollout"), common.Field("name", "app"))
+
+	// released to all nodes at once
+	nodes, err := rs.Start(nil, ns, nil, app)
+	assert.NoError(t, err)
+	assert.Nil(t, nodes)
+
+	mRollout.EXPECT().GetRollout(nil, ns, "app").Return(nil, notFound).Times(1)
+	nodes, err = rs.Start(nil, ns, oldApp, app)
+	assert.NoError(t, err)
+	assert.Nil(t, nodes)
+
+	mRollout.EXPECT().GetRollout(nil, ns, "app").Return(&models.Rollout{Namespace: ns, Name: "app"}, nil).Times(1)
+	nodes, err = rs.Start(nil, ns, oldApp, app)
+	assert.NoError(t, err)
+	assert.Nil(t, nodes)
+
+	rollout := &models.Rollout{Namespace: ns, Name: "app", Strategy: &models.RolloutStrategy{CanaryCount: 1}}
+	mRollout.EXPECT().GetRollout(nil, ns, "app").Return(rollout, nil).Times(1)
+	mResource.EXPECT().ListNode(nil, ns, gomock.Any()).Return(&models.NodeList{Items: genRolloutNodes("n2", "n1")}, nil).Times(1)
+	sNode.EXPECT().UpdateDesire(nil, ns, []string{"n1"}, app, gomock.Any()).Return(nil).Times(1)
+	mRollout.EXPECT().SaveRollout(nil, gomock.Any()).DoAndReturn(func(_ interface{}, r *models.Rollout) (*models.Rollout, error) {
+		assert.Equal(t, models.RolloutProgressing, r.Status)
+		assert.Equal(t, "2", r.Version)
+		assert.Equal(t, "1", r.PreviousVersion)
+		assert.Len(t, r.Waves, 2)
+		assert.Equal(t, models.RolloutProgressing, r.Waves[0].Status)
+		assert.Equal(t, models.RolloutPending, r.Waves[1].Status)
+		return r, nil
+	}).Times(1)
+	nodes, err = rs.Start(nil, ns, oldApp, app)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"n1", "n2"}, nodes)
+}
+
+func TestRolloutProgress(t *testing.T) {
+	rs, mRollout, mResource, mShadow, sNode, mockCtl := initRolloutService(t)
+	defer mockCtl.Finish()
+
+	ns := "default"
+	app := &specV1.Application{Namespace: ns, Name: "app", Version: "2", Selector: "a=b"}
+	genRollout := func() *models.Rollout {
+		return &models.Rollout{
+			Namespace: ns,
+			Name:      "app",
+			Strategy:  &models.RolloutStrategy{CanaryCount: 1},
+			Version:   "2",
+			Status:    models.RolloutProgressing,
+			Waves: []models.RolloutWave{
+				{Canary: true, Nodes: []string{"n1"}, Status: models.RolloutProgressing, StartTime: time.Now().UTC()},
+				{Nodes: []string{"n2"}, Status: models.RolloutPending},
+			},
+		}
+	}
+	mResource.EXPECT().GetApplication(nil, ns, "app", "").Return(app, nil).AnyTimes()
+	// the rollout is read again in the lock of namespace
+	expectRollouts := func(r *models.Rollout) {
+		mRollout.EXPECT().ListRolloutByStatus(nil, models.RolloutProgressing).Return([]models.Rollout{*r}, nil).Times(1)
+		mRollout.EXPECT().GetRollout(nil, ns, "app").Return(r, nil).Times(1)
+	}
+
+	// the canary node is not healthy yet
+	expectRollouts(genRollout())
+	mShadow.EXPECT().ListShadowByNames(nil, ns, []string{"n1"}).Return([]*models.Shadow{genRolloutShadow("n1", "app", "1", specV1.Running)}, nil).Times(1)
+	mRollout.EXPECT().SaveRollout(nil, gomock.Any()).DoAndReturn(func(_ interface{}, r *models.Rollout) (*models.Rollout, error) {
+		assert.Equal(t, models.RolloutProgressing, r.Status)
+		assert.Equal(t, 0, r.CurrentWave)
+		return r, nil
+	}).Times(1)
+	assert.NoError(t, rs.Progress())
+
+	// the canary node is healthy, the next wave is released
+	expectRollouts(genRollout())
+	mShadow.EXPECT().ListShadowByNames(nil, ns, []string{"n1"}).Return([]*models.Shadow{genRolloutShadow("n1", "app", "2", specV1.Running)}, nil).Times(1)
+	sNode.EXPECT().UpdateDesire(nil, ns, []string{"n2"}, app, gomock.Any()).Return(nil).Times(1)
+	mRollout.EXPECT().SaveRollout(nil, gomock.Any()).DoAndReturn(func(_ interface{}, r *models.Rollout) (*models.Rollout, error) {
+		assert.Equal(t, models.RolloutProgressing, r.Status)
+		assert.Equal(t, 1, r.CurrentWave)
+		assert.Equal(t, models.RolloutSucceeded, r.Waves[0].Status)
+		assert.Equal(t, models.RolloutProgressing, r.Waves[1].Status)
+		return r, nil
+	}).Times(1)
+	assert.NoError(t, rs.Progress())
+
+	// the canary node failed, the next wave is not released
+	expectRollouts(genRollout())
+	mShadow.EXPECT().ListShadowByNames(nil, ns, []string{"n1"}).Return([]*models.Shadow{genRolloutShadow("n1", "app", "2", specV1.Failed)}, nil).Times(1)
+	mRollout.EXPECT().SaveRollout(nil, gomock.Any()).DoAndReturn(func(_ interface{}, r *models.Rollout) (*models.Rollout, error) {
+		assert.Equal(t, models.RolloutFailed, r.Status)
+		assert.Equal(t, []string{"n1"}, r.Waves[0].FailedNodes)
+		assert.Equal(t, models.RolloutPending, r.Waves[1].Status)
+		return r, nil
+	}).Times(1)
+	assert.NoError(t, rs.Progress())
+
+	// the canary node didn't become healthy in time
+	timeout := genRollout()
+	timeout.Waves[0].StartTime = time.Now().UTC().Add(-time.Hour)
+	expectRollouts(timeout)
+	mShadow.EXPECT().ListShadowByNames(nil, ns, []string{"n1"}).Return(nil, nil).Times(1)
+	mRollout.EXPECT().SaveRollout(nil, gomock.Any()).DoAndReturn(func(_ interface{}, r *models.Rollout) (*models.Rollout, error) {
+		assert.Equal(t, models.RolloutFailed, r.Status)
+		assert.Equal(t, []string{"n1"}, r.Waves[0].FailedNodes)
+		return r, nil
+	}).Times(1)
+	assert.NoError(t, rs.Progress())
+
+	// the last wave is healthy
+	last := genRollout()
+	last.CurrentWave = 1
+	last.Waves[0].Status = models.RolloutSucceeded
+	last.Waves[1].Status = models.RolloutProgressing
+	last.Waves[1].StartTime = time.Now().UTC()
+	expectRollouts(last)
+	mShadow.EXPECT().ListShadowByNames(nil, ns, []string{"n2"}).Return([]*models.Shadow{genRolloutShadow("n2", "app", "2", specV1.Running)}, nil).Times(1)
+	mRollout.EXPECT().SaveRollout(nil, gomock.Any()).DoAndReturn(func(_ interface{}, r *models.Rollout) (*models.Rollout, error) {
+		assert.Equal(t, models.RolloutSucceeded, r.Status)
+		return r, nil
+	}).Times(1)
+	assert.NoError(t, rs.Progress())
+
+	// the rollout stopped before locked isn't progressed
+	stopped := genRollout()
+	mRollout.EXPECT().ListRolloutByStatus(nil, models.RolloutProgressing).Return([]models.Rollout{*stopped}, nil).Times(1)
+	stopped.Status = models.RolloutFailed
+	mRollout.EXPECT().GetRollout(nil, ns, "app").Return(stopped, nil).Times(1)
+	assert.NoError(t, rs.Progress())
+
+	// the app is updated again during the rollout
+	changed := genRollout()
+	changed.Version = "1"
+	expectRollouts(changed)
+	mRollout.EXPECT().SaveRollout(nil, gomock.Any()).DoAndReturn(func(_ interface{}, r *models.Rollout) (*models.Rollout, error) {
+		assert.Equal(t, models.RolloutFailed, r.Status)
+		return r, nil
+	}).Times(1)
+	assert.NoError(t, rs.Progress())
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"strings"
+	"time"
 
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 
@@ -11,6 +12,9 @@ import (
 	"github.com/baetyl/baetyl-cloud/v2/plugin"
 )
 
+// the retired data keys are kept for a while, since other replicas may still encrypt the secrets with them
+const dataKeyRetention = 24 * time.Hour
+
 //go:generate mockgen -destination=../mock/service/secret.go -package=service github.com/baetyl/baetyl-cloud/v2/service SecretService
 
 // SecretService SecretService
@@ -21,10 +25,15 @@ type SecretService interface {
 	Create(tx interface{}, namespace string, secret *specV1.Secret) (*specV1.Secret, error)
 	Update(namespace string, secret *specV1.Secret) (*specV1.Secret, error)
 	Delete(tx interface{}, namespace, name string) error
+	// Reencrypt re-encrypts the secrets with the current key for the key rotation, the count of secrets is returned
+	Reencrypt(namespace string) (int, error)
+	// PurgeDataKeys deletes the retired data keys which are no longer used, nothing is done if the secrets are not encrypted
+	PurgeDataKeys() error
 }
 
 type secretService struct {
-	secret plugin.Secret
+	secret     plugin.Secret
+	pluginName string
 }
 
 // NewSecretService NewSecretService
@@ -34,7 +43,8 @@ func NewSecretService(config *config.CloudConfig) (SecretService, error) {
 		return nil, err
 	}
 	return &secretService{
-		secret: secret.(plugin.Secret),
+		secret:     secret.(plugin.Secret),
+		pluginName: config.Plugin.Resource,
 	}, nil
 }
 
@@ -77,3 +87,24 @@ func (s *secretService) Update(namespace string, secret *specV1.Secret) (*specV1
 func (s *secretService) Delete(tx interface{}, namespace, name string) error {
 	return s.secret.DeleteSecret(tx, namespace, name)
 }
+
+// Reencrypt re-encrypts the secrets, only supported by the storage encrypting the secrets at rest
+func (s *secretService) Reencrypt(namespace string) (int, error) {
+	enc, ok := s.secret.(plugin.SecretEncryption)
+	if !ok {
+		return 0, common.Error(common.ErrPluginInvalid,
+			common.Field("name", s.pluginName),
+			common.Field("kind", "SecretEncryption"))
+	}
+	return enc.ReencryptSecrets(namespace)
+}
+
+// PurgeDataKeys purges the retired data keys, only supported by the storage encrypting the secrets at rest
+func (s *secretService) PurgeDataKeys() error {
+	enc, ok := s.secret.(plugin.SecretEncryption)
+	if !ok {
+		return nil
+	}
+	_, err := enc.PurgeDataKeys(time.Now().Add(-dataKeyRetention))
+	return err
+}
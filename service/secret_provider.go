@@ -0,0 +1,114 @@
+package service
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-contrib/cache/persistence"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/secret_provider.go -package=service github.com/baetyl/baetyl-cloud/v2/service SecretProviderService
+
+// SecretProviderService resolves the values of secrets declared to come from the external providers
+type SecretProviderService interface {
+	// Resolve returns the copy of secret with the values resolved by the references of data, the secret without provider is returned as is.
+	// The version of resolved secret is derived from the resolved values, so that the node doesn't keep the stale values once they change
+	Resolve(secret *specV1.Secret) (*specV1.Secret, error)
+	// CheckProvider checks the provider is configured
+	CheckProvider(name string) error
+}
+
+type SecretProviderServiceImpl struct {
+	providers map[string]plugin.SecretProvider
+	cache     persistence.CacheStore
+	duration  time.Duration
+}
+
+// NewSecretProviderService new secret provider service
+func NewSecretProviderService(config *config.CloudConfig) (SecretProviderService, error) {
+	providers := map[string]plugin.SecretProvider{}
+	for _, v := range config.Plugin.SecretProviders {
+		p, err := plugin.GetPlugin(v)
+		if err != nil {
+			return nil, err
+		}
+		providers[v] = p.(plugin.SecretProvider)
+	}
+	return &SecretProviderServiceImpl{
+		providers: providers,
+		cache:     persistence.NewInMemoryStore(config.SecretProvider.CacheDuration),
+		duration:  config.SecretProvider.CacheDuration,
+	}, nil
+}
+
+func (s *SecretProviderServiceImpl) Resolve(secret *specV1.Secret) (*specV1.Secret, error) {
+	name := secret.Annotations[common.AnnotationSecretProvider]
+	if name == "" {
+		return secret, nil
+	}
+	provider, ok := s.providers[name]
+	if !ok {
+		return nil, common.Error(common.ErrSecretProvider, common.Field("name", secret.Name),
+			common.Field("provider", name), common.Field("error", "the provider is not configured"))
+	}
+	res := *secret
+	res.Data = map[string][]byte{}
+	keys := make([]string, 0, len(secret.Data))
+	for k := range secret.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		value, err := s.getValue(provider, name, secret.Namespace, string(secret.Data[k]))
+		if err != nil {
+			log.L().Error("failed to resolve secret value", log.Any("namespace", secret.Namespace),
+				log.Any("name", secret.Name), log.Any("key", k), log.Any("provider", name), log.Error(err))
+			return nil, common.Error(common.ErrSecretProvider, common.Field("name", secret.Name),
+				common.Field("provider", name), common.Field("error", fmt.Sprintf("key %s: %s", k, err.Error())))
+		}
+		res.Data[k] = value
+	}
+	res.Version = resolvedSecretVersion(secret.Version, keys, res.Data)
+	return &res, nil
+}
+
+func (s *SecretProviderServiceImpl) CheckProvider(name string) error {
+	if _, ok := s.providers[name]; !ok {
+		return common.Error(common.ErrRequestParamInvalid, common.Field("error", fmt.Sprintf("the secret provider (%s) is not supported", name)))
+	}
+	return nil
+}
+
+// resolvedSecretVersion returns the version of the resolved secret, which changes if any resolved value changes
+func resolvedSecretVersion(version string, keys []string, data map[string][]byte) string {
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%x", k, sha256.Sum256(data[k])))
+	}
+	return baseVersion(version) + renderedVersionSeparator + shortHash(parts...)
+}
+
+// getValue returns the cached value of reference, the value is cached only in memory
+func (s *SecretProviderServiceImpl) getValue(provider plugin.SecretProvider, name, namespace, ref string) ([]byte, error) {
+	key := fmt.Sprintf("%s/%s/%s", name, namespace, ref)
+	var value []byte
+	if err := s.cache.Get(key, &value); err == nil {
+		return value, nil
+	}
+	value, err := provider.GetSecretValue(namespace, ref)
+	if err != nil {
+		return nil, err
+	}
+	if s.duration > 0 {
+		s.cache.Set(key, value, s.duration)
+	}
+	return value, nil
+}
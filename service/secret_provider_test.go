@@ -0,0 +1,76 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/gin-contrib/cache/persistence"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+func TestSecretProviderResolve(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	provider := mockPlugin.NewMockSecretProvider(mockCtl)
+	s := &SecretProviderServiceImpl{
+		providers: map[string]plugin.SecretProvider{"file": provider},
+		cache:     persistence.NewInMemoryStore(time.Minute),
+		duration:  time.Minute,
+	}
+
+	// the secret without provider is returned as is
+	plain := &specV1.Secret{Name: "plain", Namespace: "default", Data: map[string][]byte{"a": []byte("b")}}
+	res, err := s.Resolve(plain)
+	assert.NoError(t, err)
+	assert.Equal(t, plain, res)
+
+	secret := &specV1.Secret{
+		Name:        "db",
+		Namespace:   "default",
+		Version:     "3",
+		Annotations: map[string]string{common.AnnotationSecretProvider: "file"},
+		Data:        map[string][]byte{"password": []byte("db/password"), "user": []byte("db/user")},
+	}
+	provider.EXPECT().GetSecretValue("default", "db/password").Return([]byte("secret"), nil).Times(1)
+	provider.EXPECT().GetSecretValue("default", "db/user").Return([]byte("root"), nil).Times(1)
+	var version string
+	for i := 0; i < 2; i++ {
+		res, err = s.Resolve(secret)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string][]byte{"password": []byte("secret"), "user": []byte("root")}, res.Data)
+		assert.True(t, strings.HasPrefix(res.Version, "3-"))
+		version = res.Version
+	}
+	// the references are kept in the original one
+	assert.Equal(t, []byte("db/password"), secret.Data["password"])
+	assert.Equal(t, "3", secret.Version)
+
+	// the version changes once the resolved value changes
+	s.cache.Flush()
+	provider.EXPECT().GetSecretValue("default", "db/password").Return([]byte("rotated"), nil).Times(1)
+	provider.EXPECT().GetSecretValue("default", "db/user").Return([]byte("root"), nil).Times(1)
+	res, err = s.Resolve(secret)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(res.Version, "3-"))
+	assert.NotEqual(t, version, res.Version)
+
+	s.cache.Flush()
+	provider.EXPECT().GetSecretValue("default", "db/password").Return(nil, errors.New("not found")).Times(1)
+	_, err = s.Resolve(secret)
+	assert.EqualError(t, err, "The secret (db) can't be resolved from the provider (file). (key password: not found)")
+
+	secret.Annotations[common.AnnotationSecretProvider] = "vault"
+	_, err = s.Resolve(secret)
+	assert.EqualError(t, err, "The secret (db) can't be resolved from the provider (vault). (the provider is not configured)")
+
+	assert.NoError(t, s.CheckProvider("file"))
+	assert.Error(t, s.CheckProvider("vault"))
+}
@@ -2,11 +2,13 @@ package service
 
 import (
 	"testing"
+	"time"
 
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
 	"github.com/baetyl/baetyl-cloud/v2/models"
 )
 
@@ -94,3 +96,45 @@ func TestDefaultRegistryService_Update(t *testing.T) {
 	_, err = cs.Update(registry.Namespace, registry)
 	assert.NoError(t, err)
 }
+
+type mockEncryptedSecret struct {
+	*mockPlugin.MockResource
+	count  int
+	before time.Time
+}
+
+func (m *mockEncryptedSecret) ReencryptSecrets(_ string) (int, error) {
+	return m.count, nil
+}
+
+func (m *mockEncryptedSecret) PurgeDataKeys(before time.Time) (int, error) {
+	m.before = before
+	return m.count, nil
+}
+
+func TestDefaultRegistryService_Reencrypt(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	cs, err := NewSecretService(mockObject.conf)
+	assert.NoError(t, err)
+	_, err = cs.Reencrypt("default")
+	assert.Error(t, err)
+
+	cs = &secretService{secret: &mockEncryptedSecret{MockResource: mockObject.secret, count: 2}}
+	count, err := cs.Reencrypt("default")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestDefaultRegistryService_PurgeDataKeys(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	cs, err := NewSecretService(mockObject.conf)
+	assert.NoError(t, err)
+	assert.NoError(t, cs.PurgeDataKeys())
+
+	enc := &mockEncryptedSecret{MockResource: mockObject.secret, count: 1}
+	cs = &secretService{secret: enc}
+	assert.NoError(t, cs.PurgeDataKeys())
+	assert.WithinDuration(t, time.Now().Add(-dataKeyRetention), enc.before, time.Minute)
+}
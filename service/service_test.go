@@ -21,6 +21,7 @@ type MockServices struct {
 	secret         *mockPlugin.MockResource
 	app            *mockPlugin.MockResource
 	index          *mockPlugin.MockIndex
+	appHistory     *mockPlugin.MockAppHistory
 	objectStorage  *mockPlugin.MockObject
 	functionPlugin *mockPlugin.MockFunction
 	pki            *mockPlugin.MockPKI
@@ -32,6 +33,9 @@ type MockServices struct {
 	module         *mockPlugin.MockModule
 	task           *mockPlugin.MockTask
 	cache          *mockPlugin.MockDataCache
+	callback       *mockPlugin.MockCallback
+	sign           *mockPlugin.MockSign
+	installToken   *mockPlugin.MockInstallToken
 }
 
 func (m *MockServices) Close() {
@@ -117,6 +121,13 @@ func mockIndex(mock plugin.Index) plugin.Factory {
 	return factory
 }
 
+func mockAppHistory(mock plugin.AppHistory) plugin.Factory {
+	factory := func() (plugin.Plugin, error) {
+		return mock, nil
+	}
+	return factory
+}
+
 func mockTask(task plugin.Task) plugin.Factory {
 	factory := func() (plugin.Plugin, error) {
 		return task, nil
@@ -131,6 +142,27 @@ func mockCache(cache plugin.DataCache) plugin.Factory {
 	return factory
 }
 
+func mockCallback(mock plugin.Callback) plugin.Factory {
+	factory := func() (plugin.Plugin, error) {
+		return mock, nil
+	}
+	return factory
+}
+
+func mockSign(mock plugin.Sign) plugin.Factory {
+	factory := func() (plugin.Plugin, error) {
+		return mock, nil
+	}
+	return factory
+}
+
+func mockInstallToken(mock plugin.InstallToken) plugin.Factory {
+	factory := func() (plugin.Plugin, error) {
+		return mock, nil
+	}
+	return factory
+}
+
 func mockTestConfig() *config.CloudConfig {
 	conf := &config.CloudConfig{}
 	conf.Plugin.Resource = common.RandString(9)
@@ -146,6 +178,9 @@ func mockTestConfig() *config.CloudConfig {
 	conf.Plugin.Property = common.RandString(9)
 	conf.Plugin.Task = common.RandString(9)
 	conf.Plugin.Cache = common.RandString(9)
+	conf.Plugin.Callback = common.RandString(9)
+	conf.Plugin.Sign = common.RandString(9)
+	conf.Plugin.InstallToken = common.RandString(9)
 	conf.Template.Path = "../scripts/native/templates"
 	return conf
 }
@@ -195,12 +230,24 @@ func InitMockEnvironment(t *testing.T) *MockServices {
 	mIndex := mockPlugin.NewMockIndex(mockCtl)
 	plugin.RegisterFactory(conf.Plugin.Index, mockIndex(mIndex))
 
+	mAppHistory := mockPlugin.NewMockAppHistory(mockCtl)
+	plugin.RegisterFactory(conf.Plugin.AppHistory, mockAppHistory(mAppHistory))
+
 	mTask := mockPlugin.NewMockTask(mockCtl)
 	plugin.RegisterFactory(conf.Plugin.Task, mockTask(mTask))
 
 	mCache := mockPlugin.NewMockDataCache(mockCtl)
 	plugin.RegisterFactory(conf.Plugin.Cache, mockCache(mCache))
 
+	mCallback := mockPlugin.NewMockCallback(mockCtl)
+	plugin.RegisterFactory(conf.Plugin.Callback, mockCallback(mCallback))
+
+	mSign := mockPlugin.NewMockSign(mockCtl)
+	plugin.RegisterFactory(conf.Plugin.Sign, mockSign(mSign))
+
+	mInstallToken := mockPlugin.NewMockInstallToken(mockCtl)
+	plugin.RegisterFactory(conf.Plugin.InstallToken, mockInstallToken(mInstallToken))
+
 	_, err := NewSyncService(conf)
 	assert.Nil(t, err)
 
@@ -214,6 +261,7 @@ func InitMockEnvironment(t *testing.T) *MockServices {
 		secret:         mResource,
 		app:            mResource,
 		index:          mIndex,
+		appHistory:     mAppHistory,
 		objectStorage:  mockObjectStorage,
 		functionPlugin: mockFunctionPlugin,
 		pki:            mPKI,
@@ -224,6 +272,9 @@ func InitMockEnvironment(t *testing.T) *MockServices {
 		module:         mModule,
 		task:           mTask,
 		cache:          mCache,
+		callback:       mCallback,
+		sign:           mSign,
+		installToken:   mInstallToken,
 	}
 }
 
@@ -15,7 +15,11 @@ import (
 
 type SignService interface {
 	plugin.Sign
+	// GenToken generates the token of data signed by the active key, whose id is put into data
 	GenToken(map[string]interface{}) (string, error)
+	// SignToken generates the token of data as it is, which is signed by the key of InfoKeyID in data,
+	// or the legacy key if absent. It regenerates the token to verify
+	SignToken(map[string]interface{}) (string, error)
 }
 
 type signService struct {
@@ -31,12 +35,24 @@ func NewSignService(config *config.CloudConfig) (SignService, error) {
 }
 
 func (s *signService) GenToken(data map[string]interface{}) (string, error) {
+	keyID, err := s.ActiveKeyID()
+	if err != nil {
+		return "", err
+	}
+	if keyID != "" {
+		data[InfoKeyID] = keyID
+	}
+	return s.SignToken(data)
+}
+
+func (s *signService) SignToken(data map[string]interface{}) (string, error) {
 	signData, err := json.Marshal(data)
 	if err != nil {
 		return "", err
 	}
+	keyID, _ := data[InfoKeyID].(string)
 	dataStr := hex.EncodeToString(signData)
-	sign, err := s.Signature(signData)
+	sign, err := s.SignatureWithKey(keyID, signData)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignService_GenToken(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	ss, err := NewSignService(mockObject.conf)
+	assert.NoError(t, err)
+
+	// the active key id is put into the token
+	data := map[string]interface{}{InfoName: "n1", InfoNamespace: "default"}
+	mockObject.sign.EXPECT().ActiveKeyID().Return("k1", nil).Times(1)
+	mockObject.sign.EXPECT().SignatureWithKey("k1", []byte(`{"k":"k1","n":"n1","ns":"default"}`)).Return([]byte("sign"), nil).Times(1)
+	token, err := ss.GenToken(data)
+	assert.NoError(t, err)
+	hashed := sha256.Sum256([]byte("sign"))
+	assert.Equal(t, hex.EncodeToString(hashed[:])[:10], token[:10])
+	raw, err := hex.DecodeString(token[10:])
+	assert.NoError(t, err)
+	info := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(raw, &info))
+	assert.Equal(t, "k1", info[InfoKeyID])
+
+	// the token is regenerated with the key in it even if the key is retired
+	mockObject.sign.EXPECT().SignatureWithKey("k1", raw).Return([]byte("sign"), nil).Times(1)
+	res, err := ss.SignToken(info)
+	assert.NoError(t, err)
+	assert.Equal(t, token, res)
+
+	// the legacy key is active
+	data = map[string]interface{}{InfoName: "n1"}
+	mockObject.sign.EXPECT().ActiveKeyID().Return("", nil).Times(1)
+	mockObject.sign.EXPECT().SignatureWithKey("", []byte(`{"n":"n1"}`)).Return([]byte("sign"), nil).Times(1)
+	_, err = ss.GenToken(data)
+	assert.NoError(t, err)
+	assert.NotContains(t, data, InfoKeyID)
+
+	mockObject.sign.EXPECT().ActiveKeyID().Return("", fmt.Errorf("error")).Times(1)
+	_, err = ss.GenToken(data)
+	assert.Error(t, err)
+
+	mockObject.sign.EXPECT().SignatureWithKey("", []byte(`{"n":"n1"}`)).Return(nil, fmt.Errorf("error")).Times(1)
+	_, err = ss.SignToken(data)
+	assert.Error(t, err)
+}
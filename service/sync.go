@@ -32,8 +32,11 @@ type SyncServiceImpl struct {
 	NodeService   NodeService
 	AppService    ApplicationService
 	SecretService SecretService
-	ObjectService ObjectService
-	Hooks         map[string]interface{}
+	// resolves the values of secrets from the external providers, which are never persisted
+	SecretProvider SecretProviderService
+	ObjectService  ObjectService
+	AppHistory     AppHistoryService
+	Hooks          map[string]interface{}
 }
 
 // NewSyncService new SyncService
@@ -58,10 +61,18 @@ func NewSyncService(config *config.CloudConfig) (SyncService, error) {
 	if err != nil {
 		return nil, err
 	}
+	es.SecretProvider, err = NewSecretProviderService(config)
+	if err != nil {
+		return nil, err
+	}
 	es.ObjectService, err = NewObjectService(config)
 	if err != nil {
 		return nil, err
 	}
+	es.AppHistory, err = NewAppHistoryService(config)
+	if err != nil {
+		return nil, err
+	}
 	es.Hooks[HookNamePopulateConfig] = HandlerPopulateConfig(es.PopulateConfig)
 	return es, nil
 }
@@ -147,6 +158,19 @@ func extractComparingReport(report specV1.Report) specV1.Report {
 
 func (t *SyncServiceImpl) Desire(namespace string, crdInfos []specV1.ResourceInfo, metadata map[string]string) ([]specV1.ResourceValue, error) {
 	var crdDatas []specV1.ResourceValue
+	// the context to render the node placeholders of configs, loaded once if needed
+	var placeholderCtx *common.NodePlaceholderContext
+	getPlaceholderCtx := func() (*common.NodePlaceholderContext, error) {
+		if placeholderCtx != nil {
+			return placeholderCtx, nil
+		}
+		node, err := t.NodeService.Get(nil, namespace, metadata["name"])
+		if err != nil {
+			return nil, err
+		}
+		placeholderCtx = genPlaceholderContext(node, node.Desire, node.Report)
+		return placeholderCtx, nil
+	}
 	for _, info := range crdInfos {
 		crdData := specV1.ResourceValue{
 			ResourceInfo: info,
@@ -154,14 +178,35 @@ func (t *SyncServiceImpl) Desire(namespace string, crdInfos []specV1.ResourceInf
 		log.L().Info("sync get crd", log.Any("kind", info.Kind), log.Any("name", info.Name))
 		switch info.Kind {
 		case specV1.KindApplication, specV1.KindApp:
-			app, err := t.AppService.Get(namespace, info.Name, info.Version)
+			version := baseVersion(info.Version)
+			app, err := t.AppService.Get(namespace, info.Name, version)
 			if err != nil {
 				log.L().Error("failed to get application", log.Any(common.KeyContextNamespace, namespace), log.Any("name", info.Name))
 				return nil, err
 			}
+			// the nodes which are not released yet by rollout still desire the previous version
+			if version != "" && app.Version != version {
+				if h, err := t.AppHistory.GetByVersion(namespace, info.Name, version); err == nil && h.Application != nil {
+					app = h.Application
+				}
+			}
+			app, err = t.renderAppConfigVersions(namespace, app, getPlaceholderCtx)
+			if err != nil {
+				log.L().Error("failed to render configs of application", log.Any(common.KeyContextNamespace, namespace), log.Any("name", info.Name))
+				return nil, err
+			}
+			app, err = t.resolveAppSecretVersions(namespace, app)
+			if err != nil {
+				log.L().Error("failed to resolve secrets of application", log.Any(common.KeyContextNamespace, namespace), log.Any("name", info.Name))
+				return nil, err
+			}
+			// the version is changed for node if the rendered configs of node are changed
+			if version != "" && app.Version == version {
+				app.Version = info.Version
+			}
 			crdData.Value.Value = app
 		case specV1.KindConfiguration, specV1.KindConfig:
-			cfg, err := t.ConfigService.Get(nil, namespace, info.Name, info.Version)
+			cfg, err := t.ConfigService.Get(nil, namespace, info.Name, baseVersion(info.Version))
 			if err != nil {
 				log.L().Error("failed to get config", log.Any(common.KeyContextNamespace, namespace), log.Any("name", info.Name))
 				return nil, err
@@ -170,13 +215,25 @@ func (t *SyncServiceImpl) Desire(namespace string, crdInfos []specV1.ResourceInf
 				log.L().Error("failed to populate config", log.Any(common.KeyContextNamespace, namespace), log.Any("name", info.Name))
 				return nil, err
 			}
+			if hasConfigPlaceholders(cfg) {
+				ctx, err := getPlaceholderCtx()
+				if err != nil {
+					log.L().Error("failed to render config", log.Any(common.KeyContextNamespace, namespace), log.Any("name", info.Name))
+					return nil, err
+				}
+				cfg = renderConfig(cfg, ctx)
+			}
 			crdData.Value.Value = cfg
 		case specV1.KindSecret:
-			secret, err := t.SecretService.Get(namespace, info.Name, info.Version)
+			secret, err := t.SecretService.Get(namespace, info.Name, baseVersion(info.Version))
 			if err != nil {
 				log.L().Error("failed to get secret", log.Any(common.KeyContextNamespace, namespace), log.Any("name", info.Name))
 				return nil, err
 			}
+			secret, err = t.SecretProvider.Resolve(secret)
+			if err != nil {
+				return nil, err
+			}
 			crdData.Value.Value = secret
 		default:
 			return nil, fmt.Errorf("unsupported request type")
@@ -186,6 +243,74 @@ func (t *SyncServiceImpl) Desire(namespace string, crdInfos []specV1.ResourceInf
 	return crdDatas, nil
 }
 
+// renderAppConfigVersions replaces the versions of configs which have node placeholders with the rendered versions of node,
+// so that the node fetches the configs again if the rendered data changes
+func (t *SyncServiceImpl) renderAppConfigVersions(namespace string, app *specV1.Application, getCtx func() (*common.NodePlaceholderContext, error)) (*specV1.Application, error) {
+	var res *specV1.Application
+	for i, v := range app.Volumes {
+		if v.Config == nil {
+			continue
+		}
+		cfg, err := t.ConfigService.Get(nil, namespace, v.Config.Name, "")
+		if err != nil {
+			return nil, err
+		}
+		if !hasConfigPlaceholders(cfg) {
+			continue
+		}
+		ctx, err := getCtx()
+		if err != nil {
+			return nil, err
+		}
+		if res == nil {
+			copied := *app
+			copied.Volumes = append([]specV1.Volume{}, app.Volumes...)
+			res = &copied
+		}
+		ref := *v.Config
+		ref.Version = renderConfig(cfg, ctx).Version
+		res.Volumes[i].Config = &ref
+	}
+	if res == nil {
+		return app, nil
+	}
+	return res, nil
+}
+
+// resolveAppSecretVersions replaces the versions of secrets which come from the providers with the resolved versions,
+// so that the node fetches the secrets again if the resolved values change
+func (t *SyncServiceImpl) resolveAppSecretVersions(namespace string, app *specV1.Application) (*specV1.Application, error) {
+	var res *specV1.Application
+	for i, v := range app.Volumes {
+		if v.Secret == nil {
+			continue
+		}
+		secret, err := t.SecretService.Get(namespace, v.Secret.Name, "")
+		if err != nil {
+			return nil, err
+		}
+		if secret.Annotations[common.AnnotationSecretProvider] == "" {
+			continue
+		}
+		resolved, err := t.SecretProvider.Resolve(secret)
+		if err != nil {
+			return nil, err
+		}
+		if res == nil {
+			copied := *app
+			copied.Volumes = append([]specV1.Volume{}, app.Volumes...)
+			res = &copied
+		}
+		ref := *v.Secret
+		ref.Version = resolved.Version
+		res.Volumes[i].Secret = &ref
+	}
+	if res == nil {
+		return app, nil
+	}
+	return res, nil
+}
+
 func (t *SyncServiceImpl) PopulateConfig(cfg *specV1.Configuration, metadata map[string]string) error {
 	for k, v := range cfg.Data {
 		if strings.HasPrefix(k, common.ConfigObjectPrefix) {
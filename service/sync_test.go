@@ -2,6 +2,7 @@ package service
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -157,6 +158,134 @@ func TestSyncDesire(t *testing.T) {
 	assert.Equal(t, resObj2.MD5, obj2.MD5)
 	assert.Equal(t, resObj2.URL, obj2.URL)
 	assert.Empty(t, resObj2.Token)
+
+	// the node not released by rollout yet desires the previous version
+	hs := ms.NewMockAppHistoryService(mockObject.ctl)
+	sync.AppHistory = hs
+	prevApp := &specV1.Application{
+		Name:    "app",
+		Version: "v0",
+	}
+	as.EXPECT().Get(namespace, "app", "v0").Return(app, nil).Times(1)
+	hs.EXPECT().GetByVersion(namespace, "app", "v0").Return(&models.AppHistory{Version: "v0", Application: prevApp}, nil).Times(1)
+	res, err = sync.Desire(namespace, []specV1.ResourceInfo{{Kind: specV1.KindApplication, Name: "app", Version: "v0"}}, map[string]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, prevApp, res[0].Value.Value.(*specV1.Application))
+}
+
+func TestSyncDesireRenderConfig(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	cs := ms.NewMockConfigService(mockObject.ctl)
+	as := ms.NewMockApplicationService(mockObject.ctl)
+	ns := ms.NewMockNodeService(mockObject.ctl)
+	sync := SyncServiceImpl{
+		ConfigService: cs,
+		AppService:    as,
+		NodeService:   ns,
+		Hooks:         map[string]interface{}{},
+	}
+	sync.Hooks[HookNamePopulateConfig] = HandlerPopulateConfig(sync.PopulateConfig)
+
+	namespace := "default"
+	metadata := map[string]string{"namespace": namespace, "name": "node01"}
+	node := &specV1.Node{
+		Name:   "node01",
+		Labels: map[string]string{"site": "bj"},
+		Desire: specV1.Desire{common.NodeProps: map[string]interface{}{"broker": "tcp://10.0.0.1:1883"}},
+		Report: specV1.Report{},
+	}
+	cfg := &specV1.Configuration{
+		Name:    "cfg",
+		Version: "10",
+		Data:    map[string]string{"conf.yml": "site: ${node.labels.site}\nbroker: ${node.properties.broker}"},
+	}
+	plain := &specV1.Configuration{
+		Name:    "plain",
+		Version: "20",
+		Data:    map[string]string{"conf.yml": "a: b"},
+	}
+	app := &specV1.Application{
+		Name:    "app",
+		Version: "1",
+		Volumes: []specV1.Volume{
+			{Name: "cfg", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "cfg", Version: "10"}}},
+			{Name: "plain", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "plain", Version: "20"}}},
+		},
+	}
+	ns.EXPECT().Get(nil, namespace, "node01").Return(node, nil).Times(1)
+	as.EXPECT().Get(namespace, "app", "1").Return(app, nil).Times(1)
+	cs.EXPECT().Get(nil, namespace, "cfg", "").Return(cfg, nil).Times(1)
+	cs.EXPECT().Get(nil, namespace, "plain", "").Return(plain, nil).Times(1)
+	cs.EXPECT().Get(nil, namespace, "cfg", "10").Return(cfg, nil).Times(1)
+	res, err := sync.Desire(namespace, []specV1.ResourceInfo{
+		{Kind: specV1.KindApplication, Name: "app", Version: "1-abcdef12"},
+		{Kind: specV1.KindConfiguration, Name: "cfg", Version: "10-12345678"},
+	}, metadata)
+	assert.NoError(t, err)
+
+	resApp := res[0].Value.Value.(*specV1.Application)
+	resCfg := res[1].Value.Value.(*specV1.Configuration)
+	assert.Equal(t, "1-abcdef12", resApp.Version)
+	assert.Equal(t, resCfg.Version, resApp.Volumes[0].Config.Version)
+	assert.True(t, strings.HasPrefix(resCfg.Version, "10-"))
+	assert.Equal(t, "20", resApp.Volumes[1].Config.Version)
+	assert.Equal(t, "site: bj\nbroker: tcp://10.0.0.1:1883", resCfg.Data["conf.yml"])
+	// the original ones are not modified
+	assert.Equal(t, "10", app.Volumes[0].Config.Version)
+	assert.Equal(t, "10", cfg.Version)
+}
+
+func TestSyncDesireSecretProvider(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	sa := ms.NewMockApplicationService(mockObject.ctl)
+	ss := ms.NewMockSecretService(mockObject.ctl)
+	sp := ms.NewMockSecretProviderService(mockObject.ctl)
+	sync := SyncServiceImpl{AppService: sa, SecretService: ss, SecretProvider: sp}
+
+	namespace := "default"
+	secret := &specV1.Secret{
+		Name:        "db",
+		Namespace:   namespace,
+		Version:     "1",
+		Annotations: map[string]string{common.AnnotationSecretProvider: "file"},
+		Data:        map[string][]byte{"password": []byte("db/password")},
+	}
+	resolved := &specV1.Secret{Name: "db", Namespace: namespace, Version: "1-abcdef12", Data: map[string][]byte{"password": []byte("secret")}}
+	// the secret is got by the original version
+	ss.EXPECT().Get(namespace, "db", "1").Return(secret, nil).Times(2)
+	sp.EXPECT().Resolve(secret).Return(resolved, nil).Times(1)
+	res, err := sync.Desire(namespace, []specV1.ResourceInfo{{Kind: specV1.KindSecret, Name: "db", Version: "1-abcdef12"}}, map[string]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, resolved, res[0].Value.Value)
+
+	sp.EXPECT().Resolve(secret).Return(nil, common.Error(common.ErrSecretProvider, common.Field("name", "db"))).Times(1)
+	_, err = sync.Desire(namespace, []specV1.ResourceInfo{{Kind: specV1.KindSecret, Name: "db", Version: "1"}}, map[string]string{})
+	assert.Error(t, err)
+
+	// the app refers to the resolved version of secret
+	app := &specV1.Application{
+		Name:      "app",
+		Namespace: namespace,
+		Version:   "5",
+		Volumes: []specV1.Volume{
+			{Name: "db", VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "db", Version: "1"}}},
+			{Name: "plain", VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "plain", Version: "2"}}},
+		},
+	}
+	sa.EXPECT().Get(namespace, "app", "5").Return(app, nil).Times(1)
+	ss.EXPECT().Get(namespace, "db", "").Return(secret, nil).Times(1)
+	ss.EXPECT().Get(namespace, "plain", "").Return(&specV1.Secret{Name: "plain", Namespace: namespace, Version: "2"}, nil).Times(1)
+	sp.EXPECT().Resolve(secret).Return(resolved, nil).Times(1)
+	res, err = sync.Desire(namespace, []specV1.ResourceInfo{{Kind: specV1.KindApplication, Name: "app", Version: "5"}}, map[string]string{})
+	assert.NoError(t, err)
+	resApp := res[0].Value.Value.(*specV1.Application)
+	assert.Equal(t, "5", resApp.Version)
+	assert.Equal(t, "1-abcdef12", resApp.Volumes[0].Secret.Version)
+	assert.Equal(t, "2", resApp.Volumes[1].Secret.Version)
+	// the original one is not modified
+	assert.Equal(t, "1", app.Volumes[0].Secret.Version)
 }
 
 func TestSyncService_Report(t *testing.T) {
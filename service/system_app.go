@@ -351,7 +351,7 @@ func (s *SystemAppServiceImpl) genEkuiperApp(tx interface{}, ns string, node *sp
 func (s *SystemAppServiceImpl) genNodeCerts(tx interface{}, ns, nodeName, appName string) (*specV1.Secret, error) {
 	confName := fmt.Sprintf("crt-%s-%s", nodeName, common.RandString(9))
 	certName := fmt.Sprintf(`%s.%s`, ns, nodeName)
-	certPEM, err := s.PKI.SignClientCertificate(certName, models.AltNames{})
+	certPEM, err := s.PKI.SignNodeCertificate(ns, certName, models.AltNames{})
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -0,0 +1,489 @@
+package service
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	gohttp "net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/http"
+	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/baetyl/baetyl-go/v2/log"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/webhook.go -package=service github.com/baetyl/baetyl-cloud/v2/service WebhookService
+
+// the headers of the webhook requests, the signature is the hex of HMAC-SHA256 with the secret of callback,
+// which is signed on the timestamp and the body joined by a dot
+const (
+	HeaderWebhookEvent     = "X-Baetyl-Event"
+	HeaderWebhookDelivery  = "X-Baetyl-Delivery"
+	HeaderWebhookTimestamp = "X-Baetyl-Timestamp"
+	HeaderWebhookSignature = "X-Baetyl-Signature"
+)
+
+const (
+	deliveryNameLength = 16
+	// the max number of deliveries sent in one round
+	deliveryBatchSize = 100
+	// the delivery claimed by one instance isn't sent by others within the lease, which is added to the timeout
+	deliveryClaimLease = time.Minute
+	webhookSecretSize  = 32
+)
+
+// WebhookService delivers the events to the callbacks subscribing them
+type WebhookService interface {
+	// GetCallback returns the callback without its secret
+	GetCallback(namespace, name string) (*models.Callback, error)
+	ListCallback(namespace string) (*models.CallbackList, error)
+	// CreateCallback creates the callback with a new secret, which is returned only here
+	CreateCallback(callback *models.Callback) (*models.Callback, error)
+	// UpdateCallback updates the callback, the secret is kept
+	UpdateCallback(callback *models.Callback) (*models.Callback, error)
+	// DeleteCallback deletes the callback and its deliveries
+	DeleteCallback(namespace, name string) error
+	GetDelivery(namespace, name string) (*models.Delivery, error)
+	ListDelivery(namespace, callback string, params *models.ListOptions) (*models.DeliveryList, error)
+	// ListSubscribedNamespaces lists the namespaces which have the callbacks subscribing the event
+	ListSubscribedNamespaces(event string) ([]string, error)
+	// Emit records the deliveries of event to the callbacks of namespace subscribing it, which are sent by Deliver,
+	// the callback which the event with the same id has been delivered to is skipped
+	Emit(event *models.Event) error
+	// Deliver sends the due deliveries, the failed ones are retried with backoff until the max attempts
+	Deliver() error
+	// Redeliver sends the delivery at once regardless of its status, the attempts are restarted
+	Redeliver(namespace, name string) (*models.Delivery, error)
+	// NotifyOfflineNodes emits the offline events of nodes in the namespaces subscribing them, once for each offline
+	NotifyOfflineNodes() error
+}
+
+type WebhookServiceImpl struct {
+	Callback    plugin.Callback
+	Node        plugin.Node
+	Shadow      plugin.Shadow
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+	Timeout     time.Duration
+	client      *gohttp.Client
+	log         *log.Logger
+}
+
+// NewWebhookService new webhook service
+func NewWebhookService(config *config.CloudConfig) (WebhookService, error) {
+	callback, err := plugin.GetPlugin(config.Plugin.Callback)
+	if err != nil {
+		return nil, err
+	}
+	res, err := plugin.GetPlugin(config.Plugin.Resource)
+	if err != nil {
+		return nil, err
+	}
+	shadow, err := plugin.GetPlugin(config.Plugin.Shadow)
+	if err != nil {
+		return nil, err
+	}
+	timeout := http.NewClientOptions().Timeout
+	if config.Webhook.Timeout > 0 {
+		timeout = config.Webhook.Timeout
+	}
+	return &WebhookServiceImpl{
+		Callback:    callback.(plugin.Callback),
+		Node:        res.(plugin.Node),
+		Shadow:      shadow.(plugin.Shadow),
+		MaxAttempts: config.Webhook.MaxAttempts,
+		Backoff:     config.Webhook.Backoff,
+		MaxBackoff:  config.Webhook.MaxBackoff,
+		Timeout:     timeout,
+		client:      newWebhookDestination(config.Webhook.AllowedHosts, timeout).newClient(timeout),
+		log:         log.L().With(log.Any("service", "webhook")),
+	}, nil
+}
+
+func (w *WebhookServiceImpl) GetCallback(namespace, name string) (*models.Callback, error) {
+	callback, err := w.Callback.GetCallback(nil, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	callback.Secret = ""
+	return callback, nil
+}
+
+func (w *WebhookServiceImpl) ListCallback(namespace string) (*models.CallbackList, error) {
+	callbacks, err := w.Callback.ListCallback(nil, namespace)
+	if err != nil {
+		return nil, err
+	}
+	items := append([]models.Callback{}, callbacks...)
+	for i := range items {
+		items[i].Secret = ""
+	}
+	return &models.CallbackList{
+		Total: len(items),
+		Items: items,
+	}, nil
+}
+
+func (w *WebhookServiceImpl) CreateCallback(callback *models.Callback) (*models.Callback, error) {
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+	callback.Secret = secret
+	res, err := w.Callback.CreateCallback(nil, callback)
+	if err != nil {
+		return nil, err
+	}
+	res.Secret = secret
+	return res, nil
+}
+
+func (w *WebhookServiceImpl) UpdateCallback(callback *models.Callback) (*models.Callback, error) {
+	res, err := w.Callback.UpdateCallback(nil, callback)
+	if err != nil {
+		return nil, err
+	}
+	res.Secret = ""
+	return res, nil
+}
+
+func (w *WebhookServiceImpl) DeleteCallback(namespace, name string) error {
+	if err := w.Callback.DeleteDeliveryByCallback(nil, namespace, name); err != nil {
+		return err
+	}
+	return w.Callback.DeleteCallback(nil, namespace, name)
+}
+
+func (w *WebhookServiceImpl) GetDelivery(namespace, name string) (*models.Delivery, error) {
+	return w.Callback.GetDelivery(nil, namespace, name)
+}
+
+func (w *WebhookServiceImpl) ListDelivery(namespace, callback string, params *models.ListOptions) (*models.DeliveryList, error) {
+	return w.Callback.ListDelivery(nil, namespace, callback, params)
+}
+
+func (w *WebhookServiceImpl) ListSubscribedNamespaces(event string) ([]string, error) {
+	callbacks, err := w.Callback.ListCallbackByEvent(nil, event)
+	if err != nil {
+		return nil, err
+	}
+	var res []string
+	set := map[string]bool{}
+	for i := range callbacks {
+		if ns := callbacks[i].Namespace; subscribes(&callbacks[i], event) && !set[ns] {
+			set[ns] = true
+			res = append(res, ns)
+		}
+	}
+	return res, nil
+}
+
+func (w *WebhookServiceImpl) Emit(event *models.Event) error {
+	if event.ID == "" {
+		event.ID = strings.ToLower(common.RandString(deliveryNameLength))
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	callbacks, err := w.Callback.ListCallback(nil, event.Namespace)
+	if err != nil {
+		return err
+	}
+	for i := range callbacks {
+		if !subscribes(&callbacks[i], event.Type) {
+			continue
+		}
+		count, err := w.Callback.CountDeliveryByEvent(nil, event.Namespace, callbacks[i].Name, event.ID)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		delivery := &models.Delivery{
+			Name:      strings.ToLower(common.RandString(deliveryNameLength)),
+			Namespace: event.Namespace,
+			Callback:  callbacks[i].Name,
+			Event:     event,
+			Status:    models.DeliveryPending,
+			NextTime:  event.Time,
+		}
+		if err = w.Callback.CreateDelivery(nil, delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *WebhookServiceImpl) Deliver() error {
+	deliveries, err := w.Callback.ListDueDelivery(nil, time.Now().UTC(), deliveryBatchSize)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for i := range deliveries {
+		d := &deliveries[i]
+		// the delivery is claimed before sending, so that it's sent by only one of the instances
+		now := time.Now().UTC()
+		claimed, err := w.Callback.ClaimDelivery(nil, d, now, now.Add(w.Timeout+deliveryClaimLease))
+		if err != nil {
+			w.log.Error("failed to claim delivery", log.Any("namespace", d.Namespace), log.Any("name", d.Name), log.Error(err))
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		w.attempt(d)
+		if err = w.Callback.UpdateDelivery(nil, d); err != nil {
+			w.log.Error("failed to update delivery", log.Any("namespace", d.Namespace), log.Any("name", d.Name), log.Error(err))
+		}
+	}
+	return nil
+}
+
+func (w *WebhookServiceImpl) Redeliver(namespace, name string) (*models.Delivery, error) {
+	d, err := w.Callback.GetDelivery(nil, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	// the delivery is claimed as Deliver does, so that it isn't sent by others at the same time
+	now := time.Now().UTC()
+	claimed, err := w.Callback.ClaimDelivery(nil, d, now, now.Add(w.Timeout+deliveryClaimLease))
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", "the delivery is being sent, please retry later"))
+	}
+	d.Attempts = 0
+	w.attempt(d)
+	if err = w.Callback.UpdateDelivery(nil, d); err != nil {
+		return nil, err
+	}
+	return w.Callback.GetDelivery(nil, namespace, name)
+}
+
+func (w *WebhookServiceImpl) NotifyOfflineNodes() error {
+	namespaces, err := w.ListSubscribedNamespaces(models.EventNodeOffline)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, ns := range namespaces {
+		if err = w.notifyOfflineNodes(ns); err != nil {
+			w.log.Error("failed to notify offline nodes", log.Any("namespace", ns), log.Error(err))
+		}
+	}
+	return nil
+}
+
+func (w *WebhookServiceImpl) notifyOfflineNodes(namespace string) error {
+	nodes, err := w.Node.ListNode(nil, namespace, &models.ListOptions{})
+	if err != nil {
+		return err
+	}
+	if len(nodes.Items) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(nodes.Items))
+	for _, n := range nodes.Items {
+		names = append(names, n.Name)
+	}
+	shadows, err := w.Shadow.ListShadowByNames(nil, namespace, names)
+	if err != nil {
+		return err
+	}
+	reports := map[string]time.Time{}
+	for _, sd := range shadows {
+		reports[sd.Name] = sd.Time
+	}
+	now := time.Now().UTC()
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		report, ok := reports[node.Name]
+		// the node which never reports is not installed yet
+		if !ok || report.IsZero() || !now.After(report.Add(nodeOfflineTimeout(node))) {
+			continue
+		}
+		event := &models.Event{
+			// the node going offline again after reporting is a new event
+			ID:        fmt.Sprintf("%s/%s/%d", models.EventNodeOffline, node.Name, report.Unix()),
+			Type:      models.EventNodeOffline,
+			Namespace: namespace,
+			Data: map[string]interface{}{
+				"node":       node.Name,
+				"reportTime": report.UTC(),
+			},
+		}
+		if err = w.Emit(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attempt sends the delivery once and records the result
+func (w *WebhookServiceImpl) attempt(d *models.Delivery) {
+	now := time.Now().UTC()
+	d.Attempts++
+	code, err := w.send(d)
+	d.ResponseCode = code
+	if err == nil {
+		d.Status = models.DeliverySucceeded
+		d.Reason = ""
+		return
+	}
+	d.Reason = err.Error()
+	if d.Attempts >= w.MaxAttempts {
+		d.Status = models.DeliveryFailed
+		w.log.Warn("the delivery failed after the max attempts", log.Any("namespace", d.Namespace),
+			log.Any("callback", d.Callback), log.Any("name", d.Name), log.Error(err))
+		return
+	}
+	d.Status = models.DeliveryPending
+	d.NextTime = now.Add(w.backoff(d.Attempts))
+}
+
+// backoff returns the delay before the next attempt, doubled after each failed attempt
+func (w *WebhookServiceImpl) backoff(attempts int) time.Duration {
+	delay := w.Backoff
+	for i := 1; i < attempts && delay < w.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if w.MaxBackoff > 0 && delay > w.MaxBackoff {
+		delay = w.MaxBackoff
+	}
+	return delay
+}
+
+// send renders the request with the event and sends it to the callback, the status code is returned if responded
+func (w *WebhookServiceImpl) send(d *models.Delivery) (int, error) {
+	if d.Event == nil {
+		return 0, errors.New("the event of delivery is missing")
+	}
+	callback, err := w.Callback.GetCallback(nil, d.Namespace, d.Callback)
+	if err != nil {
+		return 0, err
+	}
+	body, err := renderWebhookBody(callback, d.Event)
+	if err != nil {
+		return 0, err
+	}
+	rawURL, err := renderWebhookTemplate(callback.URL, d.Event)
+	if err != nil {
+		return 0, err
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if err = checkWebhookScheme(u.Scheme); err != nil {
+		return 0, err
+	}
+	query := u.Query()
+	for k, v := range callback.Params {
+		if v, err = renderWebhookTemplate(v, d.Event); err != nil {
+			return 0, err
+		}
+		query.Set(k, v)
+	}
+	u.RawQuery = query.Encode()
+
+	req, err := gohttp.NewRequest(callback.Method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range callback.Header {
+		if v, err = renderWebhookTemplate(v, d.Event); err != nil {
+			return 0, err
+		}
+		req.Header.Set(k, v)
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set(HeaderWebhookEvent, d.Event.Type)
+	req.Header.Set(HeaderWebhookDelivery, d.Name)
+	req.Header.Set(HeaderWebhookTimestamp, timestamp)
+	// the callbacks created before the secrets are introduced aren't signed
+	if callback.Secret != "" {
+		req.Header.Set(HeaderWebhookSignature, signWebhook(callback.Secret, timestamp, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	_, err = http.HandleResponse(resp)
+	return resp.StatusCode, err
+}
+
+// renderWebhookBody renders the body templates of callback with the event, the event itself is sent if no body is set
+func renderWebhookBody(callback *models.Callback, event *models.Event) ([]byte, error) {
+	if len(callback.Body) == 0 {
+		data, err := json.Marshal(event)
+		return data, errors.Trace(err)
+	}
+	payload := map[string]string{}
+	for k, v := range callback.Body {
+		res, err := renderWebhookTemplate(v, event)
+		if err != nil {
+			return nil, err
+		}
+		payload[k] = res
+	}
+	data, err := json.Marshal(payload)
+	return data, errors.Trace(err)
+}
+
+// signWebhook returns the hex of HMAC-SHA256 on the timestamp and the body joined by a dot
+func signWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func newWebhookSecret() (string, error) {
+	secret := make([]byte, webhookSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", errors.Trace(err)
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+func renderWebhookTemplate(text string, event *models.Event) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	t, err := template.New("webhook").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", common.Error(common.ErrTemplate, common.Field("error", err.Error()))
+	}
+	buf := new(bytes.Buffer)
+	if err = t.Execute(buf, event); err != nil {
+		return "", common.Error(common.ErrTemplate, common.Field("error", err.Error()))
+	}
+	return buf.String(), nil
+}
+
+func subscribes(callback *models.Callback, event string) bool {
+	for _, e := range callback.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"net"
+	gohttp "net/http"
+	"strings"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+)
+
+const webhookMaxRedirects = 10
+
+// the carrier-grade nat range, which isn't covered by net.IP.IsPrivate
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// webhookDestination rejects the loopback, link-local and private addresses which the webhooks are sent to,
+// unless the hosts or addresses are allowed. The address is checked on every dial, including the redirects,
+// and the checked address is dialed so that the host can't be resolved to another one in between
+type webhookDestination struct {
+	hosts    map[string]bool
+	nets     []*net.IPNet
+	resolver *net.Resolver
+	dialer   *net.Dialer
+}
+
+func newWebhookDestination(allowed []string, timeout time.Duration) *webhookDestination {
+	d := &webhookDestination{
+		hosts:    map[string]bool{},
+		resolver: net.DefaultResolver,
+		dialer:   &net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second},
+	}
+	for _, a := range allowed {
+		a = strings.ToLower(strings.TrimSpace(a))
+		if _, ipNet, err := net.ParseCIDR(a); err == nil {
+			d.nets = append(d.nets, ipNet)
+		} else if ip := net.ParseIP(a); ip != nil {
+			d.nets = append(d.nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)})
+		} else if a != "" {
+			d.hosts[a] = true
+		}
+	}
+	return d
+}
+
+// newClient returns the client which sends the webhooks to the permitted destinations only
+func (d *webhookDestination) newClient(timeout time.Duration) *gohttp.Client {
+	return &gohttp.Client{
+		Timeout: timeout,
+		// the proxy isn't used, otherwise the address of the proxy is checked instead of the destination
+		Transport: &gohttp.Transport{
+			DialContext:         d.dialContext,
+			ForceAttemptHTTP2:   true,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+		CheckRedirect: func(req *gohttp.Request, via []*gohttp.Request) error {
+			if len(via) >= webhookMaxRedirects {
+				return errors.Errorf("stopped after %d redirects", webhookMaxRedirects)
+			}
+			return checkWebhookScheme(req.URL.Scheme)
+		},
+	}
+}
+
+func (d *webhookDestination) dialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ips, err := d.resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if len(ips) == 0 {
+		return nil, errors.Errorf("no address of host (%s) is found", host)
+	}
+	for _, ip := range ips {
+		if err = d.check(host, ip.IP); err != nil {
+			return nil, err
+		}
+	}
+	return d.dialer.DialContext(ctx, network, net.JoinHostPort(ips[0].IP.String(), port))
+}
+
+// check returns an error if the address of host isn't permitted
+func (d *webhookDestination) check(host string, ip net.IP) error {
+	if d.hosts[strings.ToLower(strings.TrimSuffix(host, "."))] {
+		return nil
+	}
+	for _, n := range d.nets {
+		if n.Contains(ip) {
+			return nil
+		}
+	}
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() || sharedAddressSpace.Contains(ip) {
+		return errors.Errorf("the address (%s) of host (%s) isn't allowed to be sent to", ip.String(), host)
+	}
+	return nil
+}
+
+func checkWebhookScheme(scheme string) error {
+	if scheme != "http" && scheme != "https" {
+		return errors.Errorf("the scheme (%s) isn't supported", scheme)
+	}
+	return nil
+}
@@ -0,0 +1,370 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net"
+	gohttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/json"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func newTestWebhookService(mockCtl *gomock.Controller) (*WebhookServiceImpl, *mockPlugin.MockCallback) {
+	mCallback := mockPlugin.NewMockCallback(mockCtl)
+	w := &WebhookServiceImpl{
+		Callback:    mCallback,
+		MaxAttempts: 3,
+		Backoff:     time.Second,
+		MaxBackoff:  3 * time.Second,
+		Timeout:     time.Second,
+		client:      newWebhookDestination([]string{"127.0.0.1"}, time.Second).newClient(time.Second),
+		log:         log.L(),
+	}
+	return w, mCallback
+}
+
+func TestWebhookEmit(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	w, mCallback := newTestWebhookService(mockCtl)
+
+	ns := "default"
+	callbacks := []models.Callback{
+		{Name: "c1", Namespace: ns, Events: []string{models.EventRolloutFinished}},
+		{Name: "c2", Namespace: ns, Events: []string{models.EventRolloutFinished, models.EventNodeOffline}},
+		{Name: "c3", Namespace: ns, Events: []string{models.EventNodeOffline}},
+	}
+	event := &models.Event{ID: "e1", Type: models.EventRolloutFinished, Namespace: ns}
+	mCallback.EXPECT().ListCallback(nil, ns).Return(callbacks, nil).Times(1)
+	// the event has been delivered to c1
+	mCallback.EXPECT().CountDeliveryByEvent(nil, ns, "c1", "e1").Return(1, nil).Times(1)
+	mCallback.EXPECT().CountDeliveryByEvent(nil, ns, "c2", "e1").Return(0, nil).Times(1)
+	mCallback.EXPECT().CreateDelivery(nil, gomock.Any()).DoAndReturn(func(_ interface{}, d *models.Delivery) error {
+		assert.Equal(t, "c2", d.Callback)
+		assert.Equal(t, ns, d.Namespace)
+		assert.Equal(t, models.DeliveryPending, d.Status)
+		assert.Len(t, d.Name, deliveryNameLength)
+		assert.Equal(t, event, d.Event)
+		return nil
+	}).Times(1)
+	assert.NoError(t, w.Emit(event))
+	assert.False(t, event.Time.IsZero())
+
+	mCallback.EXPECT().ListCallback(nil, ns).Return(nil, fmt.Errorf("error")).Times(1)
+	assert.Error(t, w.Emit(&models.Event{Type: models.EventNodeOffline, Namespace: ns}))
+}
+
+func TestWebhookListSubscribedNamespaces(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	w, mCallback := newTestWebhookService(mockCtl)
+
+	mCallback.EXPECT().ListCallbackByEvent(nil, models.EventNodeOffline).Return([]models.Callback{
+		{Name: "c1", Namespace: "a", Events: []string{models.EventNodeOffline}},
+		{Name: "c2", Namespace: "a", Events: []string{models.EventNodeOffline}},
+		// the event is only a part of the other event name
+		{Name: "c3", Namespace: "b", Events: []string{models.EventNodeOffline + ".x"}},
+		{Name: "c4", Namespace: "c", Events: []string{models.EventNodeOffline}},
+	}, nil).Times(1)
+	res, err := w.ListSubscribedNamespaces(models.EventNodeOffline)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "c"}, res)
+}
+
+func TestWebhookDeliver(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	w, mCallback := newTestWebhookService(mockCtl)
+
+	var received []*gohttp.Request
+	var bodies []string
+	failed := false
+	server := httptest.NewServer(gohttp.HandlerFunc(func(rw gohttp.ResponseWriter, r *gohttp.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		received = append(received, r)
+		bodies = append(bodies, string(body))
+		if failed {
+			rw.WriteHeader(gohttp.StatusInternalServerError)
+			return
+		}
+		rw.WriteHeader(gohttp.StatusOK)
+	}))
+	defer server.Close()
+
+	ns := "default"
+	callback := &models.Callback{
+		Name:      "c1",
+		Namespace: ns,
+		Method:    gohttp.MethodPost,
+		URL:       server.URL + "/hooks/{{.Type}}",
+		Params:    map[string]string{"ns": "{{.Namespace}}"},
+		Header:    map[string]string{"X-App": "{{.Data.app}}"},
+		Body:      map[string]string{"text": "app {{.Data.app}} is {{.Data.status}}", "missing": "{{.Data.none}}"},
+		Secret:    "s3cr3t",
+	}
+	event := &models.Event{ID: "e1", Type: models.EventRolloutFinished, Namespace: ns,
+		Data: map[string]interface{}{"app": "a1", "status": "Succeeded"}}
+	d := models.Delivery{Name: "d1", Namespace: ns, Callback: "c1", Event: event, Status: models.DeliveryPending}
+
+	// the delivery succeeds
+	mCallback.EXPECT().ListDueDelivery(nil, gomock.Any(), deliveryBatchSize).Return([]models.Delivery{d}, nil).Times(1)
+	mCallback.EXPECT().ClaimDelivery(nil, gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil).Times(1)
+	mCallback.EXPECT().GetCallback(nil, ns, "c1").Return(callback, nil).Times(1)
+	mCallback.EXPECT().UpdateDelivery(nil, gomock.Any()).DoAndReturn(func(_ interface{}, res *models.Delivery) error {
+		assert.Equal(t, models.DeliverySucceeded, res.Status)
+		assert.Equal(t, 1, res.Attempts)
+		assert.Equal(t, gohttp.StatusOK, res.ResponseCode)
+		return nil
+	}).Times(1)
+	assert.NoError(t, w.Deliver())
+	assert.Len(t, received, 1)
+	assert.Equal(t, "/hooks/"+models.EventRolloutFinished, received[0].URL.Path)
+	assert.Equal(t, ns, received[0].URL.Query().Get("ns"))
+	assert.Equal(t, "a1", received[0].Header.Get("X-App"))
+	assert.Equal(t, models.EventRolloutFinished, received[0].Header.Get(HeaderWebhookEvent))
+	assert.Equal(t, "d1", received[0].Header.Get(HeaderWebhookDelivery))
+	timestamp := received[0].Header.Get(HeaderWebhookTimestamp)
+	assert.NotEmpty(t, timestamp)
+	mac := hmac.New(sha256.New, []byte("s3cr3t"))
+	mac.Write([]byte(timestamp + "." + bodies[0]))
+	assert.Equal(t, hex.EncodeToString(mac.Sum(nil)), received[0].Header.Get(HeaderWebhookSignature))
+	payload := map[string]string{}
+	assert.NoError(t, json.Unmarshal([]byte(bodies[0]), &payload))
+	assert.Equal(t, map[string]string{"text": "app a1 is Succeeded", "missing": "<no value>"}, payload)
+
+	// the failed delivery is retried with backoff
+	failed = true
+	d.Attempts = 1
+	mCallback.EXPECT().ListDueDelivery(nil, gomock.Any(), deliveryBatchSize).Return([]models.Delivery{d}, nil).Times(1)
+	mCallback.EXPECT().ClaimDelivery(nil, gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil).Times(1)
+	mCallback.EXPECT().GetCallback(nil, ns, "c1").Return(callback, nil).Times(1)
+	mCallback.EXPECT().UpdateDelivery(nil, gomock.Any()).DoAndReturn(func(_ interface{}, res *models.Delivery) error {
+		assert.Equal(t, models.DeliveryPending, res.Status)
+		assert.Equal(t, 2, res.Attempts)
+		assert.Equal(t, gohttp.StatusInternalServerError, res.ResponseCode)
+		assert.NotEmpty(t, res.Reason)
+		assert.True(t, res.NextTime.After(time.Now().Add(time.Second)))
+		return nil
+	}).Times(1)
+	assert.NoError(t, w.Deliver())
+
+	// the delivery fails after the max attempts
+	d.Attempts = 2
+	mCallback.EXPECT().ListDueDelivery(nil, gomock.Any(), deliveryBatchSize).Return([]models.Delivery{d}, nil).Times(1)
+	mCallback.EXPECT().ClaimDelivery(nil, gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil).Times(1)
+	mCallback.EXPECT().GetCallback(nil, ns, "c1").Return(callback, nil).Times(1)
+	mCallback.EXPECT().UpdateDelivery(nil, gomock.Any()).DoAndReturn(func(_ interface{}, res *models.Delivery) error {
+		assert.Equal(t, models.DeliveryFailed, res.Status)
+		assert.Equal(t, 3, res.Attempts)
+		return nil
+	}).Times(1)
+	assert.NoError(t, w.Deliver())
+
+	// redeliver restarts the attempts after claiming the delivery
+	failed = false
+	mCallback.EXPECT().GetDelivery(nil, ns, "d1").Return(&models.Delivery{Name: "d1", Namespace: ns, Callback: "c1",
+		Event: event, Status: models.DeliveryFailed, Attempts: 3}, nil).Times(1)
+	mCallback.EXPECT().ClaimDelivery(nil, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ interface{}, res *models.Delivery, now, until time.Time) (bool, error) {
+			assert.Equal(t, models.DeliveryFailed, res.Status)
+			assert.Equal(t, 3, res.Attempts)
+			return true, nil
+		}).Times(1)
+	mCallback.EXPECT().GetCallback(nil, ns, "c1").Return(callback, nil).Times(1)
+	mCallback.EXPECT().UpdateDelivery(nil, gomock.Any()).DoAndReturn(func(_ interface{}, res *models.Delivery) error {
+		assert.Equal(t, models.DeliverySucceeded, res.Status)
+		assert.Equal(t, 1, res.Attempts)
+		return nil
+	}).Times(1)
+	mCallback.EXPECT().GetDelivery(nil, ns, "d1").Return(&models.Delivery{Name: "d1", Status: models.DeliverySucceeded}, nil).Times(1)
+	res, err := w.Redeliver(ns, "d1")
+	assert.NoError(t, err)
+	assert.Equal(t, models.DeliverySucceeded, res.Status)
+	assert.Len(t, received, 4)
+
+	// the delivery being sent by others isn't redelivered
+	mCallback.EXPECT().GetDelivery(nil, ns, "d1").Return(&models.Delivery{Name: "d1", Namespace: ns, Callback: "c1",
+		Event: event, Status: models.DeliveryPending, Attempts: 1}, nil).Times(1)
+	mCallback.EXPECT().ClaimDelivery(nil, gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil).Times(1)
+	_, err = w.Redeliver(ns, "d1")
+	assert.Error(t, err)
+	assert.Len(t, received, 4)
+
+	// the callback without secret isn't signed
+	callback.Secret = ""
+	mCallback.EXPECT().GetCallback(nil, ns, "c1").Return(callback, nil).Times(1)
+	_, err = w.send(&d)
+	assert.NoError(t, err)
+	assert.Len(t, received, 5)
+	assert.Empty(t, received[4].Header.Get(HeaderWebhookSignature))
+}
+
+func TestWebhookCallbackSecret(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	w, mCallback := newTestWebhookService(mockCtl)
+
+	ns := "default"
+	var secret string
+	mCallback.EXPECT().CreateCallback(nil, gomock.Any()).DoAndReturn(func(_ interface{}, c *models.Callback) (*models.Callback, error) {
+		secret = c.Secret
+		res := *c
+		return &res, nil
+	}).Times(1)
+	res, err := w.CreateCallback(&models.Callback{Name: "c1", Namespace: ns, Secret: "given"})
+	assert.NoError(t, err)
+	assert.Len(t, secret, 2*webhookSecretSize)
+	assert.Equal(t, secret, res.Secret)
+
+	// the secret is returned only on creation
+	mCallback.EXPECT().GetCallback(nil, ns, "c1").Return(&models.Callback{Name: "c1", Secret: secret}, nil).Times(1)
+	res, err = w.GetCallback(ns, "c1")
+	assert.NoError(t, err)
+	assert.Empty(t, res.Secret)
+	mCallback.EXPECT().ListCallback(nil, ns).Return([]models.Callback{{Name: "c1", Secret: secret}}, nil).Times(1)
+	list, err := w.ListCallback(ns)
+	assert.NoError(t, err)
+	assert.Empty(t, list.Items[0].Secret)
+	mCallback.EXPECT().UpdateCallback(nil, gomock.Any()).Return(&models.Callback{Name: "c1", Secret: secret}, nil).Times(1)
+	res, err = w.UpdateCallback(&models.Callback{Name: "c1", Namespace: ns})
+	assert.NoError(t, err)
+	assert.Empty(t, res.Secret)
+}
+
+func TestWebhookDeliverClaimed(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	w, mCallback := newTestWebhookService(mockCtl)
+
+	d1 := models.Delivery{Name: "d1", Namespace: "default", Callback: "c1", Status: models.DeliveryPending, Attempts: 1}
+	d2 := models.Delivery{Name: "d2", Namespace: "default", Callback: "c1", Status: models.DeliveryPending}
+	mCallback.EXPECT().ListDueDelivery(nil, gomock.Any(), deliveryBatchSize).Return([]models.Delivery{d1, d2}, nil).Times(1)
+	// the delivery claimed by another instance isn't sent
+	mCallback.EXPECT().ClaimDelivery(nil, &d1, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ interface{}, _ *models.Delivery, now, until time.Time) (bool, error) {
+			assert.Equal(t, now.Add(w.Timeout+deliveryClaimLease), until)
+			return false, nil
+		}).Times(1)
+	mCallback.EXPECT().ClaimDelivery(nil, &d2, gomock.Any(), gomock.Any()).Return(false, fmt.Errorf("error")).Times(1)
+	assert.NoError(t, w.Deliver())
+}
+
+func TestWebhookDestination(t *testing.T) {
+	d := newWebhookDestination([]string{"10.1.0.0/16", "192.168.1.1", "Internal.Example.com", ""}, time.Second)
+	denied := []string{"127.0.0.1", "::1", "169.254.169.254", "fe80::1", "10.0.0.1", "172.16.0.1", "192.168.0.1",
+		"fd00::1", "0.0.0.0", "100.64.0.1", "224.0.0.1"}
+	for _, ip := range denied {
+		assert.Error(t, d.check("example.com", net.ParseIP(ip)), ip)
+	}
+	allowed := []string{"8.8.8.8", "2001:4860:4860::8888", "10.1.2.3", "192.168.1.1"}
+	for _, ip := range allowed {
+		assert.NoError(t, d.check("example.com", net.ParseIP(ip)), ip)
+	}
+	assert.NoError(t, d.check("internal.example.com.", net.ParseIP("127.0.0.1")))
+
+	var hits int
+	target := httptest.NewServer(gohttp.HandlerFunc(func(rw gohttp.ResponseWriter, r *gohttp.Request) {
+		hits++
+		rw.WriteHeader(gohttp.StatusOK)
+	}))
+	defer target.Close()
+	redirect := httptest.NewServer(gohttp.HandlerFunc(func(rw gohttp.ResponseWriter, r *gohttp.Request) {
+		gohttp.Redirect(rw, r, target.URL, gohttp.StatusFound)
+	}))
+	defer redirect.Close()
+	_, port, _ := net.SplitHostPort(redirect.Listener.Addr().String())
+
+	// the loopback address is rejected
+	client := newWebhookDestination(nil, time.Second).newClient(time.Second)
+	_, err := client.Get(target.URL)
+	assert.Error(t, err)
+
+	// the redirect to the loopback address is rejected even if the first host is allowed
+	client = newWebhookDestination([]string{"localhost"}, time.Second).newClient(time.Second)
+	_, err = client.Get("http://localhost:" + port)
+	assert.Error(t, err)
+	assert.Equal(t, 0, hits)
+
+	client = newWebhookDestination([]string{"127.0.0.1"}, time.Second).newClient(time.Second)
+	resp, err := client.Get(redirect.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, 1, hits)
+
+	// the schemes other than http and https are rejected
+	assert.NoError(t, checkWebhookScheme("https"))
+	assert.Error(t, checkWebhookScheme("file"))
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	w := &WebhookServiceImpl{Backoff: time.Second, MaxBackoff: 5 * time.Second}
+	assert.Equal(t, time.Second, w.backoff(1))
+	assert.Equal(t, 2*time.Second, w.backoff(2))
+	assert.Equal(t, 4*time.Second, w.backoff(3))
+	assert.Equal(t, 5*time.Second, w.backoff(4))
+	assert.Equal(t, 5*time.Second, w.backoff(10))
+}
+
+func TestRenderWebhookBody(t *testing.T) {
+	event := &models.Event{ID: "e1", Type: models.EventQuotaExceeded, Namespace: "default",
+		Data: map[string]interface{}{"name": "maxNodeCount", "limit": 10}}
+
+	// the event is sent if no body is set
+	data, err := renderWebhookBody(&models.Callback{}, event)
+	assert.NoError(t, err)
+	res := &models.Event{}
+	assert.NoError(t, json.Unmarshal(data, res))
+	assert.Equal(t, "e1", res.ID)
+	assert.Equal(t, "maxNodeCount", res.Data["name"])
+
+	data, err = renderWebhookBody(&models.Callback{Body: map[string]string{"msg": "{{.Namespace}}: {{.Data.name}} reaches {{.Data.limit}}"}}, event)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"msg":"default: maxNodeCount reaches 10"}`, string(data))
+
+	_, err = renderWebhookBody(&models.Callback{Body: map[string]string{"msg": "{{.Data.name"}}, event)
+	assert.Error(t, err)
+}
+
+func TestWebhookNotifyOfflineNodes(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	w, mCallback := newTestWebhookService(mockCtl)
+	mNode := mockPlugin.NewMockNode(mockCtl)
+	mShadow := mockPlugin.NewMockShadow(mockCtl)
+	w.Node = mNode
+	w.Shadow = mShadow
+
+	ns := "default"
+	callbacks := []models.Callback{{Name: "c1", Namespace: ns, Events: []string{models.EventNodeOffline}}}
+	report := time.Now().Add(-time.Hour)
+	mCallback.EXPECT().ListCallbackByEvent(nil, models.EventNodeOffline).Return(callbacks, nil).Times(1)
+	mNode.EXPECT().ListNode(nil, ns, &models.ListOptions{}).Return(&models.NodeList{Items: []specV1.Node{
+		{Name: "offline", Namespace: ns}, {Name: "online", Namespace: ns}, {Name: "new", Namespace: ns},
+	}}, nil).Times(1)
+	mShadow.EXPECT().ListShadowByNames(nil, ns, []string{"offline", "online", "new"}).Return([]*models.Shadow{
+		{Name: "offline", Time: report},
+		{Name: "online", Time: time.Now()},
+	}, nil).Times(1)
+	mCallback.EXPECT().ListCallback(nil, ns).Return(callbacks, nil).Times(1)
+	eventID := fmt.Sprintf("%s/offline/%d", models.EventNodeOffline, report.Unix())
+	mCallback.EXPECT().CountDeliveryByEvent(nil, ns, "c1", eventID).Return(0, nil).Times(1)
+	mCallback.EXPECT().CreateDelivery(nil, gomock.Any()).DoAndReturn(func(_ interface{}, d *models.Delivery) error {
+		assert.Equal(t, models.EventNodeOffline, d.Event.Type)
+		assert.Equal(t, "offline", d.Event.Data["node"])
+		return nil
+	}).Times(1)
+	assert.NoError(t, w.NotifyOfflineNodes())
+}